@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/kballard/go-shellquote"
+	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util/errors"
 	executil "github.com/zk-org/zk/internal/util/exec"
 	"github.com/zk-org/zk/internal/util/opt"
@@ -14,12 +15,21 @@ import (
 
 // Editor represents an external editor able to edit the notes.
 type Editor struct {
-	editor string
+	// Line at which the notes are opened, for editor commands using the
+	// {{line}} template variable. Unset when 0.
+	Line int
+
+	editor    string
+	templates core.TemplateLoader
 }
 
 // NewEditor creates a new Editor from the given editor user setting or the
 // matching environment variables.
-func NewEditor(editor opt.String) (*Editor, error) {
+//
+// The editor command is a template receiving the `path` of the notes and the
+// optional `line`, e.g. `code --goto {{path}}:{{line}}`. When the template
+// doesn't use `path`, the paths are appended to the command.
+func NewEditor(editor opt.String, templates core.TemplateLoader) (*Editor, error) {
 	editor = osutil.GetOptEnv("ZK_EDITOR").
 		Or(editor).
 		Or(osutil.GetOptEnv("VISUAL")).
@@ -29,7 +39,10 @@ func NewEditor(editor opt.String) (*Editor, error) {
 		return nil, fmt.Errorf("no editor set in config")
 	}
 
-	return &Editor{editor.Unwrap()}, nil
+	return &Editor{
+		editor:    editor.Unwrap(),
+		templates: templates,
+	}, nil
 }
 
 // Open launches the editor with the notes at given paths.
@@ -38,10 +51,61 @@ func (e *Editor) Open(paths ...string) error {
 	// initial note content to `zk new`. Without this, Vim doesn't work
 	// properly in this case.
 	// See https://github.com/zk-org/zk/issues/4
-	cmd := executil.CommandFromString(e.editor + " " + shellquote.Join(paths...) + " </dev/tty")
+	command, err := e.command(paths)
+	if err != nil {
+		return err
+	}
+	cmd := executil.CommandFromString(command + " </dev/tty")
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
 	return errors.Wrapf(cmd.Run(), "failed to launch editor: %s %s", e.editor, strings.Join(paths, " "))
 }
+
+// OpenSequentially launches the editor once per note, waiting for each
+// session to exit before opening the next note. This is useful for editors
+// which can't open multiple files at the same time.
+func (e *Editor) OpenSequentially(paths ...string) error {
+	for _, path := range paths {
+		if err := e.Open(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// command renders the editor command opening the given paths.
+func (e *Editor) command(paths []string) (string, error) {
+	quotedPaths := shellquote.Join(paths...)
+	if !strings.Contains(e.editor, "{{") {
+		return e.editor + " " + quotedPaths, nil
+	}
+
+	wrap := errors.Wrapperf("failed to render the editor command: %s", e.editor)
+	template, err := e.templates.LoadTemplate(e.editor)
+	if err != nil {
+		return "", wrap(err)
+	}
+	render := func(path string) (string, error) {
+		context := map[string]interface{}{"path": path}
+		if e.Line > 0 {
+			context["line"] = e.Line
+		}
+		return template.Render(context)
+	}
+
+	command, err := render(quotedPaths)
+	if err != nil {
+		return "", wrap(err)
+	}
+	// Rendering without the paths tells whether the template uses them.
+	commandWithoutPath, err := render("")
+	if err != nil {
+		return "", wrap(err)
+	}
+	if command == commandWithoutPath {
+		command += " " + quotedPaths
+	}
+	return command, nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/zk-org/zk/internal/adapter/handlebars"
 	"github.com/zk-org/zk/internal/util/opt"
 	"github.com/zk-org/zk/internal/util/test/assert"
 )
@@ -13,7 +14,7 @@ func TestEditorUsesZkEditorFirst(t *testing.T) {
 	os.Setenv("VISUAL", "visual")
 	os.Setenv("EDITOR", "editor")
 
-	editor, err := NewEditor(opt.NewString("custom-editor"))
+	editor, err := NewEditor(opt.NewString("custom-editor"), nil)
 	assert.Nil(t, err)
 	assert.Equal(t, editor.editor, "zk-editor")
 }
@@ -23,7 +24,7 @@ func TestEditorFallsbackOnUserConfig(t *testing.T) {
 	os.Setenv("VISUAL", "visual")
 	os.Setenv("EDITOR", "editor")
 
-	editor, err := NewEditor(opt.NewString("custom-editor"))
+	editor, err := NewEditor(opt.NewString("custom-editor"), nil)
 	assert.Nil(t, err)
 	assert.Equal(t, editor.editor, "custom-editor")
 }
@@ -33,7 +34,7 @@ func TestEditorFallsbackOnVisual(t *testing.T) {
 	os.Setenv("VISUAL", "visual")
 	os.Setenv("EDITOR", "editor")
 
-	editor, err := NewEditor(opt.NullString)
+	editor, err := NewEditor(opt.NullString, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, editor.editor, "visual")
 }
@@ -43,7 +44,7 @@ func TestEditorFallsbackOnEditor(t *testing.T) {
 	os.Unsetenv("VISUAL")
 	os.Setenv("EDITOR", "editor")
 
-	editor, err := NewEditor(opt.NullString)
+	editor, err := NewEditor(opt.NullString, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, editor.editor, "editor")
 }
@@ -53,7 +54,55 @@ func TestEditorFailsWhenUnset(t *testing.T) {
 	os.Unsetenv("VISUAL")
 	os.Unsetenv("EDITOR")
 
-	editor, err := NewEditor(opt.NullString)
+	editor, err := NewEditor(opt.NullString, nil)
 	assert.Err(t, err, "no editor set in config")
 	assert.Nil(t, editor)
 }
+
+func newTemplateEditor(t *testing.T, command string, line int) *Editor {
+	os.Unsetenv("ZK_EDITOR")
+	editor, err := NewEditor(opt.NewString(command), handlebars.NewLoader(handlebars.LoaderOpts{}))
+	assert.Nil(t, err)
+	editor.Line = line
+	return editor
+}
+
+func TestEditorCommandAppendsPaths(t *testing.T) {
+	editor := newTemplateEditor(t, "vim -O", 0)
+	command, err := editor.command([]string{"/notes/a.md", "/notes/my note.md"})
+	assert.Nil(t, err)
+	assert.Equal(t, command, `vim -O /notes/a.md '/notes/my note.md'`)
+}
+
+func TestEditorCommandRendersPathAndLine(t *testing.T) {
+	editor := newTemplateEditor(t, "code --goto {{path}}:{{line}}", 42)
+	command, err := editor.command([]string{"/notes/my note.md"})
+	assert.Nil(t, err)
+	assert.Equal(t, command, `code --goto '/notes/my note.md':42`)
+}
+
+func TestEditorCommandWithOptionalLine(t *testing.T) {
+	test := func(line int, expected string) {
+		t.Helper()
+		editor := newTemplateEditor(t, "vim {{#if line}}+{{line}} {{/if}}{{path}}", line)
+		command, err := editor.command([]string{"/notes/a.md"})
+		assert.Nil(t, err)
+		assert.Equal(t, command, expected)
+	}
+
+	test(0, "vim /notes/a.md")
+	test(12, "vim +12 /notes/a.md")
+}
+
+func TestEditorCommandWithoutPathVariableAppendsPaths(t *testing.T) {
+	editor := newTemplateEditor(t, "vim +{{line}}", 3)
+	command, err := editor.command([]string{"/notes/a.md", "/notes/b.md"})
+	assert.Nil(t, err)
+	assert.Equal(t, command, "vim +3 /notes/a.md /notes/b.md")
+}
+
+func TestEditorCommandWithInvalidTemplate(t *testing.T) {
+	editor := newTemplateEditor(t, "vim {{#if path}}", 0)
+	_, err := editor.command([]string{"/notes/a.md"})
+	assert.NotNil(t, err)
+}
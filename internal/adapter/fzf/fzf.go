@@ -33,6 +33,9 @@ type Opts struct {
 	Padding int
 	// Delimiter used by fzf between fields.
 	Delimiter string
+	// Field index expression of the fields displayed in the list, e.g. `1,3`.
+	// The other fields are hidden but available to the preview command.
+	WithNth opt.String
 	// List of key bindings enabled in fzf.
 	Bindings []Binding
 }
@@ -80,6 +83,9 @@ func New(opts Opts) (*Fzf, error) {
 		"--ansi",
 		"--delimiter", opts.Delimiter,
 	}
+	if !opts.WithNth.IsNull() {
+		args = append(args, "--with-nth", opts.WithNth.String())
+	}
 
 	// Additional options.
 	additionalArgs, err := shellquote.Split(opts.Options.String())
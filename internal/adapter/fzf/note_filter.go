@@ -103,11 +103,22 @@ func (f *NoteFilter) Apply(notes []core.ContextualNote) ([]core.ContextualNote,
 		}
 	}
 
-	previewCmd := f.opts.PreviewCmd.OrString("cat {-1}").Unwrap()
+	// Without a custom preview command, the preview is printed from the
+	// indexed content stored in a hidden field of each line.
+	indexedPreview := f.opts.PreviewCmd.IsNull()
+	previewCmd := f.opts.PreviewCmd.OrString(`printf '%b\n' {2}`)
+	withNth := opt.NullString
+	if indexedPreview {
+		withNth = opt.NewString("1,3")
+	}
+	if width := f.terminal.Width(); width > 0 && width < minPreviewWidth {
+		previewCmd = opt.NullString
+	}
 
 	fzf, err := New(Opts{
 		Options:    f.opts.FzfOptions.OrString(defaultOptions),
-		PreviewCmd: opt.NewNotEmptyString(previewCmd),
+		PreviewCmd: previewCmd,
+		WithNth:    withNth,
 		Padding:    2,
 		Bindings:   bindings,
 	})
@@ -145,7 +156,11 @@ func (f *NoteFilter) Apply(notes []core.ContextualNote) ([]core.ContextualNote,
 		// The absolute path is appended at the end of the line to be used in
 		// the preview command.
 		absPathField := f.terminal.MustStyle(context.AbsPath, core.StyleUnderstate)
-		fzf.Add([]string{line, absPathField})
+		if indexedPreview {
+			fzf.Add([]string{line, escapePreview(f.notePreview(note)), absPathField})
+		} else {
+			fzf.Add([]string{line, absPathField})
+		}
 	}
 
 	selection, err := fzf.Selection()
@@ -165,6 +180,42 @@ func (f *NoteFilter) Apply(notes []core.ContextualNote) ([]core.ContextualNote,
 	return selectedNotes, nil
 }
 
+// minPreviewWidth is the minimum number of columns of the terminal to show
+// the preview window next to the list of notes.
+const minPreviewWidth = 80
+
+// previewLineCount is the maximum number of lines of the note content shown
+// in the default preview.
+const previewLineCount = 100
+
+// notePreview renders the first lines of the indexed content of the given
+// note, for the preview window.
+func (f *NoteFilter) notePreview(note core.ContextualNote) string {
+	lines := []string{}
+	if note.Title != "" {
+		lines = append(lines, f.terminal.MustStyle(note.Title, core.StyleTitle), "")
+	}
+	content := note.Plain
+	if content == "" {
+		content = note.Body
+	}
+	for i, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		if i == previewLineCount {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// escapePreview escapes the line breaks of the given text to fit in a single
+// fzf field, which is printed back with printf '%b'.
+func escapePreview(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\\\")
+	text = strings.ReplaceAll(text, "\r", "")
+	return strings.ReplaceAll(text, "\n", "\\n")
+}
+
 var defaultLineTemplate = `{{style "title" title-or-path}} {{style "understate" body}} {{style "understate" (json metadata)}}`
 
 // defaultOptions are the default fzf options used when filtering notes.
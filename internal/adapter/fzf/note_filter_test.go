@@ -0,0 +1,33 @@
+package fzf
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/adapter/term"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestNotePreview(t *testing.T) {
+	filter := NewNoteFilter(NoteFilterOpts{}, nil, term.New(), nil)
+	test := func(note core.Note, expected string) {
+		assert.Equal(t, filter.notePreview(core.ContextualNote{Note: note}), expected)
+	}
+
+	test(core.Note{}, "")
+	test(core.Note{Title: "Title", Plain: "First line\nSecond line\n"}, "Title\n\nFirst line\nSecond line")
+	// Falls back on the body without plain text.
+	test(core.Note{Body: "*Body*"}, "*Body*")
+}
+
+func TestEscapePreview(t *testing.T) {
+	test := func(text string, expected string) {
+		assert.Equal(t, escapePreview(text), expected)
+	}
+
+	test("", "")
+	test("One line", "One line")
+	test("Two\nlines", `Two\nlines`)
+	test("Windows\r\nlines", `Windows\nlines`)
+	test(`C:\path\to\c`, `C:\\path\\to\\c`)
+}
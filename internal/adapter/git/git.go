@@ -0,0 +1,86 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// Author identifies the author of a git commit.
+type Author struct {
+	Name  string
+	Email string
+}
+
+// Matches returns whether the author has the given name or email, ignoring
+// the case.
+func (a Author) Matches(nameOrEmail string) bool {
+	nameOrEmail = strings.TrimSpace(nameOrEmail)
+	return nameOrEmail != "" &&
+		(strings.EqualFold(a.Name, nameOrEmail) || strings.EqualFold(a.Email, nameOrEmail))
+}
+
+// IsRepository returns whether the given directory is inside a git working
+// tree.
+func IsRepository(dir string) bool {
+	out, err := git(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// UserName returns the name of the git user configured for the repository
+// containing the given directory.
+func UserName(dir string) (string, error) {
+	out, err := git(dir, "config", "user.name")
+	name := strings.TrimSpace(out)
+	if err != nil || name == "" {
+		return "", errors.New("no git user.name configured")
+	}
+	return name, nil
+}
+
+// LastAuthors returns the author of the last commit modifying each file
+// found under the given directory, indexed by their path relative to it.
+func LastAuthors(dir string) (map[string]Author, error) {
+	out, err := git(dir,
+		"-c", "core.quotePath=false",
+		"log", "--no-renames", "--relative", "--name-only", "--format=%x01%an%x00%ae",
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the git history")
+	}
+	return parseLastAuthors(out), nil
+}
+
+// parseLastAuthors reads the output of `git log --name-only`, ordered from
+// the most recent commit.
+func parseLastAuthors(log string) map[string]Author {
+	authors := map[string]Author{}
+	var author Author
+
+	scanner := bufio.NewScanner(strings.NewReader(log))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\x01"):
+			name, email, _ := strings.Cut(line[1:], "\x00")
+			author = Author{Name: name, Email: email}
+		case line != "":
+			if _, ok := authors[line]; !ok {
+				authors[line] = author
+			}
+		}
+	}
+	return authors
+}
+
+// git runs a git command in the given directory and returns its output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	return out.String(), err
+}
@@ -0,0 +1,48 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestParseLastAuthors(t *testing.T) {
+	test := func(log string, expected map[string]Author) {
+		assert.Equal(t, parseLastAuthors(log), expected)
+	}
+
+	test("", map[string]Author{})
+
+	test("\x01Alice\x00alice@example.com\n"+
+		"a.md\n"+
+		"dir/b c.md\n"+
+		"\n"+
+		"\x01Bob\x00bob@example.com\n"+
+		"a.md\n"+
+		"d.md\n",
+		map[string]Author{
+			"a.md":       {Name: "Alice", Email: "alice@example.com"},
+			"dir/b c.md": {Name: "Alice", Email: "alice@example.com"},
+			"d.md":       {Name: "Bob", Email: "bob@example.com"},
+		},
+	)
+
+	// Commits without files, e.g. merge commits.
+	test("\x01Alice\x00alice@example.com\n"+
+		"\n"+
+		"\x01Bob\x00bob@example.com\n"+
+		"a.md\n",
+		map[string]Author{
+			"a.md": {Name: "Bob", Email: "bob@example.com"},
+		},
+	)
+}
+
+func TestAuthorMatches(t *testing.T) {
+	author := Author{Name: "Alice Doe", Email: "alice@example.com"}
+	assert.True(t, author.Matches("Alice Doe"))
+	assert.True(t, author.Matches("alice doe"))
+	assert.True(t, author.Matches(" alice@example.com "))
+	assert.False(t, author.Matches("Alice"))
+	assert.False(t, author.Matches(""))
+}
@@ -3,26 +3,41 @@ package handlebars
 import (
 	"fmt"
 	"html"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/aymerick/raymond"
 	"github.com/zk-org/zk/internal/adapter/handlebars/helpers"
 	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/date"
 	"github.com/zk-org/zk/internal/util/errors"
 	"github.com/zk-org/zk/internal/util/paths"
 )
 
-func Init(supportsUTF8 bool, logger util.Logger) {
+func Init(supportsUTF8 bool, now date.Provider, logger util.Logger) {
+	helpers.RegisterColorOf()
 	helpers.RegisterConcat()
+	helpers.RegisterContext()
+	helpers.RegisterCountOccurrences()
 	helpers.RegisterDate(logger)
-	helpers.RegisterFormatDate(logger)
+	helpers.RegisterDateBucket(logger)
+	helpers.RegisterDefault()
+	helpers.RegisterElapsed(now, logger)
+	helpers.RegisterFormatDate(now, logger)
+	helpers.RegisterFrontmatterYAML(logger)
 	helpers.RegisterJoin()
 	helpers.RegisterJSON(logger)
 	helpers.RegisterList(supportsUTF8)
 	helpers.RegisterPrepend(logger)
+	helpers.RegisterRelative()
 	helpers.RegisterShell(logger)
 	helpers.RegisterSubstring()
+	helpers.RegisterTOC()
+	helpers.RegisterWrap()
 }
 
 // Template renders a parsed handlebars template.
@@ -40,7 +55,20 @@ func (t *Template) Styler() core.Styler {
 func (t *Template) Render(context interface{}) (string, error) {
 	res, err := t.template.Exec(context)
 	if err != nil {
-		return "", errors.Wrap(err, "render template failed")
+		return "", core.ErrTemplate{Err: errors.Wrap(err, "render template failed")}
+	}
+	return html.UnescapeString(res), nil
+}
+
+// RenderWithData implements core.DataTemplate.
+func (t *Template) RenderWithData(context interface{}, data map[string]interface{}) (string, error) {
+	frame := raymond.NewDataFrame()
+	for key, value := range data {
+		frame.Set(key, value)
+	}
+	res, err := t.template.ExecWith(context, frame)
+	if err != nil {
+		return "", core.ErrTemplate{Err: errors.Wrap(err, "render template failed")}
 	}
 	return html.UnescapeString(res), nil
 }
@@ -52,23 +80,42 @@ type Loader struct {
 	lookupPaths []string
 	styler      core.Styler
 	helpers     map[string]interface{}
+	// Partials found in the lookup paths, indexed by name. Nil until loaded.
+	partials map[string]*partial
+	logger   util.Logger
+}
+
+// partial is a template file found in the lookup paths, which other
+// templates can include with {{> name}}.
+type partial struct {
+	path   string
+	source string
+	// Parsed template, nil until the partial is included.
+	template *raymond.Template
 }
 
 type LoaderOpts struct {
 	// LookupPaths is used to resolve relative template paths.
 	LookupPaths []string
 	Styler      core.Styler
+	// Logger reports how the template paths are resolved, when debugging.
+	Logger util.Logger
 }
 
 // NewLoader creates a new instance of Loader.
 //
 func NewLoader(opts LoaderOpts) *Loader {
+	logger := opts.Logger
+	if logger == nil {
+		logger = &util.NullLogger
+	}
 	return &Loader{
 		strings:     make(map[string]*Template),
 		files:       make(map[string]*Template),
 		lookupPaths: opts.LookupPaths,
 		styler:      opts.Styler,
 		helpers:     map[string]interface{}{},
+		logger:      logger,
 	}
 }
 
@@ -77,9 +124,24 @@ func (l *Loader) RegisterHelper(name string, helper interface{}) {
 	l.helpers[name] = helper
 }
 
+// RegisterLocalHelpers declares the helpers depending on the clock and the
+// logger with this loader only, overriding the global ones registered by
+// Init. This way, several notebooks opened in the same process each use their
+// own clock and logger.
+func (l *Loader) RegisterLocalHelpers(now date.Provider, logger util.Logger) {
+	l.RegisterHelper("date", helpers.NewDateHelper(logger))
+	l.RegisterHelper("date-bucket", helpers.NewDateBucketHelper(logger))
+	l.RegisterHelper("elapsed", helpers.NewElapsedHelper("en", now, logger))
+	l.RegisterHelper("format-date", helpers.NewFormatDateHelper("en", now, logger))
+	l.RegisterHelper("frontmatter-yaml", helpers.NewFrontmatterYAMLHelper(logger))
+	l.RegisterHelper("json", helpers.NewJSONHelper(logger))
+	l.RegisterHelper("prepend", helpers.NewPrependHelper(logger))
+	l.RegisterHelper("sh", helpers.NewShellHelper(logger))
+}
+
 // LoadTemplate implements core.TemplateLoader.
 func (l *Loader) LoadTemplate(content string) (core.Template, error) {
-	wrap := errors.Wrapperf("load template failed")
+	wrap := templateErrorWrapper("load template failed")
 
 	// Already loaded?
 	template, ok := l.strings[content]
@@ -92,6 +154,10 @@ func (l *Loader) LoadTemplate(content string) (core.Template, error) {
 	if err != nil {
 		return nil, wrap(err)
 	}
+	err = l.registerPartials(vendorTempl, content, fmt.Sprintf("the template `%s`", content))
+	if err != nil {
+		return nil, wrap(err)
+	}
 	template = l.newTemplate(vendorTempl)
 	l.strings[content] = template
 	return template, nil
@@ -99,7 +165,7 @@ func (l *Loader) LoadTemplate(content string) (core.Template, error) {
 
 // LoadTemplateAt implements core.TemplateLoader.
 func (l *Loader) LoadTemplateAt(path string) (core.Template, error) {
-	wrap := errors.Wrapper("load template file failed")
+	wrap := templateErrorWrapper("load template file failed")
 
 	path, ok := l.locateTemplate(path)
 	if !ok {
@@ -113,7 +179,15 @@ func (l *Loader) LoadTemplateAt(path string) (core.Template, error) {
 	}
 
 	// Load new template.
-	vendorTempl, err := raymond.ParseFile(path)
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	vendorTempl, err := raymond.Parse(string(source))
+	if err != nil {
+		return nil, wrap(err)
+	}
+	err = l.registerPartials(vendorTempl, string(source), path)
 	if err != nil {
 		return nil, wrap(err)
 	}
@@ -122,6 +196,14 @@ func (l *Loader) LoadTemplateAt(path string) (core.Template, error) {
 	return template, nil
 }
 
+// templateErrorWrapper returns a function wrapping errors with the given
+// message, as core.ErrTemplate.
+func templateErrorWrapper(msg string) func(error) error {
+	return func(err error) error {
+		return core.ErrTemplate{Err: errors.Wrap(err, msg)}
+	}
+}
+
 // locateTemplate returns the absolute path for the given template path, by
 // looking for it in the templates directories registered in this Config.
 func (l *Loader) locateTemplate(path string) (string, bool) {
@@ -140,13 +222,106 @@ func (l *Loader) locateTemplate(path string) (string, bool) {
 
 	for _, dir := range l.lookupPaths {
 		if candidate := filepath.Join(dir, path); exists(candidate) {
+			l.logger.Debugf("template %s resolved to %s", path, candidate)
 			return candidate, true
 		}
 	}
 
+	l.logger.Debugf("template %s not found in %s", path, strings.Join(l.lookupPaths, ", "))
 	return path, false
 }
 
+// partialRegex matches the names of the partials included in a template, e.g.
+// {{> header}} or {{~> partials/footer title=title}}.
+var partialRegex = regexp.MustCompile(`{{~?>\s*([^\s}~(]+)`)
+
+// registerPartials registers in the given template the partials it includes,
+// recursively. includer describes the template in the errors.
+func (l *Loader) registerPartials(template *raymond.Template, source string, includer string) error {
+	err := l.loadPartials()
+	if err != nil {
+		return err
+	}
+	return l.registerPartialsIncludedBy(template, source, includer, map[string]bool{})
+}
+
+func (l *Loader) registerPartialsIncludedBy(template *raymond.Template, source string, includer string, registered map[string]bool) error {
+	for _, match := range partialRegex.FindAllStringSubmatch(source, -1) {
+		name := match[1]
+		if registered[name] {
+			continue
+		}
+
+		partial, ok := l.partials[name]
+		if !ok {
+			return fmt.Errorf("%s: partial not found in the templates directories, included from %s", name, includer)
+		}
+		if partial.template == nil {
+			parsed, err := raymond.Parse(partial.source)
+			if err != nil {
+				return errors.Wrapf(err, "%s", partial.path)
+			}
+			partial.template = parsed
+		}
+
+		template.RegisterPartialTemplate(name, partial.template)
+		registered[name] = true
+
+		err := l.registerPartialsIncludedBy(template, partial.source, partial.path, registered)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadPartials reads the .hbs and .md files of the lookup paths, which are
+// available as partials named after their path relative to the lookup path,
+// without the extension, e.g. {{> header}} or {{> partials/footer}}. Like for
+// the templates, the first lookup path takes precedence.
+func (l *Loader) loadPartials() error {
+	if l.partials != nil {
+		return nil
+	}
+	l.partials = map[string]*partial{}
+
+	for _, dir := range l.lookupPaths {
+		err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			ext := filepath.Ext(path)
+			if entry.IsDir() || (ext != ".hbs" && ext != ".md") {
+				return nil
+			}
+
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			name := filepath.ToSlash(strings.TrimSuffix(rel, ext))
+			if _, ok := l.partials[name]; ok {
+				return nil
+			}
+
+			source, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			l.partials[name] = &partial{path: path, source: string(source)}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to load the template partials")
+		}
+	}
+
+	return nil
+}
+
 func (l *Loader) newTemplate(vendorTempl *raymond.Template) *Template {
 	vendorTempl.RegisterHelpers(l.helpers)
 	return &Template{vendorTempl, l.styler}
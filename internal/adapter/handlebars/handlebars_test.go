@@ -4,19 +4,22 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/zk-org/zk/internal/adapter/handlebars/helpers"
 	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/date"
 	"github.com/zk-org/zk/internal/util/fixtures"
 	"github.com/zk-org/zk/internal/util/paths"
 	"github.com/zk-org/zk/internal/util/test/assert"
 )
 
 func init() {
-	Init(true, &util.NullLogger)
+	now := date.NewFrozen(time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC))
+	Init(true, &now, &util.NullLogger)
 }
 
 // styler is a test double for core.Styler
@@ -95,6 +98,47 @@ func TestLookupPaths(t *testing.T) {
 	test("subdir/test3.tpl", "Test 3") // relative
 }
 
+func TestPartials(t *testing.T) {
+	global := t.TempDir()
+	notebook := t.TempDir()
+	write := func(dir string, path string, content string) {
+		path = filepath.Join(dir, path)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		assert.Nil(t, paths.WriteString(path, content))
+	}
+	write(global, "header.hbs", "# {{title}}\n")
+	write(notebook, "header.hbs", "Overridden header")
+	write(notebook, "partials/footer.md", "{{> signature}}")
+	write(notebook, "signature.hbs", "-- {{upper author}}")
+	write(notebook, "broken.hbs", "{{> missing}}")
+	write(notebook, "note.md", "{{> header}}\nBody\n{{> partials/footer}}")
+	write(notebook, "ignored.txt", "Not a partial")
+
+	sut := testLoader(LoaderOpts{LookupPaths: []string{global, notebook}})
+	sut.RegisterHelper("upper", strings.ToUpper)
+
+	context := map[string]string{"title": "Title", "author": "me"}
+
+	// Partials can be included in the template files and strings.
+	tpl, err := sut.LoadTemplateAt("note.md")
+	assert.Nil(t, err)
+	res, err := tpl.Render(context)
+	assert.Nil(t, err)
+	assert.Equal(t, res, "# Title\nBody\n-- ME")
+
+	tpl, err = sut.LoadTemplate("{{> header}}---")
+	assert.Nil(t, err)
+	res, err = tpl.Render(context)
+	assert.Nil(t, err)
+	assert.Equal(t, res, "# Title\n---")
+
+	// Missing partials are reported with the template including them.
+	_, err = sut.LoadTemplate("{{> ignored}}")
+	assert.Err(t, err, "ignored: partial not found in the templates directories, included from the template `{{> ignored}}`")
+	_, err = sut.LoadTemplate("{{> broken}}")
+	assert.Err(t, err, "missing: partial not found in the templates directories, included from "+filepath.Join(notebook, "broken.hbs"))
+}
+
 func TestRenderString(t *testing.T) {
 	testString(t,
 		"Goodbye, {{name}}",
@@ -103,6 +147,25 @@ func TestRenderString(t *testing.T) {
 	)
 }
 
+func TestRenderWithData(t *testing.T) {
+	templ, err := testLoader(LoaderOpts{}).LoadTemplate("{{@index}}: {{name}}{{#unless @last}},{{/unless}}")
+	assert.Nil(t, err)
+
+	actual, err := templ.(*Template).RenderWithData(
+		map[string]string{"name": "Ed"},
+		map[string]interface{}{"index": 2, "last": false},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, actual, "2: Ed,")
+
+	actual, err = templ.(*Template).RenderWithData(
+		map[string]string{"name": "Thom"},
+		map[string]interface{}{"index": 3, "last": true},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, actual, "3: Thom")
+}
+
 func TestRenderFile(t *testing.T) {
 	testFile(t,
 		"template.txt",
@@ -137,6 +200,112 @@ func TestConcatHelper(t *testing.T) {
 	testString(t, "{{concat '> ' 'A quote'}}", nil, "> A quote")
 }
 
+func TestContextHelpers(t *testing.T) {
+	context := map[string]interface{}{
+		"raw-content": "---\ntags: [review]\n---\n# Title\n\nFirst sentence. This needs a #review before publishing! Last one.\n\n* See [the article](ref/article) for details.\n* Or [[other-note]]\n",
+		"tag-starts":  map[string]int{"review": 61},
+		"link-starts": map[string]int{"ref/article": 106, "ref/article.md": 106, "other-note": 152, "dir/other-note.md": 152},
+	}
+	testString(t, "{{tag-context 'review'}}", context, "This needs a #review before publishing!")
+	testString(t, "{{tag-context '#review'}}", context, "This needs a #review before publishing!")
+	testString(t, "{{tag-context 'unknown'}}", context, "")
+	testString(t, "{{link-context 'ref/article.md'}}", context, "* See [the article](ref/article) for details.")
+	testString(t, "{{link-context './ref/article'}}", context, "* See [the article](ref/article) for details.")
+	testString(t, "{{link-context 'dir/other-note.md'}}", context, "* Or [[other-note]]")
+	testString(t, "{{link-context 'unknown.md'}}", context, "")
+
+	// Without the indexed offsets, there's no context.
+	testString(t, "{{tag-context 'review'}}", map[string]interface{}{"raw-content": "A #review"}, "")
+}
+
+func TestCountOccurrencesHelper(t *testing.T) {
+	context := map[string]interface{}{
+		"plain": "Rust is great. I like rust, but not Rustaceans.",
+	}
+	testString(t, "{{count-occurrences 'rust'}}", context, "2")
+	testString(t, "{{count-occurrences 'go'}}", context, "0")
+	testString(t, "{{#if (count-occurrences 'rustaceans')}}yes{{/if}}", context, "yes")
+}
+
+func TestDefaultHelper(t *testing.T) {
+	context := map[string]interface{}{
+		"title":    "A title",
+		"blank":    "  ",
+		"filename": "205x.md",
+		"count":    0,
+		"empty":    []string{},
+		"tags":     []string{"a", "b"},
+		"metadata": map[string]interface{}{},
+	}
+	testString(t, "{{default title filename}}", context, "A title")
+	testString(t, "{{default 'A title' filename}}", context, "A title")
+	testString(t, "{{default missing filename}}", context, "205x.md")
+	testString(t, "{{default blank filename}}", context, "205x.md")
+	testString(t, "{{default '' 'Untitled'}}", context, "Untitled")
+	testString(t, "{{default count 'none'}}", context, "0")
+	testString(t, "{{default empty 'none'}}", context, "none")
+	testString(t, "{{join (default tags empty) ', '}}", context, "a, b")
+	testString(t, "{{default metadata.name (default blank 'Untitled')}}", context, "Untitled")
+}
+
+func TestTOCHelper(t *testing.T) {
+	context := map[string]interface{}{
+		"path": "dir/note.md",
+		"body": `Introduction paragraph.
+
+## Getting started
+
+### Install *zk*
+
+` + "```" + `
+# Not a heading
+` + "```" + `
+
+### Configure
+
+#### Aliases
+
+## FAQ & tips
+
+## Getting started
+
+Setext heading
+--------------
+`,
+	}
+
+	testString(t, "{{toc}}", context, `- [Getting started](#getting-started)
+  - [Install zk](#install-zk)
+  - [Configure](#configure)
+    - [Aliases](#aliases)
+- [FAQ & tips](#faq--tips)
+- [Getting started](#getting-started-1)
+- [Setext heading](#setext-heading)
+`)
+	testString(t, "{{toc max-depth=1 href=path}}", context, `- [Getting started](dir/note.md#getting-started)
+- [FAQ & tips](dir/note.md#faq--tips)
+- [Getting started](dir/note.md#getting-started-1)
+- [Setext heading](dir/note.md#setext-heading)
+`)
+
+	// Skipped levels are nested consistently.
+	testString(t, "{{toc}}", map[string]interface{}{"body": "### Deep\n\n# [Top]\n\n### Child"}, `- [Deep](#deep)
+- [\[Top\]](#top)
+  - [Child](#child)
+`)
+	testString(t, "{{toc}}", map[string]interface{}{"body": "No headings"}, "")
+}
+
+func TestRelativeHelper(t *testing.T) {
+	testString(t, "{{relative 'journal/2009-11-17.md' 'reports'}}", nil, "../journal/2009-11-17.md")
+	testString(t, "{{relative 'reports/weekly.md' 'reports'}}", nil, "weekly.md")
+	testString(t, "{{relative 'a/b/c.md' 'a/d/e'}}", nil, "../../b/c.md")
+	testString(t, "{{relative 'note.md' '.'}}", nil, "note.md")
+	testString(t, "{{relative '/notebook/dir/note.md' '/notebook'}}", nil, "dir/note.md")
+	// Can't be made relative, the path is returned unchanged.
+	testString(t, "{{relative '/notebook/note.md' 'reports'}}", nil, "/notebook/note.md")
+}
+
 func TestSubstringHelper(t *testing.T) {
 	testString(t, "{{substring '' 2 4}}", nil, "")
 	testString(t, "{{substring 'A full quote' 2 4}}", nil, "full")
@@ -145,6 +314,11 @@ func TestSubstringHelper(t *testing.T) {
 	testString(t, "{{substring 'A full quote' -5 6}}", nil, "quote")
 }
 
+func TestWrapHelper(t *testing.T) {
+	testString(t, "{{wrap 'A line which is too long' 10}}", nil, "A line\nwhich is\ntoo long")
+	testString(t, "{{wrap 'A short line' 80}}", nil, "A short line")
+}
+
 func TestJoinHelper(t *testing.T) {
 	test := func(items []string, expected string) {
 		context := map[string]interface{}{"items": items}
@@ -179,6 +353,40 @@ func TestJSONHelper(t *testing.T) {
 	}, `{"Foo":"baz","stringList":["foo","bar"]}`)
 }
 
+func TestFrontmatterYAMLHelper(t *testing.T) {
+	test := func(metadata map[string]interface{}, expected string) {
+		context := map[string]interface{}{"metadata": metadata}
+		testString(t, "{{frontmatter-yaml}}", context, expected)
+	}
+
+	test(nil, "")
+	test(map[string]interface{}{}, "")
+	test(map[string]interface{}{
+		"title":   "A note",
+		"date":    "2021-01-02",
+		"version": "12",
+		"draft":   false,
+		"count":   float64(1000000),
+		"ratio":   1.5,
+		"aliases": []interface{}{"Zettel", "yes"},
+		"nested":  map[string]interface{}{"b": float64(1), "a": "multi\nline"},
+	}, `aliases:
+- Zettel
+- "yes"
+count: 1000000
+date: "2021-01-02"
+draft: false
+nested:
+  a: |-
+    multi
+    line
+  b: 1
+ratio: 1.5
+title: A note
+version: "12"
+`)
+}
+
 func TestPrependHelper(t *testing.T) {
 	// inline
 	testString(t, "{{prepend '> ' 'A quote'}}", nil, "> A quote")
@@ -224,6 +432,42 @@ func TestSlugHelper(t *testing.T) {
 		nil,
 		"this-will-be-slugified",
 	)
+	// unicode
+	testString(t,
+		`{{slug "Crème Brûlée -- à la carte"}}`,
+		nil,
+		"creme-brulee-a-la-carte",
+	)
+	// custom separator and case
+	testString(t,
+		`{{#slug sep="_" case="preserve"}}Crème Brûlée{{/slug}}`,
+		nil,
+		"Creme_Brulee",
+	)
+	testString(t,
+		`{{#slug case="upper"}}{{title}}{{/slug}}`,
+		map[string]string{"title": "An interesting note"},
+		"AN-INTERESTING-NOTE",
+	)
+}
+
+func TestTagLinksHelper(t *testing.T) {
+	context := map[string]interface{}{"tags": []string{"Book club", "rust", "programming/go"}}
+	testString(t,
+		`{{tag-links "tags/%s.md"}}`,
+		context,
+		"[Book club](tags/book-club.md), [rust](tags/rust.md), [programming/go](tags/programming-go.md)",
+	)
+	testString(t,
+		`{{tag-links "/tags/%s/" separator=" "}}`,
+		context,
+		"[Book club](/tags/book-club/) [rust](/tags/rust/) [programming/go](/tags/programming-go/)",
+	)
+	// No tags
+	testString(t, `{{tag-links "tags/%s.md"}}`, map[string]interface{}{"tags": []string{}}, "")
+	testString(t, `{{tag-links "tags/%s.md"}}`, nil, "")
+	// Missing placeholder
+	testString(t, `{{tag-links "tags.md"}}`, context, "")
 }
 
 func TestFormatDateHelper(t *testing.T) {
@@ -238,6 +482,72 @@ func TestFormatDateHelper(t *testing.T) {
 	testString(t, "{{format-date now 'timestamp'}}", context, "200911172034")
 	testString(t, "{{format-date now 'timestamp-unix'}}", context, "1258490098")
 	testString(t, "{{format-date now 'cust: %Y-%m'}}", context, "cust: 2009-11")
+	testString(t, "{{format-date now 'iso'}}", context, "2009-11-17T20:34:58Z")
+	testString(t, "{{format-date now 'Mon, Jan 2 2006 at 15:04'}}", context, "Tue, Nov 17 2009 at 20:34")
+	testString(t, "{{format-date now 'Monday 02 January'}}", context, "Tuesday 17 November")
+	// Uses the date of the provider when none is given.
+	testString(t, "{{format-date missing 'timestamp'}}", context, "200911172034")
+	testString(t, "{{format-date 'a string'}}", context, "")
+}
+
+func TestDateBucketHelper(t *testing.T) {
+	local := time.Local
+	time.Local = time.UTC
+	defer func() { time.Local = local }()
+
+	test := func(date time.Time, period string, expected string) {
+		t.Helper()
+		context := map[string]interface{}{"date": date}
+		testString(t, "{{date-bucket date '"+period+"'}}", context, expected)
+	}
+
+	date := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+	test(date, "day", "2009-11-17")
+	test(date, "week", "2009-W47")
+	test(date, "month", "2009-11")
+	test(date, "quarter", "2009-Q4")
+	test(date, "year", "2009")
+	test(date, "unknown", "")
+
+	// The first days of January may belong to the last ISO week of the
+	// previous year.
+	test(time.Date(2021, 1, 3, 12, 0, 0, 0, time.UTC), "week", "2020-W53")
+	test(time.Date(2021, 1, 4, 12, 0, 0, 0, time.UTC), "week", "2021-W01")
+
+	// The boundaries follow the local timezone.
+	time.Local = time.FixedZone("UTC+2", 2*60*60)
+	test(time.Date(2009, 12, 31, 23, 0, 0, 0, time.UTC), "day", "2010-01-01")
+	test(time.Date(2009, 12, 31, 23, 0, 0, 0, time.UTC), "year", "2010")
+}
+
+func TestFormatDateHelperLocalized(t *testing.T) {
+	context := map[string]interface{}{"now": time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)}
+
+	test := func(lang string, template string, expected string) {
+		t.Helper()
+		loader := testLoader(LoaderOpts{})
+		loader.RegisterHelper("format-date", helpers.NewFormatDateHelper(lang, &date.Now{}, &util.NullLogger))
+		templ, err := loader.LoadTemplate(template)
+		assert.Nil(t, err)
+		actual, err := templ.Render(context)
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	test("fr", "{{format-date now '%A %d %B %Y'}}", "mardi 17 novembre 2009")
+	test("fr", "{{format-date now '%a %d %b'}}", "mar. 17 nov.")
+	test("fr", "{{format-date now 'short'}}", "17/11/2009")
+	test("fr", "{{format-date now 'medium'}}", "17 nov. 2009")
+	test("fr", "{{format-date now 'long'}}", "17 novembre 2009")
+	test("fr", "{{format-date now 'full'}}", "mardi 17 novembre 2009")
+	test("fr", "{{format-date now 'timestamp'}}", "200911172034")
+	test("fr-CA", "{{format-date now 'long'}}", "17 novembre 2009")
+	test("de", "{{format-date now 'full'}}", "Dienstag, 17. November 2009")
+	test("de", "{{format-date now 'Mon 2. January'}}", "Di. 17. November")
+	test("fr", "{{format-date now 'Monday 2 Jan'}}", "mardi 17 nov.")
+	// Fallbacks on English for unsupported languages.
+	test("xx", "{{format-date now 'full'}}", "Tuesday, November 17, 2009")
+	test("", "{{format-date now 'medium'}}", "Nov 17, 2009")
 }
 
 func TestFormatDateHelperElapsedYear(t *testing.T) {
@@ -348,6 +658,62 @@ func TestFormatDateHelperElapsedViaTimeMultiplication(t *testing.T) {
 	}
 }
 
+func TestElapsedHelper(t *testing.T) {
+	// Relative to the frozen now date of the tests.
+	now := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+	test := func(elapsed time.Duration, expected string) {
+		t.Helper()
+		testString(t, "{{elapsed date}}", map[string]interface{}{"date": now.Add(-elapsed)}, expected)
+	}
+
+	day := 24 * time.Hour
+	test(-time.Hour, "just now")
+	test(0, "just now")
+	test(59*time.Second, "just now")
+	test(time.Minute, "1 minute ago")
+	test(59*time.Minute, "59 minutes ago")
+	test(time.Hour, "1 hour ago")
+	test(23*time.Hour, "23 hours ago")
+	test(day, "1 day ago")
+	test(3*day, "3 days ago")
+	test(7*day, "1 week ago")
+	test(29*day, "4 weeks ago")
+	test(30*day, "1 month ago")
+	test(364*day, "12 months ago")
+	// Older dates are printed as is.
+	test(365*day, "Nov 17, 2008")
+
+	testString(t, "{{elapsed 'not a date'}}", nil, "")
+}
+
+func TestElapsedHelperLocalized(t *testing.T) {
+	now := date.NewFrozen(time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC))
+
+	test := func(lang string, elapsed time.Duration, expected string) {
+		t.Helper()
+		loader := testLoader(LoaderOpts{})
+		loader.RegisterHelper("elapsed", helpers.NewElapsedHelper(lang, &now, &util.NullLogger))
+		templ, err := loader.LoadTemplate("{{elapsed date}}")
+		assert.Nil(t, err)
+		actual, err := templ.Render(map[string]interface{}{"date": now.Date().Add(-elapsed)})
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	day := 24 * time.Hour
+	test("fr", 0, "à l'instant")
+	test("fr", 3*day, "il y a 3 jours")
+	test("fr-CA", time.Hour, "il y a 1 heure")
+	test("fr", 400*day, "13 oct. 2008")
+	test("de", day, "vor 1 Tag")
+	test("de", 60*day, "vor 2 Monaten")
+	test("es", 2*time.Minute, "hace 2 minutos")
+	test("it", 14*day, "2 settimane fa")
+	test("pt", 90*day, "há 3 meses")
+	// Fallbacks on English for unsupported languages.
+	test("xx", 2*time.Hour, "2 hours ago")
+}
+
 func TestDateHelper(t *testing.T) {
 	context := map[string]interface{}{"now": time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)}
 	testString(t, "{{format-date (date \"2009-11-17T20:34:58\") 'timestamp'}}", context, "200911172034")
@@ -371,6 +737,15 @@ func TestShellHelper(t *testing.T) {
 	testString(t, `{{sh "echo hello | tr '[:lower:]' '[:upper:]'"}}`, nil, "HELLO")
 }
 
+func TestColorOfHelper(t *testing.T) {
+	testString(t, "{{color-of 'programming'}}", nil, "bright-magenta")
+	testString(t, "{{color-of 'http'}}", nil, "green")
+	testString(t, "{{color-of ''}}", nil, "green")
+
+	// Usable with the style helper.
+	testString(t, "{{style (color-of 'draft') 'draft'}}", nil, "bright-yellow(draft)")
+}
+
 func TestStyleHelper(t *testing.T) {
 	// inline
 	testString(t, "{{style 'single' 'Some text'}}", nil, "single(Some text)")
@@ -392,6 +767,7 @@ func testLoader(opts LoaderOpts) *Loader {
 
 	loader.RegisterHelper("style", helpers.NewStyleHelper(opts.Styler, &util.NullLogger))
 	loader.RegisterHelper("slug", helpers.NewSlugHelper("en", &util.NullLogger))
+	loader.RegisterHelper("tag-links", helpers.NewTagLinksHelper("en", &util.NullLogger))
 
 	formatter := func(context core.LinkFormatterContext) (string, error) {
 		return context.Path + " - " + context.Title, nil
@@ -0,0 +1,44 @@
+package helpers
+
+import (
+	"hash/fnv"
+
+	"github.com/aymerick/raymond"
+	"github.com/zk-org/zk/internal/core"
+)
+
+// colorOfPalette holds the colors picked by {{color-of}}. Black, white and
+// their bright variants are left out, as they are unreadable on some terminal
+// backgrounds.
+var colorOfPalette = []core.Style{
+	core.StyleRed,
+	core.StyleGreen,
+	core.StyleYellow,
+	core.StyleBlue,
+	core.StyleMagenta,
+	core.StyleCyan,
+	core.StyleBrightRed,
+	core.StyleBrightGreen,
+	core.StyleBrightYellow,
+	core.StyleBrightBlue,
+	core.StyleBrightMagenta,
+	core.StyleBrightCyan,
+}
+
+// RegisterColorOf registers the {{color-of}} template helper, which picks an
+// ANSI color for the given string from a hash of it. The same string always
+// gets the same color, to be used with the {{style}} helper.
+//
+// {{color-of "programming"}} -> "bright-magenta"
+// {{style (color-of tag) tag}}
+func RegisterColorOf() {
+	raymond.RegisterHelper("color-of", func(text string) string {
+		return string(colorOf(text))
+	})
+}
+
+func colorOf(text string) core.Style {
+	hash := fnv.New32a()
+	hash.Write([]byte(text))
+	return colorOfPalette[hash.Sum32()%uint32(len(colorOfPalette))]
+}
@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/aymerick/raymond"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// RegisterContext registers the {{tag-context}} and {{link-context}} template
+// helpers, which extract the sentence containing the first occurrence of a tag
+// or a link in the current note.
+//
+// The offsets of the tags and links are the ones recorded when indexing the
+// note, exposed in the template context as tag-starts and link-starts.
+//
+// {{tag-context "review"}} -> "This paragraph needs a #review before publishing."
+// {{link-context "ref/article.md"}} -> "See [the article](ref/article) for more details."
+func RegisterContext() {
+	raymond.RegisterHelper("tag-context", func(tag string, options *raymond.Options) string {
+		return sentenceAt(options, "tag-starts", strings.TrimPrefix(tag, "#"))
+	})
+
+	raymond.RegisterHelper("link-context", func(path string, options *raymond.Options) string {
+		return sentenceAt(options, "link-starts", strings.TrimPrefix(path, "./"))
+	})
+}
+
+// sentenceAt returns the sentence of the raw note content found at the offset
+// of key in the startsField map of the template context.
+func sentenceAt(options *raymond.Options, startsField string, key string) string {
+	starts, ok := options.Value(startsField).(map[string]int)
+	if !ok {
+		return ""
+	}
+	start, ok := starts[key]
+	if !ok {
+		return ""
+	}
+	return strutil.SentenceAt(options.ValueStr("raw-content"), start)
+}
@@ -2,21 +2,28 @@ package helpers
 
 import (
 	"os"
+	"strings"
 	"time"
 
 	"github.com/aymerick/raymond"
 	"github.com/lestrrat-go/strftime"
-	"github.com/zk-org/zk/internal/util"
-	dateutil "github.com/zk-org/zk/internal/util/date"
 	"github.com/pkg/errors"
 	"github.com/rvflash/elapsed"
+	"github.com/zk-org/zk/internal/util"
+	dateutil "github.com/zk-org/zk/internal/util/date"
 )
 
 // RegisterDate registers the {{date}} template helper to use the `naturaldate` package to generate time.Time based on language strings.
 // This can be used in combination with the `format-date` helper to generate dates in the user's language.
 // {{format-date (date "last week") "timestamp"}}
 func RegisterDate(logger util.Logger) {
-	raymond.RegisterHelper("date", func(arg1 interface{}, arg2 interface{}) time.Time {
+	raymond.RegisterHelper("date", NewDateHelper(logger))
+}
+
+// NewDateHelper creates a new {{date}} template helper reporting its
+// errors to the given logger.
+func NewDateHelper(logger util.Logger) interface{} {
+	return func(arg1 interface{}, arg2 interface{}) time.Time {
 		var t time.Time
 		switch date := arg1.(type) {
 		case string:
@@ -33,60 +40,80 @@ func RegisterDate(logger util.Logger) {
 			logger.Println("the {{date}} template helper expects a natural human date as a string for its only argument")
 			return t
 		}
-	})
+	}
 }
 
-// RegisterFormatDate registers the {{format-date}} template helpers which format a given date.
+// RegisterFormatDate registers the {{format-date}} template helpers which
+// format a given date, in English.
+func RegisterFormatDate(now dateutil.Provider, logger util.Logger) {
+	raymond.RegisterHelper("format-date", NewFormatDateHelper("en", now, logger))
+}
+
+// NewFormatDateHelper creates a new template helper to format a given date
+// using the month and day names of the given language. English is used when
+// the language is not supported. Without a date, the one of the now provider
+// is used.
 //
-// It supports various styles: short, medium, long, full, year, time,
-// timestamp, timestamp-unix or a custom strftime format.
+// It supports various styles: short, medium, long, full, iso, year, time,
+// timestamp, timestamp-unix, a custom strftime format or a Go reference
+// layout.
 //
 // {{format-date now}} -> 2009-11-17
 // {{format-date now "medium"}} -> Nov 17, 2009
 // {{format-date now "%Y-%m"}} -> 2009-11
-func RegisterFormatDate(logger util.Logger) {
-	raymond.RegisterHelper("format-date", func(date time.Time, arg interface{}) string {
-		format := "%Y-%m-%d"
+// {{format-date now "Jan 2, 2006"}} -> Nov 17, 2009
+func NewFormatDateHelper(lang string, now dateutil.Provider, logger util.Logger) interface{} {
+	locale := findDateLocale(lang)
 
-		if arg, ok := arg.(string); ok {
-			format = findFormat(arg)
+	return func(arg1 interface{}, arg2 interface{}) string {
+		var date time.Time
+		switch arg := arg1.(type) {
+		case time.Time:
+			date = arg
+		case nil:
+			date = now.Date()
+		default:
+			logger.Printf("the {{format-date}} template helper expects a date as first argument, received: %v", arg1)
+			return ""
+		}
+
+		format := "%Y-%m-%d"
+		if arg, ok := arg2.(string); ok {
+			format = locale.findFormat(arg)
 		}
 
 		if format == "elapsed" {
 			return elapsed.Time(date)
-
-		} else {
-			res, err := strftime.Format(format, date, strftime.WithUnixSeconds('s'))
-			if err != nil {
-				logger.Printf("the {{format-date}} template helper failed to format the date: %v", err)
-				return ""
-			}
-			return res
 		}
-	})
+		res, err := locale.format(date, format)
+		if err != nil {
+			logger.Printf("the {{format-date}} template helper failed to format the date: %v", err)
+			return ""
+		}
+		return res
+	}
 }
 
 var (
-	shortFormat         = `%m/%d/%Y`
-	mediumFormat        = `%b %d, %Y`
-	longFormat          = `%B %d, %Y`
-	fullFormat          = `%A, %B %d, %Y`
+	isoFormat           = time.RFC3339
 	yearFormat          = `%Y`
 	timeFormat          = `%H:%M`
 	timestampFormat     = `%Y%m%d%H%M`
 	timestampUnixFormat = `%s`
 )
 
-func findFormat(key string) string {
+func (l dateLocale) findFormat(key string) string {
 	switch key {
 	case "short":
-		return shortFormat
+		return l.shortFormat
 	case "medium":
-		return mediumFormat
+		return l.mediumFormat
 	case "long":
-		return longFormat
+		return l.longFormat
 	case "full":
-		return fullFormat
+		return l.fullFormat
+	case "iso":
+		return isoFormat
 	case "year":
 		return yearFormat
 	case "time":
@@ -99,3 +126,198 @@ func findFormat(key string) string {
 		return key
 	}
 }
+
+// dateLocale holds the names and formats used to print dates in a given
+// language.
+type dateLocale struct {
+	months      []string
+	shortMonths []string
+	days        []string
+	shortDays   []string
+
+	shortFormat  string
+	mediumFormat string
+	longFormat   string
+	fullFormat   string
+
+	// Wording of the durations printed by {{elapsed}}.
+	justNow string
+	// ago is a format string taking the amount and unit, e.g. "%d %s ago".
+	ago   string
+	units map[elapsedUnit][2]string
+}
+
+// format formats the date with a strftime format, or a Go reference layout
+// when there's no `%` placeholder, using the names of the locale.
+func (l dateLocale) format(date time.Time, format string) (string, error) {
+	if !strings.Contains(format, "%") {
+		return l.formatLayout(date, format), nil
+	}
+	return strftime.Format(format, date,
+		strftime.WithUnixSeconds('s'),
+		strftime.WithSpecification('a', l.appender(l.shortDays, weekdayIndex)),
+		strftime.WithSpecification('A', l.appender(l.days, weekdayIndex)),
+		strftime.WithSpecification('b', l.appender(l.shortMonths, monthIndex)),
+		strftime.WithSpecification('h', l.appender(l.shortMonths, monthIndex)),
+		strftime.WithSpecification('B', l.appender(l.months, monthIndex)),
+	)
+}
+
+func weekdayIndex(t time.Time) int { return int(t.Weekday()) }
+func monthIndex(t time.Time) int   { return int(t.Month()) - 1 }
+
+// appender creates a strftime appender printing the name at the index
+// returned by the given function.
+func (l dateLocale) appender(names []string, index func(time.Time) int) strftime.Appender {
+	return strftime.AppendFunc(func(b []byte, t time.Time) []byte {
+		return append(b, names[index(t)]...)
+	})
+}
+
+// formatLayout formats the date with a Go reference layout, e.g.
+// `Mon Jan 2 2006`, using the month and day names of the locale.
+func (l dateLocale) formatLayout(date time.Time, layout string) string {
+	// The names are replaced with placeholders which are not Go layout
+	// elements, as a localized name might contain one, e.g. "Montag".
+	layout = strings.NewReplacer(
+		"January", "\x00B", "Jan", "\x00b",
+		"Monday", "\x00A", "Mon", "\x00a",
+	).Replace(layout)
+
+	return strings.NewReplacer(
+		"\x00B", l.months[monthIndex(date)],
+		"\x00b", l.shortMonths[monthIndex(date)],
+		"\x00A", l.days[weekdayIndex(date)],
+		"\x00a", l.shortDays[weekdayIndex(date)],
+	).Replace(date.Format(layout))
+}
+
+// findDateLocale returns the locale of the given two-letters language code,
+// e.g. `fr` or `fr-CA`. Fallbacks on English.
+func findDateLocale(lang string) dateLocale {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if locale, ok := dateLocales[lang]; ok {
+		return locale
+	}
+	return dateLocales["en"]
+}
+
+var dateLocales = map[string]dateLocale{
+	"en": {
+		months:       []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths:  []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		days:         []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortDays:    []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		shortFormat:  `%m/%d/%Y`,
+		mediumFormat: `%b %d, %Y`,
+		longFormat:   `%B %d, %Y`,
+		fullFormat:   `%A, %B %d, %Y`,
+		justNow:      "just now",
+		ago:          "%d %s ago",
+		units: map[elapsedUnit][2]string{
+			elapsedMinute: {"minute", "minutes"},
+			elapsedHour:   {"hour", "hours"},
+			elapsedDay:    {"day", "days"},
+			elapsedWeek:   {"week", "weeks"},
+			elapsedMonth:  {"month", "months"},
+		},
+	},
+	"fr": {
+		months:       []string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths:  []string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		days:         []string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortDays:    []string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		shortFormat:  `%d/%m/%Y`,
+		mediumFormat: `%d %b %Y`,
+		longFormat:   `%d %B %Y`,
+		fullFormat:   `%A %d %B %Y`,
+		justNow:      "à l'instant",
+		ago:          "il y a %d %s",
+		units: map[elapsedUnit][2]string{
+			elapsedMinute: {"minute", "minutes"},
+			elapsedHour:   {"heure", "heures"},
+			elapsedDay:    {"jour", "jours"},
+			elapsedWeek:   {"semaine", "semaines"},
+			elapsedMonth:  {"mois", "mois"},
+		},
+	},
+	"de": {
+		months:       []string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths:  []string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		days:         []string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortDays:    []string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		shortFormat:  `%d.%m.%Y`,
+		mediumFormat: `%d. %b %Y`,
+		longFormat:   `%d. %B %Y`,
+		fullFormat:   `%A, %d. %B %Y`,
+		justNow:      "gerade eben",
+		ago:          "vor %d %s",
+		units: map[elapsedUnit][2]string{
+			elapsedMinute: {"Minute", "Minuten"},
+			elapsedHour:   {"Stunde", "Stunden"},
+			elapsedDay:    {"Tag", "Tagen"},
+			elapsedWeek:   {"Woche", "Wochen"},
+			elapsedMonth:  {"Monat", "Monaten"},
+		},
+	},
+	"es": {
+		months:       []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths:  []string{"ene.", "feb.", "mar.", "abr.", "may.", "jun.", "jul.", "ago.", "sept.", "oct.", "nov.", "dic."},
+		days:         []string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortDays:    []string{"dom.", "lun.", "mar.", "mié.", "jue.", "vie.", "sáb."},
+		shortFormat:  `%d/%m/%Y`,
+		mediumFormat: `%d %b %Y`,
+		longFormat:   `%d de %B de %Y`,
+		fullFormat:   `%A, %d de %B de %Y`,
+		justNow:      "ahora mismo",
+		ago:          "hace %d %s",
+		units: map[elapsedUnit][2]string{
+			elapsedMinute: {"minuto", "minutos"},
+			elapsedHour:   {"hora", "horas"},
+			elapsedDay:    {"día", "días"},
+			elapsedWeek:   {"semana", "semanas"},
+			elapsedMonth:  {"mes", "meses"},
+		},
+	},
+	"it": {
+		months:       []string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		shortMonths:  []string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		days:         []string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		shortDays:    []string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		shortFormat:  `%d/%m/%Y`,
+		mediumFormat: `%d %b %Y`,
+		longFormat:   `%d %B %Y`,
+		fullFormat:   `%A %d %B %Y`,
+		justNow:      "proprio ora",
+		ago:          "%d %s fa",
+		units: map[elapsedUnit][2]string{
+			elapsedMinute: {"minuto", "minuti"},
+			elapsedHour:   {"ora", "ore"},
+			elapsedDay:    {"giorno", "giorni"},
+			elapsedWeek:   {"settimana", "settimane"},
+			elapsedMonth:  {"mese", "mesi"},
+		},
+	},
+	"pt": {
+		months:       []string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		shortMonths:  []string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+		days:         []string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		shortDays:    []string{"dom.", "seg.", "ter.", "qua.", "qui.", "sex.", "sáb."},
+		shortFormat:  `%d/%m/%Y`,
+		mediumFormat: `%d de %b de %Y`,
+		longFormat:   `%d de %B de %Y`,
+		fullFormat:   `%A, %d de %B de %Y`,
+		justNow:      "agora mesmo",
+		ago:          "há %d %s",
+		units: map[elapsedUnit][2]string{
+			elapsedMinute: {"minuto", "minutos"},
+			elapsedHour:   {"hora", "horas"},
+			elapsedDay:    {"dia", "dias"},
+			elapsedWeek:   {"semana", "semanas"},
+			elapsedMonth:  {"mês", "meses"},
+		},
+	},
+}
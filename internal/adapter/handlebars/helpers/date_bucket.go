@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aymerick/raymond"
+	"github.com/zk-org/zk/internal/util"
+)
+
+// RegisterDateBucket registers the {{date-bucket}} template helper, which
+// returns a key identifying the period containing the given date. Notes
+// sharing the same key can be grouped together, e.g. for activity reports.
+//
+// The period boundaries follow the local timezone, set with the TZ
+// environment variable.
+//
+// {{date-bucket created "day"}} -> 2009-11-17
+// {{date-bucket created "week"}} -> 2009-W47
+// {{date-bucket created "month"}} -> 2009-11
+// {{date-bucket created "quarter"}} -> 2009-Q4
+// {{date-bucket created "year"}} -> 2009
+func RegisterDateBucket(logger util.Logger) {
+	raymond.RegisterHelper("date-bucket", NewDateBucketHelper(logger))
+}
+
+// NewDateBucketHelper creates a new {{date-bucket}} template helper reporting its
+// errors to the given logger.
+func NewDateBucketHelper(logger util.Logger) interface{} {
+	return func(date interface{}, period string) string {
+		t, ok := date.(time.Time)
+		if !ok {
+			logger.Printf("the {{date-bucket}} template helper expects a date as first argument, received: %v", date)
+			return ""
+		}
+		bucket, err := dateBucket(t.In(time.Local), period)
+		if err != nil {
+			logger.Err(err)
+			return ""
+		}
+		return bucket
+	}
+}
+
+func dateBucket(t time.Time, period string) (string, error) {
+	switch period {
+	case "day":
+		return t.Format("2006-01-02"), nil
+	case "week":
+		// ISO weeks start on Monday, and the first days of January may
+		// belong to the last week of the previous year.
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week), nil
+	case "month":
+		return t.Format("2006-01"), nil
+	case "quarter":
+		return fmt.Sprintf("%04d-Q%d", t.Year(), (int(t.Month())-1)/3+1), nil
+	case "year":
+		return t.Format("2006"), nil
+	default:
+		return "", fmt.Errorf("%s: unknown period for the {{date-bucket}} template helper, expected day, week, month, quarter or year", period)
+	}
+}
@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/aymerick/raymond"
+)
+
+// RegisterDefault registers a {{default}} template helper which prints a
+// fallback value when the given one is empty, e.g. a missing variable, a
+// blank string or an empty list.
+//
+// {{default title filename}} -> "205x.md" if the note has no title
+// {{default title (default metadata.name "Untitled")}} -> "Untitled"
+func RegisterDefault() {
+	raymond.RegisterHelper("default", func(value interface{}, fallback interface{}) interface{} {
+		if isEmptyValue(value) {
+			return fallback
+		}
+		return value
+	})
+}
+
+// isEmptyValue returns whether the given template value is unset or empty.
+// Unlike the {{#if}} helper, zero and false are not considered empty.
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(val.String()) == ""
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return val.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return val.IsNil()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aymerick/raymond"
+	"github.com/zk-org/zk/internal/util"
+	dateutil "github.com/zk-org/zk/internal/util/date"
+)
+
+type elapsedUnit int
+
+const (
+	elapsedMinute elapsedUnit = iota
+	elapsedHour
+	elapsedDay
+	elapsedWeek
+	elapsedMonth
+)
+
+// RegisterElapsed registers the {{elapsed}} template helper, in English.
+func RegisterElapsed(now dateutil.Provider, logger util.Logger) {
+	raymond.RegisterHelper("elapsed", NewElapsedHelper("en", now, logger))
+}
+
+// NewElapsedHelper creates a new template helper printing the time elapsed
+// between the given date and the one of the now provider, in the given
+// language. Dates older than a year are printed with the `medium` format of
+// {{format-date}} instead.
+//
+// {{elapsed modified}} -> 3 days ago
+// {{elapsed created}} -> Nov 17, 2009
+func NewElapsedHelper(lang string, now dateutil.Provider, logger util.Logger) interface{} {
+	locale := findDateLocale(lang)
+
+	return func(arg interface{}) string {
+		date, ok := arg.(time.Time)
+		if !ok {
+			logger.Printf("the {{elapsed}} template helper expects a date as argument, received: %v", arg)
+			return ""
+		}
+
+		res, err := locale.formatElapsed(date, now.Date())
+		if err != nil {
+			logger.Printf("the {{elapsed}} template helper failed to format the date: %v", err)
+			return ""
+		}
+		return res
+	}
+}
+
+// formatElapsed prints the duration between date and now in a human-friendly
+// way, rounded down to the largest unit.
+func (l dateLocale) formatElapsed(date time.Time, now time.Time) (string, error) {
+	d := now.Sub(date)
+	day := 24 * time.Hour
+
+	switch {
+	case d < time.Minute:
+		// Dates slightly in the future are most likely due to a clock skew.
+		return l.justNow, nil
+	case d < time.Hour:
+		return l.formatAgo(int(d/time.Minute), elapsedMinute), nil
+	case d < day:
+		return l.formatAgo(int(d/time.Hour), elapsedHour), nil
+	case d < 7*day:
+		return l.formatAgo(int(d/day), elapsedDay), nil
+	case d < 30*day:
+		return l.formatAgo(int(d/(7*day)), elapsedWeek), nil
+	case d < 365*day:
+		return l.formatAgo(int(d/(30*day)), elapsedMonth), nil
+	default:
+		return l.format(date, l.mediumFormat)
+	}
+}
+
+func (l dateLocale) formatAgo(count int, unit elapsedUnit) string {
+	names := l.units[unit]
+	name := names[1]
+	if count == 1 {
+		name = names[0]
+	}
+	return fmt.Sprintf(l.ago, count, name)
+}
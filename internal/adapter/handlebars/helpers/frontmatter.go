@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"math"
+
+	"github.com/aymerick/raymond"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// RegisterFrontmatterYAML registers the {{frontmatter-yaml}} template helper,
+// which serializes the frontmatter metadata of the current note to YAML, with
+// sorted keys and quoting only the strings which would be read as another
+// type. It renders nothing for a note without metadata.
+//
+// {{frontmatter-yaml}} -> "aliases:\n- Zettel\ndate: \"2021-01-02\"\ntitle: A note\n"
+func RegisterFrontmatterYAML(logger util.Logger) {
+	raymond.RegisterHelper("frontmatter-yaml", NewFrontmatterYAMLHelper(logger))
+}
+
+// NewFrontmatterYAMLHelper creates a new {{frontmatter-yaml}} template helper reporting its
+// errors to the given logger.
+func NewFrontmatterYAMLHelper(logger util.Logger) interface{} {
+	return func(options *raymond.Options) string {
+		metadata, _ := options.Value("metadata").(map[string]interface{})
+		yaml, err := frontmatterYAML(metadata)
+		if err != nil {
+			logger.Err(errors.Wrap(err, "{{frontmatter-yaml}}"))
+			return ""
+		}
+		return yaml
+	}
+}
+
+// frontmatterYAML serializes the given metadata to YAML.
+func frontmatterYAML(metadata map[string]interface{}) (string, error) {
+	if len(metadata) == 0 {
+		return "", nil
+	}
+	out, err := yaml.Marshal(normalizeYAMLValue(metadata))
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// normalizeYAMLValue converts the whole numbers decoded as float64 from the
+// indexed JSON metadata back to integers, so that they are not serialized
+// with an exponent, e.g. 1e+06.
+func normalizeYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return int64(v)
+		}
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			res[key] = normalizeYAMLValue(val)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, val := range v {
+			res[i] = normalizeYAMLValue(val)
+		}
+		return res
+	}
+	return value
+}
@@ -11,12 +11,18 @@ import (
 // RegisterJSON registers a {{json}} template helper which serializes its
 // parameter to a JSON value.
 func RegisterJSON(logger util.Logger) {
-	raymond.RegisterHelper("json", func(arg interface{}) string {
+	raymond.RegisterHelper("json", NewJSONHelper(logger))
+}
+
+// NewJSONHelper creates a new {{json}} template helper reporting its
+// errors to the given logger.
+func NewJSONHelper(logger util.Logger) interface{} {
+	return func(arg interface{}) string {
 		jsonBytes, err := json.Marshal(arg)
 		if err != nil {
 			logger.Err(errors.Wrapf(err, "%v: not a serializable argument for {{json}}", arg))
 			return ""
 		}
 		return string(jsonBytes)
-	})
+	}
 }
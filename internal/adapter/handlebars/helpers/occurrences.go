@@ -0,0 +1,18 @@
+package helpers
+
+import (
+	"github.com/aymerick/raymond"
+	"github.com/zk-org/zk/internal/core"
+)
+
+// RegisterCountOccurrences registers the {{count-occurrences}} template
+// helper, which counts the occurrences of a term in the plain text of the
+// current note, outside of its code blocks. The term is matched as whole
+// words, ignoring the case.
+//
+// {{count-occurrences "rust"}} -> 3
+func RegisterCountOccurrences() {
+	raymond.RegisterHelper("count-occurrences", func(term string, options *raymond.Options) int {
+		return core.CountOccurrences(options.ValueStr("plain"), term)
+	})
+}
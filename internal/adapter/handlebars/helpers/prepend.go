@@ -19,7 +19,13 @@ import (
 // > A quote on
 // > several lines
 func RegisterPrepend(logger util.Logger) {
-	raymond.RegisterHelper("prepend", func(prefix string, opt interface{}) string {
+	raymond.RegisterHelper("prepend", NewPrependHelper(logger))
+}
+
+// NewPrependHelper creates a new {{prepend}} template helper reporting its
+// errors to the given logger.
+func NewPrependHelper(logger util.Logger) interface{} {
+	return func(prefix string, opt interface{}) string {
 		switch arg := opt.(type) {
 		case *raymond.Options:
 			return strings.Prepend(arg.Fn(), prefix)
@@ -29,5 +35,5 @@ func RegisterPrepend(logger util.Logger) {
 			logger.Printf("the {{prepend}} template helper is expecting a string as argument, received: %v", opt)
 			return ""
 		}
-	})
+	}
 }
@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"path/filepath"
+
+	"github.com/aymerick/raymond"
+)
+
+// RegisterRelative registers a {{relative}} template helper which computes
+// the path of a file relative to a base directory. The result is always
+// separated with forward slashes, to be used in portable Markdown links.
+//
+// When the path can't be made relative to the base, e.g. when they are on
+// different Windows drives, the path is returned unchanged.
+//
+// {{relative "journal/2009-11-17.md" "reports"}} -> "../journal/2009-11-17.md"
+// {{relative "reports/weekly.md" "reports"}} -> "weekly.md"
+func RegisterRelative() {
+	raymond.RegisterHelper("relative", func(path string, base string) string {
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return filepath.ToSlash(path)
+		}
+		return filepath.ToSlash(rel)
+	})
+}
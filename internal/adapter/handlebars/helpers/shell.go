@@ -13,7 +13,13 @@ import (
 // {{#sh "tr '[a-z]' '[A-Z]'"}}Hello, world!{{/sh}} -> HELLO, WORLD!
 // {{sh "echo 'Hello, world!'"}} -> Hello, world!
 func RegisterShell(logger util.Logger) {
-	raymond.RegisterHelper("sh", func(arg string, options *raymond.Options) string {
+	raymond.RegisterHelper("sh", NewShellHelper(logger))
+}
+
+// NewShellHelper creates a new {{sh}} template helper reporting its
+// errors to the given logger.
+func NewShellHelper(logger util.Logger) interface{} {
+	return func(arg string, options *raymond.Options) string {
 		cmd := exec.CommandFromString(arg)
 
 		// Feed any block content as piped input
@@ -26,5 +32,5 @@ func RegisterShell(logger util.Logger) {
 		}
 
 		return strings.TrimSpace(string(output))
-	})
+	}
 }
@@ -2,21 +2,35 @@ package helpers
 
 import (
 	"github.com/aymerick/raymond"
-	"github.com/gosimple/slug"
 	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/strings"
 )
 
 // NewSlugHelper creates a new template helper to slugify text.
 //
 // {{slug "This will be slugified!"}} -> this-will-be-slugified
 // {{#slug}}This will be slugified!{{/slug}} -> this-will-be-slugified
+// {{#slug sep="_" case="preserve"}}Crème Brûlée{{/slug}} -> Creme_Brulee
 func NewSlugHelper(lang string, logger util.Logger) interface{} {
 	return func(opt interface{}) string {
 		switch arg := opt.(type) {
 		case *raymond.Options:
-			return slug.MakeLang(arg.Fn(), lang)
+			separator := "-"
+			if sep, ok := arg.HashProp("sep").(string); ok {
+				separator = sep
+			}
+			letterCase := strings.SlugLowercase
+			if c, ok := arg.HashProp("case").(string); ok {
+				switch strings.SlugCase(c) {
+				case strings.SlugLowercase, strings.SlugUppercase, strings.SlugPreserve:
+					letterCase = strings.SlugCase(c)
+				default:
+					logger.Printf("the {{slug}} template helper is expecting a case among lower, upper or preserve, received: %v", c)
+				}
+			}
+			return strings.Slugify(arg.Fn(), lang, separator, letterCase)
 		case string:
-			return slug.MakeLang(arg, lang)
+			return strings.Slugify(arg, lang, "-", strings.SlugLowercase)
 		default:
 			logger.Printf("the {{slug}} template helper is expecting a string as argument, received: %v", opt)
 			return ""
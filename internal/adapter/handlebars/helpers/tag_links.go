@@ -0,0 +1,57 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aymerick/raymond"
+	"github.com/zk-org/zk/internal/util"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// NewTagLinksHelper creates a new template helper rendering the tags of the
+// current note as Markdown links, e.g. to static-site tag pages. The %s
+// placeholder of the path pattern is replaced by the slugified tag, while the
+// raw tag is kept as the link text.
+//
+// {{tag-links "tags/%s.md"}} -> [Book club](tags/book-club.md), [rust](tags/rust.md)
+// {{tag-links "tags/%s.md" separator=" "}} -> [Book club](tags/book-club.md) [rust](tags/rust.md)
+func NewTagLinksHelper(lang string, logger util.Logger) interface{} {
+	return func(pattern string, options *raymond.Options) string {
+		if !strings.Contains(pattern, "%s") {
+			logger.Printf("the {{tag-links}} template helper is expecting a path pattern containing %%s, received: %v", pattern)
+			return ""
+		}
+
+		separator := ", "
+		if sep, ok := options.HashProp("separator").(string); ok {
+			separator = sep
+		}
+
+		links := []string{}
+		for _, tag := range tagList(options.Value("tags")) {
+			path := strings.ReplaceAll(pattern, "%s", strutil.Slugify(tag, lang, "-", strutil.SlugLowercase))
+			links = append(links, fmt.Sprintf("[%s](%s)", tag, path))
+		}
+		return strings.Join(links, separator)
+	}
+}
+
+// tagList converts the tags found in a template context into a list of
+// strings.
+func tagList(tags interface{}) []string {
+	switch tags := tags.(type) {
+	case []string:
+		return tags
+	case []interface{}:
+		list := []string{}
+		for _, tag := range tags {
+			if tag, ok := tag.(string); ok {
+				list = append(list, tag)
+			}
+		}
+		return list
+	default:
+		return []string{}
+	}
+}
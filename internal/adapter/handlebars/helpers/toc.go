@@ -0,0 +1,87 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aymerick/raymond"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/zk-org/zk/internal/core"
+)
+
+// RegisterTOC registers the {{toc}} template helper, which renders the table
+// of contents of the current note as a nested Markdown list of its headings,
+// linking to their anchors. The title heading is not included.
+//
+// The max-depth hash argument limits the number of nested levels, and href
+// prefixes the anchors, e.g. to link to the note from an overview note.
+//
+// {{toc}} -> "- [Introduction](#introduction)\n  - [Usage](#usage)\n"
+// {{toc max-depth=1 href=path}} -> "- [Introduction](dir/note.md#introduction)\n"
+func RegisterTOC() {
+	raymond.RegisterHelper("toc", func(options *raymond.Options) string {
+		maxDepth := 0
+		if depth, ok := options.HashProp("max-depth").(int); ok {
+			maxDepth = depth
+		}
+		return renderTOC(parseHeadings(options.ValueStr("body")), maxDepth, options.HashStr("href"))
+	})
+}
+
+type tocHeading struct {
+	Level  int
+	Text   string
+	Anchor string
+}
+
+var tocMarkdown = goldmark.New()
+
+// parseHeadings returns the headings of the given Markdown content, in order.
+func parseHeadings(content string) []tocHeading {
+	source := []byte(content)
+	root := tocMarkdown.Parser().Parse(text.NewReader(source))
+
+	headings := []tocHeading{}
+	anchors := core.HeadingAnchors{}
+	ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if heading, ok := n.(*ast.Heading); ok && entering {
+			text := strings.TrimSpace(string(heading.Text(source)))
+			if text != "" {
+				headings = append(headings, tocHeading{
+					Level:  heading.Level,
+					Text:   text,
+					Anchor: anchors.Anchor(text),
+				})
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return headings
+}
+
+var tocLinkTextReplacer = strings.NewReplacer("[", `\[`, "]", `\]`)
+
+// renderTOC renders the headings as a nested Markdown list. A maxDepth of 0
+// keeps all the headings.
+func renderTOC(headings []tocHeading, maxDepth int, href string) string {
+	var toc strings.Builder
+	// Stack of the heading levels of the current branch, to nest the items
+	// consistently when levels are skipped or when the first heading is not
+	// the highest one.
+	levels := []int{}
+	for _, heading := range headings {
+		for len(levels) > 0 && levels[len(levels)-1] >= heading.Level {
+			levels = levels[:len(levels)-1]
+		}
+		levels = append(levels, heading.Level)
+		depth := len(levels)
+		if maxDepth > 0 && depth > maxDepth {
+			continue
+		}
+		fmt.Fprintf(&toc, "%s- [%s](%s#%s)\n", strings.Repeat("  ", depth-1), tocLinkTextReplacer.Replace(heading.Text), href, heading.Anchor)
+	}
+	return toc.String()
+}
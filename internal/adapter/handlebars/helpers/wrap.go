@@ -0,0 +1,16 @@
+package helpers
+
+import (
+	"github.com/aymerick/raymond"
+	"github.com/zk-org/zk/internal/util/strings"
+)
+
+// RegisterWrap registers a {{wrap}} template helper which hard-wraps a text at
+// word boundaries to fit the given width, preserving the Markdown structure.
+//
+// {{wrap "A line which is too long" 10}} -> "A line\nwhich is\ntoo long"
+func RegisterWrap() {
+	raymond.RegisterHelper("wrap", func(text string, width int) string {
+		return strings.Wrap(text, width)
+	})
+}
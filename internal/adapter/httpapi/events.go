@@ -0,0 +1,138 @@
+package httpapi
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/paths"
+)
+
+// changeEvent is pushed to the WebSocket clients when notes are added,
+// modified or removed. The paths are relative to the notebook root.
+type changeEvent struct {
+	Added    []string `json:"added"`
+	Modified []string `json:"modified"`
+	Removed  []string `json:"removed"`
+}
+
+// changeHub batches the changes found while indexing the notebook and
+// broadcasts them to its subscribers.
+//
+// The changes are sent once no new change was received during the debounce
+// delay, so that saving several notes at once results in a single event.
+type changeHub struct {
+	debounce time.Duration
+
+	mutex       sync.Mutex
+	subscribers map[chan changeEvent]bool
+	pending     map[string]paths.DiffKind
+	timer       *time.Timer
+}
+
+func newChangeHub(debounce time.Duration) *changeHub {
+	return &changeHub{
+		debounce:    debounce,
+		subscribers: map[chan changeEvent]bool{},
+		pending:     map[string]paths.DiffKind{},
+	}
+}
+
+// subscribe registers a new subscriber, receiving the batched changes on the
+// returned channel until unsubscribe is called.
+func (h *changeHub) subscribe() chan changeEvent {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	ch := make(chan changeEvent, 16)
+	h.subscribers[ch] = true
+	return ch
+}
+
+func (h *changeHub) unsubscribe(ch chan changeEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.subscribers[ch] {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+}
+
+// hasSubscribers returns whether at least one client is listening to the
+// changes.
+func (h *changeHub) hasSubscribers() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return len(h.subscribers) > 0
+}
+
+// add records a change found while indexing, to be sent with the next batch.
+func (h *changeHub) add(change paths.DiffChange) {
+	if change.Kind == paths.DiffUnchanged {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	switch previous, ok := h.pending[change.Path]; {
+	case !ok:
+		h.pending[change.Path] = change.Kind
+	case previous == paths.DiffAdded && change.Kind == paths.DiffRemoved:
+		// The note was only transient, the clients don't need to know.
+		delete(h.pending, change.Path)
+	case previous == paths.DiffAdded && change.Kind == paths.DiffModified:
+		// Still a new note for the clients.
+	case previous == paths.DiffRemoved && change.Kind == paths.DiffAdded:
+		h.pending[change.Path] = paths.DiffModified
+	default:
+		h.pending[change.Path] = change.Kind
+	}
+
+	if h.timer == nil {
+		h.timer = time.AfterFunc(h.debounce, h.flush)
+	} else {
+		h.timer.Reset(h.debounce)
+	}
+}
+
+// flush sends the pending changes to the subscribers.
+func (h *changeHub) flush() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.timer = nil
+	if len(h.pending) == 0 {
+		return
+	}
+
+	event := changeEvent{
+		Added:    []string{},
+		Modified: []string{},
+		Removed:  []string{},
+	}
+	for path, kind := range h.pending {
+		switch kind {
+		case paths.DiffAdded:
+			event.Added = append(event.Added, path)
+		case paths.DiffModified:
+			event.Modified = append(event.Modified, path)
+		case paths.DiffRemoved:
+			event.Removed = append(event.Removed, path)
+		}
+	}
+	sort.Strings(event.Added)
+	sort.Strings(event.Modified)
+	sort.Strings(event.Removed)
+	h.pending = map[string]paths.DiffKind{}
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Drops the event for slow clients instead of blocking the
+			// others.
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package httpapi
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/paths"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestChangeHubBatchesChanges(t *testing.T) {
+	hub := newChangeHub(20 * time.Millisecond)
+	ch := hub.subscribe()
+	assert.True(t, hub.hasSubscribers())
+
+	hub.add(paths.DiffChange{Path: "b.md", Kind: paths.DiffAdded})
+	hub.add(paths.DiffChange{Path: "a.md", Kind: paths.DiffAdded})
+	hub.add(paths.DiffChange{Path: "c.md", Kind: paths.DiffModified})
+	hub.add(paths.DiffChange{Path: "d.md", Kind: paths.DiffRemoved})
+	hub.add(paths.DiffChange{Path: "e.md", Kind: paths.DiffUnchanged})
+	// Transient note
+	hub.add(paths.DiffChange{Path: "f.md", Kind: paths.DiffAdded})
+	hub.add(paths.DiffChange{Path: "f.md", Kind: paths.DiffRemoved})
+	// A new note modified is still new.
+	hub.add(paths.DiffChange{Path: "a.md", Kind: paths.DiffModified})
+	// A note removed then added again is modified.
+	hub.add(paths.DiffChange{Path: "d.md", Kind: paths.DiffAdded})
+
+	assert.Equal(t, receive(t, ch), changeEvent{
+		Added:    []string{"a.md", "b.md"},
+		Modified: []string{"c.md", "d.md"},
+		Removed:  []string{},
+	})
+
+	hub.add(paths.DiffChange{Path: "a.md", Kind: paths.DiffRemoved})
+	assert.Equal(t, receive(t, ch), changeEvent{
+		Added:    []string{},
+		Modified: []string{},
+		Removed:  []string{"a.md"},
+	})
+
+	hub.unsubscribe(ch)
+	assert.False(t, hub.hasSubscribers())
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func TestChangeHubIgnoresUnchangedNotes(t *testing.T) {
+	hub := newChangeHub(10 * time.Millisecond)
+	ch := hub.subscribe()
+
+	hub.add(paths.DiffChange{Path: "a.md", Kind: paths.DiffUnchanged})
+	hub.add(paths.DiffChange{Path: "b.md", Kind: paths.DiffAdded})
+	hub.add(paths.DiffChange{Path: "b.md", Kind: paths.DiffRemoved})
+
+	select {
+	case event := <-ch:
+		t.Fatalf("unexpected event: %v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func receive(t *testing.T, ch chan changeEvent) changeEvent {
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the changes")
+		return changeEvent{}
+	}
+}
+
+func TestIsLocalOrigin(t *testing.T) {
+	test := func(origin string, expected bool) {
+		r := httptest.NewRequest("GET", "http://notes.lan:8080/events", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		assert.Equal(t, isLocalOrigin(r), expected)
+	}
+
+	test("", true)
+	test("http://localhost:3000", true)
+	test("http://127.0.0.1", true)
+	test("http://[::1]:5173", true)
+	test("http://notes.lan:8080", true)
+	test("http://NOTES.lan:8080", true)
+	test("http://notes.lan:3000", false)
+	test("https://example.com", false)
+	test("null", false)
+}
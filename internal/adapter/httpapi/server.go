@@ -0,0 +1,494 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/date"
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/opt"
+	"github.com/zk-org/zk/internal/util/paths"
+)
+
+// Server exposes the notes of a notebook as JSON endpoints over HTTP.
+//
+//	GET  /notes?tag=...&match=...&limit=...  List the notes matching the criteria.
+//	GET  /note?path=...                      Get a single note.
+//	GET  /graph                              Get the notes and the links between them.
+//	POST /notes                              Create a new note (read-write only).
+//	PUT  /note?path=...                      Replace the content of a note (read-write only).
+//	GET  /events                             WebSocket pushing the notes added, modified or removed.
+type Server struct {
+	// Host of the address the server listens on, accepted in the Host
+	// header of the requests in addition to the local ones.
+	host         string
+	notebook     *core.Notebook
+	fs           core.FileStorage
+	readWrite    bool
+	now          date.Provider
+	logger       util.Logger
+	pollInterval time.Duration
+	changes      *changeHub
+
+	// Serializes the requests, as each one refreshes the index.
+	mutex sync.Mutex
+}
+
+// ServerOpts holds the options used to create a Server.
+type ServerOpts struct {
+	Notebook *core.Notebook
+	FS       core.FileStorage
+	// Enables the endpoints creating and editing notes.
+	ReadWrite bool
+	Now       date.Provider
+	Logger    util.Logger
+	// Delay between two checks of the notebook for changes, while WebSocket
+	// clients are connected.
+	PollInterval time.Duration
+	// Delay without any change before the changes are pushed to the
+	// WebSocket clients.
+	Debounce time.Duration
+}
+
+// NewServer creates a new HTTP server for the given notebook.
+func NewServer(opts ServerOpts) *Server {
+	return &Server{
+		notebook:     opts.Notebook,
+		fs:           opts.FS,
+		readWrite:    opts.ReadWrite,
+		now:          opts.Now,
+		logger:       opts.Logger,
+		pollInterval: opts.PollInterval,
+		changes:      newChangeHub(opts.Debounce),
+	}
+}
+
+// ListenAndServe starts serving the API on the given TCP address, until an
+// error occurs.
+func (s *Server) ListenAndServe(addr string) error {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		s.host = host
+	}
+	if s.pollInterval > 0 {
+		go s.watch()
+	}
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+// watch reindexes the notebook periodically while WebSocket clients are
+// connected, to notify them of the changes made outside of the server.
+func (s *Server) watch() {
+	for range time.Tick(s.pollInterval) {
+		if !s.changes.hasSubscribers() {
+			continue
+		}
+		s.mutex.Lock()
+		_, err := s.index()
+		s.mutex.Unlock()
+		s.logger.Err(err)
+	}
+}
+
+// index refreshes the index of the notebook, recording the changes to be
+// pushed to the WebSocket clients.
+func (s *Server) index() (core.NoteIndexingStats, error) {
+	return s.notebook.IndexWithCallback(core.NoteIndexOpts{}, func(change paths.DiffChange) {
+		s.changes.add(change)
+	})
+}
+
+// Handler returns the HTTP handler routing the requests to the endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/notes", s.handle(map[string]handlerFunc{
+		http.MethodGet:  s.listNotes,
+		http.MethodPost: s.requireReadWrite(s.newNote),
+	}))
+	mux.HandleFunc("/note", s.handle(map[string]handlerFunc{
+		http.MethodGet: s.getNote,
+		http.MethodPut: s.requireReadWrite(s.editNote),
+	}))
+	mux.HandleFunc("/graph", s.handle(map[string]handlerFunc{
+		http.MethodGet: s.graph,
+	}))
+	mux.HandleFunc("/events", s.events)
+	return s.requireLocal(mux)
+}
+
+// requireLocal rejects the requests sent to another host than the local
+// machine or the one the server listens on, and the ones sent by pages of
+// other websites. This prevents websites from reading or modifying the notes,
+// even through DNS rebinding.
+func (s *Server) requireLocal(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.isLocalHost(r) {
+			writeError(w, newAPIError(http.StatusForbidden, "%s: host not allowed", r.Host))
+			return
+		}
+		if !isLocalOrigin(r) {
+			writeError(w, newAPIError(http.StatusForbidden, "%s: origin not allowed", r.Header.Get("Origin")))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// isLocalHost returns whether the request is sent to the local machine, or to
+// the host the server listens on.
+func (s *Server) isLocalHost(r *http.Request) bool {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if isLoopback(host) {
+		return true
+	}
+	if s.host == "" || net.ParseIP(s.host).IsUnspecified() {
+		return false
+	}
+	return strings.EqualFold(host, s.host)
+}
+
+// isLoopback returns whether the given hostname designates the local machine.
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// handlerFunc handles a request and returns the value to be sent as JSON.
+type handlerFunc func(r *http.Request) (interface{}, int, error)
+
+// apiError is an error reported with a specific HTTP status code.
+type apiError struct {
+	status int
+	err    error
+}
+
+func (e apiError) Error() string {
+	return e.err.Error()
+}
+
+func newAPIError(status int, format string, args ...interface{}) apiError {
+	return apiError{status: status, err: fmt.Errorf(format, args...)}
+}
+
+// handle dispatches the request to the handler of its method, after
+// refreshing the index. The result or error is written as JSON.
+func (s *Server) handle(handlers map[string]handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.logger.Debugf("%s %s", r.Method, r.URL)
+
+		handler, ok := handlers[r.Method]
+		if !ok {
+			writeError(w, newAPIError(http.StatusMethodNotAllowed, "method %s not allowed", r.Method))
+			return
+		}
+
+		// The notes may have been modified since the last request.
+		if _, err := s.index(); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		res, status, err := handler(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, status, res)
+	}
+}
+
+// requireReadWrite rejects the request when the server is read-only.
+func (s *Server) requireReadWrite(handler handlerFunc) handlerFunc {
+	return func(r *http.Request) (interface{}, int, error) {
+		if !s.readWrite {
+			return nil, 0, newAPIError(http.StatusForbidden, "the server is read-only, start it with --read-write to modify the notes")
+		}
+		return handler(r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var apiErr apiError
+	if errors.As(err, &apiErr) {
+		status = apiErr.status
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// listNotes returns the notes matching the `tag`, `match` and `limit` query
+// parameters.
+func (s *Server) listNotes(r *http.Request) (interface{}, int, error) {
+	query := r.URL.Query()
+	opts := core.NoteFindOpts{
+		Tags:    query["tag"],
+		Sorters: []core.NoteSorter{{Field: core.NoteSortPath, Ascending: true}},
+	}
+	if match := query["match"]; len(match) > 0 {
+		opts.Match = match
+		opts.MatchStrategy = core.MatchStrategyFts
+		opts.Sorters = nil
+	}
+	if limit := query.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 0 {
+			return nil, 0, newAPIError(http.StatusBadRequest, "%s: invalid limit", limit)
+		}
+		opts.Limit = l
+	}
+
+	notes, err := s.notebook.FindNotes(opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	res, err := s.formatNotes(notes)
+	return res, http.StatusOK, err
+}
+
+// getNote returns the note at the `path` query parameter.
+func (s *Server) getNote(r *http.Request) (interface{}, int, error) {
+	note, err := s.findNote(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	res, err := s.formatNote(*note)
+	return res, http.StatusOK, err
+}
+
+// graph returns all the notes of the notebook and the links between them.
+func (s *Server) graph(r *http.Request) (interface{}, int, error) {
+	notes, err := s.notebook.FindNotes(core.NoteFindOpts{
+		Sorters: []core.NoteSorter{{Field: core.NoteSortPath, Ascending: true}},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	ids := []core.NoteID{}
+	for _, note := range notes {
+		ids = append(ids, note.ID)
+	}
+	links, err := s.notebook.FindLinksBetweenNotes(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	formattedNotes, err := s.formatNotes(notes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return map[string]interface{}{
+		"notes": formattedNotes,
+		"links": links,
+	}, http.StatusOK, nil
+}
+
+var upgrader = websocket.Upgrader{CheckOrigin: isLocalOrigin}
+
+// isLocalOrigin accepts the WebSocket connections from the scripts and the
+// pages served on the same host or on the local machine, to prevent other
+// websites from listening to the changes.
+func isLocalOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if isLoopback(u.Hostname()) {
+		return true
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// events upgrades the request to a WebSocket connection, pushing a
+// changeEvent each time notes are added, modified or removed.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader already replied with an HTTP error.
+		s.logger.Debugf("failed to upgrade to WebSocket: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	changes := s.changes.subscribe()
+	defer s.changes.unsubscribe(changes)
+
+	// Reading is required to process the control messages, and detects when
+	// the client closes the connection.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event := <-changes:
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// newNoteRequest is the body of a request creating a new note.
+type newNoteRequest struct {
+	Title    string            `json:"title"`
+	Content  string            `json:"content"`
+	Dir      string            `json:"dir"`
+	Group    string            `json:"group"`
+	Template string            `json:"template"`
+	Extra    map[string]string `json:"extra"`
+}
+
+// newNote creates a new note from the JSON body of the request.
+func (s *Server) newNote(r *http.Request) (interface{}, int, error) {
+	// Requiring JSON prevents web pages from posting forms, which are sent
+	// without CORS preflight.
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return nil, 0, newAPIError(http.StatusUnsupportedMediaType, "expected a request body of type application/json")
+	}
+
+	var req newNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, 0, newAPIError(http.StatusBadRequest, "invalid request body: %v", err)
+	}
+	// The paths can't escape the notebook and the templates directories.
+	if req.Dir != "" && !filepath.IsLocal(req.Dir) {
+		return nil, 0, newAPIError(http.StatusBadRequest, "%s: the directory must be relative to the notebook root", req.Dir)
+	}
+	if req.Template != "" && !filepath.IsLocal(req.Template) {
+		return nil, 0, newAPIError(http.StatusBadRequest, "%s: the template must be relative to the templates directory", req.Template)
+	}
+
+	note, err := s.notebook.NewNote(core.NewNoteOpts{
+		Title:     opt.NewNotEmptyString(req.Title),
+		Content:   req.Content,
+		Directory: opt.NewString(filepath.Join(s.notebook.Path, req.Dir)),
+		Group:     opt.NewNotEmptyString(req.Group),
+		Template:  opt.NewNotEmptyString(req.Template),
+		Extra:     req.Extra,
+		Date:      s.now.Date(),
+	})
+	if err != nil {
+		var noteExists core.ErrNoteExists
+		if errors.As(err, &noteExists) {
+			return nil, 0, apiError{status: http.StatusConflict, err: err}
+		}
+		return nil, 0, apiError{status: http.StatusBadRequest, err: err}
+	}
+	// The new note is indexed directly, without going through s.index().
+	s.changes.add(paths.DiffChange{Path: note.Path, Kind: paths.DiffAdded})
+
+	notes, err := s.notebook.FindNotes(core.NoteFindOpts{IncludeIDs: []core.NoteID{note.ID}})
+	if err != nil || len(notes) == 0 {
+		return nil, 0, err
+	}
+	res, err := s.formatNote(notes[0])
+	return res, http.StatusCreated, err
+}
+
+// editNote replaces the content of the note at the `path` query parameter
+// with the body of the request.
+func (s *Server) editNote(r *http.Request) (interface{}, int, error) {
+	note, err := s.findNote(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, 0, newAPIError(http.StatusBadRequest, "invalid request body: %v", err)
+	}
+	err = s.fs.Write(filepath.Join(s.notebook.Path, note.Path), content)
+	if err != nil {
+		return nil, 0, err
+	}
+	if _, err = s.index(); err != nil {
+		return nil, 0, err
+	}
+
+	return s.getNote(r)
+}
+
+// findNote returns the indexed note at the `path` query parameter, relative
+// to the notebook root.
+func (s *Server) findNote(r *http.Request) (*core.ContextualNote, error) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		return nil, newAPIError(http.StatusBadRequest, "missing path parameter")
+	}
+
+	notes, err := s.notebook.FindNotes(core.NoteFindOpts{
+		IncludeHrefs: []string{filepath.Clean(path)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		if note.Path == filepath.Clean(path) {
+			return &note, nil
+		}
+	}
+	return nil, newAPIError(http.StatusNotFound, "%s: note not found", path)
+}
+
+// formatNote renders the note with the same JSON representation as
+// `zk list --format json`, with paths relative to the notebook root.
+func (s *Server) formatNote(note core.ContextualNote) (json.RawMessage, error) {
+	format, err := s.notebook.NewNoteFormatter("{{json .}}", core.PathStyleNotebook)
+	if err != nil {
+		return nil, err
+	}
+	res, err := format(note)
+	return json.RawMessage(res), err
+}
+
+func (s *Server) formatNotes(notes []core.ContextualNote) ([]json.RawMessage, error) {
+	res := []json.RawMessage{}
+	for _, note := range notes {
+		ft, err := s.formatNote(note)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ft)
+	}
+	return res, nil
+}
@@ -0,0 +1,61 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestRequireLocal(t *testing.T) {
+	test := func(serverHost string, url string, origin string, expected int) {
+		t.Helper()
+		s := &Server{host: serverHost}
+		handler := s.requireLocal(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		r := httptest.NewRequest("GET", url, nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, w.Code, expected)
+	}
+
+	test("localhost", "http://localhost:8080/notes", "", http.StatusNoContent)
+	test("localhost", "http://127.0.0.1:8080/notes", "", http.StatusNoContent)
+	test("localhost", "http://[::1]:8080/notes", "", http.StatusNoContent)
+	test("localhost", "http://localhost:8080/notes", "http://localhost:3000", http.StatusNoContent)
+	test("notes.lan", "http://notes.lan:8080/notes", "http://notes.lan:8080", http.StatusNoContent)
+
+	// DNS rebinding
+	test("localhost", "http://attacker.com:8080/notes", "", http.StatusForbidden)
+	test("", "http://attacker.com:8080/notes", "", http.StatusForbidden)
+	test("0.0.0.0", "http://attacker.com:8080/notes", "", http.StatusForbidden)
+	// Cross-origin requests
+	test("localhost", "http://localhost:8080/notes", "https://example.com", http.StatusForbidden)
+	test("localhost", "http://localhost:8080/note", "null", http.StatusForbidden)
+}
+
+func TestNewNoteRequiresJSON(t *testing.T) {
+	test := func(contentType string, body string, expected string) {
+		t.Helper()
+		s := &Server{readWrite: true}
+		r := httptest.NewRequest("POST", "http://localhost:8080/notes", strings.NewReader(body))
+		if contentType != "" {
+			r.Header.Set("Content-Type", contentType)
+		}
+		_, _, err := s.newNote(r)
+		assert.Err(t, err, expected)
+	}
+
+	test("", `{"title": "Note"}`, "expected a request body of type application/json")
+	test("application/x-www-form-urlencoded", `{"title": "Note"}`, "expected a request body of type application/json")
+	test("text/plain", `{"title": "Note"}`, "expected a request body of type application/json")
+	test("application/json; charset=utf-8", `{"template": "/etc/passwd"}`, "/etc/passwd: the template must be relative to the templates directory")
+	test("application/json", `{"template": "../../secret.md"}`, "../../secret.md: the template must be relative to the templates directory")
+	test("application/json", `{"dir": "../outside"}`, "../outside: the directory must be relative to the notebook root")
+}
@@ -63,6 +63,7 @@ type listSelection struct {
 	Title        bool
 	Lead         bool
 	Body         bool
+	Plain        bool
 	Snippets     bool
 	RawContent   bool
 	WordCount    bool
@@ -82,6 +83,7 @@ func newListSelection(fields []string) listSelection {
 		Title:        strutil.Contains(fields, "title"),
 		Lead:         strutil.Contains(fields, "lead"),
 		Body:         strutil.Contains(fields, "body"),
+		Plain:        strutil.Contains(fields, "plain"),
 		Snippets:     strutil.Contains(fields, "snippets"),
 		RawContent:   strutil.Contains(fields, "rawContent"),
 		WordCount:    strutil.Contains(fields, "wordCount"),
@@ -116,6 +118,9 @@ func newListNote(note core.ContextualNote, selection listSelection, basePath str
 	if selection.Body {
 		res.Body = note.Body
 	}
+	if selection.Plain {
+		res.Plain = note.Plain
+	}
 	if selection.Snippets {
 		res.Snippets = note.Snippets
 	}
@@ -151,6 +156,7 @@ type listNote struct {
 	Title        string                 `json:"title,omitempty"`
 	Lead         string                 `json:"lead,omitempty"`
 	Body         string                 `json:"body,omitempty"`
+	Plain        string                 `json:"plain,omitempty"`
 	Snippets     []string               `json:"snippets,omitempty"`
 	RawContent   string                 `json:"rawContent,omitempty"`
 	WordCount    int                    `json:"wordCount,omitempty"`
@@ -20,12 +20,15 @@ type cmdNewOpts struct {
 	Dir                     string             `json:"dir"`
 	Group                   string             `json:"group"`
 	Template                string             `json:"template"`
+	FilenameTemplate        string             `json:"filenameTemplate"`
+	ID                      string             `json:"id"`
 	Extra                   map[string]string  `json:"extra"`
 	Date                    string             `json:"date"`
 	Edit                    jsonBoolean        `json:"edit"`
 	DryRun                  jsonBoolean        `json:"dryRun"`
 	InsertLinkAtLocation    *protocol.Location `json:"insertLinkAtLocation"`
 	InsertContentAtLocation *protocol.Location `json:"insertContentAtLocation"`
+	LinkTo                  string             `json:"linkTo"`
 }
 
 func executeCommandNew(notebook *core.Notebook, documents *documentStore, context *glsp.Context, args []interface{}) (interface{}, error) {
@@ -47,14 +50,16 @@ func executeCommandNew(notebook *core.Notebook, documents *documentStore, contex
 	}
 
 	note, err := notebook.NewNote(core.NewNoteOpts{
-		Title:     opt.NewNotEmptyString(opts.Title),
-		Content:   opts.Content,
-		Directory: opt.NewNotEmptyString(opts.Dir),
-		Group:     opt.NewNotEmptyString(opts.Group),
-		Template:  opt.NewNotEmptyString(opts.Template),
-		Extra:     opts.Extra,
-		DryRun:    bool(opts.DryRun),
-		Date:      date,
+		Title:            opt.NewNotEmptyString(opts.Title),
+		Content:          opts.Content,
+		Directory:        opt.NewNotEmptyString(opts.Dir),
+		Group:            opt.NewNotEmptyString(opts.Group),
+		Template:         opt.NewNotEmptyString(opts.Template),
+		FilenameTemplate: opt.NewNotEmptyString(opts.FilenameTemplate),
+		ID:               opts.ID,
+		Extra:            opts.Extra,
+		DryRun:           bool(opts.DryRun),
+		Date:             date,
 	})
 	if err != nil {
 		var noteExists core.ErrNoteExists
@@ -98,6 +103,14 @@ func executeCommandNew(notebook *core.Notebook, documents *documentStore, contex
 	}
 
 	absPath := filepath.Join(notebook.Path, note.Path)
+	if !opts.DryRun && opts.LinkTo != "" {
+		content, err := linkToNote(notebook, absPath, opts.LinkTo)
+		if err != nil {
+			return nil, err
+		}
+		note.RawContent = content
+	}
+
 	if !opts.DryRun && opts.Edit {
 		go context.Call(protocol.ServerWindowShowDocument, protocol.ShowDocumentParams{
 			URI:       pathToURI(absPath),
@@ -110,3 +123,27 @@ func executeCommandNew(notebook *core.Notebook, documents *documentStore, contex
 		"content": note.RawContent,
 	}, nil
 }
+
+// linkToNote appends a link to the note at targetPath in the `## Links`
+// section of the note at sourcePath, and returns its updated content.
+func linkToNote(notebook *core.Notebook, sourcePath string, targetPath string) (string, error) {
+	relPath, err := notebook.RelPath(targetPath)
+	if err != nil {
+		return "", err
+	}
+	target, err := notebook.FindNote(core.NoteFindOpts{
+		IncludeHrefs: []string{relPath},
+	})
+	if err != nil {
+		return "", err
+	}
+	if target == nil {
+		return "", core.ErrNoteNotFound{Path: targetPath}
+	}
+
+	content, _, err := notebook.LinkFromNote(*target, core.LinkFromNoteOpts{
+		SourcePath: sourcePath,
+		Section:    "## Links",
+	})
+	return content, err
+}
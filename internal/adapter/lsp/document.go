@@ -190,6 +190,79 @@ func (d *document) DocumentLinkAt(pos protocol.Position) (*documentLink, error)
 	return nil, nil
 }
 
+// DocumentSymbols returns the outline of the document: its frontmatter and
+// the hierarchy of its headings.
+func (d *document) DocumentSymbols() []protocol.DocumentSymbol {
+	symbols := []protocol.DocumentSymbol{}
+
+	if count := core.FrontmatterLineCount(d.Content); count > 0 {
+		symbols = append(symbols, protocol.DocumentSymbol{
+			Name:           "Frontmatter",
+			Kind:           protocol.SymbolKindNamespace,
+			Range:          d.linesRange(0, count-1),
+			SelectionRange: d.linesRange(0, 0),
+		})
+	}
+
+	headings := core.ParseNoteHeadings(d.Content)
+	return append(symbols, d.headingSymbols(headings, len(d.GetLines())-1)...)
+}
+
+// headingSymbols nests the given headings by level. A heading is a child of
+// the closest previous heading with a lower level, so skipped levels (e.g. a
+// h3 right under a h1) are handled gracefully. The last section ends at the
+// given line.
+func (d *document) headingSymbols(headings []core.NoteHeading, lastLine int) []protocol.DocumentSymbol {
+	symbols := []protocol.DocumentSymbol{}
+
+	for i := 0; i < len(headings); {
+		heading := headings[i]
+
+		// The section of the heading ends before the next heading of the
+		// same or a higher level.
+		next := i + 1
+		for next < len(headings) && headings[next].Level > heading.Level {
+			next++
+		}
+		end := lastLine
+		if next < len(headings) {
+			end = headings[next].Line - 1
+		}
+
+		name := heading.Text
+		if name == "" {
+			name = strings.Repeat("#", heading.Level)
+		}
+
+		symbols = append(symbols, protocol.DocumentSymbol{
+			Name:           name,
+			Kind:           protocol.SymbolKindString,
+			Range:          d.linesRange(heading.Line, end),
+			SelectionRange: d.linesRange(heading.Line, heading.Line),
+			Children:       d.headingSymbols(headings[i+1:next], end),
+		})
+		i = next
+	}
+
+	return symbols
+}
+
+// linesRange returns the range spanning the given lines, without the
+// trailing blank lines.
+func (d *document) linesRange(start int, end int) protocol.Range {
+	lines := d.GetLines()
+	for end > start && strings.TrimSpace(lines[end]) == "" {
+		end--
+	}
+	return protocol.Range{
+		Start: protocol.Position{Line: protocol.UInteger(start)},
+		End: protocol.Position{
+			Line:      protocol.UInteger(end),
+			Character: protocol.UInteger(len(utf16.Encode([]rune(strings.TrimRight(lines[end], "\r"))))),
+		},
+	}
+}
+
 // Recursive function to check whether a link is within inline code.
 func linkWithinInlineCode(strBuffer string, linkStart, linkEnd int, insideInline bool) bool {
 	if backtickId := strings.Index(strBuffer, "`"); backtickId >= 0 && backtickId < linkEnd {
@@ -212,6 +285,14 @@ var insideFenced = false
 var insideIndented = false
 var currentCodeBlockStart = -1
 
+// resetCodeBlockState forgets the code block state left by a previous scan
+// of a document with isLineWithinCodeBlock.
+func resetCodeBlockState() {
+	insideFenced = false
+	insideIndented = false
+	currentCodeBlockStart = -1
+}
+
 // check whether the current line in document is within a fenced or indented 
 // code block
 func isLineWithinCodeBlock(lines []string, lineIndex int, line string) bool {
@@ -254,6 +335,7 @@ func isLineWithinCodeBlock(lines []string, lineIndex int, line string) bool {
 // document.
 func (d *document) DocumentLinks() ([]documentLink, error) {
 	links := []documentLink{}
+	resetCodeBlockState()
 
 	lines := d.GetLines()
 	for lineIndex, line := range lines {
@@ -262,7 +344,7 @@ func (d *document) DocumentLinks() ([]documentLink, error) {
 			continue
 		}
 
-		appendLink := func(href string, start, end int, hasTitle bool, isWikiLink bool) {
+		appendLink := func(href string, title string, start, end int, hasTitle bool, isWikiLink bool) {
 			if href == "" {
 				return
 			}
@@ -284,6 +366,7 @@ func (d *document) DocumentLinks() ([]documentLink, error) {
 						Character: protocol.UInteger(end),
 					},
 				},
+				Title:      title,
 				HasTitle:   hasTitle,
 				IsWikiLink: isWikiLink,
 			})
@@ -319,7 +402,8 @@ func (d *document) DocumentLinks() ([]documentLink, error) {
 				href = decodedHref
 			}
 
-			appendLink(href, match[0], match[1], false, false)
+			title := line[match[2]:match[3]]
+			appendLink(href, title, match[0], match[1], false, false)
 		}
 
 		for _, match := range wikiLinkRegex.FindAllStringSubmatchIndex(line, -1) {
@@ -329,7 +413,11 @@ func (d *document) DocumentLinks() ([]documentLink, error) {
 			}
 			href := line[match[2]:match[3]]
 			hasTitle := match[4] != -1
-			appendLink(href, match[0], match[1], hasTitle, true)
+			title := ""
+			if hasTitle {
+				title = line[match[4]:match[5]]
+			}
+			appendLink(href, title, match[0], match[1], hasTitle, true)
 		}
 		if strings.Count(line, "`")%2 == 1 {
 			insideInline = !insideInline
@@ -364,10 +452,97 @@ func (d *document) IsTagPosition(position protocol.Position, noteContentParser c
 	return strutil.Contains(note.Tags, targetWord)
 }
 
+// LinkCompletionQuery returns the text typed after an opening [[ on the line of
+// the given position, to filter the link completion items.
+//
+// Returns false if the position is not inside an opened link, or inside an
+// already closed one.
+func (d *document) LinkCompletionQuery(pos protocol.Position) (string, bool) {
+	before := d.LookBehind(pos, int(pos.Character))
+	start := strings.LastIndex(before, "[[")
+	if start == -1 {
+		return "", false
+	}
+	query := before[start+2:]
+	if strings.Contains(query, "]]") {
+		return "", false
+	}
+
+	// The closing ]] right after the caret might have been auto-paired by
+	// the editor, but any content in between means the link is already
+	// complete.
+	line, _ := d.GetLine(int(pos.Line))
+	after := d.LookForward(pos, len(line))
+	if end := strings.Index(after, "]]"); end > 0 && !strings.Contains(after[:end], "[[") {
+		return "", false
+	}
+
+	return query, true
+}
+
+// IsInCodeBlock returns whether the given position is inside a fenced or
+// indented code block.
+func (d *document) IsInCodeBlock(pos protocol.Position) bool {
+	resetCodeBlockState()
+	defer resetCodeBlockState()
+
+	lines := d.GetLines()
+	inCodeBlock := false
+	for i := 0; i <= int(pos.Line) && i < len(lines); i++ {
+		inCodeBlock = isLineWithinCodeBlock(lines, i, lines[i])
+	}
+	return inCodeBlock
+}
+
+var frontmatterTagsKeyRegex = regexp.MustCompile(`^(?:tags?|keywords?)\s*:(.*)$`)
+var frontmatterListItemRegex = regexp.MustCompile(`^\s*-(?:\s|$)`)
+
+// FrontmatterTagQuery returns the partial tag typed before the given position,
+// when it is inside the tags of the YAML frontmatter, either inline
+// (`tags: [draft, wo`) or as a list (`  - wo`).
+func (d *document) FrontmatterTagQuery(pos protocol.Position) (string, bool) {
+	lineIdx := int(pos.Line)
+	if lineIdx == 0 || lineIdx >= core.FrontmatterLineCount(d.Content)-1 {
+		return "", false
+	}
+
+	before := d.LookBehind(pos, int(pos.Character))
+	if match := frontmatterTagsKeyRegex.FindStringSubmatch(before); match != nil {
+		return lastFrontmatterTag(match[1]), true
+	}
+	if !frontmatterListItemRegex.MatchString(before) {
+		return "", false
+	}
+
+	// Looks for the key owning the list item.
+	lines := d.GetLines()
+	for i := lineIdx - 1; i > 0; i-- {
+		if frontmatterListItemRegex.MatchString(lines[i]) {
+			continue
+		}
+		match := frontmatterTagsKeyRegex.FindStringSubmatch(strings.TrimRight(lines[i], "\r"))
+		if match == nil || strings.TrimSpace(match[1]) != "" {
+			return "", false
+		}
+		return lastFrontmatterTag(before[len(frontmatterListItemRegex.FindString(before)):]), true
+	}
+	return "", false
+}
+
+// lastFrontmatterTag returns the last tag of a YAML value being typed,
+// without its quotes and # prefix.
+func lastFrontmatterTag(value string) string {
+	tag := value[strings.LastIndexAny(value, " \t,[")+1:]
+	return strings.TrimLeft(tag, `"'#`)
+}
+
 type documentLink struct {
 	Href          string
 	RelativeToDir string
 	Range         protocol.Range
+	// Title is the label of the link, e.g. `title` in [title](path) or
+	// [[filename|title]]. Empty when the link doesn't have one.
+	Title string
 	// HasTitle indicates whether this link has a title information. For
 	// example [[filename]] doesn't but [[filename|title]] does.
 	HasTitle bool
@@ -0,0 +1,176 @@
+package lsp
+
+import (
+	"fmt"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestDocumentLinks(t *testing.T) {
+	doc := &document{
+		Path:    "/notebook/dir/note.md",
+		Content: "# Title\n\nSee [an article](ref/article) and [[other-note]].\nOr [[dir/third|the third]] but not `[[code]]`.\n",
+	}
+
+	links, err := doc.DocumentLinks()
+	assert.Nil(t, err)
+	assert.Equal(t, links, []documentLink{
+		{
+			Href:          "ref/article",
+			RelativeToDir: "/notebook/dir",
+			Range:         lineRange(2, 4, 29),
+			Title:         "an article",
+		},
+		{
+			Href:          "other-note",
+			RelativeToDir: "/notebook/dir",
+			Range:         lineRange(2, 34, 48),
+			IsWikiLink:    true,
+		},
+		{
+			Href:          "dir/third",
+			RelativeToDir: "/notebook/dir",
+			Range:         lineRange(3, 3, 26),
+			Title:         "the third",
+			HasTitle:      true,
+			IsWikiLink:    true,
+		},
+	})
+}
+
+func TestDocumentLinkAt(t *testing.T) {
+	doc := &document{
+		Path:    "/notebook/note.md",
+		Content: "A [link](target) and [[wiki-link|Wiki]].",
+	}
+
+	test := func(character int, expectedHref string) {
+		link, err := doc.DocumentLinkAt(protocol.Position{Line: 0, Character: protocol.UInteger(character)})
+		assert.Nil(t, err)
+		if expectedHref == "" {
+			assert.Nil(t, link)
+		} else {
+			assert.NotNil(t, link)
+			assert.Equal(t, link.Href, expectedHref)
+		}
+	}
+
+	test(0, "")
+	test(2, "target")
+	test(10, "target")
+	test(17, "")
+	test(25, "wiki-link")
+}
+
+func TestDocumentLinkCompletionQuery(t *testing.T) {
+	test := func(line string, character int, expectedQuery string, expectedOK bool) {
+		doc := &document{Content: line}
+		query, ok := doc.LinkCompletionQuery(protocol.Position{Line: 0, Character: protocol.UInteger(character)})
+		assert.Equal(t, ok, expectedOK)
+		assert.Equal(t, query, expectedQuery)
+	}
+
+	test("See [[", 6, "", true)
+	test("See [[mee", 9, "mee", true)
+	test("See [[meeting notes", 19, "meeting notes", true)
+	// The closing ]] auto-paired by the editor.
+	test("See [[mee]]", 9, "mee", true)
+	// Not inside a link.
+	test("See [single", 11, "", false)
+	test("See [[done]] and", 16, "", false)
+	// Already closed links.
+	test("See [[mee|Title]]", 9, "", false)
+	test("See [[meeting]]", 9, "", false)
+	// The caret is before another link.
+	test("See [[mee and [[other]]", 9, "mee", true)
+}
+
+func TestDocumentIsInCodeBlock(t *testing.T) {
+	doc := &document{
+		Content: "Text #tag\n\n```go\n#code\n```\n\n    #indented\nAfter #tag\n~~~\n#tilde",
+	}
+
+	test := func(line int, expected bool) {
+		assert.Equal(t, doc.IsInCodeBlock(protocol.Position{Line: protocol.UInteger(line), Character: 1}), expected)
+	}
+
+	test(0, false)
+	test(2, true)
+	test(3, true)
+	test(5, false)
+	test(6, true)
+	test(7, false)
+	test(9, true)
+	// The state of a previous scan doesn't leak in the next one.
+	test(0, false)
+}
+
+func TestDocumentFrontmatterTagQuery(t *testing.T) {
+	doc := &document{
+		Content: "---\ntitle: Note\ntags: [draft, wo\nkeywords:\n  - science\n  - \"fi\naliases:\n  - other\n---\n\ntags: [body",
+	}
+
+	test := func(line int, character int, expectedQuery string, expectedOK bool) {
+		query, ok := doc.FrontmatterTagQuery(protocol.Position{Line: protocol.UInteger(line), Character: protocol.UInteger(character)})
+		assert.Equal(t, ok, expectedOK)
+		assert.Equal(t, query, expectedQuery)
+	}
+
+	test(1, 8, "", false)
+	test(2, 16, "wo", true)
+	test(2, 7, "", true)
+	test(4, 11, "science", true)
+	test(4, 4, "", true)
+	test(5, 7, "fi", true)
+	test(7, 9, "", false)
+	// Outside the frontmatter.
+	test(10, 11, "", false)
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	test := func(content string, expected []string) {
+		doc := &document{Content: content}
+		assert.Equal(t, symbolsOutline(doc.DocumentSymbols(), ""), expected)
+	}
+
+	test("", []string{})
+	test("Text without headings\n", []string{})
+
+	test("---\ntitle: Note\n---\n\n# Title\n\nIntro\n\n### Skipped level\n\nText\n\n## Section\n\n```\n# Code\n```\n\n##\n\n# Other\n\nEnd\n\n", []string{
+		"Frontmatter 0:0-2:3",
+		"Title 4:0-18:2",
+		"  Skipped level 8:0-10:4",
+		"  Section 12:0-16:3",
+		"  ## 18:0-18:2",
+		"Other 20:0-22:3",
+	})
+
+	// The headings preceding a higher level heading are not nested.
+	test("### Deep\n## Shallow\n# Top\n#### Deeper\n", []string{
+		"Deep 0:0-0:8",
+		"Shallow 1:0-1:10",
+		"Top 2:0-3:11",
+		"  Deeper 3:0-3:11",
+	})
+}
+
+// symbolsOutline prints the name and range of the given symbols, with their
+// children indented.
+func symbolsOutline(symbols []protocol.DocumentSymbol, indent string) []string {
+	outline := []string{}
+	for _, symbol := range symbols {
+		r := symbol.Range
+		outline = append(outline, fmt.Sprintf("%s%s %d:%d-%d:%d", indent, symbol.Name, r.Start.Line, r.Start.Character, r.End.Line, r.End.Character))
+		outline = append(outline, symbolsOutline(symbol.Children, indent+"  ")...)
+	}
+	return outline
+}
+
+func lineRange(line int, start int, end int) protocol.Range {
+	return protocol.Range{
+		Start: protocol.Position{Line: protocol.UInteger(line), Character: protocol.UInteger(start)},
+		End:   protocol.Position{Line: protocol.UInteger(line), Character: protocol.UInteger(end)},
+	}
+}
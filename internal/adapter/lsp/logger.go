@@ -36,3 +36,7 @@ func (l *glspLogger) Err(err error) {
 		l.log.Debugf("zk: warning: %v", err)
 	}
 }
+
+func (l *glspLogger) Debugf(format string, v ...interface{}) {
+	l.log.Debugf("zk: "+format, v...)
+}
@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf16"
 
 	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util"
 	"github.com/zk-org/zk/internal/util/errors"
 	"github.com/zk-org/zk/internal/util/opt"
+	"github.com/zk-org/zk/internal/util/paths"
 	strutil "github.com/zk-org/zk/internal/util/strings"
 	"github.com/tliron/glsp"
 	protocol "github.com/tliron/glsp/protocol_3_16"
@@ -30,6 +33,8 @@ type Server struct {
 	fs                     core.FileStorage
 	logger                 util.Logger
 	useAdditionalTextEdits opt.Bool
+	// Paths of the workspace folders opened by the editor.
+	workspacePaths []string
 }
 
 // ServerOpts holds the options to create a new Server.
@@ -81,6 +86,21 @@ func NewServer(opts ServerOpts) *Server {
 			protocol.SetTraceValue(*params.Trace)
 		}
 
+		for _, folder := range params.WorkspaceFolders {
+			if path, err := uriToPath(folder.URI); err == nil {
+				server.workspacePaths = append(server.workspacePaths, path)
+			}
+		}
+		if len(server.workspacePaths) == 0 {
+			if params.RootURI != nil {
+				if path, err := uriToPath(*params.RootURI); err == nil {
+					server.workspacePaths = append(server.workspacePaths, path)
+				}
+			} else if params.RootPath != nil {
+				server.workspacePaths = append(server.workspacePaths, *params.RootPath)
+			}
+		}
+
 		if params.ClientInfo != nil {
 			if params.ClientInfo.Name == "Visual Studio Code" {
 				// Visual Studio Code doesn't seem to support inl
@@ -121,6 +141,8 @@ func NewServer(opts ServerOpts) *Server {
 		}
 
 		capabilities.ReferencesProvider = &protocol.ReferenceOptions{}
+		capabilities.DocumentSymbolProvider = true
+		capabilities.WorkspaceSymbolProvider = true
 
 		return protocol.InitializeResult{
 			Capabilities: capabilities,
@@ -239,9 +261,21 @@ func NewServer(opts ServerOpts) *Server {
 		}
 
 		target, err := server.noteForLink(*link, notebook)
-		if err != nil || target == nil {
+		if err != nil {
 			return nil, err
 		}
+		if target == nil {
+			if strutil.IsURL(link.Href) {
+				return nil, nil
+			}
+			return &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: fmt.Sprintf("The note `%s` does not exist.", link.Href),
+				},
+				Range: &link.Range,
+			}, nil
+		}
 
 		path, err := uriToPath(target.URI)
 		if err != nil {
@@ -255,11 +289,17 @@ func NewServer(opts ServerOpts) *Server {
 			return nil, err
 		}
 
+		preview, err := buildHoverPreview(notebook, target.Path, string(contents))
+		if err != nil {
+			return nil, err
+		}
+
 		return &protocol.Hover{
 			Contents: protocol.MarkupContent{
 				Kind:  protocol.MarkupKindMarkdown,
-				Value: string(contents),
+				Value: preview,
 			},
+			Range: &link.Range,
 		}, nil
 	}
 
@@ -304,6 +344,18 @@ func NewServer(opts ServerOpts) *Server {
 		return documentLinks, err
 	}
 
+	handler.TextDocumentDocumentSymbol = func(context *glsp.Context, params *protocol.DocumentSymbolParams) (interface{}, error) {
+		doc, ok := server.documents.Get(params.TextDocument.URI)
+		if !ok {
+			return nil, nil
+		}
+		return doc.DocumentSymbols(), nil
+	}
+
+	handler.WorkspaceSymbol = func(context *glsp.Context, params *protocol.WorkspaceSymbolParams) ([]protocol.SymbolInformation, error) {
+		return server.workspaceSymbols(params.Query)
+	}
+
 	handler.TextDocumentDefinition = func(context *glsp.Context, params *protocol.DefinitionParams) (interface{}, error) {
 		doc, ok := server.documents.Get(params.TextDocument.URI)
 		if !ok {
@@ -396,10 +448,6 @@ func NewServer(opts ServerOpts) *Server {
 	}
 
 	handler.TextDocumentCodeAction = func(context *glsp.Context, params *protocol.CodeActionParams) (interface{}, error) {
-		if isRangeEmpty(params.Range) {
-			return nil, nil
-		}
-
 		doc, ok := server.documents.Get(params.TextDocument.URI)
 		if !ok {
 			return nil, nil
@@ -408,16 +456,7 @@ func NewServer(opts ServerOpts) *Server {
 
 		actions := []protocol.CodeAction{}
 
-		addAction := func(dir string, actionTitle string) error {
-			opts := cmdNewOpts{
-				Title: doc.ContentAtRange(params.Range),
-				Dir:   dir,
-				InsertLinkAtLocation: &protocol.Location{
-					URI:   params.TextDocument.URI,
-					Range: params.Range,
-				},
-			}
-
+		addAction := func(actionTitle string, opts cmdNewOpts) error {
 			var jsonOpts map[string]interface{}
 			err := unmarshalJSON(opts, &jsonOpts)
 			if err != nil {
@@ -437,9 +476,38 @@ func NewServer(opts ServerOpts) *Server {
 			return nil
 		}
 
-		addAction(wd, "New note in current directory")
-		addAction("", "New note in top directory")
+		// Offers to create the target of a dead link under the cursor.
+		link, err := server.deadLinkAt(doc, params.Range.Start)
+		if err != nil {
+			return nil, err
+		}
+		if link != nil {
+			notebook, err := server.notebookOf(doc)
+			if err != nil {
+				return nil, err
+			}
+			opts, err := deadLinkNewOpts(notebook, *link)
+			if err != nil {
+				return nil, err
+			}
+			addAction("Create note for this link", opts)
+			opts.LinkTo = doc.Path
+			addAction("Create note and insert backlink", opts)
+		}
+
+		if !isRangeEmpty(params.Range) {
+			location := &protocol.Location{
+				URI:   params.TextDocument.URI,
+				Range: params.Range,
+			}
+			title := doc.ContentAtRange(params.Range)
+			addAction("New note in current directory", cmdNewOpts{Title: title, Dir: wd, InsertLinkAtLocation: location})
+			addAction("New note in top directory", cmdNewOpts{Title: title, InsertLinkAtLocation: location})
+		}
 
+		if len(actions) == 0 {
+			return nil, nil
+		}
 		return actions, nil
 	}
 
@@ -563,11 +631,114 @@ func (s *Server) noteForHref(href string, relativeToDir string, notebook *core.N
 	return note, err
 }
 
+// deadLinkAt returns the internal link found in the document at the given
+// position, if its target note doesn't exist.
+func (s *Server) deadLinkAt(doc *document, pos protocol.Position) (*documentLink, error) {
+	link, err := doc.DocumentLinkAt(pos)
+	if link == nil || err != nil || strutil.IsURL(link.Href) {
+		return nil, err
+	}
+
+	notebook, err := s.notebookOf(doc)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.noteForLink(*link, notebook)
+	if target != nil || err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// deadLinkDir returns the directory in which the target of the given dead
+// link should be created. Links without a directory, e.g. [[filename]], leave
+// the note at the default location of the notebook.
+func deadLinkDir(link documentLink) string {
+	dir := filepath.Dir(link.Href)
+	if dir == "." {
+		return ""
+	}
+	return filepath.Join(link.RelativeToDir, dir)
+}
+
+// deadLinkNewOpts returns the zk.new options creating the target of the given
+// dead link at its href. Wiki links usually refer to note IDs, so their href
+// is used as the `{{id}}` of the new note.
+func deadLinkNewOpts(notebook *core.Notebook, link documentLink) (cmdNewOpts, error) {
+	href := strings.SplitN(link.Href, "#", 2)[0]
+	opts := cmdNewOpts{
+		Title: link.Title,
+		Dir:   deadLinkDir(link),
+		Edit:  true,
+	}
+	if opts.Title == "" {
+		opts.Title = paths.FilenameStem(href)
+	}
+
+	ext := filepath.Ext(href)
+	if ext == "" {
+		dir, err := notebook.DirAt(opt.NewNotEmptyString(opts.Dir).OrString(notebook.Path).Unwrap())
+		if err != nil {
+			return opts, err
+		}
+		group, err := notebook.Config.GroupConfigNamed(dir.Group)
+		if err != nil {
+			return opts, err
+		}
+		ext = "." + group.Note.Extension
+	}
+
+	if link.IsWikiLink {
+		opts.ID = paths.FilenameStem(href)
+		opts.FilenameTemplate = "{{id}}" + ext
+	} else {
+		opts.FilenameTemplate = paths.FilenameStem(href) + ext
+	}
+	return opts, nil
+}
+
 type Note struct {
 	core.MinimalNote
 	URI protocol.DocumentUri
 }
 
+// hoverPreviewMaxLength is the maximum number of characters of the note
+// summary shown in a hover preview.
+const hoverPreviewMaxLength = 500
+
+// buildHoverPreview renders a short Markdown preview of a note, made of its
+// title and either the `summary` frontmatter key or its first paragraph.
+func buildHoverPreview(notebook *core.Notebook, path string, content string) (string, error) {
+	parsed, err := notebook.Parser.ParseNoteContent(content)
+	if err != nil {
+		return "", err
+	}
+
+	title := parsed.Title.OrString(path).Unwrap()
+	summary := parsed.Lead.Unwrap()
+	if loc := core.FrontmatterRegex.FindStringIndex(content); loc != nil && strings.HasPrefix(summary, "---") {
+		// The note has no title, so its lead starts with the frontmatter.
+		body, err := notebook.Parser.ParseNoteContent(content[loc[1]:])
+		if err != nil {
+			return "", err
+		}
+		summary = body.Lead.Unwrap()
+	}
+	if s, ok := parsed.Metadata["summary"].(string); ok && strings.TrimSpace(s) != "" {
+		summary = strings.TrimSpace(s)
+	}
+
+	if runes := []rune(summary); len(runes) > hoverPreviewMaxLength {
+		summary = strings.TrimSpace(string(runes[:hoverPreviewMaxLength])) + "…"
+	}
+
+	preview := "**" + title + "**"
+	if summary != "" {
+		preview += "\n\n" + summary
+	}
+	return preview, nil
+}
+
 func (s *Server) refreshDiagnosticsOfDocument(doc *document, notify glsp.NotifyFunc, delay bool) {
 	if doc.NeedsRefreshDiagnostics { // Already refreshing
 		return
@@ -644,12 +815,16 @@ func (s *Server) refreshDiagnosticsOfDocument(doc *document, notify glsp.NotifyF
 // completion started automatically when typing an identifier, or manually.
 func (s *Server) buildInvokedCompletionList(notebook *core.Notebook, doc *document, position protocol.Position) ([]protocol.CompletionItem, error) {
 	currentWord := doc.WordAt(position)
-	if strings.HasPrefix(doc.LookBehind(position, len(currentWord)+2), "[[") {
+	if _, ok := doc.LinkCompletionQuery(position); ok {
 		return s.buildLinkCompletionList(notebook, doc, position)
 	}
 
-	if doc.IsTagPosition(position, notebook.Parser) {
-		return s.buildTagCompletionList(notebook, doc.WordAt(position))
+	if query, ok := doc.FrontmatterTagQuery(position); ok {
+		return s.buildTagCompletionList(notebook, "", query)
+	}
+
+	if doc.IsTagPosition(position, notebook.Parser) && !doc.IsInCodeBlock(position) {
+		return s.buildTagCompletionList(notebook, currentWord, strings.TrimLeft(currentWord, "#:"))
 	}
 
 	return nil, nil
@@ -672,36 +847,66 @@ func (s *Server) buildTriggerCompletionList(notebook *core.Notebook, doc *docume
 
 	switch doc.LookBehind(position, 1) {
 	case "#":
-		if notebook.Config.Format.Markdown.Hashtags {
-			return s.buildTagCompletionList(notebook, "#")
+		if notebook.Config.Format.Markdown.Hashtags && !doc.IsInCodeBlock(position) {
+			return s.buildTagCompletionList(notebook, "#", "")
 		}
 	case ":":
-		if notebook.Config.Format.Markdown.ColonTags {
-			return s.buildTagCompletionList(notebook, ":")
+		if notebook.Config.Format.Markdown.ColonTags && !doc.IsInCodeBlock(position) {
+			return s.buildTagCompletionList(notebook, ":", "")
 		}
 	}
 
 	return nil, nil
 }
 
-func (s *Server) buildTagCompletionList(notebook *core.Notebook, prefix string) ([]protocol.CompletionItem, error) {
-	tags, err := notebook.FindCollections(core.CollectionKindTag, nil)
+// buildTagCompletionList suggests the existing tags matching the given query,
+// the most frequent ones first.
+func (s *Server) buildTagCompletionList(notebook *core.Notebook, prefix string, query string) ([]protocol.CompletionItem, error) {
+	tags, err := notebook.FindCollections(core.CollectionKindTag, []core.CollectionSorter{
+		{Field: core.CollectionSortNoteCount, Ascending: false},
+		{Field: core.CollectionSortName, Ascending: true},
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	var items []protocol.CompletionItem
 	for _, tag := range tags {
+		if !tagMatchesQuery(tag.Name, query) {
+			continue
+		}
 		items = append(items, protocol.CompletionItem{
 			Label:      tag.Name,
 			InsertText: s.buildInsertForTag(tag.Name, prefix, notebook.Config),
 			Detail:     stringPtr(fmt.Sprintf("%d %s", tag.NoteCount, strutil.Pluralize("note", tag.NoteCount))),
+			// Preserve the frequency order in the client.
+			SortText: stringPtr(fmt.Sprintf("%06d", len(items))),
 		})
 	}
 
 	return items, nil
 }
 
+// tagMatchesQuery returns whether the tag name or one of its hierarchical
+// components (e.g. `project` in `work/project`) starts with the given query,
+// ignoring the case.
+func tagMatchesQuery(name string, query string) bool {
+	if query == "" {
+		return true
+	}
+	name = strings.ToLower(name)
+	query = strings.ToLower(query)
+	if strings.HasPrefix(name, query) {
+		return true
+	}
+	for i, c := range name {
+		if c == '/' && strings.HasPrefix(name[i+1:], query) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Server) buildInsertForTag(name string, prefix string, config core.Config) *string {
 	switch prefix {
 	case ":":
@@ -729,18 +934,28 @@ func (s *Server) buildLinkCompletionList(notebook *core.Notebook, doc *document,
 		return nil, err
 	}
 
+	// Markdown links started with ]( are not filtered.
+	query, isWikiLink := doc.LinkCompletionQuery(position)
+	if !isWikiLink && doc.LookBehind(position, 3) != "]((" {
+		return nil, nil
+	}
+
 	notes, err := notebook.FindMinimalNotes(core.NoteFindOpts{})
 	if err != nil {
 		return nil, err
 	}
 
+	ranked := rankLinkCompletionNotes(notes, query)
+
 	var items []protocol.CompletionItem
-	for _, note := range notes {
+	for _, note := range ranked {
 		item, err := s.newCompletionItem(notebook, note, doc, position, linkFormatter, templates)
 		if err != nil {
 			s.logger.Err(err)
 			continue
 		}
+		// Preserve the ranking order in the client.
+		item.SortText = stringPtr(fmt.Sprintf("%06d", len(items)))
 
 		items = append(items, item)
 	}
@@ -748,6 +963,31 @@ func (s *Server) buildLinkCompletionList(notebook *core.Notebook, doc *document,
 	return items, nil
 }
 
+// rankLinkCompletionNotes returns the notes whose title or path fuzzy
+// matches the query, the best matches first.
+func rankLinkCompletionNotes(notes []core.MinimalNote, query string) []core.MinimalNote {
+	type rankedNote struct {
+		note  core.MinimalNote
+		score int
+	}
+
+	ranked := []rankedNote{}
+	for _, note := range notes {
+		if score, ok := core.FuzzyScore(query, note.Title+" "+note.Path); ok {
+			ranked = append(ranked, rankedNote{note: note, score: score})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+
+	res := []core.MinimalNote{}
+	for _, r := range ranked {
+		res = append(res, r.note)
+	}
+	return res
+}
+
 func newLinkFormatter(notebook *core.Notebook, doc *document, position protocol.Position) (core.LinkFormatter, error) {
 	if doc.LookBehind(position, 3) == "]((" {
 		return core.NewMarkdownLinkFormatter(notebook.Config.Format.Markdown, true)
@@ -799,6 +1039,9 @@ func (s *Server) newCompletionItem(notebook *core.Notebook, note core.MinimalNot
 			return item, err
 		}
 		item.Detail = &detail
+	} else {
+		// Show the path to disambiguate notes having the same title.
+		item.Detail = stringPtr(note.Path)
 	}
 
 	item.TextEdit, err = s.newTextEditForLink(notebook, note, doc, pos, linkFormatter)
@@ -810,11 +1053,7 @@ func (s *Server) newCompletionItem(notebook *core.Notebook, note core.MinimalNot
 	if s.useAdditionalTextEditsWithNotebook(notebook) {
 		addTextEdits := []protocol.TextEdit{}
 
-		startOffset := -2
-		if doc.LookBehind(pos, 2) != "[[" {
-			currentWord := doc.WordAt(pos)
-			startOffset = -2 - len(currentWord)
-		}
+		startOffset := -2 - linkQueryLength(doc, pos)
 
 		// Some LSP clients (e.g. VSCode) don't support deleting the trigger
 		// characters with the main TextEdit. So let's add an additional
@@ -831,16 +1070,7 @@ func (s *Server) newCompletionItem(notebook *core.Notebook, note core.MinimalNot
 }
 
 func (s *Server) newTextEditForLink(notebook *core.Notebook, note core.MinimalNote, doc *document, pos protocol.Position, linkFormatter core.LinkFormatter) (interface{}, error) {
-	path := core.NotebookPath{
-		Path:       note.Path,
-		BasePath:   notebook.Path,
-		WorkingDir: filepath.Dir(doc.Path),
-	}
-	context, err := core.NewLinkFormatterContext(path, note.Title, note.Metadata)
-	if err != nil {
-		return nil, err
-	}
-	link, err := linkFormatter(context)
+	link, err := notebook.FormatNoteLink(linkFormatter, note, filepath.Dir(doc.Path))
 	if err != nil {
 		return nil, err
 	}
@@ -848,12 +1078,7 @@ func (s *Server) newTextEditForLink(notebook *core.Notebook, note core.MinimalNo
 	// Overwrite [[ trigger directly if the additional text edits are disabled.
 	startOffset := 0
 	if !s.useAdditionalTextEditsWithNotebook(notebook) {
-		if doc.LookBehind(pos, 2) == "[[" {
-			startOffset = -2
-		} else {
-			currentWord := doc.WordAt(pos)
-			startOffset = -2 - len(currentWord) 
-		}
+		startOffset = -2 - linkQueryLength(doc, pos)
 	}
 
 	// Some LSP clients (e.g. VSCode) auto-pair brackets, so we need to
@@ -870,6 +1095,15 @@ func (s *Server) newTextEditForLink(notebook *core.Notebook, note core.MinimalNo
 	}, nil
 }
 
+// linkQueryLength returns the number of UTF-16 code units typed after the
+// opening [[ of the link being completed.
+func linkQueryLength(doc *document, pos protocol.Position) int {
+	if query, ok := doc.LinkCompletionQuery(pos); ok {
+		return len(utf16.Encode([]rune(query)))
+	}
+	return len(doc.WordAt(pos))
+}
+
 func (s *Server) useAdditionalTextEditsWithNotebook(nb *core.Notebook) bool {
 	return nb.Config.LSP.Completion.UseAdditionalTextEdits.
 		Or(s.useAdditionalTextEdits).
@@ -0,0 +1,186 @@
+package lsp
+
+import (
+	"strings"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/adapter/fs"
+	"github.com/zk-org/zk/internal/adapter/markdown"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestDeadLinkNewOpts(t *testing.T) {
+	config := core.NewDefaultConfig()
+	journal := config.RootGroupConfig()
+	journal.Paths = []string{"journal"}
+	journal.Note.Extension = "txt"
+	config.Groups = map[string]core.GroupConfig{"journal": journal}
+
+	fs, err := fs.NewFileStorage("/notebook", &util.NullLogger)
+	assert.Nil(t, err)
+	notebook := core.NewNotebook("/notebook", config, core.NotebookPorts{FS: fs})
+
+	test := func(link documentLink, expected cmdNewOpts) {
+		link.RelativeToDir = "/notebook"
+		opts, err := deadLinkNewOpts(notebook, link)
+		assert.Nil(t, err)
+		assert.Equal(t, opts, expected)
+	}
+
+	// Markdown links create the note at their href.
+	test(documentLink{Href: "new-note", Title: "A new note"}, cmdNewOpts{
+		Title:            "A new note",
+		FilenameTemplate: "new-note.md",
+		Edit:             true,
+	})
+	test(documentLink{Href: "dir/new-note.markdown#section"}, cmdNewOpts{
+		Title:            "new-note",
+		Dir:              "/notebook/dir",
+		FilenameTemplate: "new-note.markdown",
+		Edit:             true,
+	})
+
+	// The extension of the group is used when the href has none.
+	test(documentLink{Href: "journal/today"}, cmdNewOpts{
+		Title:            "today",
+		Dir:              "/notebook/journal",
+		FilenameTemplate: "today.txt",
+		Edit:             true,
+	})
+
+	// Wiki links use their href as the ID of the new note.
+	test(documentLink{Href: "new-id", IsWikiLink: true}, cmdNewOpts{
+		Title:            "new-id",
+		FilenameTemplate: "{{id}}.md",
+		ID:               "new-id",
+		Edit:             true,
+	})
+	test(documentLink{Href: "journal/new-id", Title: "Today", HasTitle: true, IsWikiLink: true}, cmdNewOpts{
+		Title:            "Today",
+		Dir:              "/notebook/journal",
+		FilenameTemplate: "{{id}}.txt",
+		ID:               "new-id",
+		Edit:             true,
+	})
+}
+
+func TestBuildHoverPreview(t *testing.T) {
+	parser := markdown.NewParser(markdown.ParserOpts{}, &util.NullLogger)
+	notebook := core.NewNotebook("/notebook", core.NewDefaultConfig(), core.NotebookPorts{NoteContentParser: parser})
+
+	test := func(content string, expected string) {
+		preview, err := buildHoverPreview(notebook, "dir/note.md", content)
+		assert.Nil(t, err)
+		assert.Equal(t, preview, expected)
+	}
+
+	test("# Title\n\nFirst paragraph\non two lines.\n\nSecond.", "**Title**\n\nFirst paragraph\non two lines.")
+	// The path is shown without title, and the lead skips the frontmatter.
+	test("---\ntags: [a]\n---\n\nLead paragraph.\n\nOther.", "**dir/note.md**\n\nLead paragraph.")
+	test("", "**dir/note.md**")
+
+	// The `summary` frontmatter key is preferred over the lead, unless blank.
+	test("---\nsummary: \" A summary \"\n---\n# Title\n\nLead", "**Title**\n\nA summary")
+	test("---\nsummary: \"  \"\n---\n# Title\n\nLead", "**Title**\n\nLead")
+
+	// Long summaries are truncated.
+	test("# Title\n\n"+strings.Repeat("word ", 200), "**Title**\n\n"+strings.TrimSpace(strings.Repeat("word ", 100))+"…")
+}
+
+func TestTagMatchesQuery(t *testing.T) {
+	test := func(name string, query string, expected bool) {
+		assert.Equal(t, tagMatchesQuery(name, query), expected)
+	}
+
+	test("work", "", true)
+	test("work", "wo", true)
+	test("Work", "wO", true)
+	test("work", "ork", false)
+	// The components of hierarchical tags are matched as well.
+	test("work/project", "proj", true)
+	test("work/project", "work/p", true)
+	test("work/project/Alpha", "alp", true)
+	test("work/project", "ject", false)
+}
+
+func TestBuildTagCompletionList(t *testing.T) {
+	index := &collectionsIndexMock{
+		collections: []core.Collection{
+			{Name: "work/project", NoteCount: 12},
+			{Name: "reading", NoteCount: 5},
+			{Name: "Projects", NoteCount: 1},
+		},
+	}
+	notebook := core.NewNotebook("/notebook", core.NewDefaultConfig(), core.NotebookPorts{NoteIndex: index})
+
+	items, err := (&Server{}).buildTagCompletionList(notebook, "#", "pro")
+	assert.Nil(t, err)
+
+	// The most frequent tags are requested first, and their order is
+	// preserved in the client.
+	assert.Equal(t, index.sorters, []core.CollectionSorter{
+		{Field: core.CollectionSortNoteCount, Ascending: false},
+		{Field: core.CollectionSortName, Ascending: true},
+	})
+	assert.Equal(t, len(items), 2)
+	assert.Equal(t, items[0].Label, "work/project")
+	assert.Equal(t, *items[0].Detail, "12 notes")
+	assert.Equal(t, *items[0].SortText, "000000")
+	assert.Equal(t, items[1].Label, "Projects")
+	assert.Equal(t, *items[1].Detail, "1 note")
+	assert.Equal(t, *items[1].SortText, "000001")
+}
+
+// collectionsIndexMock is a core.NoteIndex returning fixed collections.
+type collectionsIndexMock struct {
+	core.NoteIndex
+	collections []core.Collection
+	sorters     []core.CollectionSorter
+}
+
+func (m *collectionsIndexMock) FindCollections(kind core.CollectionKind, sorters []core.CollectionSorter) ([]core.Collection, error) {
+	m.sorters = sorters
+	return m.collections, nil
+}
+
+func TestLinkQueryLength(t *testing.T) {
+	test := func(line string, character int, expected int) {
+		doc := &document{Content: line}
+		assert.Equal(t, linkQueryLength(doc, protocol.Position{Line: 0, Character: protocol.UInteger(character)}), expected)
+	}
+
+	test("[[", 2, 0)
+	test("[[note", 6, 4)
+	// The length is counted in UTF-16 code units.
+	test("[[été 😀", 7, 5)
+	// Falls back on the word at the caret outside a link.
+	test("See note", 8, 4)
+}
+
+func TestRankLinkCompletionNotes(t *testing.T) {
+	notes := []core.MinimalNote{
+		{Path: "log/2021-01-03.md", Title: "Daily log"},
+		{Path: "dev/languages.md", Title: "Go language"},
+		{Path: "go.md", Title: "Go"},
+		{Path: "list.md", Title: "Shopping"},
+	}
+
+	paths := func(notes []core.MinimalNote) []string {
+		res := []string{}
+		for _, note := range notes {
+			res = append(res, note.Path)
+		}
+		return res
+	}
+
+	// The order of the index is kept without query.
+	assert.Equal(t, paths(rankLinkCompletionNotes(notes, "")), []string{"log/2021-01-03.md", "dev/languages.md", "go.md", "list.md"})
+	// The best matches come first and the others are dropped.
+	assert.Equal(t, paths(rankLinkCompletionNotes(notes, "go")), []string{"dev/languages.md", "go.md", "log/2021-01-03.md"})
+	assert.Equal(t, paths(rankLinkCompletionNotes(notes, "golang")), []string{"dev/languages.md"})
+	// The path is matched as well.
+	assert.Equal(t, paths(rankLinkCompletionNotes(notes, "2021")), []string{"log/2021-01-03.md"})
+}
@@ -83,25 +83,12 @@ func linkNote(notebook *core.Notebook, documents *documentStore, context *glsp.C
 		return err
 	}
 
-	path := core.NotebookPath{
-		Path:       info.note.Path,
-		BasePath:   notebook.Path,
-		WorkingDir: filepath.Dir(doc.Path),
+	target := *info.note
+	if info.title != nil {
+		target.Title = *info.title
 	}
 
-	var title *string
-	title = info.title
-
-	if title == nil {
-		title = &info.note.Title
-	}
-
-	formatterContext, err := core.NewLinkFormatterContext(path, *title, info.note.Metadata)
-	if err != nil {
-		return err
-	}
-
-	link, err := formatter(formatterContext)
+	link, err := notebook.FormatNoteLink(formatter, target, filepath.Dir(doc.Path))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,122 @@
+package lsp
+
+import (
+	"path/filepath"
+	"sort"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/core"
+)
+
+// workspaceSymbolsLimit caps the number of symbols returned when searching
+// the workspace, to keep the editor responsive on large notebooks.
+const workspaceSymbolsLimit = 100
+
+// rankedSymbol is a workspace symbol with its fuzzy score against the query.
+type rankedSymbol struct {
+	protocol.SymbolInformation
+	score int
+}
+
+// workspaceSymbols searches the titles and headings of the notes indexed in
+// the notebooks of the workspace, ranked by how well they match the query.
+func (s *Server) workspaceSymbols(query string) ([]protocol.SymbolInformation, error) {
+	ranked := []rankedSymbol{}
+	for _, notebook := range s.workspaceNotebooks() {
+		symbols, err := notebookSymbols(notebook, query)
+		if err != nil {
+			return nil, err
+		}
+		ranked = append(ranked, symbols...)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		// Shorter names are closer to the query.
+		return len(ranked[i].Name) < len(ranked[j].Name)
+	})
+	if len(ranked) > workspaceSymbolsLimit {
+		ranked = ranked[:workspaceSymbolsLimit]
+	}
+
+	symbols := []protocol.SymbolInformation{}
+	for _, symbol := range ranked {
+		symbols = append(symbols, symbol.SymbolInformation)
+	}
+	return symbols, nil
+}
+
+// workspaceNotebooks returns the notebooks containing the workspace folders
+// and the opened documents.
+func (s *Server) workspaceNotebooks() []*core.Notebook {
+	paths := append([]string{}, s.workspacePaths...)
+	for path := range s.documents.documents {
+		paths = append(paths, path)
+	}
+
+	notebooks := []*core.Notebook{}
+	found := map[string]bool{}
+	for _, path := range paths {
+		notebook, err := s.notebooks.Open(path)
+		if err != nil || found[notebook.Path] {
+			continue
+		}
+		found[notebook.Path] = true
+		notebooks = append(notebooks, notebook)
+	}
+	return notebooks
+}
+
+// notebookSymbols returns the titles and headings of the notes of the
+// notebook matching the query.
+//
+// The headings recorded in the index are first filtered with a regular
+// expression equivalent to the fuzzy query, to avoid parsing the notes.
+func notebookSymbols(notebook *core.Notebook, query string) ([]rankedSymbol, error) {
+	pattern := core.FuzzyPattern(query)
+	if pattern == "" {
+		pattern = ".*"
+	}
+	// Fetch more candidates than returned, as the shortest matches are not
+	// always the best ranked ones.
+	headings, err := notebook.FindHeadings(pattern, workspaceSymbolsLimit*5)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := []rankedSymbol{}
+	for _, match := range headings {
+		score, ok := core.FuzzyScore(query, match.Heading.Text)
+		if !ok {
+			continue
+		}
+
+		kind := protocol.SymbolKindString
+		if match.Heading.Level == 0 {
+			// The title heading of the note is reported as the note itself.
+			kind = protocol.SymbolKindFile
+		}
+		line := protocol.UInteger(match.Heading.Line)
+		container := match.Path
+
+		symbols = append(symbols, rankedSymbol{
+			SymbolInformation: protocol.SymbolInformation{
+				Name: match.Heading.Text,
+				Kind: kind,
+				Location: protocol.Location{
+					URI: pathToURI(filepath.Join(notebook.Path, match.Path)),
+					Range: protocol.Range{
+						Start: protocol.Position{Line: line},
+						End:   protocol.Position{Line: line},
+					},
+				},
+				ContainerName: &container,
+			},
+			score: score,
+		})
+	}
+
+	return symbols, nil
+}
@@ -0,0 +1,77 @@
+package lsp
+
+import (
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestNotebookSymbols(t *testing.T) {
+	index := &headingsIndexMock{
+		headings: []core.HeadingMatch{
+			{Path: "setup.md", Title: "Setup", Heading: core.Heading{Level: 0, Text: "Setup", Line: 2}},
+			{Path: "setup.md", Title: "Setup", Heading: core.Heading{Level: 2, Text: "Step by step", Line: 6}},
+			{Path: "other.md", Title: "Other", Heading: core.Heading{Level: 2, Text: "Sleep", Line: 4}},
+		},
+	}
+	notebook := core.NewNotebook("/notebook", core.NewDefaultConfig(), core.NotebookPorts{NoteIndex: index})
+
+	symbols, err := notebookSymbols(notebook, "stp")
+	assert.Nil(t, err)
+
+	// The index is searched with the equivalent regular expression, and the
+	// candidates not matching the fuzzy query are dropped.
+	assert.Equal(t, index.regex, "(?is)s.*t.*p")
+	assert.Equal(t, index.limit, workspaceSymbolsLimit*5)
+	assert.Equal(t, len(symbols), 2)
+
+	setup := "setup.md"
+	assert.Equal(t, symbols[0].SymbolInformation, protocol.SymbolInformation{
+		Name: "Setup",
+		Kind: protocol.SymbolKindFile,
+		Location: protocol.Location{
+			URI:   "file:///notebook/setup.md",
+			Range: lineRange(2, 0, 0),
+		},
+		ContainerName: &setup,
+	})
+	assert.Equal(t, symbols[1].SymbolInformation, protocol.SymbolInformation{
+		Name: "Step by step",
+		Kind: protocol.SymbolKindString,
+		Location: protocol.Location{
+			URI:   "file:///notebook/setup.md",
+			Range: lineRange(6, 0, 0),
+		},
+		ContainerName: &setup,
+	})
+}
+
+func TestNotebookSymbolsWithEmptyQuery(t *testing.T) {
+	index := &headingsIndexMock{
+		headings: []core.HeadingMatch{
+			{Path: "setup.md", Title: "Setup", Heading: core.Heading{Level: 0, Text: "Setup"}},
+		},
+	}
+	notebook := core.NewNotebook("/notebook", core.NewDefaultConfig(), core.NotebookPorts{NoteIndex: index})
+
+	symbols, err := notebookSymbols(notebook, " ")
+	assert.Nil(t, err)
+	assert.Equal(t, index.regex, ".*")
+	assert.Equal(t, len(symbols), 1)
+}
+
+// headingsIndexMock is a core.NoteIndex returning fixed headings.
+type headingsIndexMock struct {
+	core.NoteIndex
+	headings []core.HeadingMatch
+	regex    string
+	limit    int
+}
+
+func (m *headingsIndexMock) FindHeadings(regex string, limit int) ([]core.HeadingMatch, error) {
+	m.regex = regex
+	m.limit = limit
+	return m.headings, nil
+}
@@ -17,6 +17,8 @@ type Tags struct {
 	gast.BaseInline
 	// Tags in this list.
 	Tags []string
+	// Byte offset of the tags in the source.
+	Start int
 }
 
 func (n *Tags) Dump(source []byte, level int) {
@@ -79,7 +81,7 @@ func (p *hashtagParser) Trigger() []byte {
 
 func (p *hashtagParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
 	previousChar := block.PrecendingCharacter()
-	line, _ := block.PeekLine()
+	line, segment := block.PeekLine()
 
 	// A hashtag can't be directly preceded by a # or any other valid character.
 	if isValidTagChar(previousChar, '\x00') {
@@ -170,6 +172,7 @@ func (p *hashtagParser) Parse(parent ast.Node, block text.Reader, pc parser.Cont
 	return &Tags{
 		BaseInline: gast.BaseInline{},
 		Tags:       []string{tag},
+		Start:      segment.Start,
 	}
 }
 
@@ -191,7 +194,7 @@ func (p *colontagParser) Trigger() []byte {
 
 func (p *colontagParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
 	previousChar := block.PrecendingCharacter()
-	line, _ := block.PeekLine()
+	line, segment := block.PeekLine()
 
 	// A colontag can't be directly preceded by a : or any other valid character.
 	if isValidTagChar(previousChar, '\x00') {
@@ -250,6 +253,7 @@ func (p *colontagParser) Parse(parent ast.Node, block text.Reader, pc parser.Con
 	return &Tags{
 		BaseInline: gast.BaseInline{},
 		Tags:       tags,
+		Start:      segment.Start,
 	}
 }
 
@@ -0,0 +1,37 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// RenderHTML converts a Markdown document to HTML.
+//
+// Raw HTML found in the document, such as anchors, is kept as is.
+func RenderHTML(source string) (string, error) {
+	return renderHTML(source, html.WithUnsafe())
+}
+
+// RenderSafeHTML converts a Markdown document to HTML which can be embedded
+// safely in a web page.
+//
+// Raw HTML found in the document is omitted, and the links with a dangerous
+// URL scheme, such as javascript:, are emptied.
+func RenderSafeHTML(source string) (string, error) {
+	return renderHTML(source)
+}
+
+func renderHTML(source string, opts ...renderer.Option) (string, error) {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(opts...),
+	)
+
+	var out bytes.Buffer
+	err := md.Convert([]byte(source), &out)
+	return out.String(), err
+}
@@ -0,0 +1,28 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestRenderHTML(t *testing.T) {
+	html, err := RenderHTML("<a id=\"anchor\"></a>\n\n# Title\n")
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<p><a id=\"anchor\"></a></p>\n<h1>Title</h1>\n")
+}
+
+func TestRenderSafeHTML(t *testing.T) {
+	test := func(source string, expected string) {
+		html, err := RenderSafeHTML(source)
+		assert.Nil(t, err)
+		assert.Equal(t, html, expected)
+	}
+
+	test("# Title\n\nA [link](note.md).\n", "<h1>Title</h1>\n<p>A <a href=\"note.md\">link</a>.</p>\n")
+	// Raw HTML is omitted.
+	test("<script>alert(1)</script>\n", "<!-- raw HTML omitted -->\n")
+	test("Inline <img src=x onerror=alert(1)> HTML\n", "<p>Inline <!-- raw HTML omitted --> HTML</p>\n")
+	// Dangerous URLs are removed.
+	test("[link](javascript:alert(1))\n", "<p><a href=\"\">link</a></p>\n")
+}
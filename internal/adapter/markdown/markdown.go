@@ -2,6 +2,7 @@ package markdown
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"net/url"
 	"regexp"
@@ -90,21 +91,62 @@ func (p *Parser) ParseNoteContent(content string) (*core.NoteContent, error) {
 	}
 	body := parseBody(bodyStart, bytes)
 
-	tags, err := parseTags(frontmatter, root, bytes)
+	tags, tagStarts, err := parseTags(frontmatter, root, bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := parsePlain(root, bodyStart, bytes)
 	if err != nil {
 		return nil, err
 	}
 
 	return &core.NoteContent{
-		Title:    title,
-		Body:     body,
-		Lead:     parseLead(body),
-		Links:    links,
-		Tags:     tags,
-		Metadata: frontmatter.values,
+		Title:     title,
+		Body:      body,
+		Plain:     plain,
+		Lead:      parseLead(body),
+		Links:     links,
+		Tags:      tags,
+		TagStarts: tagStarts,
+		Headings:  parseHeadings(root, bytes),
+		Metadata:  frontmatter.values,
 	}, nil
 }
 
+// parseHeadings extracts the headings of the note with their anchors. The
+// headings without text are skipped.
+func parseHeadings(root ast.Node, source []byte) []core.Heading {
+	headings := []core.Heading{}
+	anchors := core.HeadingAnchors{}
+	ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if heading, ok := n.(*ast.Heading); ok && entering {
+			text := strings.TrimSpace(string(heading.Text(source)))
+			if text != "" {
+				headings = append(headings, core.Heading{
+					Level:  heading.Level,
+					Text:   text,
+					Anchor: anchors.Anchor(text),
+					Line:   headingLine(heading, source),
+				})
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return headings
+}
+
+// headingLine returns the line of the given heading in the source, starting
+// from 0.
+func headingLine(heading *ast.Heading, source []byte) int {
+	if heading.Lines().Len() == 0 {
+		return 0
+	}
+	start := heading.Lines().At(0).Start
+	return bytes.Count(source[:start], []byte("\n"))
+}
+
 // parseTitle extracts the note title with its node.
 func parseTitle(frontmatter frontmatter, root ast.Node, source []byte) (title opt.String, bodyStart int, err error) {
 	if title = frontmatter.getString("title", "Title"); !title.IsNull() {
@@ -148,6 +190,76 @@ func parseBody(startIndex int, source []byte) opt.String {
 	)
 }
 
+var plainBlankLinesRegex = regexp.MustCompile(`\n{3,}`)
+
+// parsePlain renders the body content as plain text, stripping the Markdown
+// syntax, images, code blocks and raw HTML.
+func parsePlain(root ast.Node, bodyStart int, source []byte) (opt.String, error) {
+	var out strings.Builder
+
+	err := ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			switch n.(type) {
+			case *ast.Paragraph, *ast.Heading, *ast.List:
+				out.WriteString("\n\n")
+			case *ast.TextBlock:
+				out.WriteString("\n")
+			}
+			return ast.WalkContinue, nil
+		}
+
+		// Skip the blocks located before the start of the body, such as the
+		// title heading.
+		if n.Parent() != nil && n.Parent().Kind() == ast.KindDocument && blockStart(n) < bodyStart {
+			return ast.WalkSkipChildren, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock, *ast.RawHTML, *ast.Image:
+			return ast.WalkSkipChildren, nil
+
+		case *ast.Text:
+			out.Write(n.Segment.Value(source))
+			if n.HardLineBreak() {
+				out.WriteString("\n")
+			} else if n.SoftLineBreak() {
+				out.WriteString(" ")
+			}
+
+		case *ast.String:
+			out.Write(n.Value)
+
+		case *ast.AutoLink:
+			out.Write(n.Label(source))
+
+		case *extensions.Tags:
+			out.WriteString(strings.Join(n.Tags, " "))
+		}
+
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return opt.NullString, err
+	}
+
+	plain := plainBlankLinesRegex.ReplaceAllString(out.String(), "\n\n")
+	return opt.NewNotEmptyString(strings.TrimSpace(plain)), nil
+}
+
+// blockStart returns the offset of the first line of a block node, or -1 if
+// it doesn't have any line.
+func blockStart(n ast.Node) int {
+	if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+		return n.Lines().At(0).Start
+	}
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		if start := blockStart(child); start >= 0 {
+			return start
+		}
+	}
+	return -1
+}
+
 // parseLead extracts the body content until the first blank line.
 func parseLead(body opt.String) opt.String {
 	lead := ""
@@ -163,8 +275,11 @@ func parseLead(body opt.String) opt.String {
 }
 
 // parseTags extracts tags as #hashtags, :colon:tags: or from the YAML frontmatter.
-func parseTags(frontmatter frontmatter, root ast.Node, source []byte) ([]string, error) {
+// The byte offset of the first occurrence of each tag in the content is
+// returned as well.
+func parseTags(frontmatter frontmatter, root ast.Node, source []byte) ([]string, map[string]int, error) {
 	tags := make([]string, 0)
+	starts := map[string]int{}
 
 	// Parse from YAML frontmatter, either:
 	// * a list of strings
@@ -203,17 +318,32 @@ func parseTags(frontmatter frontmatter, root ast.Node, source []byte) ([]string,
 		if tagsNode, ok := n.(*extensions.Tags); ok && entering {
 			for _, tag := range tagsNode.Tags {
 				tags = append(tags, tag)
+				if _, ok := starts[tag]; !ok {
+					starts[tag] = tagsNode.Start
+				}
 			}
 		}
 		return ast.WalkContinue, nil
 	})
 
-	return strutil.RemoveDuplicates(tags), err
+	return strutil.RemoveDuplicates(tags), starts, err
 }
 
 // parseLinks extracts outbound links from the note.
 func (p *Parser) parseLinks(root ast.Node, source []byte) ([]core.Link, error) {
 	links := make([]core.Link, 0)
+	// Offset after the last link found, to locate the next links of the
+	// same paragraph.
+	cursor := 0
+
+	start := func(n ast.Node, href []byte, snStart int, snEnd int) int {
+		if cursor < snStart {
+			cursor = snStart
+		}
+		start := linkStart(n, href, source, cursor, snEnd)
+		cursor = start + 1
+		return start
+	}
 
 	err := ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if entering {
@@ -232,6 +362,7 @@ func (p *Parser) parseLinks(root ast.Node, source []byte) ([]core.Link, error) {
 						Snippet:      snippet,
 						SnippetStart: snStart,
 						SnippetEnd:   snEnd,
+						Start:        start(n, link.Destination, snStart, snEnd),
 					})
 				}
 
@@ -247,6 +378,7 @@ func (p *Parser) parseLinks(root ast.Node, source []byte) ([]core.Link, error) {
 						Snippet:      snippet,
 						SnippetStart: snStart,
 						SnippetEnd:   snEnd,
+						Start:        start(n, []byte(href), snStart, snEnd),
 					})
 				}
 
@@ -263,6 +395,7 @@ func (p *Parser) parseLinks(root ast.Node, source []byte) ([]core.Link, error) {
 						Snippet:      snippet,
 						SnippetStart: snStart,
 						SnippetEnd:   snEnd,
+						Start:        start(n, link.Destination, snStart, snEnd),
 					})
 				}
 			}
@@ -272,6 +405,23 @@ func (p *Parser) parseLinks(root ast.Node, source []byte) ([]core.Link, error) {
 	return links, err
 }
 
+// linkStart returns the byte offset of the link node in the source. When the
+// node has no text segment, e.g. for wiki links, its href is searched in the
+// source between the from and end offsets.
+func linkStart(n ast.Node, href []byte, source []byte, from int, end int) int {
+	for child := n.FirstChild(); child != nil; child = child.FirstChild() {
+		if text, ok := child.(*ast.Text); ok {
+			return text.Segment.Start
+		}
+	}
+	if from <= end && end <= len(source) {
+		if i := bytes.Index(source[from:end], href); i != -1 {
+			return from + i
+		}
+	}
+	return from
+}
+
 func extractLines(n ast.Node, source []byte) (content string, start, end int) {
 	if n == nil {
 		return
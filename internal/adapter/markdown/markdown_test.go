@@ -88,6 +88,52 @@ Paragraph
 `, "Paragraph")
 }
 
+func TestParsePlain(t *testing.T) {
+	test := func(source string, expectedPlain string) {
+		content := parse(t, source)
+		assert.Equal(t, content.Plain, opt.NewNotEmptyString(expectedPlain))
+	}
+
+	test("", "")
+	test("# A title\n    \n", "")
+	test("Paragraph \n\n# A title", "")
+	test("Paragraph \n\n# A title\nBody", "Body")
+
+	test(
+		`---
+title: A title
+---
+
+# Heading with *emphasis*
+
+A [link](http://example.com), a [[wiki link]] and <https://autolink.com>.
+Some **strong** text with `+"`code`"+`.
+
+![An image](image.png)
+
+* item1
+* item2
+
+> Quote
+
+`+"```go\nfunc main() {}\n```"+`
+
+<div>HTML</div>
+
+Tagged #tag1 and #tag2 here.`,
+		`Heading with emphasis
+
+A link, a wiki link and https://autolink.com. Some strong text with code.
+
+item1
+item2
+
+Quote
+
+Tagged tag1 and tag2 here.`,
+	)
+}
+
 func TestParseLead(t *testing.T) {
 	test := func(source string, expectedLead string) {
 		content := parse(t, source)
@@ -154,6 +200,42 @@ Paragraph`,
 	)
 }
 
+func TestParseHeadings(t *testing.T) {
+	test := func(source string, expected []core.Heading) {
+		content := parse(t, source)
+		assert.Equal(t, content.Headings, expected)
+	}
+
+	test("", []core.Heading{})
+	test("Paragraph", []core.Heading{})
+
+	test(`---
+title: Frontmatter title
+---
+
+# Getting *started*
+
+Setext heading
+--------------
+
+## FAQ: what's new?
+
+`+"```"+`
+# Not a heading
+`+"```"+`
+
+### Getting started
+#### Getting started
+##
+`, []core.Heading{
+		{Level: 1, Text: "Getting started", Anchor: "getting-started", Line: 4},
+		{Level: 2, Text: "Setext heading", Anchor: "setext-heading", Line: 6},
+		{Level: 2, Text: "FAQ: what's new?", Anchor: "faq-whats-new", Line: 9},
+		{Level: 3, Text: "Getting started", Anchor: "getting-started-1", Line: 15},
+		{Level: 4, Text: "Getting started", Anchor: "getting-started-2", Line: 16},
+	})
+}
+
 func TestParseHashtags(t *testing.T) {
 	test := func(source string, tags []string) {
 		content := parseWithOptions(t, source, ParserOpts{
@@ -350,6 +432,30 @@ Tags: [tag1, "#tag1", tag2]
 `, []string{"tag1", "tag2", "tag3"})
 }
 
+func TestParseTagStarts(t *testing.T) {
+	test := func(source string, starts map[string]int) {
+		content := parseWithOptions(t, source, ParserOpts{
+			HashtagEnabled:      true,
+			MultiWordTagEnabled: true,
+			ColontagEnabled:     true,
+		})
+		assert.Equal(t, content.TagStarts, starts)
+	}
+
+	test("", map[string]int{})
+	test("#tag", map[string]int{"tag": 0})
+	test("A #tag and :colon:tags: then #tag", map[string]int{"tag": 2, "colon": 11, "tags": 11})
+	test("A #word tag# here", map[string]int{"word tag": 2})
+
+	// Tags only declared in the frontmatter have no offset.
+	test(`---
+tags: [tag1, tag2]
+---
+
+Body with #tag2
+`, map[string]int{"tag2": 38})
+}
+
 func TestParseLinks(t *testing.T) {
 	test := func(source string, links []core.Link) {
 		content := parse(t, source)
@@ -392,6 +498,7 @@ Neuron links with titles: [[trailing|Trailing link]]# #[[leading |  Leading link
 			Snippet:      "Heading with a [link](heading)",
 			SnippetStart: 3,
 			SnippetEnd:   33,
+			Start:        19,
 		},
 		{
 			Title:      "multiple links",
@@ -403,6 +510,7 @@ Neuron links with titles: [[trailing|Trailing link]]# #[[leading |  Leading link
 A link can have [one relation](one "rel-1") or [several relations](several "rel-1 rel-2").`,
 			SnippetStart: 35,
 			SnippetEnd:   222,
+			Start:        57,
 		},
 		{
 			Title:      "relative",
@@ -414,6 +522,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 A link can have [one relation](one "rel-1") or [several relations](several "rel-1 rel-2").`,
 			SnippetStart: 35,
 			SnippetEnd:   222,
+			Start:        111,
 		},
 		{
 			Title:      "one relation",
@@ -425,6 +534,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 A link can have [one relation](one "rel-1") or [several relations](several "rel-1 rel-2").`,
 			SnippetStart: 35,
 			SnippetEnd:   222,
+			Start:        149,
 		},
 		{
 			Title:      "several relations",
@@ -436,6 +546,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 A link can have [one relation](one "rel-1") or [several relations](several "rel-1 rel-2").`,
 			SnippetStart: 35,
 			SnippetEnd:   222,
+			Start:        180,
 		},
 		{
 			Title:        "https://inline-link.com",
@@ -446,6 +557,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "An https://inline-link.com and http://another-inline-link.com.",
 			SnippetStart: 224,
 			SnippetEnd:   286,
+			Start:        227,
 		},
 		{
 			Title:        "http://another-inline-link.com",
@@ -456,6 +568,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "An https://inline-link.com and http://another-inline-link.com.",
 			SnippetStart: 224,
 			SnippetEnd:   286,
+			Start:        255,
 		},
 		{
 			Title:        "Wiki link",
@@ -466,6 +579,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "A [[Wiki link]] is surrounded by [[2-brackets | two brackets]].",
 			SnippetStart: 288,
 			SnippetEnd:   351,
+			Start:        292,
 		},
 		{
 			Title:        "two brackets",
@@ -476,6 +590,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "A [[Wiki link]] is surrounded by [[2-brackets | two brackets]].",
 			SnippetStart: 288,
 			SnippetEnd:   351,
+			Start:        323,
 		},
 		{
 			Title:        "lien accentué",
@@ -486,6 +601,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "[[lien accentué]]",
 			SnippetStart: 353,
 			SnippetEnd:   371,
+			Start:        355,
 		},
 		{
 			Title:        `esca]]ped [chara\cters`,
@@ -496,6 +612,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      `It can contain [[esca]\]ped \[chara\\cters]].`,
 			SnippetStart: 373,
 			SnippetEnd:   418,
+			Start:        373,
 		},
 		{
 			Title:        "Folgezettel link",
@@ -506,6 +623,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "A [[[Folgezettel link]]] is surrounded by three brackets.",
 			SnippetStart: 420,
 			SnippetEnd:   477,
+			Start:        425,
 		},
 		{
 			Title:        "trailing hash",
@@ -516,6 +634,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "Neuron also supports a [[trailing hash]]# for Folgezettel links.",
 			SnippetStart: 479,
 			SnippetEnd:   543,
+			Start:        504,
 		},
 		{
 			Title:        "leading hash",
@@ -526,6 +645,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "A #[[leading hash]] is used for #uplinks.",
 			SnippetStart: 545,
 			SnippetEnd:   586,
+			Start:        550,
 		},
 		{
 			Title:        "Trailing link",
@@ -536,6 +656,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "Neuron links with titles: [[trailing|Trailing link]]# #[[leading |  Leading link]]",
 			SnippetStart: 588,
 			SnippetEnd:   670,
+			Start:        616,
 		},
 		{
 			Title:        "Leading link",
@@ -546,6 +667,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "Neuron links with titles: [[trailing|Trailing link]]# #[[leading |  Leading link]]",
 			SnippetStart: 588,
 			SnippetEnd:   670,
+			Start:        645,
 		},
 		{
 			Title:        "External links",
@@ -556,6 +678,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      `[External links](http://example.com) are marked [as such](ftp://domain).`,
 			SnippetStart: 672,
 			SnippetEnd:   744,
+			Start:        673,
 		},
 		{
 			Title:        "as such",
@@ -566,6 +689,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      `[External links](http://example.com) are marked [as such](ftp://domain).`,
 			SnippetStart: 672,
 			SnippetEnd:   744,
+			Start:        721,
 		},
 	})
 
@@ -581,6 +705,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "[foo%20bar](202110031652%20foo%20bar)",
 			SnippetStart: 0,
 			SnippetEnd:   37,
+			Start:        1,
 		},
 	})
 	test("[[202110031652%20foo%20bar]]", []core.Link{
@@ -593,6 +718,7 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 			Snippet:      "[[202110031652%20foo%20bar]]",
 			SnippetStart: 0,
 			SnippetEnd:   28,
+			Start:        2,
 		},
 	})
 }
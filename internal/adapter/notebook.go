@@ -0,0 +1,98 @@
+// Package adapter wires the adapters together to open notebooks, for both the
+// zk CLI and the public Go API.
+package adapter
+
+import (
+	"path/filepath"
+
+	"github.com/zk-org/zk/internal/adapter/handlebars"
+	hbhelpers "github.com/zk-org/zk/internal/adapter/handlebars/helpers"
+	"github.com/zk-org/zk/internal/adapter/markdown"
+	"github.com/zk-org/zk/internal/adapter/sqlite"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/date"
+	osutil "github.com/zk-org/zk/internal/util/os"
+	"github.com/zk-org/zk/internal/util/rand"
+)
+
+// NotebookFactoryOpts holds the dependencies shared by the notebooks created
+// with NewNotebookFactory.
+type NotebookFactoryOpts struct {
+	FS     core.FileStorage
+	Styler core.Styler
+	Logger util.Logger
+	Now    date.Provider
+	// Directories in which the templates are looked up, before the
+	// .zk/templates directory of the notebook.
+	TemplateDirs []string
+}
+
+// NewNotebookFactory creates a core.NotebookFactory opening notebooks indexed
+// with SQLite and parsing their notes as Markdown.
+func NewNotebookFactory(opts NotebookFactoryOpts) core.NotebookFactory {
+	return func(path string, config core.Config) (*core.Notebook, error) {
+		dbPath := filepath.Join(path, ".zk/notebook.db")
+		db, err := sqlite.Open(dbPath)
+		if err != nil {
+			return nil, err
+		}
+
+		markdownParser := markdown.NewParser(
+			markdown.ParserOpts{
+				HashtagEnabled:      config.Format.Markdown.Hashtags,
+				MultiWordTagEnabled: config.Format.Markdown.MultiwordTags,
+				ColontagEnabled:     config.Format.Markdown.ColonTags,
+			},
+			opts.Logger,
+		)
+
+		notebook := core.NewNotebook(path, config, core.NotebookPorts{
+			NoteIndex:         sqlite.NewNoteIndex(path, db, opts.Logger),
+			NoteContentParser: markdownParser,
+			// Markdown files with another extension than the notes' can be
+			// included in the index as well.
+			NoteContentParsers: map[string]core.NoteContentParser{
+				"md":       markdownParser,
+				"markdown": markdownParser,
+			},
+			TemplateLoaderFactory: func(language string) (core.TemplateLoader, error) {
+				loader := handlebars.NewLoader(handlebars.LoaderOpts{
+					LookupPaths: append(
+						append([]string{}, opts.TemplateDirs...),
+						filepath.Join(path, ".zk/templates"),
+					),
+					Styler: opts.Styler,
+					Logger: opts.Logger,
+				})
+
+				loader.RegisterLocalHelpers(opts.Now, opts.Logger)
+				loader.RegisterHelper("style", hbhelpers.NewStyleHelper(opts.Styler, opts.Logger))
+				loader.RegisterHelper("slug", hbhelpers.NewSlugHelper(language, opts.Logger))
+				loader.RegisterHelper("tag-links", hbhelpers.NewTagLinksHelper(language, opts.Logger))
+				loader.RegisterHelper("format-date", hbhelpers.NewFormatDateHelper(language, opts.Now, opts.Logger))
+				loader.RegisterHelper("elapsed", hbhelpers.NewElapsedHelper(language, opts.Now, opts.Logger))
+
+				linkFormatter, err := core.NewLinkFormatter(config.Format.Markdown, loader)
+				if err != nil {
+					return nil, err
+				}
+				loader.RegisterHelper("format-link", hbhelpers.NewLinkHelper(linkFormatter, opts.Logger))
+
+				return loader, nil
+			},
+			IDGeneratorFactory: func(opts core.IDOptions) func() string {
+				return rand.NewIDGenerator(opts)
+			},
+			FS:     opts.FS,
+			Logger: opts.Logger,
+			OSEnv: func() map[string]string {
+				return osutil.Env()
+			},
+			HTMLRenderer: markdown.RenderSafeHTML,
+			Now:          opts.Now,
+		})
+
+		return notebook, nil
+	}
+}
@@ -21,6 +21,7 @@ type CollectionDAO struct {
 	findAssociationStmt    *LazyStmt
 	createAssociationStmt  *LazyStmt
 	removeAssociationsStmt *LazyStmt
+	findStartsStmt         *LazyStmt
 }
 
 // NewCollectionDAO creates a new instance of a DAO working on the given
@@ -50,8 +51,8 @@ func NewCollectionDAO(tx Transaction, logger util.Logger) *CollectionDAO {
 
 		// Creates a new association between a note and a collection.
 		createAssociationStmt: tx.PrepareLazy(`
-			INSERT INTO notes_collections (note_id, collection_id)
-			VALUES (?, ?)
+			INSERT INTO notes_collections (note_id, collection_id, start)
+			VALUES (?, ?, ?)
 		`),
 
 		// Removes all associations for the given note.
@@ -59,6 +60,14 @@ func NewCollectionDAO(tx Transaction, logger util.Logger) *CollectionDAO {
 			DELETE FROM notes_collections
 			 WHERE note_id = ?
 		`),
+
+		// Finds the offsets of the collections of a given kind in a note.
+		findStartsStmt: tx.PrepareLazy(`
+			SELECT c.name, nc.start
+			  FROM notes_collections nc
+			  JOIN collections c ON c.id = nc.collection_id
+			 WHERE nc.note_id = ? AND c.kind = ? AND nc.start >= 0
+		`),
 	}
 }
 
@@ -85,7 +94,36 @@ func (d *CollectionDAO) FindAll(kind core.CollectionKind, sorters []core.Collect
 		 WHERE kind = ?
 		 GROUP BY c.id
 	`
+	return d.findAll(query, kind, sorters, kind)
+}
 
+// FindAllNested returns the collections of the given kind, including the
+// parents of the nested collections (e.g. `a` for `a/b`) even when they are
+// not associated with any note. The note count of a collection includes the
+// notes of its nested collections, each note being counted only once.
+func (d *CollectionDAO) FindAllNested(kind core.CollectionKind, sorters []core.CollectionSorter) ([]core.Collection, error) {
+	// The parent of a nested collection is its name until the last /,
+	// excluded. The UNION removes the duplicate (note, collection) pairs.
+	query := `
+		WITH RECURSIVE nested(note_id, name) AS (
+			SELECT nc.note_id, c.name
+			  FROM notes_collections nc
+			 INNER JOIN collections c ON c.id = nc.collection_id
+			 WHERE c.kind = ?
+			 UNION
+			SELECT note_id, RTRIM(RTRIM(name, REPLACE(name, '/', '')), '/')
+			  FROM nested
+			 WHERE INSTR(name, '/') > 0
+		)
+		SELECT (SELECT id FROM collections WHERE kind = ? AND name = c.name), c.name, COUNT(c.note_id) as count
+		  FROM nested c
+		 WHERE c.name <> ''
+		 GROUP BY c.name
+	`
+	return d.findAll(query, kind, sorters, kind, kind)
+}
+
+func (d *CollectionDAO) findAll(query string, kind core.CollectionKind, sorters []core.CollectionSorter, args ...interface{}) ([]core.Collection, error) {
 	orderTerms := []string{}
 	if sorters != nil {
 		for _, sorter := range sorters {
@@ -95,7 +133,7 @@ func (d *CollectionDAO) FindAll(kind core.CollectionKind, sorters []core.Collect
 	orderTerms = append(orderTerms, `c.name ASC`)
 	query += "ORDER BY " + strings.Join(orderTerms, ", ") + "\n"
 
-	rows, err := d.tx.Query(query, kind)
+	rows, err := d.tx.Query(query, args...)
 	if err != nil {
 		return []core.Collection{}, err
 	}
@@ -177,8 +215,9 @@ func (d *CollectionDAO) create(kind core.CollectionKind, name string) (core.Coll
 }
 
 // Associate creates a new association between a note and a collection, if it
-// does not already exist.
-func (d *CollectionDAO) Associate(noteId core.NoteID, collectionId core.CollectionID) (core.NoteCollectionID, error) {
+// does not already exist. start is the byte offset of the collection in the
+// note content, or -1 when unknown.
+func (d *CollectionDAO) Associate(noteId core.NoteID, collectionId core.CollectionID, start int) (core.NoteCollectionID, error) {
 	wrap := errors.Wrapperf("failed to associate note %d to collection %d", noteId, collectionId)
 
 	id, err := d.findAssociation(noteId, collectionId)
@@ -189,7 +228,7 @@ func (d *CollectionDAO) Associate(noteId core.NoteID, collectionId core.Collecti
 	case id.IsValid():
 		return id, nil
 	default:
-		id, err = d.createAssociation(noteId, collectionId)
+		id, err = d.createAssociation(noteId, collectionId, start)
 		return id, wrap(err)
 	}
 }
@@ -217,12 +256,12 @@ func (d *CollectionDAO) findAssociation(noteId core.NoteID, collectionId core.Co
 	}
 }
 
-func (d *CollectionDAO) createAssociation(noteId core.NoteID, collectionId core.CollectionID) (core.NoteCollectionID, error) {
+func (d *CollectionDAO) createAssociation(noteId core.NoteID, collectionId core.CollectionID, start int) (core.NoteCollectionID, error) {
 	if !noteId.IsValid() || !collectionId.IsValid() {
 		return 0, fmt.Errorf("Note ID (%d) or collection ID (%d) not valid", noteId, collectionId)
 	}
 
-	res, err := d.createAssociationStmt.Exec(noteId, collectionId)
+	res, err := d.createAssociationStmt.Exec(noteId, collectionId, start)
 	if err != nil {
 		return 0, err
 	}
@@ -235,6 +274,31 @@ func (d *CollectionDAO) createAssociation(noteId core.NoteID, collectionId core.
 	return core.NoteCollectionID(id), nil
 }
 
+// FindStarts returns the byte offset of each collection of the given kind
+// in the content of a note, when known.
+func (d *CollectionDAO) FindStarts(noteId core.NoteID, kind core.CollectionKind) (map[string]int, error) {
+	wrap := errors.Wrapperf("failed to find the %s offsets of note %d", kind, noteId)
+
+	rows, err := d.findStartsStmt.Query(noteId, kind)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	defer rows.Close()
+
+	starts := map[string]int{}
+	for rows.Next() {
+		var (
+			name  string
+			start int
+		)
+		if err := rows.Scan(&name, &start); err != nil {
+			return nil, wrap(err)
+		}
+		starts[name] = start
+	}
+	return starts, wrap(rows.Err())
+}
+
 // RemoveAssociations deletes all associations with the given note.
 func (d *CollectionDAO) RemoveAssociations(noteId core.NoteID) error {
 	if !noteId.IsValid() {
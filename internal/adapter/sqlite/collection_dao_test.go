@@ -84,10 +84,55 @@ func TestCollectionDaoFindAllSortedByNoteCount(t *testing.T) {
 	})
 }
 
+func TestCollectionDaoFindAllNested(t *testing.T) {
+	testCollectionDAO(t, func(tx Transaction, dao *CollectionDAO) {
+		associate := func(noteID core.NoteID, tag string) {
+			id, err := dao.FindOrCreate("tag", tag)
+			assert.Nil(t, err)
+			_, err = dao.Associate(noteID, id, -1)
+			assert.Nil(t, err)
+		}
+		// Note 1 is tagged with both a parent and one of its nested tags.
+		associate(1, "project")
+		associate(1, "project/alpha")
+		associate(5, "project/beta")
+		// The implicit parents have no ID.
+		associate(5, "year/2021/march")
+
+		cs, err := dao.FindAllNested("tag", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, cs, []core.Collection{
+			{ID: 2, Kind: "tag", Name: "adventure", NoteCount: 2},
+			{ID: 4, Kind: "tag", Name: "fantasy", NoteCount: 1},
+			{ID: 1, Kind: "tag", Name: "fiction", NoteCount: 1},
+			{ID: 5, Kind: "tag", Name: "history", NoteCount: 1},
+			{ID: 8, Kind: "tag", Name: "project", NoteCount: 2},
+			{ID: 9, Kind: "tag", Name: "project/alpha", NoteCount: 1},
+			{ID: 10, Kind: "tag", Name: "project/beta", NoteCount: 1},
+			// Duplicate associations are counted once.
+			{ID: 7, Kind: "tag", Name: "science", NoteCount: 2},
+			{ID: 0, Kind: "tag", Name: "year", NoteCount: 1},
+			{ID: 0, Kind: "tag", Name: "year/2021", NoteCount: 1},
+			{ID: 11, Kind: "tag", Name: "year/2021/march", NoteCount: 1},
+		})
+
+		cs, err = dao.FindAllNested("tag", []core.CollectionSorter{
+			{Field: core.CollectionSortNoteCount, Ascending: false},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, cs[:4], []core.Collection{
+			{ID: 2, Kind: "tag", Name: "adventure", NoteCount: 2},
+			{ID: 8, Kind: "tag", Name: "project", NoteCount: 2},
+			{ID: 7, Kind: "tag", Name: "science", NoteCount: 2},
+			{ID: 4, Kind: "tag", Name: "fantasy", NoteCount: 1},
+		})
+	})
+}
+
 func TestCollectionDAOAssociate(t *testing.T) {
 	testCollectionDAO(t, func(tx Transaction, dao *CollectionDAO) {
 		// Returns existing association
-		id, err := dao.Associate(core.NoteID(1), core.CollectionID(2))
+		id, err := dao.Associate(core.NoteID(1), core.CollectionID(2), -1)
 		assert.Nil(t, err)
 		assert.Equal(t, id, core.NoteCollectionID(2))
 
@@ -96,12 +141,31 @@ func TestCollectionDAOAssociate(t *testing.T) {
 		collectionId := core.CollectionID(3)
 		sql := "SELECT id FROM notes_collections WHERE note_id = ? AND collection_id = ?"
 		assertNotExistTx(t, tx, sql, noteId, collectionId)
-		_, err = dao.Associate(noteId, collectionId)
+		_, err = dao.Associate(noteId, collectionId, 12)
 		assert.Nil(t, err)
 		assertExistTx(t, tx, sql, noteId, collectionId)
 	})
 }
 
+func TestCollectionDAOFindStarts(t *testing.T) {
+	testCollectionDAO(t, func(tx Transaction, dao *CollectionDAO) {
+		// The offsets of the fixtures are unknown.
+		starts, err := dao.FindStarts(core.NoteID(1), "tag")
+		assert.Nil(t, err)
+		assert.Equal(t, starts, map[string]int{})
+
+		_, err = dao.Associate(core.NoteID(1), core.CollectionID(5), 42)
+		assert.Nil(t, err)
+		_, err = dao.Associate(core.NoteID(1), core.CollectionID(3), 12)
+		assert.Nil(t, err)
+
+		// Only the collections of the given kind are returned.
+		starts, err = dao.FindStarts(core.NoteID(1), "tag")
+		assert.Nil(t, err)
+		assert.Equal(t, starts, map[string]int{"history": 42})
+	})
+}
+
 func TestCollectionDAORemoveAssociations(t *testing.T) {
 	testCollectionDAO(t, func(tx Transaction, dao *CollectionDAO) {
 		noteId := core.NoteID(1)
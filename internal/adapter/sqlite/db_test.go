@@ -1,6 +1,8 @@
 package sqlite
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/zk-org/zk/internal/util/fixtures"
@@ -8,17 +10,35 @@ import (
 )
 
 func TestOpen(t *testing.T) {
-	_, err := Open(fixtures.Path("sample.db"))
+	db, err := Open(copySampleDB(t))
 	assert.Nil(t, err)
+	defer db.Close()
+
+	// The sample database is migrated to the latest version.
+	var version int
+	err = db.db.QueryRow("PRAGMA user_version").Scan(&version)
+	assert.Nil(t, err)
+	assert.Equal(t, version, 13)
 }
 
 func TestClose(t *testing.T) {
-	db, err := Open(fixtures.Path("sample.db"))
+	db, err := Open(copySampleDB(t))
 	assert.Nil(t, err)
 	err = db.Close()
 	assert.Nil(t, err)
 }
 
+// copySampleDB copies the sample database fixture to a temporary directory,
+// to be migrated without altering the fixture.
+func copySampleDB(t *testing.T) string {
+	content, err := os.ReadFile(fixtures.Path("sample.db"))
+	assert.Nil(t, err)
+	path := filepath.Join(t.TempDir(), "sample.db")
+	err = os.WriteFile(path, content, 0644)
+	assert.Nil(t, err)
+	return path
+}
+
 func TestMigrateFrom0(t *testing.T) {
 	db, err := OpenInMemory()
 	assert.Nil(t, err)
@@ -27,7 +47,7 @@ func TestMigrateFrom0(t *testing.T) {
 		var version int
 		err := tx.QueryRow("PRAGMA user_version").Scan(&version)
 		assert.Nil(t, err)
-		assert.Equal(t, version, 7)
+		assert.Equal(t, version, 13)
 
 		_, err = tx.Exec(`
 			INSERT INTO notes (path, sortable_path, title, body, word_count, checksum)
@@ -28,8 +28,8 @@ func NewLinkDAO(tx Transaction, logger util.Logger) *LinkDAO {
 
 		// Add a new link.
 		addLinkStmt: tx.PrepareLazy(`
-			INSERT INTO links (source_id, target_id, title, href, type, external, rels, snippet, snippet_start, snippet_end)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			INSERT INTO links (source_id, target_id, title, href, type, external, rels, snippet, snippet_start, snippet_end, start, candidate_ids)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`),
 
 		// Remove all the outbound links of a note.
@@ -52,7 +52,7 @@ func (d *LinkDAO) Add(links []core.ResolvedLink) error {
 		sourceID := noteIDToSQL(link.SourceID)
 		targetID := noteIDToSQL(link.TargetID)
 
-		_, err := d.addLinkStmt.Exec(sourceID, targetID, link.Title, link.Href, link.Type, link.IsExternal, joinLinkRels(link.Rels), link.Snippet, link.SnippetStart, link.SnippetEnd)
+		_, err := d.addLinkStmt.Exec(sourceID, targetID, link.Title, link.Href, link.Type, link.IsExternal, joinLinkRels(link.Rels), link.Snippet, link.SnippetStart, link.SnippetEnd, link.Start, joinNoteIDs(link.CandidateIDs, ","))
 		if err != nil {
 			return err
 		}
@@ -98,12 +98,18 @@ func (d *LinkDAO) FindBetweenNotes(ids []core.NoteID) ([]core.ResolvedLink, erro
 	return d.findWhere(fmt.Sprintf("source_id IN (%s) AND target_id IN (%s)", idsString, idsString))
 }
 
+// FindOfNotes returns all the links from or to the given notes.
+func (d *LinkDAO) FindOfNotes(ids []core.NoteID) ([]core.ResolvedLink, error) {
+	idsString := joinNoteIDs(ids, ",")
+	return d.findWhere(fmt.Sprintf("source_id IN (%s) OR target_id IN (%s)", idsString, idsString))
+}
+
 // findWhere returns all the links, filtered by the given where query.
 func (d *LinkDAO) findWhere(where string) ([]core.ResolvedLink, error) {
 	links := make([]core.ResolvedLink, 0)
 
 	query := `
-		SELECT id, source_id, source_path, target_id, target_path, title, href, type, external, rels, snippet, snippet_start, snippet_end
+		SELECT id, source_id, source_path, target_id, target_path, title, href, type, external, rels, snippet, snippet_start, snippet_end, start
 		  FROM resolved_links
 	`
 
@@ -133,16 +139,16 @@ func (d *LinkDAO) findWhere(where string) ([]core.ResolvedLink, error) {
 
 func (d *LinkDAO) scanLink(row RowScanner) (*core.ResolvedLink, error) {
 	var (
-		id, sourceID, snippetStart, snippetEnd     int
-		targetID                                   sql.NullInt64
-		sourcePath, title, href, linkType, snippet string
-		external                                   bool
-		targetPath, rels                           sql.NullString
+		id, sourceID, snippetStart, snippetEnd, start int
+		targetID                                      sql.NullInt64
+		sourcePath, title, href, linkType, snippet    string
+		external                                      bool
+		targetPath, rels                              sql.NullString
 	)
 
 	err := row.Scan(
 		&id, &sourceID, &sourcePath, &targetID, &targetPath, &title, &href,
-		&linkType, &external, &rels, &snippet, &snippetStart, &snippetEnd,
+		&linkType, &external, &rels, &snippet, &snippetStart, &snippetEnd, &start,
 	)
 	switch {
 	case err == sql.ErrNoRows:
@@ -165,6 +171,7 @@ func (d *LinkDAO) scanLink(row RowScanner) (*core.ResolvedLink, error) {
 				Snippet:      snippet,
 				SnippetStart: snippetStart,
 				SnippetEnd:   snippetEnd,
+				Start:        start,
 			},
 		}, nil
 	}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util"
@@ -29,6 +31,7 @@ type NoteDAO struct {
 	findIdByPathStmt       *LazyStmt
 	findIdsByPathRegexStmt *LazyStmt
 	findByIdStmt           *LazyStmt
+	findHeadingsStmt       *LazyStmt
 }
 
 // NewNoteDAO creates a new instance of a DAO working on the given database
@@ -46,14 +49,14 @@ func NewNoteDAO(tx Transaction, logger util.Logger) *NoteDAO {
 
 		// Add a new note to the index.
 		addStmt: tx.PrepareLazy(`
-			INSERT INTO notes (path, sortable_path, title, lead, body, raw_content, word_count, metadata, checksum, created, modified)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			INSERT INTO notes (path, sortable_path, title, lead, body, plain, raw_content, word_count, metadata, headings, checksum, created, modified)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`),
 
 		// Update the content of a note.
 		updateStmt: tx.PrepareLazy(`
 			UPDATE notes
-			   SET title = ?, lead = ?, body = ?, raw_content = ?, word_count = ?, metadata = ?, checksum = ?, modified = ?
+			   SET title = ?, lead = ?, body = ?, plain = ?, raw_content = ?, word_count = ?, metadata = ?, headings = ?, checksum = ?, modified = ?
 			 WHERE path = ?
 		`),
 
@@ -80,10 +83,33 @@ func NewNoteDAO(tx Transaction, logger util.Logger) *NoteDAO {
 
 		// Find a note from its ID.
 		findByIdStmt: tx.PrepareLazy(`
-			SELECT id, path, title, lead, body, raw_content, word_count, created, modified, metadata, checksum, tags, lead AS snippet
+			SELECT id, path, title, lead, body, plain, raw_content, word_count, created, modified, metadata, checksum, tags, lead AS snippet
 			  FROM notes_with_metadata
 			 WHERE id = ?
 		`),
+
+		// Find the note titles and headings matching a regex. The title
+		// heading of a note is reported as the title itself, at level 0.
+		findHeadingsStmt: tx.PrepareLazy(`
+			SELECT path, title, level, text, anchor, line FROM (
+				SELECT n.path, n.title, 0 AS level, n.title AS text, '' AS anchor,
+				       IFNULL((SELECT json_extract(h.value, '$.line')
+				                 FROM json_each(n.headings) h
+				                WHERE json_extract(h.value, '$.level') = 1
+				                  AND json_extract(h.value, '$.text') = n.title
+				                LIMIT 1), 0) AS line
+				  FROM notes n
+				 WHERE n.title <> '' AND n.title REGEXP ?1
+				 UNION ALL
+				SELECT n.path, n.title, json_extract(h.value, '$.level'), json_extract(h.value, '$.text'),
+				       json_extract(h.value, '$.anchor'), json_extract(h.value, '$.line')
+				  FROM notes n, json_each(n.headings) h
+				 WHERE NOT (json_extract(h.value, '$.level') = 1 AND json_extract(h.value, '$.text') = n.title)
+				   AND json_extract(h.value, '$.text') REGEXP ?1
+			)
+			 ORDER BY LENGTH(text), path, line
+			 LIMIT ?2
+		`),
 	}
 }
 
@@ -135,10 +161,11 @@ func (d *NoteDAO) Add(note core.Note) (core.NoteID, error) {
 	sortablePath := strings.ReplaceAll(note.Path, "/", "\x01")
 
 	metadata := d.metadataToJSON(note)
+	headings := d.headingsToJSON(note)
 	res, err := d.addStmt.Exec(
-		note.Path, sortablePath, note.Title, note.Lead, note.Body,
-		note.RawContent, note.WordCount, metadata, note.Checksum, note.Created,
-		note.Modified,
+		note.Path, sortablePath, note.Title, note.Lead, note.Body, note.Plain,
+		note.RawContent, note.WordCount, metadata, headings, note.Checksum,
+		note.Created, note.Modified,
 	)
 	if err != nil {
 		return 0, err
@@ -163,9 +190,11 @@ func (d *NoteDAO) Update(note core.Note) (core.NoteID, error) {
 	}
 
 	metadata := d.metadataToJSON(note)
+	headings := d.headingsToJSON(note)
 	_, err = d.updateStmt.Exec(
-		note.Title, note.Lead, note.Body, note.RawContent, note.WordCount,
-		metadata, note.Checksum, note.Modified, note.Path,
+		note.Title, note.Lead, note.Body, note.Plain, note.RawContent,
+		note.WordCount, metadata, headings, note.Checksum, note.Modified,
+		note.Path,
 	)
 	return id, err
 }
@@ -181,6 +210,18 @@ func (d *NoteDAO) metadataToJSON(note core.Note) string {
 	return string(json)
 }
 
+func (d *NoteDAO) headingsToJSON(note core.Note) string {
+	if note.Headings == nil {
+		return "[]"
+	}
+	json, err := json.Marshal(note.Headings)
+	if err != nil {
+		d.logger.Err(errors.Wrapf(err, "cannot serialize note headings to JSON: %s", note.Path))
+		return "[]"
+	}
+	return string(json)
+}
+
 // Remove deletes the note with the given path from the index.
 func (d *NoteDAO) Remove(path string) error {
 	id, err := d.FindIdByPath(path)
@@ -238,6 +279,31 @@ func (d *NoteDAO) findIdsByPathRegex(regex string) ([]core.NoteID, error) {
 	return ids, nil
 }
 
+// FindHeadings returns the note titles and headings matching the given regex,
+// the shortest first, up to limit of them.
+func (d *NoteDAO) FindHeadings(regex string, limit int) ([]core.HeadingMatch, error) {
+	matches := []core.HeadingMatch{}
+	rows, err := d.findHeadingsStmt.Query(regex, limit)
+	if err != nil {
+		return matches, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var match core.HeadingMatch
+		err := rows.Scan(
+			&match.Path, &match.Title, &match.Heading.Level, &match.Heading.Text,
+			&match.Heading.Anchor, &match.Heading.Line,
+		)
+		if err != nil {
+			return matches, err
+		}
+		matches = append(matches, match)
+	}
+
+	return matches, rows.Err()
+}
+
 func (d *NoteDAO) findIdWithStmt(stmt *LazyStmt, args ...interface{}) (core.NoteID, error) {
 	row, err := stmt.QueryRow(args...)
 	if err != nil {
@@ -305,6 +371,29 @@ func (d *NoteDAO) FindIdsByHref(href string, allowPartialHref bool) ([]core.Note
 	return []core.NoteID{}, nil
 }
 
+// FindAmbiguousIdsByHref returns the IDs of all the notes matching the given
+// href, if there are several of them and none is an exact match of the href.
+func (d *NoteDAO) FindAmbiguousIdsByHref(href string, allowPartialHref bool) ([]core.NoteID, error) {
+	ids, err := d.FindIdsByHref(href, allowPartialHref)
+	if len(ids) < 2 || err != nil {
+		return nil, err
+	}
+
+	// A note whose path matches exactly the href, disregarding its file
+	// extension, is not ambiguous.
+	href = regexp.QuoteMeta(strings.SplitN(href, "#", 2)[0])
+	exactRegex := "^" + href + `(\.[^/.]*)?$`
+	if allowPartialHref {
+		exactRegex = "^(.*/)?" + href + `(\.[^/.]*)?$`
+	}
+	exactIds, err := d.findIdsByPathRegex(exactRegex)
+	if len(exactIds) == 1 || err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
 func (d *NoteDAO) FindMinimal(opts core.NoteFindOpts) ([]core.MinimalNote, error) {
 	notes := make([]core.MinimalNote, 0)
 
@@ -362,6 +451,90 @@ func (d *NoteDAO) Find(opts core.NoteFindOpts) ([]core.ContextualNote, error) {
 	return notes, nil
 }
 
+// FindAmbiguousLinks returns the links of the given note which could resolve
+// to several notes, from the candidates recorded when indexing them.
+func (d *NoteDAO) FindAmbiguousLinks(id core.NoteID) ([]core.AmbiguousLink, error) {
+	links := []core.AmbiguousLink{}
+
+	rows, err := d.tx.Query(`
+		SELECT href, candidate_ids
+		  FROM links
+		 WHERE candidate_ids != '' AND source_id = ?
+		 ORDER BY id
+	`, id)
+	if err != nil {
+		return links, err
+	}
+	defer rows.Close()
+
+	hrefs := []string{}
+	linkCandidateIDs := [][]string{}
+	candidateIDs := []string{}
+	for rows.Next() {
+		var href, cids string
+		if err := rows.Scan(&href, &cids); err != nil {
+			return links, err
+		}
+		ids := strings.Split(cids, ",")
+		hrefs = append(hrefs, href)
+		linkCandidateIDs = append(linkCandidateIDs, ids)
+		candidateIDs = append(candidateIDs, ids...)
+	}
+	if err := rows.Err(); err != nil {
+		return links, err
+	}
+	if len(hrefs) == 0 {
+		return links, nil
+	}
+
+	paths, err := d.findPathsByIds(candidateIDs)
+	if err != nil {
+		return links, err
+	}
+
+	for i, href := range hrefs {
+		candidates := []string{}
+		for _, id := range linkCandidateIDs[i] {
+			// Notes removed since the link was indexed are skipped.
+			if path, ok := paths[id]; ok {
+				candidates = append(candidates, path)
+			}
+		}
+		if len(candidates) < 2 {
+			continue
+		}
+		links = append(links, core.AmbiguousLink{
+			Href:       href,
+			Candidates: candidates,
+		})
+	}
+
+	return links, nil
+}
+
+// findPathsByIds returns the paths of the given notes, indexed by their ID.
+func (d *NoteDAO) findPathsByIds(ids []string) (map[string]string, error) {
+	paths := map[string]string{}
+
+	rows, err := d.tx.Query(fmt.Sprintf(
+		"SELECT id, path FROM notes WHERE id IN (%s)", strings.Join(ids, ","),
+	))
+	if err != nil {
+		return paths, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, path string
+		if err := rows.Scan(&id, &path); err != nil {
+			return paths, err
+		}
+		paths[id] = path
+	}
+
+	return paths, rows.Err()
+}
+
 // parseListFromNullString splits a 0-separated string.
 func parseListFromNullString(str sql.NullString) []string {
 	list := []string{}
@@ -373,6 +546,17 @@ func parseListFromNullString(str sql.NullString) []string {
 	return list
 }
 
+// parseDomains parses the comma-separated list of link domains of a note,
+// sorted for a stable output.
+func parseDomains(str sql.NullString) []string {
+	if !str.Valid || str.String == "" {
+		return nil
+	}
+	domains := strutil.RemoveBlank(strings.Split(str.String, ","))
+	sort.Strings(domains)
+	return domains
+}
+
 // expandMentionsIntoMatch finds the titles associated with the notes in opts.Mention to
 // expand them into the opts.Match predicate.
 func (d *NoteDAO) expandMentionsIntoMatch(opts core.NoteFindOpts) (core.NoteFindOpts, error) {
@@ -436,6 +620,7 @@ const (
 
 func (d *NoteDAO) findRows(opts core.NoteFindOpts, selection noteSelection) (*sql.Rows, error) {
 	snippetCol := `n.lead`
+	snippetArgs := []interface{}{}
 	joinClauses := []string{}
 	whereExprs := []string{}
 	additionalOrderTerms := []string{}
@@ -523,21 +708,57 @@ func (d *NoteDAO) findRows(opts core.NoteFindOpts, selection noteSelection) (*sq
 				whereExprs = append(whereExprs, `n.raw_content LIKE '%' || ? || '%' ESCAPE '\'`)
 				args = append(args, escapeLikeTerm(match, '\\'))
 			}
-		case core.MatchStrategyFts:
+		case core.MatchStrategyFts, core.MatchStrategyPhrase, core.MatchStrategyPrefix:
+			convertQuery := fts5.ConvertQuery
+			switch opts.MatchStrategy {
+			case core.MatchStrategyPhrase:
+				convertQuery = fts5.PhraseQuery
+			case core.MatchStrategyPrefix:
+				convertQuery = fts5.PrefixQuery
+			}
+
 			snippetCol = `snippet(fts_match.notes_fts, 2, '<zk:match>', '</zk:match>', '…', 20)`
 			joinClauses = append(joinClauses, "JOIN notes_fts fts_match ON n.id = fts_match.rowid")
 			additionalOrderTerms = append(additionalOrderTerms, `bm25(fts_match.notes_fts, 1000.0, 500.0, 1.0)`)
 			for _, match := range opts.Match {
+				query := convertQuery(match)
+				if query == "" {
+					continue
+				}
 				whereExprs = append(whereExprs, "fts_match.notes_fts MATCH ?")
-				args = append(args, fts5.ConvertQuery(match))
+				args = append(args, query)
 			}
 		case core.MatchStrategyRe:
+			// The snippet shows the context of the first pattern, falling back
+			// on the lead when it matches outside of the body.
+			snippetCol = "COALESCE(NULLIF(regexp_snippet(?, n.body), ''), n.lead)"
+			snippetArgs = append(snippetArgs, opts.Match[0])
 			for _, match := range opts.Match {
 				whereExprs = append(whereExprs, "n.raw_content REGEXP ?")
 				args = append(args, match)
 			}
 			break
+		case core.MatchStrategyFuzzy:
+			for _, match := range opts.Match {
+				pattern := core.FuzzyPattern(match)
+				if pattern == "" {
+					continue
+				}
+				whereExprs = append(whereExprs, "n.title REGEXP ?")
+				args = append(args, pattern)
+			}
+		}
+	}
+
+	// Negated terms are looked up in the body column of the full-text index,
+	// so that each exclusion costs a single index query.
+	for _, match := range opts.NotMatch {
+		query := fts5.ConvertQuery(match)
+		if query == "" {
+			continue
 		}
+		whereExprs = append(whereExprs, "n.id NOT IN (SELECT rowid FROM notes_fts WHERE notes_fts MATCH ?)")
+		args = append(args, "body : ("+query+")")
 	}
 
 	if opts.IncludeHrefs != nil {
@@ -556,51 +777,73 @@ func (d *NoteDAO) findRows(opts core.NoteFindOpts, selection noteSelection) (*sq
 		opts = opts.ExcludingIDs(ids)
 	}
 
-	if opts.Tags != nil {
-		separatorRegex := regexp.MustCompile(`(\ OR\ )|\|`)
-		for _, tagsArg := range opts.Tags {
-			tags := separatorRegex.Split(tagsArg, -1)
+	// setupTagFilter adds a filter on the notes whose tags satisfy the
+	// boolean expression given in tagsArg. matchExpr returns the SQL
+	// expression matching a single tag of the expression.
+	setupTagFilter := func(tagsArg string, matchExpr func(tag string) string) error {
+		tagExpr, err := core.ParseTagExpr(tagsArg)
+		if err != nil || tagExpr == nil {
+			return err
+		}
 
-			negate := false
-			globs := make([]string, 0)
-			for _, tag := range tags {
-				tag = strings.TrimSpace(tag)
+		var convert func(expr core.TagExpr) string
+		joinTagExprs := func(exprs []core.TagExpr, op string) string {
+			sqlExprs := make([]string, 0)
+			for _, expr := range exprs {
+				sqlExprs = append(sqlExprs, convert(expr))
+			}
+			return "(" + strings.Join(sqlExprs, op) + ")"
+		}
+		convert = func(expr core.TagExpr) string {
+			switch expr := expr.(type) {
+			case core.TagExprTag:
+				return fmt.Sprintf(`n.id IN (
+SELECT note_id FROM notes_collections
+WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND %s)
+)`,
+					core.CollectionKindTag,
+					matchExpr(expr.Name),
+				)
+			case core.TagExprNot:
+				return "NOT " + convert(expr.Expr)
+			case core.TagExprAnd:
+				return joinTagExprs(expr.Exprs, " AND ")
+			case core.TagExprOr:
+				return joinTagExprs(expr.Exprs, " OR ")
+			default:
+				panic(fmt.Sprintf("unknown tag expression: %v", expr))
+			}
+		}
 
-				if strings.HasPrefix(tag, "-") {
-					negate = true
-					tag = strings.TrimPrefix(tag, "-")
-				} else if strings.HasPrefix(tag, "NOT") {
-					negate = true
-					tag = strings.TrimPrefix(tag, "NOT")
-				}
+		whereExprs = append(whereExprs, convert(tagExpr))
+		return nil
+	}
 
-				tag = strings.TrimSpace(tag)
-				if len(tag) == 0 {
-					continue
-				}
-				globs = append(globs, "t.name GLOB ?")
+	for _, tagsArg := range opts.Tags {
+		err := setupTagFilter(tagsArg, func(tag string) string {
+			if !opts.RecursiveTags {
 				args = append(args, tag)
+				return "t.name GLOB ?"
 			}
+			// Matches the tag itself and any of its nested tags.
+			args = append(args, tag, tag+"/*")
+			return "(t.name GLOB ? OR t.name GLOB ?)"
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 
-			if len(globs) == 0 {
-				continue
-			}
-			if negate && len(globs) > 1 {
-				return nil, fmt.Errorf("cannot negate a tag in a OR group: %s", tagsArg)
-			}
-
-			expr := "n.id"
-			if negate {
-				expr += " NOT"
-			}
-			expr += fmt.Sprintf(` IN (
-SELECT note_id FROM notes_collections
-WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND (%s))
-)`,
-				core.CollectionKindTag,
-				strings.Join(globs, " OR "),
-			)
-			whereExprs = append(whereExprs, expr)
+	for _, prefixArg := range opts.TagPrefixes {
+		err := setupTagFilter(prefixArg, func(prefix string) string {
+			// Matches the tag itself and any of its nested tags, without
+			// interpreting glob characters in the prefix.
+			prefix = strings.TrimSuffix(prefix, "/")
+			args = append(args, prefix, utf8.RuneCountInString(prefix)+1, prefix+"/")
+			return "(t.name = ? OR SUBSTR(t.name, 1, ?) = ?)"
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -653,6 +896,70 @@ WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND (%s))
 		)`)
 	}
 
+	if opts.AmbiguousLinks {
+		whereExprs = append(whereExprs, `n.id IN (
+			SELECT source_id FROM links WHERE candidate_ids != ''
+		)`)
+	}
+
+	if len(opts.LinkDomains) > 0 {
+		domainExprs := make([]string, 0)
+		for _, domain := range opts.LinkDomains {
+			domainExprs = append(domainExprs, "link_matches_domain(href, ?)")
+			args = append(args, domain)
+		}
+		whereExprs = append(whereExprs, `n.id IN (
+			SELECT source_id FROM links WHERE external = 1 AND (`+strings.Join(domainExprs, " OR ")+`)
+		)`)
+	}
+
+	if opts.IDMismatch {
+		// Notes without an ID in their frontmatter are always matching.
+		whereExprs = append(whereExprs, `NOT filename_has_id(n.path, IFNULL(CAST(json_extract(n.metadata, '$.id') AS TEXT), ''))`)
+	}
+
+	if len(opts.IncludeGlobs) > 0 {
+		globExprs := make([]string, 0)
+		for _, glob := range opts.IncludeGlobs {
+			globExprs = append(globExprs, "path_glob(?, n.path)")
+			args = append(args, glob)
+		}
+		whereExprs = append(whereExprs, "("+strings.Join(globExprs, " OR ")+")")
+	}
+
+	for _, glob := range opts.ExcludeGlobs {
+		whereExprs = append(whereExprs, "NOT path_glob(?, n.path)")
+		args = append(args, glob)
+	}
+
+	if pattern := core.TodoMarkersPattern(opts.TodoMarkers); pattern != "" {
+		// The plain text of the notes doesn't contain their code blocks.
+		whereExprs = append(whereExprs, "n.plain REGEXP ?")
+		args = append(args, pattern)
+	}
+
+	for _, filter := range opts.MinOccurrences {
+		whereExprs = append(whereExprs, "count_occurrences(n.plain, ?) >= ?")
+		args = append(args, filter.Term, filter.Min)
+	}
+
+	if opts.MinTagDepth > 0 || opts.MaxTagDepth > 0 {
+		depthExpr := fmt.Sprintf(`IFNULL((
+SELECT MAX(LENGTH(TRIM(t.name, '/')) - LENGTH(REPLACE(TRIM(t.name, '/'), '/', '')) + 1)
+FROM collections t JOIN notes_collections nc ON nc.collection_id = t.id
+WHERE nc.note_id = n.id AND t.kind = '%s'
+), 0)`, core.CollectionKindTag)
+
+		if opts.MinTagDepth > 0 {
+			whereExprs = append(whereExprs, depthExpr+" >= ?")
+			args = append(args, opts.MinTagDepth)
+		}
+		if opts.MaxTagDepth > 0 {
+			whereExprs = append(whereExprs, depthExpr+" <= ?")
+			args = append(args, opts.MaxTagDepth)
+		}
+	}
+
 	if opts.CreatedStart != nil {
 		whereExprs = append(whereExprs, "created >= ?")
 		args = append(args, opts.CreatedStart)
@@ -722,7 +1029,10 @@ WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND (%s))
 	if selection != noteSelectionID {
 		query += ", n.path, n.title, n.metadata"
 		if selection != noteSelectionMinimal {
-			query += fmt.Sprintf(", n.lead, n.body, n.raw_content, n.word_count, n.created, n.modified, n.checksum, n.tags, %s AS snippet", snippetCol)
+			query += fmt.Sprintf(", n.lead, n.body, n.plain, n.raw_content, n.word_count, n.created, n.modified, n.checksum, n.tags, n.headings, %s AS snippet", snippetCol)
+			// The snippet column precedes the other bound parameters.
+			args = append(snippetArgs, args...)
+			query += ", (SELECT GROUP_CONCAT(DISTINCT link_domain(l.href)) FROM links l WHERE l.source_id = n.id AND l.external = 1) AS domains"
 		}
 	}
 
@@ -744,6 +1054,12 @@ WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND (%s))
 
 	if opts.Limit > 0 {
 		query += fmt.Sprintf("LIMIT %d\n", opts.Limit)
+	} else if opts.Offset > 0 {
+		// SQLite requires a LIMIT clause to use OFFSET.
+		query += "LIMIT -1\n"
+	}
+	if opts.Offset > 0 {
+		query += fmt.Sprintf("OFFSET %d\n", opts.Offset)
 	}
 
 	// d.logger.Println(query)
@@ -794,16 +1110,18 @@ func (d *NoteDAO) scanMinimalNote(row RowScanner) (*core.MinimalNote, error) {
 
 func (d *NoteDAO) scanNote(row RowScanner) (*core.ContextualNote, error) {
 	var (
-		id, wordCount                 int
-		title, lead, body, rawContent string
-		snippets, tags                sql.NullString
-		path, metadataJSON, checksum  string
-		created, modified             time.Time
+		id, wordCount                        int
+		title, lead, body, plain, rawContent string
+		snippets, tags, domains              sql.NullString
+		path, metadataJSON, checksum         string
+		headingsJSON                         string
+		created, modified                    time.Time
 	)
 
 	err := row.Scan(
-		&id, &path, &title, &metadataJSON, &lead, &body, &rawContent,
-		&wordCount, &created, &modified, &checksum, &tags, &snippets,
+		&id, &path, &title, &metadataJSON, &lead, &body, &plain, &rawContent,
+		&wordCount, &created, &modified, &checksum, &tags, &headingsJSON,
+		&snippets, &domains,
 	)
 	switch {
 	case err == sql.ErrNoRows:
@@ -815,6 +1133,10 @@ func (d *NoteDAO) scanNote(row RowScanner) (*core.ContextualNote, error) {
 		if err != nil {
 			d.logger.Err(errors.Wrap(err, path))
 		}
+		headings, err := unmarshalHeadings(headingsJSON)
+		if err != nil {
+			d.logger.Err(errors.Wrap(err, path))
+		}
 
 		return &core.ContextualNote{
 			Snippets: parseListFromNullString(snippets),
@@ -824,10 +1146,13 @@ func (d *NoteDAO) scanNote(row RowScanner) (*core.ContextualNote, error) {
 				Title:      title,
 				Lead:       lead,
 				Body:       body,
+				Plain:      plain,
 				RawContent: rawContent,
 				WordCount:  wordCount,
 				Links:      []core.Link{},
 				Tags:       parseListFromNullString(tags),
+				Headings:   headings,
+				Domains:    parseDomains(domains),
 				Metadata:   metadata,
 				Created:    created,
 				Modified:   modified,
@@ -851,6 +1176,9 @@ func orderTerm(sorter core.NoteSorter) string {
 	case core.NoteSortPath:
 		return "n.path" + order
 	case core.NoteSortRandom:
+		if sorter.Seed != nil {
+			return fmt.Sprintf("seeded_random(n.id, %d)", *sorter.Seed)
+		}
 		return "RANDOM()"
 	case core.NoteSortTitle:
 		return "n.title" + order
@@ -902,3 +1230,16 @@ func buildMentionQuery(title, metadataJSON string) string {
 
 	return "(" + strings.Join(titles, " OR ") + ")"
 }
+
+// seededRandom returns a pseudo-random number derived from the given note ID
+// and seed, to shuffle notes in a reproducible order.
+//
+// It is exposed as a custom SQLite function as `seeded_random()`.
+func seededRandom(id int64, seed int64) int64 {
+	// SplitMix64 finalizer, see https://prng.di.unimi.it/splitmix64.c
+	z := uint64(id) + uint64(seed)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z = z ^ (z >> 31)
+	return int64(z >> 1)
+}
@@ -109,9 +109,11 @@ func TestNoteDAOAdd(t *testing.T) {
 			Title:      "Added note",
 			Lead:       "Note",
 			Body:       "Note body",
+			Plain:      "Note body",
 			RawContent: "# Added note\nNote body",
 			WordCount:  2,
 			Metadata:   map[string]interface{}{"key": "value"},
+			Headings:   []core.Heading{{Level: 1, Text: "Added note", Anchor: "added-note"}},
 			Created:    time.Date(2019, 11, 20, 20, 32, 56, 0, time.UTC),
 			Modified:   time.Date(2020, 11, 22, 16, 49, 47, 0, time.UTC),
 			Checksum:   "check",
@@ -125,16 +127,71 @@ func TestNoteDAOAdd(t *testing.T) {
 			Title:      "Added note",
 			Lead:       "Note",
 			Body:       "Note body",
+			Plain:      "Note body",
 			RawContent: "# Added note\nNote body",
 			WordCount:  2,
 			Checksum:   "check",
 			Created:    time.Date(2019, 11, 20, 20, 32, 56, 0, time.UTC),
 			Modified:   time.Date(2020, 11, 22, 16, 49, 47, 0, time.UTC),
 			Metadata:   `{"key":"value"}`,
+			Headings:   `[{"level":1,"text":"Added note","anchor":"added-note","line":0}]`,
 		})
 	})
 }
 
+func TestNoteDAOAddHeadings(t *testing.T) {
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		headings := []core.Heading{
+			{Level: 1, Text: "Title", Anchor: "title"},
+			{Level: 2, Text: "Usage", Anchor: "usage"},
+			{Level: 2, Text: "Usage", Anchor: "usage-1"},
+		}
+		_, err := dao.Add(core.Note{Path: "log/added.md", Headings: headings})
+		assert.Nil(t, err)
+
+		notes, err := dao.Find(core.NoteFindOpts{IncludeHrefs: []string{"log/added.md"}})
+		assert.Nil(t, err)
+		assert.Equal(t, len(notes), 1)
+		assert.Equal(t, notes[0].Headings, headings)
+	})
+}
+
+func TestNoteDAOFindHeadings(t *testing.T) {
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		_, err := dao.Add(core.Note{
+			Path:  "log/headings.md",
+			Title: "Release notes",
+			Headings: []core.Heading{
+				{Level: 1, Text: "Release notes", Anchor: "release-notes", Line: 3},
+				{Level: 2, Text: "Upgrading", Anchor: "upgrading", Line: 5},
+				{Level: 2, Text: "Breaking changes", Anchor: "breaking-changes", Line: 9},
+			},
+		})
+		assert.Nil(t, err)
+
+		test := func(regex string, limit int, expected []core.HeadingMatch) {
+			matches, err := dao.FindHeadings(regex, limit)
+			assert.Nil(t, err)
+			assert.Equal(t, matches, expected)
+		}
+
+		// The title heading is reported as the note title, at level 0.
+		test("(?i)release", 10, []core.HeadingMatch{
+			{Path: "log/headings.md", Title: "Release notes", Heading: core.Heading{Level: 0, Text: "Release notes", Line: 3}},
+		})
+		test("(?i)grad|chang", 10, []core.HeadingMatch{
+			{Path: "log/headings.md", Title: "Release notes", Heading: core.Heading{Level: 2, Text: "Upgrading", Anchor: "upgrading", Line: 5}},
+			{Path: "log/headings.md", Title: "Release notes", Heading: core.Heading{Level: 2, Text: "Breaking changes", Anchor: "breaking-changes", Line: 9}},
+		})
+		// The shortest matches come first.
+		test("(?i)release|grad|chang", 2, []core.HeadingMatch{
+			{Path: "log/headings.md", Title: "Release notes", Heading: core.Heading{Level: 2, Text: "Upgrading", Anchor: "upgrading", Line: 5}},
+			{Path: "log/headings.md", Title: "Release notes", Heading: core.Heading{Level: 0, Text: "Release notes", Line: 3}},
+		})
+		test("unknown", 10, []core.HeadingMatch{})
+	})
+}
+
 // Check that we can't add a duplicate note with an existing path.
 func TestNoteDAOAddExistingNote(t *testing.T) {
 	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
@@ -150,6 +207,7 @@ func TestNoteDAOUpdate(t *testing.T) {
 			Title:      "Updated note",
 			Lead:       "Updated lead",
 			Body:       "Updated body",
+			Plain:      "Updated plain",
 			RawContent: "Updated raw content",
 			Checksum:   "updated checksum",
 			Metadata:   map[string]interface{}{"updated-key": "updated-value"},
@@ -167,12 +225,14 @@ func TestNoteDAOUpdate(t *testing.T) {
 			Title:      "Updated note",
 			Lead:       "Updated lead",
 			Body:       "Updated body",
+			Plain:      "Updated plain",
 			RawContent: "Updated raw content",
 			Checksum:   "updated checksum",
 			WordCount:  42,
 			Created:    time.Date(2019, 11, 20, 20, 32, 56, 0, time.UTC),
 			Modified:   time.Date(2020, 11, 22, 16, 49, 47, 0, time.UTC),
 			Metadata:   `{"updated-key":"updated-value"}`,
+			Headings:   `[]`,
 		})
 	})
 }
@@ -346,6 +406,17 @@ func TestNoteDAOFindLimit(t *testing.T) {
 	})
 }
 
+func TestNoteDAOFindOffset(t *testing.T) {
+	testNoteDAOFindPaths(t, core.NoteFindOpts{Offset: 6}, []string{
+		"index.md",
+		"log/2021-01-04.md",
+	})
+	testNoteDAOFindPaths(t, core.NoteFindOpts{Limit: 2, Offset: 2}, []string{
+		"f39c8.md",
+		"ref/test/a.md",
+	})
+}
+
 func TestNoteDAOFindTag(t *testing.T) {
 	test := func(tags []string, expectedPaths []string) {
 		testNoteDAOFindPaths(t, core.NoteFindOpts{Tags: tags}, expectedPaths)
@@ -363,6 +434,123 @@ func TestNoteDAOFindTag(t *testing.T) {
 	test([]string{"-fiction"}, []string{"ref/test/ref.md", "ref/test/b.md", "f39c8.md", "ref/test/a.md", "log/2021-02-04.md", "index.md", "log/2021-01-04.md"})
 	test([]string{"NOT   fiction"}, []string{"ref/test/ref.md", "ref/test/b.md", "f39c8.md", "ref/test/a.md", "log/2021-02-04.md", "index.md", "log/2021-01-04.md"})
 	test([]string{"NOTfiction"}, []string{"ref/test/ref.md", "ref/test/b.md", "f39c8.md", "ref/test/a.md", "log/2021-02-04.md", "index.md", "log/2021-01-04.md"})
+	test([]string{"fiction adventure"}, []string{"log/2021-01-03.md"})
+	test([]string{"fiction AND adventure"}, []string{"log/2021-01-03.md"})
+	test([]string{"science NOT fantasy"}, []string{"ref/test/b.md"})
+	test([]string{"(fiction OR fantasy) AND NOT adventure"}, []string{"f39c8.md"})
+	test([]string{"science -(history OR fantasy)"}, []string{})
+	test([]string{"NOT fiction | adventure"}, []string{"ref/test/ref.md", "ref/test/b.md", "f39c8.md", "ref/test/a.md", "log/2021-01-03.md", "log/2021-02-04.md", "index.md", "log/2021-01-04.md"})
+}
+
+func TestNoteDAOFindTagExprError(t *testing.T) {
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		_, err := dao.Find(core.NoteFindOpts{Tags: []string{"fiction AND (adventure"}})
+		assert.Err(t, err, "invalid tag expression `fiction AND (adventure`: unclosed `(` at position 13")
+	})
+}
+
+func TestNoteDAOFindTagHierarchy(t *testing.T) {
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		collections := NewCollectionDAO(tx, &util.NullLogger)
+		add := func(path string, tags ...string) {
+			id, err := dao.Add(core.Note{
+				Path:     path,
+				Created:  time.Date(2019, 11, 20, 20, 32, 56, 0, time.UTC),
+				Modified: time.Date(2020, 11, 22, 16, 49, 47, 0, time.UTC),
+			})
+			assert.Nil(t, err)
+			for _, tag := range tags {
+				tagID, err := collections.FindOrCreate(core.CollectionKindTag, tag)
+				assert.Nil(t, err)
+				_, err = collections.Associate(id, tagID, -1)
+				assert.Nil(t, err)
+			}
+		}
+		add("proj/root.md", "project")
+		add("proj/alpha.md", "project/alpha", "urgent")
+		add("proj/beta.md", "project/beta/docs")
+		add("proj/other.md", "projects")
+
+		test := func(tags []string, recursive bool, expectedPaths []string) {
+			matches, err := dao.Find(core.NoteFindOpts{
+				Tags:          tags,
+				RecursiveTags: recursive,
+				IncludeHrefs:  []string{"proj"},
+				Sorters:       []core.NoteSorter{{Field: core.NoteSortPath, Ascending: true}},
+			})
+			assert.Nil(t, err)
+
+			actual := make([]string, 0)
+			for _, m := range matches {
+				actual = append(actual, m.Path)
+			}
+			assert.Equal(t, actual, expectedPaths)
+		}
+
+		// Tags are matched exactly by default.
+		test([]string{"project"}, false, []string{"proj/root.md"})
+		test([]string{"project/beta"}, false, []string{})
+		test([]string{"project/beta/docs"}, false, []string{"proj/beta.md"})
+		test([]string{"project*"}, false, []string{"proj/alpha.md", "proj/beta.md", "proj/other.md", "proj/root.md"})
+
+		test([]string{"project"}, true, []string{"proj/alpha.md", "proj/beta.md", "proj/root.md"})
+		test([]string{"project/beta"}, true, []string{"proj/beta.md"})
+		test([]string{"project NOT urgent"}, true, []string{"proj/beta.md", "proj/root.md"})
+	})
+}
+
+func TestNoteDAOFindTagPrefix(t *testing.T) {
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		collections := NewCollectionDAO(tx, &util.NullLogger)
+		add := func(path string, tags ...string) {
+			id, err := dao.Add(core.Note{
+				Path:     path,
+				Created:  time.Date(2019, 11, 20, 20, 32, 56, 0, time.UTC),
+				Modified: time.Date(2020, 11, 22, 16, 49, 47, 0, time.UTC),
+			})
+			assert.Nil(t, err)
+			for _, tag := range tags {
+				tagID, err := collections.FindOrCreate(core.CollectionKindTag, tag)
+				assert.Nil(t, err)
+				_, err = collections.Associate(id, tagID, -1)
+				assert.Nil(t, err)
+			}
+		}
+		add("proj/root.md", "proj")
+		add("proj/nested.md", "proj/zk/docs")
+		add("proj/other.md", "project", "area/proj")
+		add("proj/glob.md", "pr*j/a")
+
+		test := func(prefixes []string, expectedPaths []string) {
+			matches, err := dao.Find(core.NoteFindOpts{
+				TagPrefixes:  prefixes,
+				IncludeHrefs: []string{"proj"},
+				Sorters:      []core.NoteSorter{{Field: core.NoteSortPath, Ascending: true}},
+			})
+			assert.Nil(t, err)
+
+			actual := make([]string, 0)
+			for _, m := range matches {
+				actual = append(actual, m.Path)
+			}
+			assert.Equal(t, actual, expectedPaths)
+		}
+
+		test([]string{"proj"}, []string{"proj/nested.md", "proj/root.md"})
+		test([]string{"proj/"}, []string{"proj/nested.md", "proj/root.md"})
+		test([]string{"proj/zk"}, []string{"proj/nested.md"})
+		test([]string{"proj | area"}, []string{"proj/nested.md", "proj/other.md", "proj/root.md"})
+		test([]string{"-proj"}, []string{"proj/glob.md", "proj/other.md"})
+		test([]string{"proj", "NOT proj/zk"}, []string{"proj/root.md"})
+		// Glob characters are matched literally.
+		test([]string{"pr*j"}, []string{"proj/glob.md"})
+	})
+}
+
+func TestNoteDAOFindTagDepth(t *testing.T) {
+	testNoteDAOFindPaths(t, core.NoteFindOpts{MinTagDepth: 1}, []string{"ref/test/b.md", "f39c8.md", "log/2021-01-03.md"})
+	testNoteDAOFindPaths(t, core.NoteFindOpts{MinTagDepth: 2}, []string{})
+	testNoteDAOFindPaths(t, core.NoteFindOpts{MaxTagDepth: 1, Limit: 3}, []string{"ref/test/ref.md", "ref/test/b.md", "f39c8.md"})
 }
 
 func TestNoteDAOFindMatch(t *testing.T) {
@@ -403,6 +591,7 @@ func TestNoteDAOFindMatch(t *testing.T) {
 					WordCount:  3,
 					Links:      []core.Link{},
 					Tags:       []string{"fiction", "adventure"},
+					Domains:    []string{"domain.com"},
 					Metadata: map[string]interface{}{
 						"author": "Dom",
 					},
@@ -502,6 +691,95 @@ func TestNoteDAOFindExactMatch(t *testing.T) {
 	test(`[exact% ch\ar_acters]`, []string{"ref/test/a.md"})
 }
 
+func TestNoteDAOFindPhraseMatch(t *testing.T) {
+	test := func(match string, expected []string) {
+		testNoteDAOFindPaths(t,
+			core.NoteFindOpts{
+				Match:         []string{match},
+				MatchStrategy: core.MatchStrategyPhrase,
+			},
+			expected,
+		)
+	}
+
+	test("lot of content", []string{"log/2021-01-03.md"})
+	// The words must be in the same order.
+	test("content of lot", []string{})
+	// FTS operators are matched literally.
+	test("lot OR nothing", []string{})
+}
+
+func TestNoteDAOFindPrefixMatch(t *testing.T) {
+	test := func(match string, expected []string) {
+		testNoteDAOFindPaths(t,
+			core.NoteFindOpts{
+				Match:         []string{match},
+				MatchStrategy: core.MatchStrategyPrefix,
+			},
+			expected,
+		)
+	}
+
+	test("zettel", []string{"index.md"})
+	test("zettel*", []string{"index.md"})
+	// Every prefix must match.
+	test("zettel ind", []string{"index.md"})
+	test("zettel dail", []string{})
+}
+
+func TestNoteDAOFindNotMatch(t *testing.T) {
+	test := func(opts core.NoteFindOpts, expected []string) {
+		opts.Sorters = []core.NoteSorter{{Field: core.NoteSortPath, Ascending: true}}
+		testNoteDAOFindPaths(t, opts, expected)
+	}
+
+	test(core.NoteFindOpts{NotMatch: []string{"daily"}}, []string{
+		"f39c8.md", "index.md", "ref/test/a.md", "ref/test/b.md", "ref/test/ref.md",
+	})
+	// Every term is excluded.
+	test(core.NoteFindOpts{NotMatch: []string{"daily", "directory OR zettelkasten"}}, []string{
+		"f39c8.md", "ref/test/a.md", "ref/test/ref.md",
+	})
+	// Only the body is searched, "Daily note" is the title of a note.
+	test(core.NoteFindOpts{NotMatch: []string{"lot"}, Match: []string{"daily"}, MatchStrategy: core.MatchStrategyFts}, []string{
+		"log/2021-01-04.md", "log/2021-02-04.md",
+	})
+	test(core.NoteFindOpts{NotMatch: []string{""}, Match: []string{"daily"}, MatchStrategy: core.MatchStrategyFts}, []string{
+		"log/2021-01-03.md", "log/2021-01-04.md", "log/2021-02-04.md",
+	})
+}
+
+func TestNoteDAOFindRegexMatch(t *testing.T) {
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		notes, err := dao.Find(core.NoteFindOpts{
+			Match:         []string{"(?i)LOT.*content"},
+			MatchStrategy: core.MatchStrategyRe,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, len(notes), 1)
+		assert.Equal(t, notes[0].Path, "log/2021-01-03.md")
+		assert.Equal(t, notes[0].Snippets, []string{"A daily note\n\nWith <zk:match>lot of content</zk:match>"})
+
+		// Matching outside of the body shows the lead.
+		notes, err = dao.Find(core.NoteFindOpts{
+			Match:         []string{"^# Index"},
+			MatchStrategy: core.MatchStrategyRe,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, len(notes), 1)
+		assert.Equal(t, notes[0].Snippets, []string{"Index of the Zettelkasten"})
+
+		// The snippet pattern is bound before the other parameters.
+		notes, err = dao.Find(core.NoteFindOpts{
+			Match:         []string{"it's|lot of content", "Daily"},
+			MatchStrategy: core.MatchStrategyRe,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, len(notes), 1)
+		assert.Equal(t, notes[0].Snippets, []string{"A daily note\n\nWith <zk:match>lot of content</zk:match>"})
+	})
+}
+
 func TestNoteDAOFindMentionRequiresFtsMatchStrategy(t *testing.T) {
 	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
 		_, err := dao.Find(core.NoteFindOpts{
@@ -591,6 +869,33 @@ func TestNoteDAOFindExcludingMultiplePaths(t *testing.T) {
 	)
 }
 
+func TestNoteDAOFindIncludingGlobs(t *testing.T) {
+	testNoteDAOFindPaths(t,
+		core.NoteFindOpts{IncludeGlobs: []string{"log/*-01-*.md"}},
+		[]string{"log/2021-01-03.md", "log/2021-01-04.md"},
+	)
+	// `**` matches any number of directories, and the globs are unioned.
+	testNoteDAOFindPaths(t,
+		core.NoteFindOpts{IncludeGlobs: []string{"ref/**/a.md", "*.md"}},
+		[]string{"f39c8.md", "ref/test/a.md", "index.md"},
+	)
+}
+
+func TestNoteDAOFindExcludingGlobs(t *testing.T) {
+	testNoteDAOFindPaths(t,
+		core.NoteFindOpts{ExcludeGlobs: []string{"ref/**", "log/*-01-*.md"}},
+		[]string{"f39c8.md", "log/2021-02-04.md", "index.md"},
+	)
+	// The excluded globs are subtracted from the included ones.
+	testNoteDAOFindPaths(t,
+		core.NoteFindOpts{
+			IncludeGlobs: []string{"log/**"},
+			ExcludeGlobs: []string{"**/*-04.md"},
+		},
+		[]string{"log/2021-01-03.md"},
+	)
+}
+
 func TestNoteDAOFindMentions(t *testing.T) {
 	testNoteDAOFind(t,
 		core.NoteFindOpts{
@@ -700,6 +1005,7 @@ func TestNoteDAOFindMentionedBy(t *testing.T) {
 					WordCount:  3,
 					Links:      []core.Link{},
 					Tags:       []string{"fiction", "adventure"},
+					Domains:    []string{"domain.com"},
 					Metadata: map[string]interface{}{
 						"author": "Dom",
 					},
@@ -841,6 +1147,7 @@ func TestNoteDAOFindLinkedByWithSnippets(t *testing.T) {
 					WordCount:  3,
 					Links:      []core.Link{},
 					Tags:       []string{"fiction", "adventure"},
+					Domains:    []string{"domain.com"},
 					Metadata: map[string]interface{}{
 						"author": "Dom",
 					},
@@ -965,6 +1272,125 @@ func TestNoteDAOFindOrphan(t *testing.T) {
 	)
 }
 
+func TestNoteDAOFindLinkDomains(t *testing.T) {
+	test := func(domains []string, expected []string) {
+		testNoteDAOFindPaths(t, core.NoteFindOpts{LinkDomains: domains}, expected)
+	}
+
+	test([]string{"domain.com"}, []string{"log/2021-01-03.md"})
+	test([]string{"www.Domain.com"}, []string{"log/2021-01-03.md"})
+	test([]string{"other.com", "domain.com"}, []string{"log/2021-01-03.md"})
+	test([]string{"sub.domain.com"}, []string{})
+	test([]string{"main.com"}, []string{})
+}
+
+func TestNoteDAOFindIDMismatch(t *testing.T) {
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		add := func(path string, metadata map[string]interface{}) {
+			_, err := dao.Add(core.Note{
+				Path:     path,
+				Metadata: metadata,
+				Created:  time.Date(2019, 11, 20, 20, 32, 56, 0, time.UTC),
+				Modified: time.Date(2020, 11, 22, 16, 49, 47, 0, time.UTC),
+			})
+			assert.Nil(t, err)
+		}
+		add("id/i2hn8-a-concept.md", map[string]interface{}{"id": "i2hn8"})
+		add("id/renamed-concept.md", map[string]interface{}{"id": "o3fk2"})
+		add("id/200911172034 A note.md", map[string]interface{}{"id": 200911172034})
+		add("id/A renamed note.md", map[string]interface{}{"id": 200911172035})
+
+		matches, err := dao.Find(core.NoteFindOpts{
+			IDMismatch: true,
+			Sorters:    []core.NoteSorter{{Field: core.NoteSortPath, Ascending: true}},
+		})
+		assert.Nil(t, err)
+
+		actual := make([]string, 0)
+		for _, m := range matches {
+			actual = append(actual, m.Path)
+		}
+		// The fixtures without an ID in their frontmatter are not reported.
+		assert.Equal(t, actual, []string{"id/A renamed note.md", "id/renamed-concept.md"})
+	})
+}
+
+func TestNoteDAOFindTodoMarkers(t *testing.T) {
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		add := func(path string, plain string) {
+			_, err := dao.Add(core.Note{
+				Path:     path,
+				Body:     plain,
+				Plain:    plain,
+				Created:  time.Date(2019, 11, 20, 20, 32, 56, 0, time.UTC),
+				Modified: time.Date(2020, 11, 22, 16, 49, 47, 0, time.UTC),
+			})
+			assert.Nil(t, err)
+		}
+		add("todo/a.md", "TODO: write this note")
+		add("todo/b.md", "Fix the typo FIXME")
+		add("todo/c.md", "Nothing todo, TODOS are not markers")
+
+		test := func(markers []string, expected []string) {
+			matches, err := dao.Find(core.NoteFindOpts{
+				IncludeHrefs: []string{"todo"},
+				TodoMarkers:  markers,
+				Sorters:      []core.NoteSorter{{Field: core.NoteSortPath, Ascending: true}},
+			})
+			assert.Nil(t, err)
+
+			actual := make([]string, 0)
+			for _, m := range matches {
+				actual = append(actual, m.Path)
+			}
+			assert.Equal(t, actual, expected)
+		}
+
+		test([]string{"TODO", "FIXME"}, []string{"todo/a.md", "todo/b.md"})
+		test([]string{"FIXME"}, []string{"todo/b.md"})
+		// Without markers, the notes are not filtered.
+		test([]string{}, []string{"todo/a.md", "todo/b.md", "todo/c.md"})
+	})
+}
+
+func TestNoteDAOFindMinOccurrences(t *testing.T) {
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		add := func(path string, plain string) {
+			_, err := dao.Add(core.Note{
+				Path:     path,
+				Body:     plain,
+				Plain:    plain,
+				Created:  time.Date(2019, 11, 20, 20, 32, 56, 0, time.UTC),
+				Modified: time.Date(2020, 11, 22, 16, 49, 47, 0, time.UTC),
+			})
+			assert.Nil(t, err)
+		}
+		add("occ/a.md", "Rust, rust and RUST")
+		add("occ/b.md", "Rust and Go")
+		add("occ/c.md", "Trust the Rustaceans")
+
+		test := func(filters []core.OccurrencesFilter, expected []string) {
+			matches, err := dao.Find(core.NoteFindOpts{
+				IncludeHrefs:   []string{"occ"},
+				MinOccurrences: filters,
+				Sorters:        []core.NoteSorter{{Field: core.NoteSortPath, Ascending: true}},
+			})
+			assert.Nil(t, err)
+
+			actual := make([]string, 0)
+			for _, m := range matches {
+				actual = append(actual, m.Path)
+			}
+			assert.Equal(t, actual, expected)
+		}
+
+		test([]core.OccurrencesFilter{{Term: "rust", Min: 1}}, []string{"occ/a.md", "occ/b.md"})
+		test([]core.OccurrencesFilter{{Term: "rust", Min: 2}}, []string{"occ/a.md"})
+		test([]core.OccurrencesFilter{{Term: "rust", Min: 1}, {Term: "go", Min: 1}}, []string{"occ/b.md"})
+		test([]core.OccurrencesFilter{}, []string{"occ/a.md", "occ/b.md", "occ/c.md"})
+	})
+}
+
 func TestNoteDAOFindCreatedOn(t *testing.T) {
 	start := time.Date(2020, 11, 22, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2020, 11, 23, 0, 0, 0, 0, time.UTC)
@@ -1084,6 +1510,28 @@ func TestNoteDAOFindSortWordCount(t *testing.T) {
 	})
 }
 
+func TestNoteDAOFindSortRandomWithSeed(t *testing.T) {
+	seed := int64(42)
+	opts := core.NoteFindOpts{
+		Sorters: []core.NoteSorter{{Field: core.NoteSortRandom, Seed: &seed}},
+	}
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		findPaths := func() []string {
+			matches, err := dao.Find(opts)
+			assert.Nil(t, err)
+			paths := make([]string, 0)
+			for _, m := range matches {
+				paths = append(paths, m.Path)
+			}
+			return paths
+		}
+
+		paths := findPaths()
+		assert.Equal(t, len(paths), 8)
+		assert.Equal(t, findPaths(), paths)
+	})
+}
+
 func testNoteDAOFindSort(t *testing.T, field core.NoteSortField, ascending bool, expected []string) {
 	testNoteDAOFindPaths(t,
 		core.NoteFindOpts{
@@ -1127,18 +1575,19 @@ func testNoteDAOWithFixtures(t *testing.T, fixtures string, callback func(tx Tra
 }
 
 type noteRow struct {
-	Path, Title, Lead, Body, RawContent, Checksum, Metadata string
-	WordCount                                               int
-	Created, Modified                                       time.Time
+	Path, Title, Lead, Body, Plain, RawContent, Checksum, Metadata string
+	Headings                                                       string
+	WordCount                                                      int
+	Created, Modified                                              time.Time
 }
 
 func queryNoteRow(tx Transaction, where string) (noteRow, error) {
 	var row noteRow
 	err := tx.QueryRow(fmt.Sprintf(`
-		SELECT path, title, lead, body, raw_content, word_count, checksum, created, modified, metadata
+		SELECT path, title, lead, body, plain, raw_content, word_count, checksum, created, modified, metadata, headings
 		  FROM notes
 		 WHERE %v
-	`, where)).Scan(&row.Path, &row.Title, &row.Lead, &row.Body, &row.RawContent, &row.WordCount, &row.Checksum, &row.Created, &row.Modified, &row.Metadata)
+	`, where)).Scan(&row.Path, &row.Title, &row.Lead, &row.Body, &row.Plain, &row.RawContent, &row.WordCount, &row.Checksum, &row.Created, &row.Modified, &row.Metadata, &row.Headings)
 	return row, err
 }
 
@@ -22,6 +22,7 @@ type NoteIndex struct {
 }
 
 type dao struct {
+	tx          Transaction
 	notes       *NoteDAO
 	links       *LinkDAO
 	collections *CollectionDAO
@@ -77,6 +78,24 @@ func (ni *NoteIndex) findLinkMatch(dao *dao, baseDir string, href string, linkTy
 	return dao.notes.FindIdByHref(href, allowPartialMatch)
 }
 
+// findLinkCandidates returns the notes an ambiguous link could resolve to,
+// following the same rules as findLinkMatch.
+func (ni *NoteIndex) findLinkCandidates(dao *dao, baseDir string, href string, linkType core.LinkType) ([]core.NoteID, error) {
+	if strutil.IsURL(href) {
+		return nil, nil
+	}
+
+	if relHref, err := ni.relNotebookPath(baseDir, href); err == nil {
+		id, _ := dao.notes.FindIdByHref(relHref, false)
+		if id.IsValid() {
+			return dao.notes.FindAmbiguousIdsByHref(relHref, false)
+		}
+	}
+
+	allowPartialMatch := (linkType == core.LinkTypeWikiLink)
+	return dao.notes.FindAmbiguousIdsByHref(href, allowPartialMatch)
+}
+
 func (ni *NoteIndex) findPathMatch(dao *dao, baseDir string, href string) (core.NoteID, error) {
 	href, err := ni.relNotebookPath(baseDir, href)
 	if err != nil {
@@ -94,6 +113,42 @@ func (ni *NoteIndex) FindLinksBetweenNotes(ids []core.NoteID) (links []core.Reso
 	return
 }
 
+// FindLinksOfNotes implements core.NoteIndex.
+func (ni *NoteIndex) FindLinksOfNotes(ids []core.NoteID) (links []core.ResolvedLink, err error) {
+	err = ni.commit(func(dao *dao) error {
+		links, err = dao.links.FindOfNotes(ids)
+		return err
+	})
+	return
+}
+
+// FindTagStarts implements core.NoteIndex.
+func (ni *NoteIndex) FindTagStarts(id core.NoteID) (starts map[string]int, err error) {
+	err = ni.commit(func(dao *dao) error {
+		starts, err = dao.collections.FindStarts(id, core.CollectionKindTag)
+		return err
+	})
+	return
+}
+
+// FindAmbiguousLinks implements core.NoteIndex.
+func (ni *NoteIndex) FindAmbiguousLinks(id core.NoteID) (links []core.AmbiguousLink, err error) {
+	err = ni.commit(func(dao *dao) error {
+		links, err = dao.notes.FindAmbiguousLinks(id)
+		return err
+	})
+	return
+}
+
+// FindHeadings implements core.NoteIndex.
+func (ni *NoteIndex) FindHeadings(regex string, limit int) (headings []core.HeadingMatch, err error) {
+	err = ni.commit(func(dao *dao) error {
+		headings, err = dao.notes.FindHeadings(regex, limit)
+		return err
+	})
+	return
+}
+
 // FindCollections implements core.NoteIndex.
 func (ni *NoteIndex) FindCollections(kind core.CollectionKind, sorters []core.CollectionSorter) (collections []core.Collection, err error) {
 	err = ni.commit(func(dao *dao) error {
@@ -103,6 +158,15 @@ func (ni *NoteIndex) FindCollections(kind core.CollectionKind, sorters []core.Co
 	return
 }
 
+// FindNestedCollections implements core.NoteIndex.
+func (ni *NoteIndex) FindNestedCollections(kind core.CollectionKind, sorters []core.CollectionSorter) (collections []core.Collection, err error) {
+	err = ni.commit(func(dao *dao) error {
+		collections, err = dao.collections.FindAllNested(kind, sorters)
+		return err
+	})
+	return
+}
+
 // IndexedPaths implements core.NoteIndex.
 func (ni *NoteIndex) IndexedPaths() (metadata <-chan paths.Metadata, err error) {
 	err = ni.commit(func(dao *dao) error {
@@ -132,7 +196,7 @@ func (ni *NoteIndex) Add(note core.Note) (id core.NoteID, err error) {
 			return err
 		}
 
-		return ni.associateTags(dao.collections, id, note.Tags)
+		return ni.associateTags(dao.collections, id, note)
 	})
 
 	err = errors.Wrapf(err, "%v: failed to index the note", note.Path)
@@ -240,19 +304,23 @@ func (ni *NoteIndex) Update(note core.Note) error {
 		if err != nil {
 			return err
 		}
-		return ni.associateTags(dao.collections, id, note.Tags)
+		return ni.associateTags(dao.collections, id, note)
 	})
 
 	return errors.Wrapf(err, "%v: failed to update note index", note.Path)
 }
 
-func (ni *NoteIndex) associateTags(collections *CollectionDAO, noteId core.NoteID, tags []string) error {
-	for _, tag := range tags {
+func (ni *NoteIndex) associateTags(collections *CollectionDAO, noteId core.NoteID, note core.Note) error {
+	for _, tag := range note.Tags {
 		tagId, err := collections.FindOrCreate(core.CollectionKindTag, tag)
 		if err != nil {
 			return err
 		}
-		_, err = collections.Associate(noteId, tagId)
+		start, ok := note.TagStarts[tag]
+		if !ok {
+			start = -1
+		}
+		_, err = collections.Associate(noteId, tagId, start)
 		if err != nil {
 			return err
 		}
@@ -278,10 +346,19 @@ func (ni *NoteIndex) resolveLinkNoteIDs(dao *dao, sourceID core.NoteID, links []
 			return resolvedLinks, err
 		}
 
+		var candidateIDs []core.NoteID
+		if targetID.IsValid() {
+			candidateIDs, err = ni.findLinkCandidates(dao, "" /* base dir */, link.Href, link.Type)
+			if err != nil {
+				return resolvedLinks, err
+			}
+		}
+
 		resolvedLinks = append(resolvedLinks, core.ResolvedLink{
-			Link:     link,
-			SourceID: sourceID,
-			TargetID: targetID,
+			Link:         link,
+			SourceID:     sourceID,
+			TargetID:     targetID,
+			CandidateIDs: candidateIDs,
 		})
 	}
 
@@ -329,12 +406,26 @@ func (ni *NoteIndex) SetNeedsReindexing(needsReindexing bool) error {
 	})
 }
 
+// Reset implements core.NoteIndex.
+func (ni *NoteIndex) Reset() error {
+	err := ni.commit(func(dao *dao) error {
+		return reset(dao.tx)
+	})
+	return errors.Wrap(err, "failed to reset the index")
+}
+
+// Close implements core.NoteIndex.
+func (ni *NoteIndex) Close() error {
+	return ni.db.Close()
+}
+
 func (ni *NoteIndex) commit(transaction func(dao *dao) error) error {
 	if ni.dao != nil {
 		return transaction(ni.dao)
 	} else {
 		return ni.db.WithTransaction(func(tx Transaction) error {
 			dao := dao{
+				tx:          tx,
 				notes:       NewNoteDAO(tx, ni.logger),
 				links:       NewLinkDAO(tx, ni.logger),
 				collections: NewCollectionDAO(tx, ni.logger),
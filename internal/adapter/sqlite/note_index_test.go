@@ -199,6 +199,22 @@ func TestNoteIndexAddWithTags(t *testing.T) {
 	assertTaggedOrNot(t, db, true, id, "fiction")
 }
 
+func TestNoteIndexFindTagStarts(t *testing.T) {
+	_, index := testNoteIndex(t)
+
+	id, err := index.Add(core.Note{
+		Path:      "log/added.md",
+		Tags:      []string{"new-tag", "fiction"},
+		TagStarts: map[string]int{"new-tag": 24},
+	})
+	assert.Nil(t, err)
+
+	// The tags declared only in the frontmatter have no offset.
+	starts, err := index.FindTagStarts(id)
+	assert.Nil(t, err)
+	assert.Equal(t, starts, map[string]int{"new-tag": 24})
+}
+
 func TestNoteIndexUpdateWithTags(t *testing.T) {
 	db, index := testNoteIndex(t)
 	id := core.NoteID(1)
@@ -218,6 +234,77 @@ func TestNoteIndexUpdateWithTags(t *testing.T) {
 	assertSQL(true)
 }
 
+func TestNoteIndexAddWithAmbiguousLinks(t *testing.T) {
+	_, index := testNoteIndex(t)
+
+	_, err := index.Add(core.Note{
+		Path: "ambiguous.md",
+		Links: []core.Link{
+			{Title: "Ambiguous", Href: "log/2021-01"},
+			{Title: "Exact", Href: "log/2021-01-03"},
+			{Title: "Exact with extension", Href: "log/2021-01-04.md"},
+			{Title: "Unknown", Href: "unknown"},
+		},
+	})
+	assert.Nil(t, err)
+
+	notes, err := index.Find(core.NoteFindOpts{AmbiguousLinks: true})
+	assert.Nil(t, err)
+	assert.Equal(t, len(notes), 1)
+	assert.Equal(t, notes[0].Path, "ambiguous.md")
+
+	links, err := index.FindAmbiguousLinks(notes[0].ID)
+	assert.Nil(t, err)
+	assert.Equal(t, links, []core.AmbiguousLink{
+		{
+			Href:       "log/2021-01",
+			Candidates: []string{"log/2021-01-03.md", "log/2021-01-04.md"},
+		},
+	})
+}
+
+func TestNoteIndexFindLinksOfNotes(t *testing.T) {
+	_, index := testNoteIndex(t)
+
+	links, err := index.FindLinksOfNotes([]core.NoteID{1})
+	assert.Nil(t, err)
+
+	hrefs := []string{}
+	for _, link := range links {
+		hrefs = append(hrefs, fmt.Sprintf("%d -> %d: %s", link.SourceID, link.TargetID, link.Href))
+	}
+	// Includes the external links and the backlinks.
+	assert.Equal(t, hrefs, []string{
+		"1 -> 2: log/2021-01-04.md",
+		"1 -> 0: https://domain.com",
+		"4 -> 1: log/2021-01-03.md",
+	})
+
+	links, err = index.FindLinksOfNotes([]core.NoteID{})
+	assert.Nil(t, err)
+	assert.Equal(t, links, []core.ResolvedLink{})
+}
+
+func TestNoteIndexReset(t *testing.T) {
+	db, index := testNoteIndex(t)
+
+	err := index.Reset()
+	assert.Nil(t, err)
+
+	assertExistOrNot(t, db, false, "SELECT id FROM notes")
+	assertExistOrNot(t, db, false, "SELECT id FROM links")
+	assertExistOrNot(t, db, false, "SELECT id FROM collections")
+
+	// The schema is recreated and requires a full reindexing.
+	needsReindexing, err := index.NeedsReindexing()
+	assert.Nil(t, err)
+	assert.True(t, needsReindexing)
+
+	_, err = index.Add(core.Note{Path: "new.md", Tags: []string{"fiction"}})
+	assert.Nil(t, err)
+	assertTagExistsOrNot(t, db, true, "fiction")
+}
+
 func testNoteIndex(t *testing.T) (*DB, *NoteIndex) {
 	db := testDB(t)
 	return db, NewNoteIndex("", db, &util.NullLogger)
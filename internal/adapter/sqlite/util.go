@@ -54,6 +54,17 @@ func joinNoteIDs(ids []core.NoteID, delimiter string) string {
 	return strings.Join(strs, delimiter)
 }
 
+// unmarshalHeadings parses the JSON list of headings of a note, which is nil
+// when the note has no headings.
+func unmarshalHeadings(headingsJSON string) (headings []core.Heading, err error) {
+	err = json.Unmarshal([]byte(headingsJSON), &headings)
+	if len(headings) == 0 {
+		headings = nil
+	}
+	err = errors.Wrapf(err, "cannot parse note headings from JSON: %s", headingsJSON)
+	return
+}
+
 func unmarshalMetadata(metadataJSON string) (metadata map[string]interface{}, err error) {
 	err = json.Unmarshal([]byte(metadataJSON), &metadata)
 	err = errors.Wrapf(err, "cannot parse note metadata from JSON: %s", metadataJSON)
@@ -7,12 +7,19 @@ import (
 
 	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/mattn/go-isatty"
+	"github.com/zk-org/zk/internal/util/opt"
+	"golang.org/x/term"
 )
 
 // Terminal offers utilities to interact with the terminal.
 type Terminal struct {
 	NoInput    bool
 	ForceInput string
+	// AutoConfirm answers yes to every confirmation prompt.
+	AutoConfirm bool
+	// ConfirmDefault overrides the default answer of the confirmation
+	// prompts, when set.
+	ConfirmDefault opt.Bool
 }
 
 func New() *Terminal {
@@ -30,6 +37,16 @@ func (t *Terminal) IsTTY() bool {
 	return isatty.IsTerminal(os.Stdin.Fd())
 }
 
+// Width returns the number of columns of the terminal attached to the
+// standard output, or 0 if it is unknown.
+func (t *Terminal) Width() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
 // SupportsUTF8 returns whether the computer is configured to support UTF-8.
 func (t *Terminal) SupportsUTF8() bool {
 	lang := strings.ToUpper(os.Getenv("LANG"))
@@ -39,6 +56,11 @@ func (t *Terminal) SupportsUTF8() bool {
 
 // Confirm is a shortcut to prompt a yes/no question to the user.
 func (t *Terminal) Confirm(msg string, defaultAnswer bool) (confirmed, skipped bool) {
+	if t.AutoConfirm {
+		return true, false
+	}
+	defaultAnswer = t.ConfirmDefault.OrBool(defaultAnswer).Unwrap()
+
 	if !t.IsInteractive() {
 		switch strings.ToLower(t.ForceInput) {
 		case "y":
@@ -0,0 +1,365 @@
+// Package api implements the Go API of zk exposed by the pkg/zk package, on
+// top of which the zk CLI commands are built as well.
+//
+// See pkg/zk for the documentation of the API and its stability guarantees.
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zk-org/zk/internal/adapter"
+	"github.com/zk-org/zk/internal/adapter/fs"
+	"github.com/zk-org/zk/internal/adapter/handlebars"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/date"
+	"github.com/zk-org/zk/internal/util/opt"
+	"github.com/zk-org/zk/internal/util/paths"
+)
+
+// Options customizes how a notebook is opened.
+type Options struct {
+	// Path to a config file used as a base for the config of the notebook,
+	// like the global config of the zk binary. Ignored when empty.
+	ConfigPath string
+	// Logger receiving the warnings, which are discarded when nil.
+	Logger *log.Logger
+}
+
+// Notebook is a zk notebook opened with OpenNotebook. It is not safe for
+// concurrent use, and must be closed with Close when not needed anymore.
+type Notebook struct {
+	// Absolute path to the root directory of the notebook.
+	Path string
+
+	notebook *core.Notebook
+}
+
+// ErrNotebookNotFound is returned by OpenNotebook when neither the given
+// directory nor its parents are a notebook.
+var ErrNotebookNotFound = errors.New("notebook not found")
+
+var initTemplates sync.Once
+
+// OpenNotebook opens the notebook containing the given directory.
+//
+// A leading ~ in the path is expanded to the home directory of the user.
+func OpenNotebook(path string, opts Options) (*Notebook, error) {
+	var logger util.Logger = &util.NullLogger
+	if opts.Logger != nil {
+		logger = util.StdLogger{Logger: opts.Logger}
+	}
+
+	if strings.HasPrefix(path, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	fs, err := fs.NewFileStorage("", logger)
+	if err != nil {
+		return nil, err
+	}
+	now := &date.Now{}
+
+	// The helpers depending on the clock and the logger are registered with
+	// the template loaders of each notebook, the global ones are only
+	// fallbacks.
+	initTemplates.Do(func() {
+		handlebars.Init(true, &date.Now{}, &util.NullLogger)
+	})
+
+	config := core.NewDefaultConfig()
+	if opts.ConfigPath != "" {
+		config, err = core.OpenConfig(opts.ConfigPath, config, fs, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	templateLoader := handlebars.NewLoader(handlebars.LoaderOpts{
+		LookupPaths: []string{},
+		Styler:      core.NullStyler,
+		Logger:      logger,
+	})
+	templateLoader.RegisterLocalHelpers(now, logger)
+
+	store := core.NewNotebookStore(config, core.NotebookStorePorts{
+		FS:             fs,
+		TemplateLoader: templateLoader,
+		NotebookFactory: adapter.NewNotebookFactory(adapter.NotebookFactoryOpts{
+			FS:     fs,
+			Styler: core.NullStyler,
+			Logger: logger,
+			Now:    now,
+		}),
+	})
+
+	notebook, err := store.Open(path)
+	if err != nil {
+		var errNotFound core.ErrNotebookNotFound
+		if errors.As(err, &errNotFound) {
+			return nil, fmt.Errorf("%s: %w", path, ErrNotebookNotFound)
+		}
+		return nil, err
+	}
+
+	return NewNotebook(notebook), nil
+}
+
+// NewNotebook wraps a notebook already opened by the zk CLI.
+func NewNotebook(notebook *core.Notebook) *Notebook {
+	return &Notebook{
+		Path:     notebook.Path,
+		notebook: notebook,
+	}
+}
+
+// Close releases the resources of the notebook, such as its index database.
+// The notebook can't be used afterwards.
+func (n *Notebook) Close() error {
+	return n.notebook.Close()
+}
+
+// IndexOpts customizes the indexing of a notebook.
+type IndexOpts struct {
+	// Reindex all the notes, even the ones not modified since the last
+	// indexing.
+	Force bool
+	// Drop the index and rebuild it from scratch.
+	Rebuild bool
+	// Globs of files to index in addition to the notes, relative to the root
+	// of the notebook, e.g. "**/*.canvas".
+	Include []string
+	// Print the details of the indexing on the standard output.
+	Verbose bool
+	// Called with the description of each change found while indexing, e.g.
+	// to show the progress. Ignored when nil.
+	Progress func(change string)
+}
+
+// IndexStats holds the statistics of an indexing.
+type IndexStats struct {
+	// Number of notes found in the notebook.
+	SourceCount int `json:"sourceCount"`
+	// Number of notes added to the index.
+	AddedCount int `json:"addedCount"`
+	// Number of notes modified since the last indexing.
+	ModifiedCount int `json:"modifiedCount"`
+	// Number of notes removed since the last indexing.
+	RemovedCount int `json:"removedCount"`
+	// Number of files skipped by the ignore rules.
+	SkippedCount int `json:"skippedCount"`
+	// Duration of the indexing.
+	Duration time.Duration `json:"duration"`
+}
+
+// String returns the summary of the indexing printed by `zk index`.
+func (s IndexStats) String() string {
+	return core.NoteIndexingStats(s).String()
+}
+
+// Index updates the index of the notebook with the notes added, modified or
+// removed since the last indexing.
+//
+// The index must be up to date for FindNotes to return the latest notes.
+func (n *Notebook) Index(opts IndexOpts) (IndexStats, error) {
+	stats, err := n.notebook.IndexWithCallback(core.NoteIndexOpts{
+		Force:   opts.Force,
+		Rebuild: opts.Rebuild,
+		Include: opts.Include,
+		Verbose: opts.Verbose,
+	}, func(change paths.DiffChange) {
+		if opts.Progress != nil {
+			opts.Progress(change.String())
+		}
+	})
+	return IndexStats(stats), err
+}
+
+// Filter selects the notes returned by FindNotes. The zero value matches all
+// the notes.
+//
+// The paths are relative to the root of the notebook, and include the notes
+// in the descendant directories.
+type Filter struct {
+	// Full-text search queries, using the syntax of `zk list --match`.
+	Match []string
+	// Paths of the notes or directories to search in.
+	Paths []string
+	// Paths of the notes or directories to exclude.
+	ExcludePaths []string
+	// Tags, or tag expressions such as "draft OR todo", the notes are
+	// tagged with.
+	Tags []string
+	// Match as well the nested tags of Tags, e.g. "project/alpha" for
+	// "project".
+	RecursiveTags bool
+	// Paths of notes the found notes are linking to.
+	LinkTo []string
+	// Paths of notes linking to the found notes.
+	LinkedBy []string
+	// Find only the notes which are not linked by any other note.
+	Orphan bool
+	// Find only the notes created after the given date, when not zero.
+	CreatedAfter time.Time
+	// Find only the notes created before the given date, when not zero.
+	CreatedBefore time.Time
+	// Find only the notes modified after the given date, when not zero.
+	ModifiedAfter time.Time
+	// Find only the notes modified before the given date, when not zero.
+	ModifiedBefore time.Time
+	// Sort criteria, using the syntax of `zk list --sort`, e.g. "created-".
+	Sort []string
+	// Maximum number of notes found, unlimited when zero.
+	Limit int
+}
+
+// Note is a note found in a notebook.
+type Note struct {
+	// Path relative to the root of the notebook.
+	Path string
+	// Title of the note.
+	Title string
+	// First paragraph of the note body.
+	Lead string
+	// Content of the note, after the frontmatter and title heading.
+	Body string
+	// Whole raw content of the note file.
+	RawContent string
+	// Number of words in the note.
+	WordCount int
+	// Tags found in the note.
+	Tags []string
+	// Metadata of the YAML frontmatter.
+	Metadata map[string]interface{}
+	// Date of creation.
+	Created time.Time
+	// Date of the last modification.
+	Modified time.Time
+	// Excerpts of the note matching the Match filter.
+	Snippets []string
+}
+
+// FindNotes returns the indexed notes matching the given filter.
+func (n *Notebook) FindNotes(filter Filter) ([]Note, error) {
+	opts, err := filter.findOpts()
+	if err != nil {
+		return nil, err
+	}
+	notes, err := n.notebook.FindNotes(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Note, 0, len(notes))
+	for _, note := range notes {
+		res = append(res, newNote(note.Note, note.Snippets))
+	}
+	return res, nil
+}
+
+func (f Filter) findOpts() (core.NoteFindOpts, error) {
+	opts := core.NoteFindOpts{
+		Match:         f.Match,
+		MatchStrategy: core.MatchStrategyFts,
+		IncludeHrefs:  f.Paths,
+		ExcludeHrefs:  f.ExcludePaths,
+		Tags:          f.Tags,
+		RecursiveTags: f.RecursiveTags,
+		Orphan:        f.Orphan,
+		CreatedStart:  optTime(f.CreatedAfter),
+		CreatedEnd:    optTime(f.CreatedBefore),
+		ModifiedStart: optTime(f.ModifiedAfter),
+		ModifiedEnd:   optTime(f.ModifiedBefore),
+		Limit:         f.Limit,
+	}
+	if len(f.LinkTo) > 0 {
+		opts.LinkTo = &core.LinkFilter{Hrefs: f.LinkTo}
+	}
+	if len(f.LinkedBy) > 0 {
+		opts.LinkedBy = &core.LinkFilter{Hrefs: f.LinkedBy}
+	}
+
+	var err error
+	opts.Sorters, err = core.NoteSortersFromStrings(f.Sort)
+	return opts, err
+}
+
+func optTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// NewNoteOpts holds the options used to create a note with CreateNote.
+type NewNoteOpts struct {
+	// Title of the note, defaults to the title configured for its group.
+	Title string
+	// Content of the note, given to the template as {{content}}.
+	Content string
+	// Existing directory in which to create the note, relative to the root
+	// of the notebook. Defaults to the root.
+	Directory string
+	// Name of the config group the note belongs to, defaults to the group of
+	// its directory.
+	Group string
+	// Path to a custom template used to render the note.
+	Template string
+	// Extra variables given to the templates.
+	Extra map[string]string
+	// Creation date of the note, defaults to now.
+	Date time.Time
+	// Don't write the note. The returned note holds its path and content.
+	DryRun bool
+}
+
+// CreateNote creates a new note from the templates of the notebook and
+// indexes it.
+func (n *Notebook) CreateNote(opts NewNoteOpts) (Note, error) {
+	date := opts.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	note, err := n.notebook.NewNote(core.NewNoteOpts{
+		Title:     opt.NewNotEmptyString(opts.Title),
+		Content:   opts.Content,
+		Directory: opt.NewNotEmptyString(filepath.Join(n.Path, opts.Directory)),
+		Group:     opt.NewNotEmptyString(opts.Group),
+		Template:  opt.NewNotEmptyString(opts.Template),
+		Extra:     opts.Extra,
+		Date:      date,
+		DryRun:    opts.DryRun,
+	})
+	if err != nil {
+		return Note{}, err
+	}
+	return newNote(*note, nil), nil
+}
+
+func newNote(note core.Note, snippets []string) Note {
+	return Note{
+		Path:       note.Path,
+		Title:      note.Title,
+		Lead:       note.Lead,
+		Body:       note.Body,
+		RawContent: note.RawContent,
+		WordCount:  note.WordCount,
+		Tags:       note.Tags,
+		Metadata:   note.Metadata,
+		Created:    note.Created,
+		Modified:   note.Modified,
+		Snippets:   snippets,
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/strings"
+)
+
+// Backlinks maintains a section listing the notes linking to each note
+// matching a set of criteria.
+type Backlinks struct {
+	Write   bool   `xor:"mode" help:"Insert or update the backlinks section of the notes."`
+	DryRun  bool   `xor:"mode" help:"Don't actually update the notes. Instead, prints the changes as a diff on stdout."`
+	Heading string `placeholder:HEADING default:"## Backlinks" help:"Heading of the backlinks section."`
+	Quiet   bool   `short:q help:"Do not print the total number of notes updated."`
+	cli.Filtering
+}
+
+func (cmd *Backlinks) Run(container *cli.Container) error {
+	if !cmd.Write && !cmd.DryRun {
+		return errors.New("either --write or --dry-run is required")
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	findOpts, err := cmd.Filtering.NewNoteFindOpts(notebook)
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+
+	notes, err := notebook.FindNotes(findOpts)
+	if err != nil {
+		return err
+	}
+
+	filter := container.NewNoteFilter(fzf.NoteFilterOpts{
+		Interactive:  cmd.Interactive,
+		AlwaysFilter: false,
+		NotebookDir:  notebook.Path,
+	})
+
+	notes, err = filter.Apply(notes)
+	if err != nil {
+		if err == fzf.ErrCancelled {
+			return nil
+		}
+		return err
+	}
+
+	updates, err := notebook.UpdateBacklinks(notes, core.BacklinksOpts{
+		Heading: cmd.Heading,
+		DryRun:  cmd.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, update := range updates {
+		if !update.Changed() {
+			continue
+		}
+		count++
+		if cmd.DryRun {
+			fmt.Print(update.UnifiedDiff())
+		}
+	}
+
+	if !cmd.Quiet {
+		verb := "Updated"
+		if cmd.DryRun {
+			verb = "Would update"
+		}
+		fmt.Fprintf(os.Stderr, "\n%s %d %s\n", verb, count, strings.Pluralize("note", count))
+	}
+
+	return nil
+}
@@ -0,0 +1,513 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/zk-org/zk/internal/cli"
+)
+
+// Completion generates a shell completion script.
+type Completion struct {
+	Shell string `arg help:"Shell for which the completion script is generated, among: bash, zsh, fish, powershell." enum:"bash,zsh,fish,powershell"`
+}
+
+func (cmd *Completion) Run(container *cli.Container, ctx *kong.Context) error {
+	commands := completionCommands(ctx.Model.Node)
+
+	switch cmd.Shell {
+	case "bash":
+		return writeBashCompletion(os.Stdout, commands)
+	case "zsh":
+		return writeZshCompletion(os.Stdout, commands)
+	case "fish":
+		return writeFishCompletion(os.Stdout, commands)
+	case "powershell":
+		return writePowerShellCompletion(os.Stdout, commands)
+	default:
+		return fmt.Errorf("%s: unsupported shell", cmd.Shell)
+	}
+}
+
+// Dynamic completions are delegated to zk itself, so that they degrade to the
+// static flags when no notebook is found.
+const (
+	completionNotesCmd = `zk list --quiet --format '{{path}}'`
+	completionTagsCmd  = `zk tag list --quiet --format name`
+)
+
+// completionCommand holds the metadata of a zk command needed to complete
+// it in a shell.
+type completionCommand struct {
+	// Path of the command, e.g. "tag list". The root is empty.
+	Path string
+	Help string
+	// Direct subcommands.
+	Commands []completionCommand
+	Flags    []completionFlag
+	// Indicates whether the positional arguments are note paths.
+	CompletesNotes bool
+	// Allowed values of an enum positional argument.
+	Values []string
+}
+
+type completionFlag struct {
+	// Flag names, e.g. --tag and -t.
+	Names []string
+	Help  string
+	// Indicates whether the flag is followed by a value.
+	HasValue bool
+	// Indicates whether the value of the flag is a note tag.
+	CompletesTags bool
+	// Allowed values of an enum flag.
+	Values []string
+}
+
+// completionCommands returns the given command node and all its visible
+// descendants, depth first.
+func completionCommands(node *kong.Node) []completionCommand {
+	command := completionCommand{
+		Path: commandPath(node),
+		Help: node.Help,
+	}
+
+	for _, group := range node.AllFlags(true) {
+		for _, flag := range group {
+			f := completionFlag{
+				Names:         []string{"--" + flag.Name},
+				Help:          flag.Help,
+				HasValue:      !flag.IsBool(),
+				CompletesTags: flag.Name == "tag" || flag.Name == "tag-prefix",
+			}
+			if flag.Short != 0 {
+				f.Names = append(f.Names, "-"+string(flag.Short))
+			}
+			if flag.Enum != "" {
+				f.Values = flag.EnumSlice()
+			}
+			command.Flags = append(command.Flags, f)
+		}
+	}
+
+	for _, positional := range node.Positional {
+		if positional.Name == "path" {
+			command.CompletesNotes = true
+		}
+		if positional.Enum != "" {
+			command.Values = positional.EnumSlice()
+		}
+	}
+
+	commands := []completionCommand{}
+	for _, child := range node.Children {
+		if child.Hidden || child.Type != kong.CommandNode {
+			continue
+		}
+		descendants := completionCommands(child)
+		command.Commands = append(command.Commands, descendants[0])
+		commands = append(commands, descendants...)
+	}
+
+	return append([]completionCommand{command}, commands...)
+}
+
+// commandPath returns the space-separated names of the command node and its
+// parents, excluding the application.
+func commandPath(node *kong.Node) string {
+	names := []string{}
+	for n := node; n != nil && n.Type != kong.ApplicationNode; n = n.Parent {
+		names = append([]string{n.Name}, names...)
+	}
+	return strings.Join(names, " ")
+}
+
+// Name returns the last word of the command path.
+func (c completionCommand) Name() string {
+	return c.Path[strings.LastIndex(c.Path, " ")+1:]
+}
+
+// args returns the words completing the first positional argument, either
+// subcommands or enum values.
+func (c completionCommand) args() []string {
+	names := []string{}
+	for _, command := range c.Commands {
+		names = append(names, command.Name())
+	}
+	return append(names, c.Values...)
+}
+
+func (c completionCommand) flagNames() []string {
+	names := []string{}
+	for _, flag := range c.Flags {
+		names = append(names, flag.Names...)
+	}
+	return names
+}
+
+// completionPaths returns the quoted paths of the non-root commands.
+func completionPaths(commands []completionCommand, quote func(string) string) []string {
+	paths := []string{}
+	for _, command := range commands {
+		if command.Path != "" {
+			paths = append(paths, quote(command.Path))
+		}
+	}
+	return paths
+}
+
+// shQuote quotes a string for POSIX shells.
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes a string for fish.
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// psQuote quotes a string for PowerShell.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func writeBashCompletion(w io.Writer, commands []completionCommand) error {
+	var b strings.Builder
+	b.WriteString(`# bash completion for zk, generated with ` + "`zk completion bash`" + `.
+# Load it from your ~/.bashrc with:
+#   source <(zk completion bash)
+
+_zk_command() {
+    local cmd="" candidate i
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        [[ "${COMP_WORDS[i]}" == -* ]] && continue
+        candidate="${cmd:+$cmd }${COMP_WORDS[i]}"
+        case "$candidate" in
+            ` + strings.Join(completionPaths(commands, shQuote), "|") + `) cmd="$candidate" ;;
+        esac
+    done
+    echo "$cmd"
+}
+
+_zk() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    local commands="" flags="" notes=""
+
+    case "$(_zk_command)" in
+`)
+	for _, command := range commands {
+		fmt.Fprintf(&b, "        %s)\n", shQuote(command.Path))
+		fmt.Fprintf(&b, "            commands=%s\n", shQuote(strings.Join(command.args(), " ")))
+		fmt.Fprintf(&b, "            flags=%s\n", shQuote(strings.Join(command.flagNames(), " ")))
+		if command.CompletesNotes {
+			b.WriteString("            notes=1\n")
+		}
+		cases := []string{}
+		for _, flag := range command.Flags {
+			var values string
+			switch {
+			case flag.CompletesTags:
+				values = `"$(` + completionTagsCmd + ` 2>/dev/null)"`
+			case len(flag.Values) > 0:
+				values = shQuote(strings.Join(flag.Values, " "))
+			default:
+				continue
+			}
+			cases = append(cases, fmt.Sprintf("%s) COMPREPLY=($(compgen -W %s -- \"$cur\")); return ;;", strings.Join(flag.Names, "|"), values))
+		}
+		writeShellCase(&b, `"$prev"`, cases)
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString(`    esac
+
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+    elif [[ -n "$commands" ]]; then
+        COMPREPLY=($(compgen -W "$commands" -- "$cur"))
+    elif [[ -n "$notes" ]]; then
+        local IFS=$'\n'
+        COMPREPLY=($(compgen -W "$(` + completionNotesCmd + ` 2>/dev/null)" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -f -- "$cur"))
+    fi
+}
+
+complete -o filenames -F _zk zk
+`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeShellCase writes a POSIX case statement completing the values of the
+// flags, if any.
+func writeShellCase(b *strings.Builder, word string, cases []string) {
+	if len(cases) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "            case %s in\n", word)
+	for _, c := range cases {
+		fmt.Fprintf(b, "                %s\n", c)
+	}
+	b.WriteString("            esac\n")
+}
+
+func writeZshCompletion(w io.Writer, commands []completionCommand) error {
+	var b strings.Builder
+	b.WriteString(`#compdef zk
+# zsh completion for zk, generated with ` + "`zk completion zsh`" + `.
+# Load it from your ~/.zshrc, after compinit, with:
+#   source <(zk completion zsh)
+
+_zk_notes() {
+    local -a lines paths descriptions
+    local line
+    lines=(${(f)"$(zk list --quiet --format '{{path}}'$'\t''{{title}}' 2>/dev/null)"})
+    for line in $lines; do
+        paths+=("${line%%$'\t'*}")
+        descriptions+=("${line%%$'\t'*}  -- ${line#*$'\t'}")
+    done
+    compadd -l -d descriptions -a paths
+}
+
+_zk_tags() {
+    local -a tags
+    tags=(${(f)"$(` + completionTagsCmd + ` 2>/dev/null)"})
+    compadd -a tags
+}
+
+_zk() {
+    local cmd="" candidate notes="" i
+    local -a commands flags
+    for ((i = 2; i < CURRENT; i++)); do
+        [[ "${words[i]}" == -* ]] && continue
+        candidate="${cmd:+$cmd }${words[i]}"
+        case "$candidate" in
+            ` + strings.Join(completionPaths(commands, shQuote), "|") + `) cmd="$candidate" ;;
+        esac
+    done
+
+    case "$cmd" in
+`)
+	zshDescribe := func(name, help string) string {
+		return shQuote(strings.ReplaceAll(name, ":", `\:`) + ":" + help)
+	}
+	for _, command := range commands {
+		fmt.Fprintf(&b, "        %s)\n", shQuote(command.Path))
+		items := []string{}
+		for _, sub := range command.Commands {
+			items = append(items, zshDescribe(sub.Name(), sub.Help))
+		}
+		for _, value := range command.Values {
+			items = append(items, shQuote(value))
+		}
+		fmt.Fprintf(&b, "            commands=(%s)\n", strings.Join(items, " "))
+		items = []string{}
+		for _, flag := range command.Flags {
+			for _, name := range flag.Names {
+				items = append(items, zshDescribe(name, flag.Help))
+			}
+		}
+		fmt.Fprintf(&b, "            flags=(%s)\n", strings.Join(items, " "))
+		if command.CompletesNotes {
+			b.WriteString("            notes=1\n")
+		}
+		cases := []string{}
+		for _, flag := range command.Flags {
+			var action string
+			switch {
+			case flag.CompletesTags:
+				action = "_zk_tags"
+			case len(flag.Values) > 0:
+				action = "compadd -- " + strings.Join(flag.Values, " ")
+			default:
+				continue
+			}
+			cases = append(cases, fmt.Sprintf("%s) %s; return ;;", strings.Join(flag.Names, "|"), action))
+		}
+		writeShellCase(&b, `"${words[CURRENT-1]}"`, cases)
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString(`    esac
+
+    if [[ "${words[CURRENT]}" == -* ]]; then
+        _describe -t flags 'flag' flags
+    elif (( ${#commands} )); then
+        _describe -t commands 'command' commands
+    elif [[ -n "$notes" ]]; then
+        _zk_notes
+    else
+        _files
+    fi
+}
+
+if [[ "${funcstack[1]}" == "_zk" ]]; then
+    _zk "$@"
+else
+    compdef _zk zk
+fi
+`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeFishCompletion(w io.Writer, commands []completionCommand) error {
+	var b strings.Builder
+	b.WriteString(`# fish completion for zk, generated with ` + "`zk completion fish`" + `.
+# Load it from your ~/.config/fish/config.fish with:
+#   zk completion fish | source
+
+function __zk_command
+    set -l tokens (commandline -opc)
+    set -e tokens[1]
+    set -l cmd ''
+    for token in $tokens
+        string match -q -- '-*' $token; and continue
+        set -l candidate (string trim -- "$cmd $token")
+        if contains -- $candidate ` + strings.Join(completionPaths(commands, fishQuote), " ") + `
+            set cmd $candidate
+        end
+    end
+    echo $cmd
+end
+
+function __zk_using
+    set -l cmd (__zk_command)
+    test "$cmd" = "$argv[1]"
+end
+
+function __zk_notes
+    zk list --quiet --format '{{path}}'\t'{{title}}' 2>/dev/null
+end
+
+function __zk_tags
+    ` + completionTagsCmd + ` 2>/dev/null
+end
+
+complete -c zk -f
+`)
+	for _, command := range commands {
+		using := fishQuote("__zk_using " + shQuote(command.Path))
+		b.WriteString("\n")
+		for _, sub := range command.Commands {
+			fmt.Fprintf(&b, "complete -c zk -n %s -a %s -d %s\n", using, fishQuote(sub.Name()), fishQuote(sub.Help))
+		}
+		for _, flag := range command.Flags {
+			args := ""
+			for _, name := range flag.Names {
+				if strings.HasPrefix(name, "--") {
+					args += " -l " + fishQuote(strings.TrimPrefix(name, "--"))
+				} else {
+					args += " -s " + fishQuote(strings.TrimPrefix(name, "-"))
+				}
+			}
+			if flag.HasValue {
+				args += " -r"
+			}
+			switch {
+			case flag.CompletesTags:
+				args += " -a '(__zk_tags)'"
+			case len(flag.Values) > 0:
+				args += " -a " + fishQuote(strings.Join(flag.Values, " "))
+			case flag.HasValue:
+				args += " -F"
+			}
+			fmt.Fprintf(&b, "complete -c zk -n %s%s -d %s\n", using, args, fishQuote(flag.Help))
+		}
+		if command.CompletesNotes {
+			fmt.Fprintf(&b, "complete -c zk -n %s -a '(__zk_notes)'\n", using)
+		} else if len(command.Values) > 0 {
+			fmt.Fprintf(&b, "complete -c zk -n %s -a %s\n", using, fishQuote(strings.Join(command.Values, " ")))
+		} else if len(command.Commands) == 0 {
+			fmt.Fprintf(&b, "complete -c zk -n %s -F\n", using)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writePowerShellCompletion(w io.Writer, commands []completionCommand) error {
+	psList := func(items []string) string {
+		quoted := []string{}
+		for _, item := range items {
+			quoted = append(quoted, psQuote(item))
+		}
+		return "@(" + strings.Join(quoted, ", ") + ")"
+	}
+
+	var b strings.Builder
+	b.WriteString(`# PowerShell completion for zk, generated with ` + "`zk completion powershell`" + `.
+# Load it from your $PROFILE with:
+#   zk completion powershell | Out-String | Invoke-Expression
+
+Register-ArgumentCompleter -Native -CommandName zk -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $commands = @{
+`)
+	noteCommands := []string{}
+	tagFlags := []string{}
+	isTagFlag := map[string]bool{}
+	values := []string{}
+	for _, command := range commands {
+		fmt.Fprintf(&b, "        %s = %s\n", psQuote(command.Path), psList(command.args()))
+		if command.CompletesNotes {
+			noteCommands = append(noteCommands, command.Path)
+		}
+		for _, flag := range command.Flags {
+			for _, name := range flag.Names {
+				if flag.CompletesTags && !isTagFlag[name] {
+					isTagFlag[name] = true
+					tagFlags = append(tagFlags, name)
+				}
+				if len(flag.Values) > 0 {
+					values = append(values, fmt.Sprintf("        %s = %s", psQuote(command.Path+" "+name), psList(flag.Values)))
+				}
+			}
+		}
+	}
+	b.WriteString("    }\n    $flags = @{\n")
+	for _, command := range commands {
+		fmt.Fprintf(&b, "        %s = %s\n", psQuote(command.Path), psList(command.flagNames()))
+	}
+	b.WriteString("    }\n    $values = @{\n")
+	for _, value := range values {
+		b.WriteString(value + "\n")
+	}
+	fmt.Fprintf(&b, "    }\n    $noteCommands = %s\n", psList(noteCommands))
+	fmt.Fprintf(&b, "    $tagFlags = %s\n", psList(tagFlags))
+	b.WriteString(`
+    $words = @($commandAst.CommandElements | Where-Object { $_.Extent.EndOffset -lt $cursorPosition } | ForEach-Object { $_.ToString() })
+    $cmd = ''
+    foreach ($word in ($words | Select-Object -Skip 1)) {
+        if ($word.StartsWith('-')) { continue }
+        $candidate = "$cmd $word".Trim()
+        if ($commands.ContainsKey($candidate)) { $cmd = $candidate }
+    }
+    $prev = if ($words.Count -gt 1) { $words[-1] } else { '' }
+
+    $candidates = if ($tagFlags -contains $prev) {
+        ` + completionTagsCmd + ` 2>$null
+    } elseif ($values.ContainsKey("$cmd $prev")) {
+        $values["$cmd $prev"]
+    } elseif ($wordToComplete.StartsWith('-')) {
+        $flags[$cmd]
+    } elseif ($commands[$cmd].Count -gt 0) {
+        $commands[$cmd]
+    } elseif ($noteCommands -contains $cmd) {
+        ` + completionNotesCmd + ` 2>$null
+    } else {
+        # Falls back on the file completion.
+        return
+    }
+
+    $candidates | Where-Object { $_ -and $_.StartsWith($wordToComplete) } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func parseCompletionCommands(t *testing.T) []completionCommand {
+	var root struct {
+		NotebookDir string `placeholder:PATH help:"Notebook directory."`
+
+		Completion Completion `cmd help:"Generate a completion script."`
+		Edit       struct {
+			Force bool `short:f help:"Do not confirm."`
+			cli.Filtering
+		} `cmd help:"Edit notes."`
+		Tag struct {
+			List struct {
+				Format string `short:f help:"Format." enum:"name,json" default:"name"`
+			} `cmd help:"List tags."`
+		} `cmd help:"Manage tags."`
+		LSP struct{} `cmd hidden`
+	}
+	parser, err := kong.New(&root, kong.Name("zk"))
+	assert.Nil(t, err)
+	return completionCommands(parser.Model.Node)
+}
+
+func TestCompletionCommands(t *testing.T) {
+	commands := parseCompletionCommands(t)
+
+	paths := []string{}
+	for _, command := range commands {
+		paths = append(paths, command.Path)
+	}
+	// Hidden commands are excluded.
+	assert.Equal(t, paths, []string{"", "completion", "edit", "tag", "tag list"})
+
+	assert.Equal(t, commands[0].args(), []string{"completion", "edit", "tag"})
+	assert.Equal(t, commands[0].flagNames(), []string{"--help", "-h", "--notebook-dir"})
+	assert.Equal(t, commands[1].args(), []string{"bash", "zsh", "fish", "powershell"})
+	assert.Equal(t, commands[3].args(), []string{"list"})
+	assert.Equal(t, commands[4].Name(), "list")
+
+	// Note paths are completed for the filtering arguments.
+	assert.False(t, commands[1].CompletesNotes)
+	assert.True(t, commands[2].CompletesNotes)
+
+	for _, flag := range commands[2].Flags {
+		switch flag.Names[0] {
+		case "--force":
+			assert.Equal(t, flag.Names, []string{"--force", "-f"})
+			assert.False(t, flag.HasValue)
+		case "--tag", "--tag-prefix":
+			assert.True(t, flag.CompletesTags)
+			assert.True(t, flag.HasValue)
+		default:
+			assert.False(t, flag.CompletesTags)
+		}
+	}
+	assert.Equal(t, commands[4].Flags[2].Values, []string{"name", "json"})
+}
+
+func TestCompletionScripts(t *testing.T) {
+	commands := parseCompletionCommands(t)
+
+	test := func(write func(w *strings.Builder) error, expectedLines ...string) {
+		var out strings.Builder
+		assert.Nil(t, write(&out))
+		for _, line := range expectedLines {
+			if !strings.Contains(out.String(), line) {
+				t.Errorf("Missing line in the completion script:\n%s", line)
+			}
+		}
+	}
+
+	test(func(w *strings.Builder) error { return writeBashCompletion(w, commands) },
+		`'completion'|'edit'|'tag'|'tag list') cmd="$candidate" ;;`,
+		`            commands='bash zsh fish powershell'`,
+		`                --tag|-t) COMPREPLY=($(compgen -W "$(zk tag list --quiet --format name 2>/dev/null)" -- "$cur")); return ;;`,
+		`                --format|-f) COMPREPLY=($(compgen -W 'name json' -- "$cur")); return ;;`,
+		`complete -o filenames -F _zk zk`,
+	)
+	test(func(w *strings.Builder) error { return writeZshCompletion(w, commands) },
+		`#compdef zk`,
+		`            commands=('completion:Generate a completion script.' 'edit:Edit notes.' 'tag:Manage tags.')`,
+		`                --format|-f) compadd -- name json; return ;;`,
+		`    compdef _zk zk`,
+	)
+	test(func(w *strings.Builder) error { return writeFishCompletion(w, commands) },
+		`complete -c zk -n '__zk_using \'\'' -a 'edit' -d 'Edit notes.'`,
+		`complete -c zk -n '__zk_using \'edit\'' -l 'tag' -s 't' -r -a '(__zk_tags)' -d 'Find notes tagged with the given tags.'`,
+		`complete -c zk -n '__zk_using \'edit\'' -a '(__zk_notes)'`,
+		`complete -c zk -n '__zk_using \'completion\'' -a 'bash zsh fish powershell'`,
+	)
+	test(func(w *strings.Builder) error { return writePowerShellCompletion(w, commands) },
+		`        'tag' = @('list')`,
+		`        'tag list --format' = @('name', 'json')`,
+		`    $noteCommands = @('edit')`,
+		`    $tagFlags = @('--tag', '-t', '--tag-prefix')`,
+	)
+}
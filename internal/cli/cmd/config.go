@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// Config manages the configuration files.
+type Config struct {
+	Edit ConfigEdit `cmd group:"cmd" help:"Open the notebook config file in the editor."`
+}
+
+// ConfigEdit opens a configuration file with the user editor, creating it
+// from the default config when missing.
+type ConfigEdit struct {
+	Global bool `short:g help:"Edit the global config file instead of the notebook one."`
+}
+
+func (cmd *ConfigEdit) Run(container *cli.Container) error {
+	path, err := cmd.configPath(container)
+	if err != nil {
+		return err
+	}
+
+	exists, err := container.FS.FileExists(path)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		content, err := container.Notebooks.GenerateConfig(core.NewDefaultInitOpts())
+		if err != nil {
+			return errors.Wrapf(err, "%s: failed to create the config file", path)
+		}
+		err = container.FS.Write(path, []byte(content))
+		if err != nil {
+			return errors.Wrapf(err, "%s: failed to create the config file", path)
+		}
+	}
+
+	editor, err := container.NewEditor()
+	if err != nil {
+		return err
+	}
+	return editor.Open(path)
+}
+
+// configPath returns the path to the config file to edit.
+func (cmd *ConfigEdit) configPath(container *cli.Container) (string, error) {
+	if cmd.Global {
+		return cli.GlobalConfigPath(), nil
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(notebook.Path, ".zk/config.toml"), nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// Dedup reports the clusters of notes with identical or similar content,
+// among the notes matching a set of criteria.
+type Dedup struct {
+	Threshold float64 `default:"1" placeholder:RATIO help:"Minimum similarity between 0 and 1 to group two notes, 1 matching only identical content."`
+	cli.Filtering
+}
+
+func (cmd *Dedup) Run(container *cli.Container) error {
+	if cmd.Threshold <= 0 || cmd.Threshold > 1 {
+		return fmt.Errorf("%v: the threshold must be greater than 0 and at most 1", cmd.Threshold)
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	findOpts, err := cmd.Filtering.NewNoteFindOpts(notebook)
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+
+	notes, err := notebook.FindNotes(findOpts)
+	if err != nil {
+		return err
+	}
+
+	filter := container.NewNoteFilter(fzf.NoteFilterOpts{
+		Interactive:  cmd.Interactive,
+		AlwaysFilter: false,
+		NotebookDir:  notebook.Path,
+	})
+
+	notes, err = filter.Apply(notes)
+	if err != nil {
+		if err == fzf.ErrCancelled {
+			return nil
+		}
+		return err
+	}
+
+	format, err := notebook.NewNoteFormatter("{{path}}", core.PathStyleRelative)
+	if err != nil {
+		return err
+	}
+
+	clusters := core.FindDuplicateNotes(notes, cmd.Threshold)
+	for i, cluster := range clusters {
+		if i > 0 {
+			fmt.Println()
+		}
+		for _, note := range cluster {
+			path, err := format(note)
+			if err != nil {
+				return err
+			}
+			fmt.Println(path)
+		}
+	}
+
+	return nil
+}
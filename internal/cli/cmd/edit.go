@@ -13,7 +13,11 @@ import (
 
 // Edit opens notes matching a set of criteria with the user editor.
 type Edit struct {
-	Force bool `short:f help:"Do not confirm before editing many notes at the same time."`
+	Force        bool   `short:f help:"Do not confirm before editing many notes at the same time."`
+	SingleWindow bool   `help:"Open all the notes in a single editor session, even when the editor is configured to open them one after the other."`
+	Search       string `placeholder:QUERY help:"Open the note best matching the full-text search query, or pick among the ranked matches with --interactive."`
+	ForceCreate  bool   `help:"Create a new note titled after the --search query when no note matches it."`
+	Line         int    `placeholder:LINE help:"Open the notes at the given line, when the editor command uses the {{line}} template variable."`
 	cli.Filtering
 }
 
@@ -28,10 +32,31 @@ func (cmd *Edit) Run(container *cli.Container) error {
 		return errors.Wrapf(err, "incorrect criteria")
 	}
 
+	if cmd.ForceCreate && cmd.Search == "" {
+		return errors.New("--force-create can only be used with --search")
+	}
+
+	if cmd.Search != "" {
+		if findOpts.MatchStrategy != core.MatchStrategyFts {
+			return errors.New("--search can only be used with the fts match strategy")
+		}
+		// Matching with the FTS strategy ranks the notes by relevance.
+		findOpts.Match = append(findOpts.Match, cmd.Search)
+		if !cmd.Interactive {
+			findOpts.Limit = 1
+		}
+	}
+
 	notes, err := notebook.FindNotes(findOpts)
 	if err != nil {
 		return err
 	}
+	if cmd.Search != "" && len(notes) == 0 {
+		if cmd.ForceCreate {
+			return cmd.newNote(container, notebook)
+		}
+		return core.ErrNoteNotFound{Query: cmd.Search}
+	}
 
 	filter := container.NewNoteFilter(fzf.NoteFilterOpts{
 		Interactive:  cmd.Interactive,
@@ -69,7 +94,19 @@ func (cmd *Edit) Run(container *cli.Container) error {
 		if err != nil {
 			return err
 		}
-		return editor.Open(paths...)
+		editor.Line = cmd.Line
+		if notebook.Config.Tool.EditorSequential && !cmd.SingleWindow {
+			err = editor.OpenSequentially(paths...)
+		} else {
+			err = editor.Open(paths...)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Index the notes that were modified during the editing session.
+		index := Index{Quiet: true}
+		return index.RunWithNotebook(container, notebook)
 
 	} else {
 		fmt.Fprintln(os.Stderr, "Found 0 note")
@@ -77,6 +114,20 @@ func (cmd *Edit) Run(container *cli.Container) error {
 	}
 }
 
+// newNote creates and edits a note titled after the --search query, in the
+// same way as `zk new`.
+func (cmd *Edit) newNote(container *cli.Container, notebook *core.Notebook) error {
+	directory := "."
+	if dir := cmd.newNoteDir(notebook); dir != nil {
+		directory = dir.Path
+	}
+	newCmd := New{
+		Directory: directory,
+		Title:     cmd.Search,
+	}
+	return newCmd.Run(container)
+}
+
 // newNoteDir returns the directory in which to create a new note when the fzf
 // binding is triggered.
 func (cmd *Edit) newNoteDir(notebook *core.Notebook) *core.Dir {
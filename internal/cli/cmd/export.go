@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"os"
+
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/adapter/markdown"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/strings"
+)
+
+// Export bundles the notes matching a set of criteria into a single document.
+type Export struct {
+	Format string `group:format short:f default:markdown help:"Format of the exported document among: markdown, html." enum:"markdown,html"`
+	Output string `group:format short:o placeholder:PATH help:"Write the exported document to the given file instead of the standard output."`
+	TOC    bool   `group:format name:toc help:"Prepend a table of contents linking to each note."`
+	Quiet  bool   `group:format short:q help:"Do not print the total number of notes found."`
+	cli.Filtering
+}
+
+func (cmd *Export) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	findOpts, err := cmd.Filtering.NewNoteFindOpts(notebook)
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+
+	notes, err := notebook.FindNotes(findOpts)
+	if err != nil {
+		return err
+	}
+
+	filter := container.NewNoteFilter(fzf.NoteFilterOpts{
+		Interactive:  cmd.Interactive,
+		AlwaysFilter: false,
+		NotebookDir:  notebook.Path,
+	})
+
+	notes, err = filter.Apply(notes)
+	if err != nil {
+		if err == fzf.ErrCancelled {
+			return nil
+		}
+		return err
+	}
+
+	content, err := notebook.ExportNotes(notes, core.NoteExportOpts{
+		TOC: cmd.TOC,
+	})
+	if err != nil {
+		return err
+	}
+
+	if cmd.Format == "html" {
+		body, err := markdown.RenderHTML(content)
+		if err != nil {
+			return errors.Wrap(err, "failed to render the HTML document")
+		}
+		content = fmt.Sprintf(exportHTMLTemplate, html.EscapeString(notebook.Config.Note.Lang), body)
+	}
+
+	if cmd.Output == "" {
+		fmt.Print(content)
+	} else {
+		path, err := container.FS.Abs(cmd.Output)
+		if err != nil {
+			return err
+		}
+		err = container.FS.Write(path, []byte(content))
+		if err != nil {
+			return errors.Wrapf(err, "%s: failed to write the exported document", cmd.Output)
+		}
+	}
+
+	if !cmd.Quiet {
+		count := len(notes)
+		fmt.Fprintf(os.Stderr, "\nExported %d %s\n", count, strings.Pluralize("note", count))
+	}
+
+	return nil
+}
+
+const exportHTMLTemplate = `<!DOCTYPE html>
+<html lang="%s">
+<head>
+<meta charset="utf-8">
+</head>
+<body>
+%s</body>
+</html>
+`
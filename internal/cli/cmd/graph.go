@@ -25,7 +25,7 @@ func (cmd *Graph) Run(container *cli.Container) error {
 		return err
 	}
 
-	format, err := notebook.NewNoteFormatter("{{json .}}")
+	format, err := notebook.NewNoteFormatter("{{json .}}", core.PathStyleRelative)
 	if err != nil {
 		return err
 	}
@@ -1,21 +1,26 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"time"
 
+	"github.com/schollz/progressbar/v3"
+	"github.com/zk-org/zk/internal/api"
 	"github.com/zk-org/zk/internal/cli"
 	"github.com/zk-org/zk/internal/core"
-	"github.com/zk-org/zk/internal/util/paths"
-	"github.com/schollz/progressbar/v3"
+	"github.com/zk-org/zk/internal/util/errors"
 )
 
 // Index indexes the content of all the notes in the notebook.
 type Index struct {
-	Force   bool `short:"f" help:"Force indexing all the notes."`
-	Verbose bool `short:"v" xor:"print" help:"Print detailed information about the indexing process."`
-	Quiet   bool `short:"q" xor:"print" help:"Do not print statistics nor progress."`
+	Force       bool     `short:"f" help:"Force indexing all the notes."`
+	Rebuild     bool     `help:"Drop the index and rebuild it from scratch, keeping the previous one if it fails."`
+	Verbose     bool     `short:"v" xor:"print" help:"Print detailed information about the indexing process."`
+	Quiet       bool     `short:"q" xor:"print" help:"Do not print statistics nor progress."`
+	StatsFormat string   `placeholder:FORMAT default:"text" enum:"text,json" help:"Format of the statistics among: text, json."`
+	Include     []string `placeholder:GLOB sep:"none" help:"Index the files matching the glob in addition to the notes, e.g. **/*.canvas. They stay indexed until the index is rebuilt."`
 }
 
 func (cmd *Index) Help() string {
@@ -32,6 +37,10 @@ func (cmd *Index) Run(container *cli.Container) error {
 }
 
 func (cmd *Index) RunWithNotebook(container *cli.Container, notebook *core.Notebook) error {
+	if cmd.Verbose && cmd.StatsFormat == "json" {
+		return errors.New("--verbose can't be used with --stats-format json")
+	}
+
 	showProgress := container.Terminal.IsInteractive()
 
 	var bar *progressbar.ProgressBar
@@ -43,16 +52,17 @@ func (cmd *Index) RunWithNotebook(container *cli.Container, notebook *core.Noteb
 		)
 	}
 
-	opts := core.NoteIndexOpts{
+	stats, err := api.NewNotebook(notebook).Index(api.IndexOpts{
 		Force:   cmd.Force,
+		Rebuild: cmd.Rebuild,
 		Verbose: cmd.Verbose,
-	}
-
-	stats, err := notebook.IndexWithCallback(opts, func(change paths.DiffChange) {
-		if showProgress {
-			bar.Add(1)
-			bar.Describe(change.String())
-		}
+		Include: cmd.Include,
+		Progress: func(change string) {
+			if showProgress {
+				bar.Add(1)
+				bar.Describe(change)
+			}
+		},
 	})
 
 	if showProgress {
@@ -63,7 +73,16 @@ func (cmd *Index) RunWithNotebook(container *cli.Container, notebook *core.Noteb
 		return err
 	}
 
-	if !cmd.Quiet {
+	if cmd.Quiet {
+		return nil
+	}
+	if cmd.StatsFormat == "json" {
+		out, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	} else {
 		fmt.Println(stats)
 	}
 
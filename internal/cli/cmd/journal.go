@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	dateutil "github.com/zk-org/zk/internal/util/date"
+)
+
+// Journal creates or opens the daily journal note of a given date.
+type Journal struct {
+	Date      string `placeholder:DATE xor:"date" help:"Open the journal note of the given date, e.g. 'yesterday' or '2021-02-16'."`
+	Yesterday bool   `xor:"date"                  help:"Open the journal note of yesterday."`
+	Tomorrow  bool   `xor:"date"                  help:"Open the journal note of tomorrow."`
+	PrintPath bool   `short:p                     help:"Print the path of the journal note instead of editing it."`
+}
+
+func (cmd *Journal) Help() string {
+	return "The path of the journal notes is set with the `journal.path` template in the config, e.g. `journal/{{format-date now}}.md`."
+}
+
+func (cmd *Journal) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	date, err := cmd.date(container.Now.Date())
+	if err != nil {
+		return err
+	}
+
+	path, _, err := notebook.JournalNote(core.JournalNoteOpts{
+		Date: date,
+	})
+	if err != nil {
+		return err
+	}
+
+	if cmd.PrintPath {
+		fmt.Println(path)
+		return nil
+	}
+
+	editor, err := container.NewNoteEditor(notebook)
+	if err != nil {
+		return err
+	}
+	return editor.Open(path)
+}
+
+// date returns the date of the journal note to open.
+func (cmd *Journal) date(now time.Time) (time.Time, error) {
+	switch {
+	case cmd.Yesterday:
+		return now.AddDate(0, 0, -1), nil
+	case cmd.Tomorrow:
+		return now.AddDate(0, 0, 1), nil
+	case cmd.Date != "":
+		return dateutil.TimeFromNatural(cmd.Date)
+	default:
+		return now, nil
+	}
+}
@@ -1,32 +1,56 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/adapter/git"
 	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
 	"github.com/zk-org/zk/internal/util/errors"
-	"github.com/zk-org/zk/internal/util/strings"
+	strutil "github.com/zk-org/zk/internal/util/strings"
 )
 
 // List displays notes matching a set of criteria.
 type List struct {
 	Format     string `group:format short:f placeholder:TEMPLATE   help:"Pretty print the list using a custom template or one of the predefined formats: oneline, short, medium, long, full, json, jsonl."`
-	Header     string `group:format                                help:"Arbitrary text printed at the start of the list."`
-	Footer     string `group:format default:\n                     help:"Arbitrary text printed at the end of the list."`
+	PathStyle  string `group:format placeholder:STYLE enum:"relative,notebook,absolute,id" default:relative help:"Print the note paths relative to the working directory (relative) or the notebook (notebook), as absolute paths (absolute) or as note IDs (id)."`
+	Header     string `group:format                                help:"Template printed at the start of the list."`
+	Footer     string `group:format default:\n                     help:"Template printed at the end of the list."`
 	Delimiter  string "group:format short:d default:\n             help:\"Print notes delimited by the given separator.\""
 	Delimiter0 bool   "group:format short:0 name:delimiter0        help:\"Print notes delimited by ASCII NUL characters. This is useful when used in conjunction with `xargs -0`.\""
+	Wrap       string `group:format placeholder:WIDTH              help:"Hard-wrap the notes at the given width, or at the terminal width with 'auto'."`
 	NoPager    bool   `group:format short:P help:"Do not pipe output into a pager."`
 	Quiet      bool   `group:format short:q help:"Do not print the total number of notes found."`
+	HTML       bool   `group:format help:"Include the body of the notes rendered as HTML in the JSON formats."`
+	Into       string `group:format placeholder:PATH help:"Write the list into the given file, between the <!-- zk:NAME --> and <!-- /zk:NAME --> markers of the --section, instead of printing it."`
+	Section    string `group:format placeholder:NAME help:"Name of the section markers replaced with --into."`
+	Gaps       string `group:format placeholder:REGEX help:"Print the ranges of numbers missing from the sequence captured by the first group of the given regular expression in the note paths, instead of the notes."`
 	cli.Filtering
+
+	CreatedToday  bool     `group:filter help:"Find notes created today."`
+	ModifiedToday bool     `group:filter help:"Find notes modified today."`
+	StaleDays     int      `group:filter placeholder:COUNT help:"Find notes which were not modified during the given number of days."`
+	Author        string   `group:filter placeholder:NAME help:"Find notes whose last git commit was made by the given author name or email."`
+	ModifiedByMe  bool     `group:filter help:"Find notes whose last git commit was made by the current git user."`
+	NotMatch      []string `group:filter placeholder:QUERY help:"Exclude notes whose body matches the given full-text query."`
+	Group         string   `group:filter placeholder:NAME help:"Find notes belonging to the given config group, matching the path of their directory."`
+
+	Recent bool `group:sort help:"List the most recently modified notes first, up to 20 notes unless --limit is given."`
 }
 
 func (cmd *List) Run(container *cli.Container) error {
-	cmd.Header = strings.ExpandWhitespaceLiterals(cmd.Header)
-	cmd.Footer = strings.ExpandWhitespaceLiterals(cmd.Footer)
-	cmd.Delimiter = strings.ExpandWhitespaceLiterals(cmd.Delimiter)
+	cmd.Header = strutil.ExpandWhitespaceLiterals(cmd.Header)
+	cmd.Footer = strutil.ExpandWhitespaceLiterals(cmd.Footer)
+	cmd.Delimiter = strutil.ExpandWhitespaceLiterals(cmd.Delimiter)
 
 	if cmd.Delimiter0 {
 		if cmd.Delimiter != "\n" {
@@ -43,6 +67,10 @@ func (cmd *List) Run(container *cli.Container) error {
 		cmd.Footer = "\x00"
 	}
 
+	if cmd.HTML && cmd.Format != "json" && cmd.Format != "jsonl" {
+		return errors.New("--html can only be used with JSON format")
+	}
+
 	if cmd.Format == "json" || cmd.Format == "jsonl" {
 		if cmd.Header != "" {
 			return errors.New("--header can't be used with JSON format")
@@ -53,6 +81,9 @@ func (cmd *List) Run(container *cli.Container) error {
 		if cmd.Delimiter != "\n" {
 			return errors.New("--delimiter can't be used with JSON format")
 		}
+		if cmd.Wrap != "" {
+			return errors.New("--wrap can't be used with JSON format")
+		}
 
 		switch cmd.Format {
 		case "json":
@@ -69,12 +100,35 @@ func (cmd *List) Run(container *cli.Container) error {
 		}
 	}
 
+	wrapWidth, err := cmd.wrapWidth(container)
+	if err != nil {
+		return err
+	}
+	gapsRegex, err := cmd.gapsRegex()
+	if err != nil {
+		return err
+	}
+	if (cmd.Into == "") != (cmd.Section == "") {
+		return errors.New("--into and --section must be used together")
+	}
+	if cmd.Section != "" && !sectionNameRegex.MatchString(cmd.Section) {
+		return fmt.Errorf("%s: invalid --section name, expected letters, digits, dashes, underscores or dots", cmd.Section)
+	}
+
 	notebook, err := container.CurrentNotebook()
 	if err != nil {
 		return err
 	}
 
-	format, err := notebook.NewNoteFormatter(cmd.noteTemplate())
+	format, err := notebook.NewNoteListFormatter(cmd.noteTemplate(), core.PathStyle(cmd.PathStyle), cmd.HTML)
+	if err != nil {
+		return err
+	}
+	header, err := notebook.NewNoteListHeaderFormatter(cmd.Header)
+	if err != nil {
+		return err
+	}
+	footer, err := notebook.NewNoteListHeaderFormatter(cmd.Footer)
 	if err != nil {
 		return err
 	}
@@ -83,6 +137,26 @@ func (cmd *List) Run(container *cli.Container) error {
 	if err != nil {
 		return errors.Wrapf(err, "incorrect criteria")
 	}
+	findOpts, err = cmd.todayFindOpts(findOpts, container.Now.Date())
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+	findOpts, err = cmd.staleFindOpts(findOpts, container.Now.Date())
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+	findOpts, err = cmd.authorFindOpts(findOpts, notebook, container.Logger)
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+	findOpts, err = cmd.groupFindOpts(findOpts, notebook)
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+	findOpts.NotMatch = cmd.NotMatch
+	if cmd.Recent {
+		findOpts = recentNotesFindOpts(findOpts)
+	}
 
 	notes, err := notebook.FindNotes(findOpts)
 	if err != nil {
@@ -103,38 +177,311 @@ func (cmd *List) Run(container *cli.Container) error {
 		return err
 	}
 
+	if gapsRegex != nil {
+		return cmd.printGaps(container, notes, gapsRegex)
+	}
+
 	count := len(notes)
-	if count > 0 {
-		err = container.Paginate(cmd.NoPager, func(out io.Writer) error {
-			if cmd.Header != "" {
-				fmt.Fprint(out, cmd.Header)
-			}
-			for i, note := range notes {
-				if i > 0 {
-					fmt.Fprint(out, cmd.Delimiter)
-				}
-
-				ft, err := format(note)
-				if err != nil {
-					return err
-				}
-				fmt.Fprint(out, ft)
-			}
-			if cmd.Footer != "" {
-				fmt.Fprint(out, cmd.Footer)
-			}
+	print := func(out io.Writer) error {
+		return cmd.printNotes(out, notes, format, header, footer, wrapWidth)
+	}
 
-			return nil
-		})
+	if cmd.Into != "" {
+		err = cmd.printInto(print)
+	} else if count > 0 {
+		err = container.Paginate(cmd.NoPager, print)
 	}
 
 	if err == nil && !cmd.Quiet {
-		fmt.Fprintf(os.Stderr, "\nFound %d %s\n", count, strings.Pluralize("note", count))
+		fmt.Fprintf(os.Stderr, "\nFound %d %s\n", count, strutil.Pluralize("note", count))
 	}
 
 	return err
 }
 
+// printNotes renders the list of notes with its header and footer. Nothing is
+// printed when there are no notes.
+func (cmd *List) printNotes(out io.Writer, notes []core.ContextualNote, format core.NoteListFormatter, header core.NoteListHeaderFormatter, footer core.NoteListHeaderFormatter, wrapWidth int) error {
+	count := len(notes)
+	if count == 0 {
+		return nil
+	}
+
+	ft, err := header(count)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(out, ft)
+
+	for i, note := range notes {
+		if i > 0 {
+			fmt.Fprint(out, cmd.Delimiter)
+		}
+
+		ft, err := format(note, i, count)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, strutil.Wrap(ft, wrapWidth))
+	}
+
+	ft, err = footer(count)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(out, ft)
+
+	return nil
+}
+
+var sectionNameRegex = regexp.MustCompile(`^[\pL\pN_.-]+$`)
+
+// printInto replaces the section of the file given with --into by the list,
+// creating the section at the end of the file if needed. The file is left
+// untouched when the list didn't change.
+func (cmd *List) printInto(print func(out io.Writer) error) error {
+	var out bytes.Buffer
+	err := print(&out)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(cmd.Into)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	updated := core.NamedSection(cmd.Section).Replace(string(content), out.String())
+	if updated == string(content) {
+		return nil
+	}
+	return os.WriteFile(cmd.Into, []byte(updated), 0644)
+}
+
+// wrapWidth returns the width at which the notes are hard-wrapped, or 0 to
+// disable wrapping.
+func (cmd *List) wrapWidth(container *cli.Container) (int, error) {
+	switch cmd.Wrap {
+	case "":
+		return 0, nil
+	case "auto":
+		return container.Terminal.Width(), nil
+	default:
+		width, err := strconv.Atoi(cmd.Wrap)
+		if err != nil || width < 0 {
+			return 0, fmt.Errorf("%s: invalid --wrap width, expected a number of columns or 'auto'", cmd.Wrap)
+		}
+		return width, nil
+	}
+}
+
+// gapsRegex returns the regular expression given with --gaps, or nil.
+func (cmd *List) gapsRegex() (*regexp.Regexp, error) {
+	if cmd.Gaps == "" {
+		return nil, nil
+	}
+	if cmd.Format != "" {
+		return nil, errors.New("--gaps can't be used with --format")
+	}
+	regex, err := regexp.Compile(cmd.Gaps)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: invalid --gaps regular expression", cmd.Gaps)
+	}
+	if regex.NumSubexp() == 0 {
+		return nil, fmt.Errorf("%s: --gaps expects a regular expression with a group capturing the sequence number", cmd.Gaps)
+	}
+	return regex, nil
+}
+
+// printGaps prints the ranges of numbers missing from the sequence numbered
+// by the given notes, one per line.
+func (cmd *List) printGaps(container *cli.Container, notes []core.ContextualNote, regex *regexp.Regexp) error {
+	paths := make([]string, 0, len(notes))
+	for _, note := range notes {
+		paths = append(paths, note.Path)
+	}
+	gaps := sequenceGaps(paths, regex)
+
+	err := container.Paginate(cmd.NoPager, func(out io.Writer) error {
+		for _, gap := range gaps {
+			fmt.Fprintln(out, gap)
+		}
+		return nil
+	})
+
+	if err == nil && !cmd.Quiet {
+		fmt.Fprintf(os.Stderr, "\nFound %d %s\n", len(gaps), strutil.Pluralize("gap", len(gaps)))
+	}
+	return err
+}
+
+// sequenceGap is a range of consecutive numbers missing from a sequence.
+type sequenceGap struct {
+	Start int
+	End   int
+}
+
+func (g sequenceGap) String() string {
+	if g.Start == g.End {
+		return strconv.Itoa(g.Start)
+	}
+	return fmt.Sprintf("%d-%d", g.Start, g.End)
+}
+
+// sequenceGaps returns the numbers missing between the lowest and highest
+// numbers captured by the first group of the regex in the given paths. The
+// paths which don't match or don't capture a number are ignored.
+func sequenceGaps(paths []string, regex *regexp.Regexp) []sequenceGap {
+	numbers := []int{}
+	for _, path := range paths {
+		match := regex.FindStringSubmatch(path)
+		if len(match) < 2 {
+			continue
+		}
+		if number, err := strconv.Atoi(match[1]); err == nil {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+
+	gaps := []sequenceGap{}
+	for i := 1; i < len(numbers); i++ {
+		if numbers[i] > numbers[i-1]+1 {
+			gaps = append(gaps, sequenceGap{Start: numbers[i-1] + 1, End: numbers[i] - 1})
+		}
+	}
+	return gaps
+}
+
+// todayFindOpts restricts the notes to the ones created or modified on the
+// day of the given date, with --created-today and --modified-today.
+func (cmd *List) todayFindOpts(opts core.NoteFindOpts, now time.Time) (core.NoteFindOpts, error) {
+	start, end := cli.DayRange(now.Local())
+
+	if cmd.CreatedToday {
+		if opts.CreatedStart != nil || opts.CreatedEnd != nil {
+			return opts, errors.New("--created-today can't be used with --created, --created-on, --created-before or --created-after")
+		}
+		opts.CreatedStart = &start
+		opts.CreatedEnd = &end
+	}
+	if cmd.ModifiedToday {
+		if opts.ModifiedStart != nil || opts.ModifiedEnd != nil {
+			return opts, errors.New("--modified-today can't be used with --modified, --modified-on, --modified-before or --modified-after")
+		}
+		opts.ModifiedStart = &start
+		opts.ModifiedEnd = &end
+	}
+
+	return opts, nil
+}
+
+// staleFindOpts restricts the notes to the ones not modified during the
+// number of days given with --stale-days, counted from the start of the day
+// of the given date in the local timezone. A note modified yesterday is stale
+// for one day.
+func (cmd *List) staleFindOpts(opts core.NoteFindOpts, now time.Time) (core.NoteFindOpts, error) {
+	if cmd.StaleDays == 0 {
+		return opts, nil
+	}
+	if cmd.StaleDays < 0 {
+		return opts, fmt.Errorf("%d: --stale-days expects a positive number of days", cmd.StaleDays)
+	}
+	if opts.ModifiedStart != nil || opts.ModifiedEnd != nil {
+		return opts, errors.New("--stale-days can't be used with --modified, --modified-on, --modified-before, --modified-after or --modified-today")
+	}
+
+	year, month, day := now.Local().Date()
+	end := time.Date(year, month, day-cmd.StaleDays+1, 0, 0, 0, 0, time.Local)
+	opts.ModifiedEnd = &end
+	return opts, nil
+}
+
+// authorFindOpts excludes the notes whose last git commit was not made by the
+// author given with --author or --modified-by-me. The filter is ignored with
+// a warning when the notebook is not in a git repository.
+func (cmd *List) authorFindOpts(opts core.NoteFindOpts, notebook *core.Notebook, logger util.Logger) (core.NoteFindOpts, error) {
+	if cmd.Author == "" && !cmd.ModifiedByMe {
+		return opts, nil
+	}
+	if cmd.Author != "" && cmd.ModifiedByMe {
+		return opts, errors.New("--author and --modified-by-me can't be used together")
+	}
+	if !git.IsRepository(notebook.Path) {
+		logger.Err(errors.New("the notebook is not in a git repository, ignoring --author and --modified-by-me"))
+		return opts, nil
+	}
+
+	author := cmd.Author
+	if cmd.ModifiedByMe {
+		name, err := git.UserName(notebook.Path)
+		if err != nil {
+			return opts, errors.Wrap(err, "--modified-by-me")
+		}
+		author = name
+	}
+
+	authors, err := git.LastAuthors(notebook.Path)
+	if err != nil {
+		return opts, err
+	}
+	notes, err := notebook.FindMinimalNotes(core.NoteFindOpts{})
+	if err != nil {
+		return opts, err
+	}
+
+	excludedIDs := []core.NoteID{}
+	for _, note := range notes {
+		if last, ok := authors[note.Path]; !ok || !last.Matches(author) {
+			excludedIDs = append(excludedIDs, note.ID)
+		}
+	}
+	return opts.ExcludingIDs(excludedIDs), nil
+}
+
+// groupFindOpts restricts the notes to the ones belonging to the config group
+// given with --group.
+func (cmd *List) groupFindOpts(opts core.NoteFindOpts, notebook *core.Notebook) (core.NoteFindOpts, error) {
+	if cmd.Group == "" {
+		return opts, nil
+	}
+	if _, ok := notebook.Config.Groups[cmd.Group]; !ok {
+		return opts, fmt.Errorf("no group named `%s` found in the config", cmd.Group)
+	}
+
+	notes, err := notebook.FindMinimalNotes(core.NoteFindOpts{})
+	if err != nil {
+		return opts, err
+	}
+
+	ids := []core.NoteID{}
+	for _, note := range notes {
+		group, err := notebook.Config.GroupNameForNote(note.Path)
+		if err != nil {
+			return opts, err
+		}
+		if group == cmd.Group {
+			ids = append(ids, note.ID)
+		}
+	}
+	return opts.IncludingIDs(ids), nil
+}
+
+// recentNotesLimit is the default number of notes listed with --recent.
+const recentNotesLimit = 20
+
+// recentNotesFindOpts sorts the notes by modification date, most recent
+// first, keeping any other sorting criteria as tie-breakers. The number of
+// notes is limited to recentNotesLimit, unless a limit was already given.
+func recentNotesFindOpts(opts core.NoteFindOpts) core.NoteFindOpts {
+	opts.Sorters = append([]core.NoteSorter{
+		{Field: core.NoteSortModified, Ascending: false},
+	}, opts.Sorters...)
+	if opts.Limit == 0 {
+		opts.Limit = recentNotesLimit
+	}
+	return opts
+}
+
 func (cmd *List) noteTemplate() string {
 	format := cmd.Format
 	if format == "" {
@@ -143,7 +490,7 @@ func (cmd *List) noteTemplate() string {
 
 	templ, ok := defaultNoteFormats[format]
 	if !ok {
-		templ = strings.ExpandWhitespaceLiterals(format)
+		templ = strutil.ExpandWhitespaceLiterals(format)
 	}
 
 	return templ
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"regexp"
 	"testing"
+	"time"
 
+	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util/test/assert"
 )
 
@@ -65,3 +68,79 @@ func TestListFormatCustom(t *testing.T) {
 	// \n and \t in custom formats are expanded.
 	test(`{{title}}\t{{path}}\n{{snippet}}`, "{{title}}\t{{path}}\n{{snippet}}")
 }
+
+func TestListRecentNotesFindOpts(t *testing.T) {
+	opts := recentNotesFindOpts(core.NoteFindOpts{})
+	assert.Equal(t, opts.Sorters, []core.NoteSorter{
+		{Field: core.NoteSortModified, Ascending: false},
+	})
+	assert.Equal(t, opts.Limit, 20)
+
+	// An explicit limit and other sorting criteria are kept.
+	opts = recentNotesFindOpts(core.NoteFindOpts{
+		Limit:   5,
+		Sorters: []core.NoteSorter{{Field: core.NoteSortTitle, Ascending: true}},
+	})
+	assert.Equal(t, opts.Sorters, []core.NoteSorter{
+		{Field: core.NoteSortModified, Ascending: false},
+		{Field: core.NoteSortTitle, Ascending: true},
+	})
+	assert.Equal(t, opts.Limit, 5)
+}
+
+func TestListStaleFindOpts(t *testing.T) {
+	now := time.Date(2022, 3, 10, 15, 30, 0, 0, time.Local)
+
+	opts, err := (&List{}).staleFindOpts(core.NoteFindOpts{}, now)
+	assert.Nil(t, err)
+	assert.Nil(t, opts.ModifiedEnd)
+
+	opts, err = (&List{StaleDays: 1}).staleFindOpts(core.NoteFindOpts{}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, *opts.ModifiedEnd, time.Date(2022, 3, 10, 0, 0, 0, 0, time.Local))
+
+	opts, err = (&List{StaleDays: 30}).staleFindOpts(core.NoteFindOpts{}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, *opts.ModifiedEnd, time.Date(2022, 2, 9, 0, 0, 0, 0, time.Local))
+
+	_, err = (&List{StaleDays: -2}).staleFindOpts(core.NoteFindOpts{}, now)
+	assert.Err(t, err, "-2: --stale-days expects a positive number of days")
+
+	_, err = (&List{StaleDays: 2}).staleFindOpts(core.NoteFindOpts{ModifiedEnd: &now}, now)
+	assert.Err(t, err, "--stale-days can't be used with --modified, --modified-on, --modified-before, --modified-after or --modified-today")
+}
+
+func TestListGapsRegex(t *testing.T) {
+	regex, err := (&List{}).gapsRegex()
+	assert.Nil(t, err)
+	assert.Nil(t, regex)
+
+	regex, err = (&List{Gaps: `(\d+)\.md$`}).gapsRegex()
+	assert.Nil(t, err)
+	assert.NotNil(t, regex)
+
+	_, err = (&List{Gaps: `\d+\.md$`}).gapsRegex()
+	assert.Err(t, err, `\d+\.md$: --gaps expects a regular expression with a group capturing the sequence number`)
+
+	_, err = (&List{Gaps: `(\d+`}).gapsRegex()
+	assert.Err(t, err, `(\d+: invalid --gaps regular expression`)
+
+	_, err = (&List{Gaps: `(\d+)`, Format: "path"}).gapsRegex()
+	assert.Err(t, err, "--gaps can't be used with --format")
+}
+
+func TestSequenceGaps(t *testing.T) {
+	test := func(paths []string, expected []string) {
+		actual := []string{}
+		for _, gap := range sequenceGaps(paths, regexp.MustCompile(`(\d+)\.md$`)) {
+			actual = append(actual, gap.String())
+		}
+		assert.Equal(t, actual, expected)
+	}
+
+	test([]string{}, []string{})
+	test([]string{"1.md", "2.md", "3.md"}, []string{})
+	test([]string{"journal/001.md", "journal/002.md", "journal/005.md", "journal/007.md"}, []string{"3-4", "6"})
+	// Unordered paths, duplicates and the paths without a number are fine.
+	test([]string{"10.md", "8.md", "index.md", "8.md", "12.md"}, []string{"9", "11"})
+}
@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/zk-org/zk/internal/adapter/term"
 	"github.com/zk-org/zk/internal/cli"
 	"github.com/zk-org/zk/internal/core"
 	dateutil "github.com/zk-org/zk/internal/util/date"
@@ -18,31 +23,50 @@ import (
 type New struct {
 	Directory   string            `arg optional default:"." help:"Directory in which to create the note."`
 	Interactive bool              `short:i                  help:"Read contents from standard input."`
-	Title       string            `short:t   placeholder:TITLE help:"Title of the new note."`
+	FromStdin   bool              `                         help:"Read the content of the note from standard input and print its path instead of editing it."`
+	Title       string            `short:t   placeholder:TITLE help:"Title of the new note." xor:"title"`
+	TitlesFile  string            `          placeholder:PATH  help:"Create one note per line of the given file, using the line as title. Prints the paths of the created notes." xor:"title"`
 	Date        string            `          placeholder:DATE  help:"Set the current date."`
 	Group       string            `short:g   placeholder:NAME  help:"Name of the config group this note belongs to. Takes precedence over the config of the directory."`
 	Extra       map[string]string `                            help:"Extra variables passed to the templates." mapsep:","`
 	Template    string            `          placeholder:PATH  help:"Custom template used to render the note."`
 	PrintPath   bool              `short:p                     help:"Print the path of the created note instead of editing it."`
+	Open        bool              `                            help:"Open the created note in the editor, even when printing its path or format. Ignored with --no-input."`
+	Format      string            `short:f   placeholder:FORMAT help:"Print the created note in the given format instead of editing it, among: json."`
 	DryRun      bool              `short:n                     help:"Don't actually create the note. Instead, prints its content on stdout and the generated path on stderr."`
 	ID          string            `          placeholder:ID    help:"Skip id generation and use provided value."`
+	LinkFrom    string            `          placeholder:PATH  help:"Add a link to the new note in an existing note."`
+	LinkSection string            `          placeholder:HEADING default:"## Links" help:"Section of the --link-from and --parent notes in which the links are appended."`
+	Parent      string            `          placeholder:PATH  help:"Create a child of an existing note, with an ID derived from the parent's (e.g. 1a under 1) and links between both notes."`
 }
 
 func (cmd *New) Run(container *cli.Container) error {
+	if cmd.Format != "" && cmd.Format != "json" {
+		return fmt.Errorf("%s: unknown format, expected json", cmd.Format)
+	}
+	if cmd.Open && cmd.DryRun {
+		return errors.New("--open can't be used with --dry-run, as no note is created")
+	}
+
 	notebook, err := container.CurrentNotebook()
 	if err != nil {
 		return err
 	}
 
 	var content []byte
-	if cmd.Interactive {
+	if cmd.FromStdin {
+		content, err = readNoteContentFromStdin(container.Terminal)
+		if err != nil {
+			return err
+		}
+	} else if cmd.Interactive {
 		content, err = io.ReadAll(os.Stdin)
 		if err != nil {
 			return err
 		}
 	}
 
-	date := time.Now()
+	date := container.Now.Date()
 	if cmd.Date != "" {
 		date, err = dateutil.TimeFromNatural(cmd.Date)
 		if err != nil {
@@ -50,34 +74,63 @@ func (cmd *New) Run(container *cli.Container) error {
 		}
 	}
 
-	note, err := notebook.NewNote(core.NewNoteOpts{
-		Title:     opt.NewNotEmptyString(cmd.Title),
-		Content:   string(content),
-		Directory: opt.NewNotEmptyString(cmd.Directory),
-		Group:     opt.NewNotEmptyString(cmd.Group),
-		Template:  opt.NewNotEmptyString(cmd.Template),
-		Extra:     cmd.Extra,
-		Date:      date,
-		DryRun:    cmd.DryRun,
-		ID:        cmd.ID,
-	})
+	if cmd.TitlesFile != "" {
+		return cmd.newFromTitlesFile(container, notebook, string(content), date)
+	}
+
+	note, err := notebook.NewNote(cmd.newNoteOpts(cmd.Title, string(content), date))
 
 	if cmd.DryRun {
 		if err != nil {
 			return err
 		}
 		path := filepath.Join(notebook.Path, note.Path)
-		fmt.Fprintln(os.Stderr, path)
-		fmt.Print(note.RawContent)
+		if cmd.Format == "json" {
+			err = printNewNoteJSON(path, note)
+			if err != nil {
+				return err
+			}
+		} else {
+			fmt.Fprintln(os.Stderr, path)
+			fmt.Print(note.RawContent)
+		}
+
+		for _, source := range cmd.linkSources() {
+			content, changed, err := cmd.linkFrom(notebook, note, source)
+			if err != nil {
+				return err
+			}
+			if changed {
+				fmt.Fprintln(os.Stderr, source)
+				fmt.Fprint(os.Stderr, content)
+			}
+		}
 		return nil
 	}
 
 	var path string
 	if err == nil {
 		path = filepath.Join(notebook.Path, note.Path)
+
+		for _, source := range cmd.linkSources() {
+			_, _, err = cmd.linkFrom(notebook, note, source)
+			if err != nil {
+				return err
+			}
+		}
+
+		if cmd.Format == "json" {
+			err = printNewNoteJSON(path, note)
+			if err != nil {
+				return err
+			}
+			return cmd.openNotes(container, notebook, path)
+		}
 	} else {
 		var noteExists core.ErrNoteExists
-		if !errors.As(err, &noteExists) {
+		// Structured output is meant for scripts, which can't answer the
+		// prompt. Neither can a note created from piped content.
+		if !errors.As(err, &noteExists) || cmd.Format == "json" || cmd.FromStdin {
 			return err
 		}
 
@@ -92,9 +145,9 @@ func (cmd *New) Run(container *cli.Container) error {
 		path = noteExists.Path
 	}
 
-	if cmd.PrintPath {
+	if cmd.PrintPath || cmd.FromStdin {
 		fmt.Printf("%+v\n", path)
-		return nil
+		return cmd.openNotes(container, notebook, path)
 	} else {
 		editor, err := container.NewNoteEditor(notebook)
 		if err != nil {
@@ -103,3 +156,163 @@ func (cmd *New) Run(container *cli.Container) error {
 		return editor.Open(path)
 	}
 }
+
+// newFromTitlesFile creates one note for each non-empty line of the file
+// given with --titles-file. The notes which already exist are skipped.
+func (cmd *New) newFromTitlesFile(container *cli.Container, notebook *core.Notebook, content string, date time.Time) error {
+	titles, err := readTitlesFile(cmd.TitlesFile)
+	if err != nil {
+		return err
+	}
+
+	paths := []string{}
+
+	for _, title := range titles {
+		note, err := notebook.NewNote(cmd.newNoteOpts(title, content, date))
+		if err != nil {
+			var noteExists core.ErrNoteExists
+			if errors.As(err, &noteExists) {
+				fmt.Fprintf(os.Stderr, "%s, skipping\n", noteExists)
+				continue
+			}
+			return err
+		}
+
+		for _, source := range cmd.linkSources() {
+			_, _, err = cmd.linkFrom(notebook, note, source)
+			if err != nil {
+				return err
+			}
+		}
+
+		path := filepath.Join(notebook.Path, note.Path)
+		paths = append(paths, path)
+		if cmd.Format == "json" {
+			err = printNewNoteJSON(path, note)
+			if err != nil {
+				return err
+			}
+		} else {
+			fmt.Println(path)
+		}
+	}
+
+	return cmd.openNotes(container, notebook, paths...)
+}
+
+// openNotes opens the created notes in the editor when --open is given,
+// unless the user input is disabled.
+func (cmd *New) openNotes(container *cli.Container, notebook *core.Notebook, paths ...string) error {
+	if !cmd.Open || container.Terminal.NoInput || len(paths) == 0 {
+		return nil
+	}
+
+	editor, err := container.NewNoteEditor(notebook)
+	if err != nil {
+		return err
+	}
+	if notebook.Config.Tool.EditorSequential {
+		return editor.OpenSequentially(paths...)
+	}
+	return editor.Open(paths...)
+}
+
+// readNoteContentFromStdin reads the content of a new note piped to the
+// standard input, which must be UTF-8 text.
+func readNoteContentFromStdin(terminal *term.Terminal) ([]byte, error) {
+	// Reading from a terminal would wait for the user to type the content.
+	if terminal.IsTTY() {
+		return nil, errors.New("--from-stdin expects the content of the note to be piped to the standard input")
+	}
+
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(content) || bytes.IndexByte(content, 0) != -1 {
+		return nil, errors.New("--from-stdin expects UTF-8 text, the standard input looks like binary data")
+	}
+	return content, nil
+}
+
+// readTitlesFile returns the non-empty lines of the given file, trimmed.
+func readTitlesFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the titles file: %w", err)
+	}
+
+	titles := make([]string, 0)
+	for _, line := range strings.Split(string(content), "\n") {
+		if title := strings.TrimSpace(line); title != "" {
+			titles = append(titles, title)
+		}
+	}
+	return titles, nil
+}
+
+func (cmd *New) newNoteOpts(title string, content string, date time.Time) core.NewNoteOpts {
+	directory := cmd.Directory
+	// A child note is created next to its parent, unless a directory is given.
+	if cmd.Parent != "" && directory == "." {
+		directory = ""
+	}
+
+	return core.NewNoteOpts{
+		Title:       opt.NewNotEmptyString(title),
+		Content:     content,
+		Directory:   opt.NewNotEmptyString(directory),
+		Group:       opt.NewNotEmptyString(cmd.Group),
+		Template:    opt.NewNotEmptyString(cmd.Template),
+		Extra:       cmd.Extra,
+		Date:        date,
+		DryRun:      cmd.DryRun,
+		ID:          cmd.ID,
+		Parent:      opt.NewNotEmptyString(cmd.Parent),
+		LinkSection: cmd.LinkSection,
+	}
+}
+
+// linkSources returns the paths of the notes which link to the new note:
+// the ones given with --link-from and --parent.
+func (cmd *New) linkSources() []string {
+	sources := []string{}
+	for _, source := range []string{cmd.LinkFrom, cmd.Parent} {
+		if source != "" {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// linkFrom adds a link to the given note in the source note.
+func (cmd *New) linkFrom(notebook *core.Notebook, note *core.Note, source string) (string, bool, error) {
+	return notebook.LinkFromNote(*note, core.LinkFromNoteOpts{
+		SourcePath: source,
+		Section:    cmd.LinkSection,
+		DryRun:     cmd.DryRun,
+	})
+}
+
+// newNoteJSON is the structured result of `zk new --format json`, a stable
+// contract for editor plugins.
+type newNoteJSON struct {
+	// Absolute path to the created note.
+	Path string `json:"path"`
+	// ID of the note, which is its filename stem, e.g. to insert a wiki link.
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+func printNewNoteJSON(path string, note *core.Note) error {
+	out, err := json.Marshal(newNoteJSON{
+		Path:  path,
+		ID:    note.FilenameStem(),
+		Title: note.Title,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
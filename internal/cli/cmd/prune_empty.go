@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	gostrings "strings"
+
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/strings"
+)
+
+// PruneEmpty lists or removes the notes without content, among the notes
+// matching a set of criteria.
+type PruneEmpty struct {
+	MinWords int  `default:"1" placeholder:COUNT help:"Minimum number of words in the body of a note to keep it."`
+	Delete   bool `help:"Delete the empty notes instead of listing them."`
+	Force    bool `short:f help:"Do not confirm before deleting the notes."`
+	cli.Filtering
+}
+
+func (cmd *PruneEmpty) Run(container *cli.Container) error {
+	if cmd.MinWords < 0 {
+		return fmt.Errorf("%d: --min-words expects a positive number of words", cmd.MinWords)
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	findOpts, err := cmd.Filtering.NewNoteFindOpts(notebook)
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+
+	notes, err := notebook.FindNotes(findOpts)
+	if err != nil {
+		return err
+	}
+
+	filter := container.NewNoteFilter(fzf.NoteFilterOpts{
+		Interactive:  cmd.Interactive,
+		AlwaysFilter: false,
+		NotebookDir:  notebook.Path,
+	})
+
+	notes, err = filter.Apply(notes)
+	if err != nil {
+		if err == fzf.ErrCancelled {
+			return nil
+		}
+		return err
+	}
+
+	empties, err := notebook.FindEmptyNotes(notes, cmd.MinWords)
+	if err != nil {
+		return err
+	}
+
+	format, err := notebook.NewNoteFormatter("{{path}}", core.PathStyleRelative)
+	if err != nil {
+		return err
+	}
+
+	for _, note := range empties {
+		path, err := format(note.ContextualNote)
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		if len(note.Backlinks) > 0 {
+			fmt.Fprintf(os.Stderr, "%s: linked from %s\n", path, gostrings.Join(note.Backlinks, ", "))
+		}
+	}
+
+	count := len(empties)
+	summary := fmt.Sprintf("%d empty %s", count, strings.Pluralize("note", count))
+
+	if !cmd.Delete || count == 0 {
+		fmt.Fprintf(os.Stderr, "\nFound %s\n", summary)
+		return nil
+	}
+
+	if !cmd.Force {
+		confirmed, skipped := container.Terminal.Confirm(fmt.Sprintf("Delete %s?", summary), false)
+		if skipped {
+			return fmt.Errorf("deleting notes requires a confirmation, use --force to skip it")
+		} else if !confirmed {
+			return nil
+		}
+	}
+
+	for _, note := range empties {
+		err := os.Remove(filepath.Join(notebook.Path, note.Path))
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete %s", note.Path)
+		}
+	}
+
+	// Remove the deleted notes from the index.
+	index := Index{Quiet: true}
+	err = index.RunWithNotebook(container, notebook)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "\nDeleted %s\n", summary)
+	return nil
+}
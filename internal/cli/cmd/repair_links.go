@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	gostrings "strings"
+
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/strings"
+)
+
+// RepairLinks rewrites the relative links broken after moving notes manually.
+type RepairLinks struct {
+	DryRun bool `help:"Don't actually update the notes. Instead, prints the links which would be repaired."`
+	Force  bool `short:f help:"Do not confirm before repairing the links."`
+	cli.Filtering
+}
+
+func (cmd *RepairLinks) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	findOpts, err := cmd.Filtering.NewNoteFindOpts(notebook)
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+
+	notes, err := notebook.FindNotes(findOpts)
+	if err != nil {
+		return err
+	}
+
+	filter := container.NewNoteFilter(fzf.NoteFilterOpts{
+		Interactive:  cmd.Interactive,
+		AlwaysFilter: false,
+		NotebookDir:  notebook.Path,
+	})
+
+	notes, err = filter.Apply(notes)
+	if err != nil {
+		if err == fzf.ErrCancelled {
+			return nil
+		}
+		return err
+	}
+
+	links, err := notebook.FindBrokenLinks(notes)
+	if err != nil {
+		return err
+	}
+
+	linkCount := 0
+	sources := map[string]bool{}
+	for _, link := range links {
+		if link.IsRepairable() {
+			linkCount++
+			sources[link.SourcePath] = true
+			fmt.Printf("%s: %s -> %s\n", link.SourcePath, link.Href, link.NewHref)
+		} else if len(link.Candidates) == 0 {
+			fmt.Fprintf(os.Stderr, "%s: %s: no matching note found\n", link.SourcePath, link.Href)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s: ambiguous link, could be %s\n", link.SourcePath, link.Href, gostrings.Join(link.Candidates, ", "))
+		}
+	}
+
+	summary := fmt.Sprintf("%d %s in %d %s",
+		linkCount, strings.Pluralize("link", linkCount),
+		len(sources), strings.Pluralize("note", len(sources)),
+	)
+
+	if cmd.DryRun {
+		fmt.Fprintf(os.Stderr, "\nWould repair %s\n", summary)
+		return nil
+	}
+
+	if !cmd.Force && linkCount > 0 {
+		confirmed, skipped := container.Terminal.Confirm(fmt.Sprintf("Repair %s?", summary), true)
+		if skipped {
+			return fmt.Errorf("repairing links requires a confirmation, use --force to skip it")
+		} else if !confirmed {
+			return nil
+		}
+	}
+
+	count, err := notebook.RepairLinks(links)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "\nRepaired %d %s in %d %s\n",
+		linkCount, strings.Pluralize("link", linkCount),
+		count, strings.Pluralize("note", count),
+	)
+	return nil
+}
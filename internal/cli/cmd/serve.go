@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/zk-org/zk/internal/adapter/httpapi"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/util/date"
+)
+
+// Serve starts a local HTTP server exposing the notes as JSON endpoints.
+type Serve struct {
+	Addr         string        `placeholder:ADDR default:"localhost:8080" help:"TCP address the server listens on."`
+	ReadWrite    bool          `help:"Enable the endpoints creating and editing notes."`
+	PollInterval time.Duration `placeholder:DURATION default:"1s" help:"Delay between two checks for changes in the notebook, while WebSocket clients are listening to /events."`
+}
+
+func (cmd *Serve) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	server := httpapi.NewServer(httpapi.ServerOpts{
+		Notebook:     notebook,
+		FS:           container.FS,
+		ReadWrite:    cmd.ReadWrite,
+		Now:          &date.Now{},
+		Logger:       container.Logger,
+		PollInterval: cmd.PollInterval,
+		Debounce:     250 * time.Millisecond,
+	})
+
+	fmt.Fprintf(os.Stderr, "Serving %s on http://%s\n", notebook.Path, cmd.Addr)
+	return server.ListenAndServe(cmd.Addr)
+}
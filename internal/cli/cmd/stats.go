@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// Stats prints aggregate metrics about the notes matching a set of criteria.
+type Stats struct {
+	Format string `group:format short:f default:text help:"Format of the statistics among: text, json." enum:"text,json"`
+	cli.Filtering
+}
+
+func (cmd *Stats) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	findOpts, err := cmd.Filtering.NewNoteFindOpts(notebook)
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+
+	notes, err := notebook.FindNotes(findOpts)
+	if err != nil {
+		return err
+	}
+
+	filter := container.NewNoteFilter(fzf.NoteFilterOpts{
+		Interactive:  cmd.Interactive,
+		AlwaysFilter: false,
+		NotebookDir:  notebook.Path,
+	})
+
+	notes, err = filter.Apply(notes)
+	if err != nil {
+		if err == fzf.ErrCancelled {
+			return nil
+		}
+		return err
+	}
+
+	stats, err := notebook.Stats(notes)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Format == "json" {
+		out, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	bar := "#"
+	if container.Terminal.SupportsUTF8() {
+		bar = "█"
+	}
+	printStats(os.Stdout, stats, bar)
+	return nil
+}
+
+// statsHistogramWidth is the maximum number of columns of a histogram bar.
+const statsHistogramWidth = 40
+
+func printStats(out io.Writer, stats core.NotebookStats, bar string) {
+	fmt.Fprintf(out, "Notes           %d\n", stats.NoteCount)
+	fmt.Fprintf(out, "Words           %d\n", stats.WordCount)
+	fmt.Fprintf(out, "Links           %d\n", stats.LinkCount)
+	fmt.Fprintf(out, "Links per note  %.1f\n", stats.AverageLinkCount)
+	fmt.Fprintf(out, "Orphans         %d\n", stats.OrphanCount)
+	fmt.Fprintf(out, "Dead links      %d\n", stats.DeadLinkCount)
+	fmt.Fprintf(out, "Tags            %d\n", stats.TagCount)
+
+	if len(stats.Created) == 0 {
+		return
+	}
+
+	max := 0
+	for _, bucket := range stats.Created {
+		if bucket.Count > max {
+			max = bucket.Count
+		}
+	}
+	countWidth := len(fmt.Sprint(max))
+
+	fmt.Fprintln(out, "\nCreated notes per month")
+	for _, bucket := range stats.Created {
+		width := bucket.Count * statsHistogramWidth / max
+		if width == 0 {
+			width = 1
+		}
+		fmt.Fprintf(out, "%s  %*d %s\n", bucket.Period, countWidth, bucket.Count, strings.Repeat(bar, width))
+	}
+}
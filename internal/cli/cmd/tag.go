@@ -26,6 +26,7 @@ type TagList struct {
 	NoPager    bool     `group:format short:P help:"Do not pipe output into a pager."`
 	Quiet      bool     `group:format short:q help:"Do not print the total number of tags found."`
 	Sort       []string `group:sort short:s placeholder:TERM help:"Order the tags by the given criterion."`
+	Rollup     bool     `help:"Count the notes of the nested tags in their parent tags, listing the parents without notes of their own."`
 }
 
 func (cmd *TagList) Run(container *cli.Container) error {
@@ -89,7 +90,12 @@ func (cmd *TagList) Run(container *cli.Container) error {
 		return err
 	}
 
-	tags, err := notebook.FindCollections(core.CollectionKindTag, sorters)
+	var tags []core.Collection
+	if cmd.Rollup {
+		tags, err = notebook.FindNestedCollections(core.CollectionKindTag, sorters)
+	} else {
+		tags, err = notebook.FindCollections(core.CollectionKindTag, sorters)
+	}
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/strings"
+)
+
+// ValidateFrontmatter checks the frontmatter of the notes matching a set of
+// criteria against the schema declared in the config.
+type ValidateFrontmatter struct {
+	Quiet bool `group:format short:q help:"Do not print the violations found."`
+	cli.Filtering
+}
+
+func (cmd *ValidateFrontmatter) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	if len(notebook.Config.FrontmatterSchema) == 0 {
+		return errors.New("no frontmatter schema declared in the config, see the [frontmatter-schema] section")
+	}
+
+	findOpts, err := cmd.Filtering.NewNoteFindOpts(notebook)
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+
+	notes, err := notebook.FindNotes(findOpts)
+	if err != nil {
+		return err
+	}
+
+	filter := container.NewNoteFilter(fzf.NoteFilterOpts{
+		Interactive:  cmd.Interactive,
+		AlwaysFilter: false,
+		NotebookDir:  notebook.Path,
+	})
+
+	notes, err = filter.Apply(notes)
+	if err != nil {
+		if err == fzf.ErrCancelled {
+			return nil
+		}
+		return err
+	}
+
+	violations := notebook.ValidateFrontmatter(notes)
+	if !cmd.Quiet {
+		for _, violation := range violations {
+			fmt.Println(violation)
+		}
+	}
+
+	if count := len(violations); count > 0 {
+		return fmt.Errorf("found %d frontmatter %s", count, strings.Pluralize("violation", count))
+	}
+	return nil
+}
@@ -6,26 +6,27 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/zk-org/zk/internal/adapter"
 	"github.com/zk-org/zk/internal/adapter/editor"
 	"github.com/zk-org/zk/internal/adapter/fs"
 	"github.com/zk-org/zk/internal/adapter/fzf"
 	"github.com/zk-org/zk/internal/adapter/handlebars"
 	hbhelpers "github.com/zk-org/zk/internal/adapter/handlebars/helpers"
-	"github.com/zk-org/zk/internal/adapter/markdown"
-	"github.com/zk-org/zk/internal/adapter/sqlite"
 	"github.com/zk-org/zk/internal/adapter/term"
 	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/date"
 	"github.com/zk-org/zk/internal/util/errors"
 	osutil "github.com/zk-org/zk/internal/util/os"
 	"github.com/zk-org/zk/internal/util/pager"
 	"github.com/zk-org/zk/internal/util/paths"
-	"github.com/zk-org/zk/internal/util/rand"
 )
 
 type Dirs struct {
 	NotebookDir string
-	WorkingDir  string
+	// Name of a notebook registered in the global config.
+	NotebookName string
+	WorkingDir   string
 }
 
 type Container struct {
@@ -38,6 +39,7 @@ type Container struct {
 	TemplateLoader     core.TemplateLoader
 	WorkingDir         string
 	Notebooks          *core.NotebookStore
+	Now                date.Provider
 	currentNotebook    *core.Notebook
 	currentNotebookErr error
 }
@@ -50,13 +52,15 @@ func NewContainer(version string) (*Container, error) {
 	logger := util.NewProxyLogger(util.NewStdLogger("zk: ", 0))
 	fs, err := fs.NewFileStorage("", logger)
 	config := core.NewDefaultConfig()
+	now := date.NewFrozenNow()
 
-	handlebars.Init(term.SupportsUTF8(), logger)
+	handlebars.Init(term.SupportsUTF8(), &now, logger)
 	// Template loader used for embedded templates (e.g. default config, fzf
 	// line, etc.).
 	templateLoader := handlebars.NewLoader(handlebars.LoaderOpts{
 		LookupPaths: []string{},
 		Styler:      styler,
+		Logger:      logger,
 	})
 	templateLoader.RegisterHelper("style", hbhelpers.NewStyleHelper(styler, logger))
 
@@ -72,17 +76,14 @@ func NewContainer(version string) (*Container, error) {
 		}
 	}
 
+	term.ConfirmDefault = config.Confirm.Default
+
 	// Set the default notebook if not already set
 	// might be overrided if --notebook-dir flag is present
 	if osutil.GetOptEnv("ZK_NOTEBOOK_DIR").IsNull() && !config.Notebook.Dir.IsNull() {
-		// Expand in case there are environment variables on the path
-		notebookDir := os.Expand(config.Notebook.Dir.Unwrap(), os.Getenv)
-		if strings.HasPrefix(notebookDir, "~") {
-			dirname, err := os.UserHomeDir()
-			if err != nil {
-				return nil, wrap(err)
-			}
-			notebookDir = filepath.Join(dirname, notebookDir[1:])
+		notebookDir, err := expandNotebookDir(config.Notebook.Dir.Unwrap())
+		if err != nil {
+			return nil, wrap(err)
 		}
 		os.Setenv("ZK_NOTEBOOK_DIR", notebookDir)
 	}
@@ -100,58 +101,17 @@ func NewContainer(version string) (*Container, error) {
 		Terminal:       term,
 		FS:             fs,
 		TemplateLoader: templateLoader,
+		Now:            &now,
 		Notebooks: core.NewNotebookStore(config, core.NotebookStorePorts{
 			FS:             fs,
 			TemplateLoader: templateLoader,
-			NotebookFactory: func(path string, config core.Config) (*core.Notebook, error) {
-				dbPath := filepath.Join(path, ".zk/notebook.db")
-				db, err := sqlite.Open(dbPath)
-				if err != nil {
-					return nil, err
-				}
-
-				notebook := core.NewNotebook(path, config, core.NotebookPorts{
-					NoteIndex: sqlite.NewNoteIndex(path, db, logger),
-					NoteContentParser: markdown.NewParser(
-						markdown.ParserOpts{
-							HashtagEnabled:      config.Format.Markdown.Hashtags,
-							MultiWordTagEnabled: config.Format.Markdown.MultiwordTags,
-							ColontagEnabled:     config.Format.Markdown.ColonTags,
-						},
-						logger,
-					),
-					TemplateLoaderFactory: func(language string) (core.TemplateLoader, error) {
-						loader := handlebars.NewLoader(handlebars.LoaderOpts{
-							LookupPaths: []string{
-								filepath.Join(globalConfigDir(), "templates"),
-								filepath.Join(path, ".zk/templates"),
-							},
-							Styler: styler,
-						})
-
-						loader.RegisterHelper("style", hbhelpers.NewStyleHelper(styler, logger))
-						loader.RegisterHelper("slug", hbhelpers.NewSlugHelper(language, logger))
-
-						linkFormatter, err := core.NewLinkFormatter(config.Format.Markdown, loader)
-						if err != nil {
-							return nil, err
-						}
-						loader.RegisterHelper("format-link", hbhelpers.NewLinkHelper(linkFormatter, logger))
-
-						return loader, nil
-					},
-					IDGeneratorFactory: func(opts core.IDOptions) func() string {
-						return rand.NewIDGenerator(opts)
-					},
-					FS:     fs,
-					Logger: logger,
-					OSEnv: func() map[string]string {
-						return osutil.Env()
-					},
-				})
-
-				return notebook, nil
-			},
+			NotebookFactory: adapter.NewNotebookFactory(adapter.NotebookFactoryOpts{
+				FS:           fs,
+				Styler:       styler,
+				Logger:       logger,
+				Now:          &now,
+				TemplateDirs: []string{filepath.Join(globalConfigDir(), "templates")},
+			}),
 		}),
 	}, nil
 }
@@ -164,7 +124,7 @@ func locateGlobalConfig() (string, error) {
 		return "", nil
 	}
 
-	configPath := filepath.Join(globalConfigDir(), "config.toml")
+	configPath := GlobalConfigPath()
 	exists, err := paths.Exists(configPath)
 	switch {
 	case err != nil:
@@ -176,6 +136,12 @@ func locateGlobalConfig() (string, error) {
 	}
 }
 
+// GlobalConfigPath returns the path to the global configuration file, which
+// might not exist.
+func GlobalConfigPath() string {
+	return filepath.Join(globalConfigDir(), "config.toml")
+}
+
 // globalConfigDir returns the parent directory of the global configuration file.
 func globalConfigDir() string {
 	path, ok := os.LookupEnv("XDG_CONFIG_HOME")
@@ -189,6 +155,30 @@ func globalConfigDir() string {
 	return filepath.Join(path, "zk")
 }
 
+// NamedNotebookDir returns the path to the notebook registered with the given
+// name in the global config.
+func (c *Container) NamedNotebookDir(name string) (string, error) {
+	path, ok := c.Config.Notebooks[name]
+	if !ok {
+		return "", core.ErrNotebookNotFound{Name: name}
+	}
+	return expandNotebookDir(path)
+}
+
+// expandNotebookDir expands the environment variables and the home directory
+// in a notebook path from the config.
+func expandNotebookDir(path string) (string, error) {
+	path = os.Expand(path, os.Getenv)
+	if strings.HasPrefix(path, "~") {
+		dirname, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(dirname, path[1:])
+	}
+	return path, nil
+}
+
 // SetCurrentNotebook sets the first notebook found in the given search paths
 // as the current default one.
 func (c *Container) SetCurrentNotebook(searchDirs []Dirs) error {
@@ -200,10 +190,13 @@ func (c *Container) SetCurrentNotebook(searchDirs []Dirs) error {
 		notebookDir := c.FS.Canonical(dirs.NotebookDir)
 		workingDir := c.FS.Canonical(dirs.WorkingDir)
 
+		c.Logger.Debugf("looking for a notebook from %s", notebookDir)
 		c.currentNotebook, c.currentNotebookErr = c.Notebooks.Open(notebookDir)
 		if c.currentNotebookErr == nil {
+			c.Logger.Debugf("found notebook at %s, with working directory %s", c.currentNotebook.Path, workingDir)
 			c.setWorkingDir(workingDir)
 			c.Config = c.currentNotebook.Config
+			c.Terminal.ConfirmDefault = c.Config.Confirm.Default
 			// FIXME: Is there something to do to support multiple notebooks here?
 			os.Setenv("ZK_NOTEBOOK_DIR", c.currentNotebook.Path)
 		}
@@ -237,7 +230,13 @@ func (c *Container) NewNoteFilter(opts fzf.NoteFilterOpts) *fzf.NoteFilter {
 }
 
 func (c *Container) NewNoteEditor(notebook *core.Notebook) (*editor.Editor, error) {
-	return editor.NewEditor(notebook.Config.Tool.Editor)
+	return editor.NewEditor(notebook.Config.Tool.Editor, c.TemplateLoader)
+}
+
+// NewEditor creates an editor from the current config, when there's no
+// notebook at hand.
+func (c *Container) NewEditor() (*editor.Editor, error) {
+	return editor.NewEditor(c.Config.Tool.Editor, c.TemplateLoader)
 }
 
 // Paginate creates an auto-closing io.Writer which will be automatically
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/alecthomas/kong"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// Stable identifiers of the kinds of errors, printed with
+// --output-errors json so that scripts can tell them apart.
+const (
+	ErrorCodeGeneric          = "error"
+	ErrorCodeNotebookNotFound = "notebook-not-found"
+	ErrorCodeNoteNotFound     = "note-not-found"
+	ErrorCodeNoteExists       = "note-exists"
+	ErrorCodeTemplate         = "template-error"
+	ErrorCodeParse            = "parse-error"
+)
+
+// ErrorCode returns the code identifying the kind of the given error.
+func ErrorCode(err error) string {
+	var (
+		notebookNotFound core.ErrNotebookNotFound
+		noteNotFound     core.ErrNoteNotFound
+		noteExists       core.ErrNoteExists
+		template         core.ErrTemplate
+		parse            *kong.ParseError
+	)
+	switch {
+	case errors.As(err, &notebookNotFound):
+		return ErrorCodeNotebookNotFound
+	case errors.As(err, &noteNotFound):
+		return ErrorCodeNoteNotFound
+	case errors.As(err, &noteExists):
+		return ErrorCodeNoteExists
+	case errors.As(err, &template):
+		return ErrorCodeTemplate
+	case errors.As(err, &parse):
+		return ErrorCodeParse
+	default:
+		return ErrorCodeGeneric
+	}
+}
+
+// PrintError prints the given error to out, either as a `zk: error:` line or
+// as a JSON object with the error message and its code.
+func PrintError(out io.Writer, err error, asJSON bool) {
+	if !asJSON {
+		fmt.Fprintf(out, "zk: error: %v\n", err)
+		return
+	}
+
+	jsonBytes, jsonErr := json.Marshal(struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}{
+		Error: err.Error(),
+		Code:  ErrorCode(err),
+	})
+	if jsonErr != nil {
+		fmt.Fprintf(out, "zk: error: %v\n", err)
+		return
+	}
+	fmt.Fprintln(out, string(jsonBytes))
+}
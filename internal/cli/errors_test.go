@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestErrorCode(t *testing.T) {
+	test := func(err error, expected string) {
+		assert.Equal(t, ErrorCode(err), expected)
+		assert.Equal(t, ErrorCode(errors.Wrap(err, "wrapped")), expected)
+	}
+
+	test(core.ErrNotebookNotFound{Path: "dir"}, "notebook-not-found")
+	test(core.ErrNotebookNotFound{Name: "work"}, "notebook-not-found")
+	test(core.ErrNoteNotFound{Path: "note.md"}, "note-not-found")
+	test(core.ErrNoteNotFound{Query: "query"}, "note-not-found")
+	test(core.ErrNoteExists{Name: "note", Path: "note.md"}, "note-exists")
+	test(core.ErrTemplate{Err: errors.New("parse error")}, "template-error")
+
+	parser, err := kong.New(&struct{}{})
+	assert.Nil(t, err)
+	_, err = parser.Parse([]string{"--unknown"})
+	test(err, "parse-error")
+
+	test(errors.New("something else"), "error")
+}
+
+func TestPrintError(t *testing.T) {
+	test := func(err error, asJSON bool, expected string) {
+		var out bytes.Buffer
+		PrintError(&out, err, asJSON)
+		assert.Equal(t, out.String(), expected)
+	}
+
+	err := errors.Wrap(core.ErrNoteNotFound{Path: "a \"note\".md"}, "new note")
+	test(err, false, "zk: error: new note: a \"note\".md: note not found\n")
+	test(err, true, `{"error":"new note: a \"note\".md: note not found","code":"note-not-found"}`+"\n")
+	test(fmt.Errorf("multi\nline"), true, `{"error":"multi\nline","code":"error"}`+"\n")
+}
@@ -2,9 +2,13 @@ package cli
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
+	gostrings "strings"
 	"time"
 
 	"github.com/alecthomas/kong"
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/kballard/go-shellquote"
 	"github.com/zk-org/zk/internal/core"
 	dateutil "github.com/zk-org/zk/internal/util/date"
@@ -18,10 +22,15 @@ type Filtering struct {
 
 	Interactive    bool     `kong:"group='filter',short='i',help='Select notes interactively with fzf.'" json:"-"`
 	Limit          int      `kong:"group='filter',short='n',placeholder='COUNT',help='Limit the number of notes found.'" json:"limit"`
+	Skip           int      `kong:"group='filter',placeholder='COUNT',help='Skip the given number of notes found, after sorting them.'" json:"skip"`
 	Match          []string `kong:"group='filter',short='m',placeholder='QUERY',help='Terms to search for in the notes.'" json:"match"`
-	MatchStrategy  string   `kong:"group='filter',short='M',default='fts',placeholder='STRATEGY',help='Text matching strategy among: fts, re, exact.'" json:"matchStrategy"`
-	Exclude        []string `kong:"group='filter',short='x',placeholder='PATH',help='Ignore notes matching the given path, including its descendants.'" json:"excludeHrefs"`
+	MatchStrategy  string   `kong:"group='filter',short='M',default='fts',placeholder='STRATEGY',help='Text matching strategy among: fts, phrase, prefix, re, exact, fuzzy.'" json:"matchStrategy"`
+	IgnoreCase     bool     `kong:"group='filter',help='Ignore the case of the regular expressions given to --match.'" json:"ignoreCase"`
+	Include        []string `kong:"group='filter',sep='none',placeholder='GLOB',help='Find notes whose path from the notebook root matches the given glob, e.g. journal/**.'" json:"includeGlobs"`
+	Exclude        []string `kong:"group='filter',short='x',placeholder='PATH',help='Ignore notes matching the given path, including its descendants, or glob.'" json:"excludeHrefs"`
 	Tag            []string `kong:"group='filter',short='t',help='Find notes tagged with the given tags.'" json:"tags"`
+	RecursiveTags  bool     `kong:"group='filter',help='Match the nested tags of the --tag filters as well, e.g. project/alpha for project.'" json:"recursiveTags"`
+	TagPrefix      []string `kong:"group='filter',placeholder='TAG',help='Find notes tagged with the given tags or any of their nested tags.'" json:"tagPrefixes"`
 	Mention        []string `kong:"group='filter',placeholder='PATH',help='Find notes mentioning the title of the given ones.'" json:"mention"`
 	MentionedBy    []string `kong:"group='filter',placeholder='PATH',help='Find notes whose title is mentioned in the given ones.'" json:"mentionedBy"`
 	LinkTo         []string `kong:"group='filter',short='l',placeholder='PATH',help='Find notes which are linking to the given ones.'" json:"linkTo"`
@@ -29,17 +38,27 @@ type Filtering struct {
 	LinkedBy       []string `kong:"group='filter',short='L',placeholder='PATH',help='Find notes which are linked by the given ones.'" json:"linkedBy"`
 	NoLinkedBy     []string `kong:"group='filter',placeholder='PATH',help='Find notes which are not linked by the given ones.'" json:"-"`
 	Orphan         bool     `kong:"group='filter',help='Find notes which are not linked by any other note.'" json:"orphan"`
+	AmbiguousLinks bool     `kong:"group='filter',help='Find notes having links which could resolve to several notes.'" json:"ambiguousLinks"`
+	IDMismatch     bool     `kong:"group='filter',help='Find notes whose filename does not contain the ID of their frontmatter.'" json:"idMismatch"`
+	Domain         []string `kong:"group='filter',placeholder='DOMAIN',help='Find notes linking to a URL of the given domain or its subdomains, e.g. example.com.'" json:"domain"`
 	Related        []string `kong:"group='filter',placeholder='PATH',help='Find notes which might be related to the given ones.'" json:"related"`
 	MaxDistance    int      `kong:"group='filter',placeholder='COUNT',help='Maximum distance between two linked notes.'" json:"maxDistance"`
 	Recursive      bool     `kong:"group='filter',short='r',help='Follow links recursively.'" json:"recursive"`
-	Created        string   `kong:"group='filter',placeholder='DATE',help:'Find notes created on the given date.'" json:"created"`
+	MinTagDepth    int      `kong:"group='filter',placeholder='COUNT',help='Find notes having a hierarchical tag with at least the given depth.'" json:"minTagDepth"`
+	MaxTagDepth    int      `kong:"group='filter',placeholder='COUNT',help='Find notes whose hierarchical tags have at most the given depth.'" json:"maxTagDepth"`
+	HasTodos       bool     `kong:"group='filter',help='Find notes containing TODO markers, outside of code blocks.'" json:"hasTodos"`
+	MinOccurrences []string `kong:"group='filter',placeholder='TERM=COUNT',help='Find notes containing the given term at least COUNT times, outside of code blocks.'" json:"minOccurrences"`
+	Created        string   `kong:"group='filter',placeholder='DATE',help='Find notes created on the given day, from midnight to midnight in the local timezone.'" json:"created"`
+	CreatedOn      string   `kong:"group='filter',placeholder='DATE',help='Find notes created on the given day, same as --created.'" json:"createdOn"`
 	CreatedBefore  string   `kong:"group='filter',placeholder='DATE',help='Find notes created before the given date.'" json:"createdBefore"`
 	CreatedAfter   string   `kong:"group='filter',placeholder='DATE',help='Find notes created after the given date.'" json:"createdAfter"`
-	Modified       string   `kong:"group='filter',placeholder='DATE',help='Find notes modified on the given date.'" json:"modified"`
+	Modified       string   `kong:"group='filter',placeholder='DATE',help='Find notes modified on the given day, from midnight to midnight in the local timezone.'" json:"modified"`
+	ModifiedOn     string   `kong:"group='filter',placeholder='DATE',help='Find notes modified on the given day, same as --modified.'" json:"modifiedOn"`
 	ModifiedBefore string   `kong:"group='filter',placeholder='DATE',help='Find notes modified before the given date.'" json:"modifiedBefore"`
 	ModifiedAfter  string   `kong:"group='filter',placeholder='DATE',help='Find notes modified after the given date.'" json:"modifiedAfter"`
 
 	Sort []string `kong:"group='sort',short='s',placeholder='TERM',help='Order the notes by the given criterion.'" json:"sort"`
+	Seed string   `kong:"group='sort',placeholder='NUMBER',help='Seed used to shuffle the notes reproducibly with --sort random.'" json:"seed"`
 
 	// Deprecated
 	ExactMatch bool `kong:"hidden,short='e'" json:"exactMatch"`
@@ -75,31 +94,55 @@ func (f Filtering) ExpandNamedFilters(filters map[string]string, expandedFilters
 			}
 
 			actualPaths = append(actualPaths, parsedFilter.Path...)
+			f.Include = append(f.Include, parsedFilter.Include...)
 			f.Exclude = append(f.Exclude, parsedFilter.Exclude...)
 			f.Tag = append(f.Tag, parsedFilter.Tag...)
+			f.TagPrefix = append(f.TagPrefix, parsedFilter.TagPrefix...)
 			f.Mention = append(f.Mention, parsedFilter.Mention...)
 			f.MentionedBy = append(f.MentionedBy, parsedFilter.MentionedBy...)
 			f.LinkTo = append(f.LinkTo, parsedFilter.LinkTo...)
 			f.NoLinkTo = append(f.NoLinkTo, parsedFilter.NoLinkTo...)
 			f.LinkedBy = append(f.LinkedBy, parsedFilter.LinkedBy...)
 			f.NoLinkedBy = append(f.NoLinkedBy, parsedFilter.NoLinkedBy...)
+			f.Domain = append(f.Domain, parsedFilter.Domain...)
 			f.Related = append(f.Related, parsedFilter.Related...)
+			f.MinOccurrences = append(f.MinOccurrences, parsedFilter.MinOccurrences...)
 			f.Sort = append(f.Sort, parsedFilter.Sort...)
+			if f.Seed == "" {
+				f.Seed = parsedFilter.Seed
+			}
 
 			f.ExactMatch = f.ExactMatch || parsedFilter.ExactMatch
 			f.Interactive = f.Interactive || parsedFilter.Interactive
 			f.Orphan = f.Orphan || parsedFilter.Orphan
+			f.AmbiguousLinks = f.AmbiguousLinks || parsedFilter.AmbiguousLinks
+			f.IDMismatch = f.IDMismatch || parsedFilter.IDMismatch
 			f.Recursive = f.Recursive || parsedFilter.Recursive
+			f.HasTodos = f.HasTodos || parsedFilter.HasTodos
+			f.IgnoreCase = f.IgnoreCase || parsedFilter.IgnoreCase
+			f.RecursiveTags = f.RecursiveTags || parsedFilter.RecursiveTags
 
 			if f.Limit == 0 {
 				f.Limit = parsedFilter.Limit
 			}
+			if f.Skip == 0 {
+				f.Skip = parsedFilter.Skip
+			}
 			if f.MaxDistance == 0 {
 				f.MaxDistance = parsedFilter.MaxDistance
 			}
+			if f.MinTagDepth == 0 {
+				f.MinTagDepth = parsedFilter.MinTagDepth
+			}
+			if f.MaxTagDepth == 0 {
+				f.MaxTagDepth = parsedFilter.MaxTagDepth
+			}
 			if f.Created == "" {
 				f.Created = parsedFilter.Created
 			}
+			if f.CreatedOn == "" {
+				f.CreatedOn = parsedFilter.CreatedOn
+			}
 			if f.CreatedBefore == "" {
 				f.CreatedBefore = parsedFilter.CreatedBefore
 			}
@@ -109,6 +152,9 @@ func (f Filtering) ExpandNamedFilters(filters map[string]string, expandedFilters
 			if f.Modified == "" {
 				f.Modified = parsedFilter.Modified
 			}
+			if f.ModifiedOn == "" {
+				f.ModifiedOn = parsedFilter.ModifiedOn
+			}
 			if f.ModifiedBefore == "" {
 				f.ModifiedBefore = parsedFilter.ModifiedBefore
 			}
@@ -149,17 +195,53 @@ func (f Filtering) NewNoteFindOpts(notebook *core.Notebook) (core.NoteFindOpts,
 	if err != nil {
 		return opts, err
 	}
+	if opts.MatchStrategy == core.MatchStrategyRe {
+		for i, match := range opts.Match {
+			if f.IgnoreCase {
+				match = "(?i)" + match
+			}
+			if _, err := regexp.Compile(match); err != nil {
+				return opts, fmt.Errorf("invalid --match regular expression: %w", err)
+			}
+			opts.Match[i] = match
+		}
+	}
 
 	if paths, ok := relPaths(notebook, f.Path); ok {
 		opts.IncludeHrefs = paths
 	}
 
-	if paths, ok := relPaths(notebook, f.Exclude); ok {
+	for _, glob := range f.Include {
+		if !doublestar.ValidatePattern(glob) {
+			return opts, fmt.Errorf("%s: invalid --include glob", glob)
+		}
+		opts.IncludeGlobs = append(opts.IncludeGlobs, glob)
+	}
+
+	// The excluded paths containing wildcards are globs relative to the
+	// notebook root, the other ones exclude a path and its descendants.
+	excludedPaths := []string{}
+	for _, exclude := range f.Exclude {
+		if !gostrings.ContainsAny(exclude, "*?[") {
+			excludedPaths = append(excludedPaths, exclude)
+			continue
+		}
+		if !doublestar.ValidatePattern(exclude) {
+			return opts, fmt.Errorf("%s: invalid --exclude glob", exclude)
+		}
+		opts.ExcludeGlobs = append(opts.ExcludeGlobs, exclude)
+	}
+	if paths, ok := relPaths(notebook, excludedPaths); ok {
 		opts.ExcludeHrefs = paths
 	}
 
 	if len(f.Tag) > 0 {
 		opts.Tags = f.Tag
+		opts.RecursiveTags = f.RecursiveTags
+	}
+
+	if len(f.TagPrefix) > 0 {
+		opts.TagPrefixes = f.TagPrefix
 	}
 
 	if len(f.Mention) > 0 {
@@ -203,9 +285,33 @@ func (f Filtering) NewNoteFindOpts(notebook *core.Notebook) (core.NoteFindOpts,
 	}
 
 	opts.Orphan = f.Orphan
+	opts.AmbiguousLinks = f.AmbiguousLinks
+	opts.IDMismatch = f.IDMismatch
+	opts.LinkDomains = f.Domain
+	opts.MinTagDepth = f.MinTagDepth
+	opts.MaxTagDepth = f.MaxTagDepth
+
+	if f.HasTodos {
+		if core.TodoMarkersPattern(notebook.Config.Note.TodoMarkers) == "" {
+			return opts, errors.New("--has-todos requires at least one marker in the note.todo-markers setting")
+		}
+		opts.TodoMarkers = notebook.Config.Note.TodoMarkers
+	}
 
-	if f.Created != "" {
-		start, end, err := parseDayRange(f.Created)
+	for _, filter := range f.MinOccurrences {
+		occurrences, err := parseOccurrencesFilter(filter)
+		if err != nil {
+			return opts, err
+		}
+		opts.MinOccurrences = append(opts.MinOccurrences, occurrences)
+	}
+
+	createdDay, err := dayFilter("created", f.Created, f.CreatedOn)
+	if err != nil {
+		return opts, err
+	}
+	if createdDay != "" {
+		start, end, err := parseDayRange(createdDay)
 		if err != nil {
 			return opts, err
 		}
@@ -228,8 +334,12 @@ func (f Filtering) NewNoteFindOpts(notebook *core.Notebook) (core.NoteFindOpts,
 		}
 	}
 
-	if f.Modified != "" {
-		start, end, err := parseDayRange(f.Modified)
+	modifiedDay, err := dayFilter("modified", f.Modified, f.ModifiedOn)
+	if err != nil {
+		return opts, err
+	}
+	if modifiedDay != "" {
+		start, end, err := parseDayRange(modifiedDay)
 		if err != nil {
 			return opts, err
 		}
@@ -256,10 +366,29 @@ func (f Filtering) NewNoteFindOpts(notebook *core.Notebook) (core.NoteFindOpts,
 	if err != nil {
 		return opts, err
 	}
+	if f.Seed != "" {
+		seed, err := strconv.ParseInt(f.Seed, 10, 64)
+		if err != nil {
+			return opts, fmt.Errorf("%s: invalid --seed, expected an integer", f.Seed)
+		}
+		for i, sorter := range sorters {
+			if sorter.Field == core.NoteSortRandom {
+				sorters[i].Seed = &seed
+			}
+		}
+	}
 	opts.Sorters = sorters
 
+	if f.Limit < 0 {
+		return opts, fmt.Errorf("%d: invalid --limit, expected a positive number", f.Limit)
+	}
 	opts.Limit = f.Limit
 
+	if f.Skip < 0 {
+		return opts, fmt.Errorf("%d: invalid --skip, expected a positive number", f.Skip)
+	}
+	opts.Offset = f.Skip
+
 	return opts, nil
 }
 
@@ -274,17 +403,50 @@ func relPaths(notebook *core.Notebook, paths []string) ([]string, bool) {
 	return relPaths, len(relPaths) > 0
 }
 
+// parseOccurrencesFilter parses a --min-occurrences filter formatted as
+// TERM=COUNT.
+func parseOccurrencesFilter(filter string) (core.OccurrencesFilter, error) {
+	i := gostrings.LastIndex(filter, "=")
+	if i == -1 {
+		return core.OccurrencesFilter{}, fmt.Errorf("%s: invalid --min-occurrences filter, expected TERM=COUNT", filter)
+	}
+	term := gostrings.TrimSpace(filter[:i])
+	count, err := strconv.Atoi(gostrings.TrimSpace(filter[i+1:]))
+	if term == "" || err != nil || count < 1 {
+		return core.OccurrencesFilter{}, fmt.Errorf("%s: invalid --min-occurrences filter, expected TERM=COUNT with a positive COUNT", filter)
+	}
+	return core.OccurrencesFilter{Term: term, Min: count}, nil
+}
+
+// dayFilter returns the day given to either --<name> or its --<name>-on
+// alias.
+func dayFilter(name string, day string, dayOn string) (string, error) {
+	if day != "" && dayOn != "" {
+		return "", fmt.Errorf("--%s can't be used with --%s-on", name, name)
+	}
+	if day != "" {
+		return day, nil
+	}
+	return dayOn, nil
+}
+
 func parseDayRange(date string) (start time.Time, end time.Time, err error) {
 	day, err := dateutil.TimeFromNatural(date)
 	if err != nil {
 		return
 	}
 
+	start, end = DayRange(day)
+	return start, end, nil
+}
+
+// DayRange returns the range of dates covering the day of the given date.
+func DayRange(day time.Time) (start time.Time, end time.Time) {
     // we add -1 second so that the day range ends at 23:59:59
     // i.e, the 'new day' begins at 00:00:00
 	start = startOfDay(day).Add(time.Second * -1)
 	end = start.AddDate(0, 0, 1)
-	return start, end, nil
+	return start, end
 }
 
 func startOfDay(t time.Time) time.Time {
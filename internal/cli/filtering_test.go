@@ -47,6 +47,7 @@ func TestExpandNamedFiltersNone(t *testing.T) {
 func TestExpandNamedFiltersJoinLists(t *testing.T) {
 	f := Filtering{
 		Path:        []string{"path1", "f1", "f2"},
+		Include:     []string{"incl/**"},
 		Exclude:     []string{"excl-path1", "excl-path2"},
 		Tag:         []string{"tag1", "tag2"},
 		Mention:     []string{"mention1", "mention2"},
@@ -55,20 +56,22 @@ func TestExpandNamedFiltersJoinLists(t *testing.T) {
 		NoLinkTo:    []string{"link3", "link4"},
 		LinkedBy:    []string{"linked1", "linked2"},
 		NoLinkedBy:  []string{"linked3", "linked4"},
+		Domain:      []string{"example.com"},
 		Related:     []string{"related1", "related2"},
 		Sort:        []string{"title", "created"},
 	}
 
 	res, err := f.ExpandNamedFilters(
 		map[string]string{
-			"f1": "path2 --exclude excl-path3 -x excl-path4 --tag tag3 -t tag4 --mention mention3,mention4 --mentioned-by note3",
-			"f2": "--link-to link5 --no-link-to link6 --linked-by linked5 --no-linked-by linked6 --related related3 --related related4 --sort random-",
+			"f1": "path2 --include 'log/*.md' --exclude excl-path3 -x excl-path4 --tag tag3 -t tag4 --mention mention3,mention4 --mentioned-by note3",
+			"f2": "--link-to link5 --no-link-to link6 --linked-by linked5 --no-linked-by linked6 --domain example.org --related related3 --related related4 --sort random-",
 		},
 		[]string{},
 	)
 
 	assert.Nil(t, err)
 	assert.Equal(t, res.Path, []string{"path1", "path2"})
+	assert.Equal(t, res.Include, []string{"incl/**", "log/*.md"})
 	assert.Equal(t, res.Exclude, []string{"excl-path1", "excl-path2", "excl-path3", "excl-path4"})
 	assert.Equal(t, res.Tag, []string{"tag1", "tag2", "tag3", "tag4"})
 	assert.Equal(t, res.Mention, []string{"mention1", "mention2", "mention3", "mention4"})
@@ -77,6 +80,7 @@ func TestExpandNamedFiltersJoinLists(t *testing.T) {
 	assert.Equal(t, res.NoLinkTo, []string{"link3", "link4", "link6"})
 	assert.Equal(t, res.LinkedBy, []string{"linked1", "linked2", "linked5"})
 	assert.Equal(t, res.NoLinkedBy, []string{"linked3", "linked4", "linked6"})
+	assert.Equal(t, res.Domain, []string{"example.com", "example.org"})
 	assert.Equal(t, res.Related, []string{"related1", "related2", "related3", "related4"})
 	assert.Equal(t, res.Sort, []string{"title", "created", "random-"})
 }
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/kong"
+)
+
+// NewCommandDefaultsResolver creates a kong resolver providing the default
+// values of the command flags set in the user config, e.g. with:
+//
+//	[list]
+//	sort = ["created-"]
+//
+// The flags given on the command line take precedence over these defaults.
+// A boolean flag enabled in the config can be disabled with `--flag=false`.
+func NewCommandDefaultsResolver(defaults map[string]map[string]interface{}) kong.Resolver {
+	return &commandDefaultsResolver{defaults: defaults}
+}
+
+type commandDefaultsResolver struct {
+	defaults map[string]map[string]interface{}
+}
+
+// Validate checks that the config only sets existing flags.
+func (r *commandDefaultsResolver) Validate(app *kong.Application) error {
+	for _, cmd := range app.Children {
+		defaults, ok := r.defaults[cmd.Name]
+		if !ok {
+			continue
+		}
+		for name := range defaults {
+			if !hasFlag(cmd, name) {
+				return fmt.Errorf("failed to read config: %s.%s: unknown flag for `zk %s`", cmd.Name, name, cmd.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func isTopLevelCommand(cmd *kong.Node) bool {
+	return cmd.Parent == nil || cmd.Parent.Type == kong.ApplicationNode
+}
+
+func hasFlag(cmd *kong.Node, name string) bool {
+	for _, flag := range cmd.Flags {
+		if flag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *commandDefaultsResolver) Resolve(context *kong.Context, parent *kong.Path, flag *kong.Flag) (interface{}, error) {
+	// The config sections are named after the top-level commands only, so
+	// that `[list]` doesn't apply to `zk tag list`.
+	if parent.Command == nil || !isTopLevelCommand(parent.Command) {
+		return nil, nil
+	}
+	value, ok := r.defaults[parent.Command.Name][flag.Name]
+	if !ok {
+		return nil, nil
+	}
+
+	// The values are given as strings to kong, to be decoded the same way as
+	// the command line arguments.
+	if items, ok := value.([]interface{}); ok {
+		values := []interface{}{}
+		for _, item := range items {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values, nil
+	}
+	return fmt.Sprint(value), nil
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+type resolverTestCLI struct {
+	List struct {
+		Sort  []string `short:"s"`
+		Limit int      `short:"n"`
+		Quiet bool     `short:"q"`
+		Pager bool
+	} `cmd:""`
+	Index struct {
+		Quiet bool
+	} `cmd:""`
+	Tag struct {
+		List struct {
+			Sort []string `short:"s"`
+		} `cmd:""`
+	} `cmd:""`
+}
+
+func parseWithDefaults(t *testing.T, defaults map[string]map[string]interface{}, args ...string) (resolverTestCLI, error) {
+	var cli resolverTestCLI
+	parser, err := kong.New(&cli, kong.Resolvers(NewCommandDefaultsResolver(defaults)))
+	assert.Nil(t, err)
+	_, err = parser.Parse(args)
+	return cli, err
+}
+
+func TestCommandDefaultsResolver(t *testing.T) {
+	defaults := map[string]map[string]interface{}{
+		"list": {
+			"sort":  []interface{}{"created-", "title"},
+			"limit": int64(20),
+			"quiet": true,
+		},
+	}
+
+	cli, err := parseWithDefaults(t, defaults, "list")
+	assert.Nil(t, err)
+	assert.Equal(t, cli.List.Sort, []string{"created-", "title"})
+	assert.Equal(t, cli.List.Limit, 20)
+	assert.True(t, cli.List.Quiet)
+	assert.False(t, cli.List.Pager)
+
+	// The other commands are not affected.
+	cli, err = parseWithDefaults(t, defaults, "index")
+	assert.Nil(t, err)
+	assert.False(t, cli.Index.Quiet)
+}
+
+func TestCommandDefaultsResolverIgnoresNestedCommands(t *testing.T) {
+	defaults := map[string]map[string]interface{}{
+		"list": {"sort": []interface{}{"created-"}},
+	}
+
+	// `[list]` doesn't apply to `zk tag list`.
+	cli, err := parseWithDefaults(t, defaults, "tag", "list")
+	assert.Nil(t, err)
+	assert.Equal(t, len(cli.Tag.List.Sort), 0)
+
+	cli, err = parseWithDefaults(t, defaults, "list")
+	assert.Nil(t, err)
+	assert.Equal(t, cli.List.Sort, []string{"created-"})
+}
+
+func TestCommandDefaultsResolverCommandLineTakesPrecedence(t *testing.T) {
+	defaults := map[string]map[string]interface{}{
+		"list": {
+			"sort":  []interface{}{"created-"},
+			"limit": int64(20),
+			"quiet": true,
+		},
+	}
+
+	cli, err := parseWithDefaults(t, defaults, "list", "--sort", "path", "-n", "5", "--quiet=false")
+	assert.Nil(t, err)
+	assert.Equal(t, cli.List.Sort, []string{"path"})
+	assert.Equal(t, cli.List.Limit, 5)
+	assert.False(t, cli.List.Quiet)
+}
+
+func TestCommandDefaultsResolverSingleValue(t *testing.T) {
+	cli, err := parseWithDefaults(t, map[string]map[string]interface{}{
+		"list": {"sort": "created-,title"},
+	}, "list")
+	assert.Nil(t, err)
+	assert.Equal(t, cli.List.Sort, []string{"created-", "title"})
+}
+
+func TestCommandDefaultsResolverUnknownFlag(t *testing.T) {
+	_, err := parseWithDefaults(t, map[string]map[string]interface{}{
+		"list": {"unknown": true},
+	}, "list")
+	assert.Err(t, err, "failed to read config: list.unknown: unknown flag for `zk list`")
+}
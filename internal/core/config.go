@@ -3,44 +3,66 @@ package core
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/zk-org/zk/internal/util/errors"
 	"github.com/zk-org/zk/internal/util/opt"
 	toml "github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v2"
 )
 
 // Config holds the user configuration.
 type Config struct {
 	Notebook NotebookConfig
+	Index    IndexConfig
 	Note     NoteConfig
 	Groups   map[string]GroupConfig
 	Format   FormatConfig
 	Tool     ToolConfig
 	LSP      LSPConfig
+	Journal  JournalConfig
+	Confirm  ConfirmConfig
 	Filters  map[string]string
 	Aliases  map[string]string
 	Extra    map[string]string
+	// Rules enforced on the frontmatter of the notes, indexed by metadata key.
+	FrontmatterSchema map[string]FrontmatterFieldSchema
+	// Paths to the named notebooks registered in the global config, selected
+	// with --notebook.
+	Notebooks map[string]string
+	// Default values of the command flags, indexed by command name then by
+	// flag name. They are used unless the flag is given on the command line.
+	CommandDefaults map[string]map[string]interface{}
 }
 
 // NewDefaultConfig creates a new Config with the default settings.
 func NewDefaultConfig() Config {
 	return Config{
 		Notebook: NotebookConfig{
-			Dir: opt.NullString,
+			Dir:          opt.NullString,
+			Ignore:       []string{},
+			IgnoreHidden: true,
+		},
+		Index: IndexConfig{
+			Include: []string{},
 		},
 		Note: NoteConfig{
-			FilenameTemplate: "{{id}}",
-			Extension:        "md",
-			BodyTemplatePath: opt.NullString,
-			Lang:             "en",
-			DefaultTitle:     "Untitled",
+			FilenameTemplate:    "{{id}}",
+			Extension:           "md",
+			BodyTemplatePath:    opt.NullString,
+			FrontmatterBasePath: opt.NullString,
+			Lang:                "en",
+			DefaultTitle:        "Untitled",
 			IDOptions: IDOptions{
-				Charset: CharsetAlphanum,
-				Length:  4,
-				Case:    CaseLower,
+				Strategy: IDStrategyRandom,
+				Charset:  CharsetAlphanum,
+				Length:   4,
+				Case:     CaseLower,
 			},
-			Exclude: []string{},
+			Exclude:     []string{},
+			TodoMarkers: []string{"TODO", "FIXME"},
 		},
 		Groups: map[string]GroupConfig{},
 		Format: FormatConfig{
@@ -49,6 +71,7 @@ func NewDefaultConfig() Config {
 				ColonTags:         false,
 				MultiwordTags:     false,
 				LinkFormat:        "markdown",
+				LinkTarget:        "path",
 				LinkEncodePath:    true,
 				LinkDropExtension: true,
 			},
@@ -66,9 +89,19 @@ func NewDefaultConfig() Config {
 				DeadLink:  LSPDiagnosticError,
 			},
 		},
-		Filters: map[string]string{},
-		Aliases: map[string]string{},
-		Extra:   map[string]string{},
+		Journal: JournalConfig{
+			Path:             `journal/{{format-date now "%Y-%m-%d"}}.md`,
+			BodyTemplatePath: opt.NullString,
+		},
+		Confirm: ConfirmConfig{
+			Default: opt.NullBool,
+		},
+		Filters:           map[string]string{},
+		Aliases:           map[string]string{},
+		Extra:             map[string]string{},
+		FrontmatterSchema: map[string]FrontmatterFieldSchema{},
+		Notebooks:         map[string]string{},
+		CommandDefaults:   map[string]map[string]interface{}{},
 	}
 }
 
@@ -106,9 +139,17 @@ func (c Config) GroupConfigNamed(name string) (GroupConfig, error) {
 }
 
 // GroupNameForPath returns the name of the GroupConfig matching the given
-// path, relative to the notebook.
+// path, relative to the notebook. When several groups match, the first one in
+// alphabetical order wins.
 func (c Config) GroupNameForPath(path string) (string, error) {
-	for name, config := range c.Groups {
+	names := make([]string, 0, len(c.Groups))
+	for name := range c.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		config := c.Groups[name]
 		for _, groupPath := range config.Paths {
 			matches, err := filepath.Match(groupPath, path)
 			if err != nil {
@@ -125,6 +166,12 @@ func (c Config) GroupNameForPath(path string) (string, error) {
 	return "", nil
 }
 
+// GroupNameForNote returns the name of the GroupConfig matching the directory
+// of the note at the given path, relative to the notebook.
+func (c Config) GroupNameForNote(path string) (string, error) {
+	return c.GroupNameForPath(filepath.Dir(path))
+}
+
 // FormatConfig holds the configuration for document formats, such as Markdown.
 type FormatConfig struct {
 	Markdown MarkdownConfig
@@ -140,8 +187,12 @@ type MarkdownConfig struct {
 	MultiwordTags bool
 
 	// Format used to generate links between notes.
-	// Either "wiki", "markdown" or a custom template. Default is "markdown".
+	// Either "wiki", "wiki-label", "markdown" or a custom template. Default is
+	// "markdown".
 	LinkFormat string
+	// Target of the generated wiki links: the note "path" or its "title".
+	// Default is "path".
+	LinkTarget string
 	// Indicates whether a link's path will be percent-encoded.
 	// Defaults to true for "markdown" format only, false otherwise.
 	LinkEncodePath bool
@@ -151,13 +202,16 @@ type MarkdownConfig struct {
 
 // ToolConfig holds the external tooling configuration.
 type ToolConfig struct {
-	Editor     opt.String
-	Shell      opt.String
-	Pager      opt.String
-	FzfPreview opt.String
-	FzfLine    opt.String
-	FzfOptions opt.String
-	FzfBindNew opt.String
+	Editor opt.String
+	// Indicates whether the notes are opened one after the other, for editors
+	// which don't accept multiple files.
+	EditorSequential bool
+	Shell            opt.String
+	Pager            opt.String
+	FzfPreview       opt.String
+	FzfLine          opt.String
+	FzfOptions       opt.String
+	FzfBindNew       opt.String
 }
 
 // LSPConfig holds the Language Server Protocol configuration.
@@ -196,9 +250,61 @@ const (
 	LSPDiagnosticHint    LSPDiagnosticSeverity = 4
 )
 
+// JournalConfig holds the configuration of the daily journal.
+type JournalConfig struct {
+	// Template of the path of a journal note, relative to the notebook root
+	// and including the file extension.
+	Path string
+	// Custom template used to render the content of new journal notes.
+	BodyTemplatePath opt.String
+}
+
+// ConfirmConfig holds the configuration of the confirmation prompts.
+type ConfirmConfig struct {
+	// Answer selected when the user presses Enter, overriding the default
+	// of each command when set.
+	Default opt.Bool
+}
+
+// FrontmatterFieldSchema holds the rules enforced on a frontmatter key by
+// `zk validate-frontmatter`.
+type FrontmatterFieldSchema struct {
+	// Indicates whether the key must be set in every note.
+	Required bool
+	// Expected type of the value, any type is accepted when empty.
+	Type FrontmatterFieldType
+	// Allowed values, or allowed items for lists. Any value is accepted
+	// when empty.
+	Values []string
+}
+
+// FrontmatterFieldType represents the type of a frontmatter value.
+type FrontmatterFieldType string
+
+const (
+	FrontmatterFieldAny     FrontmatterFieldType = ""
+	FrontmatterFieldString  FrontmatterFieldType = "string"
+	FrontmatterFieldNumber  FrontmatterFieldType = "number"
+	FrontmatterFieldBoolean FrontmatterFieldType = "boolean"
+	FrontmatterFieldList    FrontmatterFieldType = "list"
+)
+
 // NotebookConfig holds configuration about the default notebook
 type NotebookConfig struct {
 	Dir opt.String
+	// .gitignore-style patterns of the paths to skip when indexing notes,
+	// relative to the notebook root.
+	Ignore []string
+	// Indicates whether hidden files and directories are skipped when
+	// indexing notes.
+	IgnoreHidden bool
+}
+
+// IndexConfig holds the configuration of the note indexing.
+type IndexConfig struct {
+	// Globs of additional files to index besides the notes, relative to the
+	// notebook root, e.g. `**/*.canvas`.
+	Include []string
 }
 
 // NoteConfig holds the user configuration used when generating new notes.
@@ -213,10 +319,20 @@ type NoteConfig struct {
 	Lang string
 	// Default title to use when none is provided.
 	DefaultTitle string
-	// Settings used when generating a random ID.
+	// IANA name of the encoding used to read the notes which are not valid
+	// UTF-8 and have no byte order mark, e.g. latin1.
+	Encoding string
+	// Settings used when generating the ID of a new note.
 	IDOptions IDOptions
 	// Path globs to ignore when indexing notes.
 	Exclude []string
+	// Metadata merged into the frontmatter of new notes.
+	FrontmatterBase map[string]interface{}
+	// Path to a YAML template holding the metadata merged into the
+	// frontmatter of new notes.
+	FrontmatterBasePath opt.String
+	// Words counted as TODO markers in the body of the notes.
+	TodoMarkers []string
 }
 
 // GroupConfig holds the user configuration for a given group of notes.
@@ -270,7 +386,57 @@ func OpenConfig(path string, parentConfig Config, fs FileStorage, isGlobal bool)
 		return parentConfig, errors.Wrapf(err, "failed to open config file at %s", path)
 	}
 
-	return ParseConfig(content, path, parentConfig, isGlobal)
+	config, err := ParseConfig(content, path, parentConfig, isGlobal)
+	if err != nil {
+		return config, err
+	}
+
+	err = validateFrontmatterBaseFile(config.Note, filepath.Join(filepath.Dir(path), "templates"), fs)
+	if err != nil {
+		return config, errors.Wrap(err, "failed to read config")
+	}
+	return config, nil
+}
+
+// templateExprRegex matches the Handlebars expressions of a template.
+var templateExprRegex = regexp.MustCompile(`{{.*?}}`)
+
+// validateFrontmatterBaseFile checks that the YAML template set with
+// note.frontmatter-base holds a YAML mapping, if it is found in the given
+// templates directory. As the template is rendered only when creating a note,
+// its expressions are replaced with placeholder values.
+func validateFrontmatterBaseFile(config NoteConfig, templatesDir string, fs FileStorage) error {
+	path := config.FrontmatterBasePath.Unwrap()
+	if path == "" {
+		return nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(templatesDir, path)
+	}
+	// The template might be located in another templates directory.
+	if exists, err := fs.FileExists(path); err != nil || !exists {
+		return nil
+	}
+
+	content, err := fs.Read(path)
+	if err != nil {
+		return err
+	}
+	lines := []string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		// Lines made only of expressions, e.g. {{#if}} blocks, are dropped.
+		if strings.TrimSpace(templateExprRegex.ReplaceAllString(line, "")) == "" && strings.TrimSpace(line) != "" {
+			continue
+		}
+		lines = append(lines, templateExprRegex.ReplaceAllString(line, "x"))
+	}
+
+	base := yaml.MapSlice{}
+	err = yaml.Unmarshal([]byte(strings.Join(lines, "\n")), &base)
+	if err != nil {
+		return errors.Wrapf(err, "%s: invalid frontmatter base", path)
+	}
+	return nil
 }
 
 // ParseConfig creates a new Config instance from its TOML representation.
@@ -298,6 +464,25 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 			return config, wrap(errors.New("notebook.dir should not be set on local configuration"))
 		}
 	}
+	for _, v := range notebook.Ignore {
+		config.Notebook.Ignore = append(config.Notebook.Ignore, v)
+	}
+	if notebook.IgnoreHidden != nil {
+		config.Notebook.IgnoreHidden = *notebook.IgnoreHidden
+	}
+
+	// Index
+	for _, v := range tomlConf.Index.Include {
+		config.Index.Include = append(config.Index.Include, v)
+	}
+	if len(tomlConf.Notebooks) > 0 {
+		if !isGlobal {
+			return config, wrap(errors.New("notebooks should not be set on local configuration"))
+		}
+		for name, path := range tomlConf.Notebooks {
+			config.Notebooks[name] = path
+		}
+	}
 
 	// Note
 	note := tomlConf.Note
@@ -310,6 +495,13 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 	if note.Template != "" {
 		config.Note.BodyTemplatePath = opt.NewNotEmptyString(note.Template)
 	}
+	if note.IDStrategy != "" {
+		strategy, err := idStrategyFromString(note.IDStrategy)
+		if err != nil {
+			return config, wrap(err)
+		}
+		config.Note.IDOptions.Strategy = strategy
+	}
 	if note.IDLength != 0 {
 		config.Note.IDOptions.Length = note.IDLength
 	}
@@ -325,12 +517,32 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 	if note.DefaultTitle != "" {
 		config.Note.DefaultTitle = note.DefaultTitle
 	}
+	if note.Encoding != "" {
+		if _, err := EncodingFromString(note.Encoding); err != nil {
+			return config, wrap(errors.Wrapf(err, "note.encoding"))
+		}
+		config.Note.Encoding = note.Encoding
+	}
 	for _, v := range note.Exclude {
 		config.Note.Exclude = append(config.Note.Exclude, v)
 	}
 	for _, v := range note.Ignore {
 		config.Note.Exclude = append(config.Note.Exclude, v)
 	}
+	if note.TodoMarkers != nil {
+		config.Note.TodoMarkers = note.TodoMarkers
+	}
+	switch base := note.FrontmatterBase.(type) {
+	case nil:
+	case string:
+		config.Note.FrontmatterBase = nil
+		config.Note.FrontmatterBasePath = opt.NewNotEmptyString(base)
+	case map[string]interface{}:
+		config.Note.FrontmatterBase = base
+		config.Note.FrontmatterBasePath = opt.NullString
+	default:
+		return config, wrap(errors.New("note.frontmatter-base should be a path to a YAML file or a table"))
+	}
 	if tomlConf.Extra != nil {
 		for k, v := range tomlConf.Extra {
 			config.Extra[k] = v
@@ -344,7 +556,11 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 			parent = config.RootGroupConfig()
 		}
 
-		config.Groups[name] = parent.merge(dirTOML, name)
+		group, err := parent.merge(dirTOML, name)
+		if err != nil {
+			return config, wrap(errors.Wrapf(err, "group.%s", name))
+		}
+		config.Groups[name] = group
 	}
 
 	// Format
@@ -364,6 +580,13 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 	if markdown.LinkFormat != nil {
 		config.Format.Markdown.LinkFormat = *markdown.LinkFormat
 	}
+	if markdown.LinkTarget != nil {
+		target, err := linkTargetFromString(*markdown.LinkTarget)
+		if err != nil {
+			return config, wrap(err)
+		}
+		config.Format.Markdown.LinkTarget = target
+	}
 	if markdown.LinkEncodePath != nil {
 		config.Format.Markdown.LinkEncodePath = *markdown.LinkEncodePath
 	} else if markdown.LinkFormat != nil {
@@ -378,6 +601,9 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 	if tool.Editor != nil {
 		config.Tool.Editor = opt.NewNotEmptyString(*tool.Editor)
 	}
+	if tool.EditorSequential != nil {
+		config.Tool.EditorSequential = *tool.EditorSequential
+	}
 	if tool.Shell != nil {
 		config.Tool.Shell = opt.NewNotEmptyString(*tool.Shell)
 	}
@@ -425,6 +651,23 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 		}
 	}
 
+	// Journal
+	journal := tomlConf.Journal
+	if journal.Path != "" {
+		config.Journal.Path = journal.Path
+	}
+	if journal.Template != "" {
+		config.Journal.BodyTemplatePath = opt.NewNotEmptyString(journal.Template)
+	}
+
+	// Confirm
+	if tomlConf.Confirm.Default != nil {
+		config.Confirm.Default, err = confirmDefaultFromString(*tomlConf.Confirm.Default)
+		if err != nil {
+			return config, wrap(err)
+		}
+	}
+
 	// Filters
 	if tomlConf.Filters != nil {
 		for k, v := range tomlConf.Filters {
@@ -439,14 +682,51 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 		}
 	}
 
+	// Command defaults
+	commandDefaults := map[string]map[string]interface{}{
+		"new":  tomlConf.New,
+		"list": tomlConf.List,
+		"edit": tomlConf.Edit,
+	}
+	for cmd, flags := range commandDefaults {
+		if len(flags) == 0 {
+			continue
+		}
+		defaults, ok := config.CommandDefaults[cmd]
+		if !ok {
+			defaults = map[string]interface{}{}
+			config.CommandDefaults[cmd] = defaults
+		}
+		for k, v := range flags {
+			defaults[k] = v
+		}
+	}
+
+	// Frontmatter schema
+	for k, v := range tomlConf.FrontmatterSchema {
+		fieldType, err := frontmatterFieldTypeFromString(v.Type)
+		if err != nil {
+			return config, wrap(errors.Wrapf(err, "frontmatter-schema.%s.type", k))
+		}
+		// Frontmatter keys are case insensitive.
+		config.FrontmatterSchema[strings.ToLower(k)] = FrontmatterFieldSchema{
+			Required: v.Required,
+			Type:     fieldType,
+			Values:   v.Values,
+		}
+	}
+
 	return config, nil
 }
 
-func (c GroupConfig) merge(tomlConf tomlGroupConfig, name string) GroupConfig {
+func (c GroupConfig) merge(tomlConf tomlGroupConfig, name string) (GroupConfig, error) {
 	res := c.Clone()
 
 	if tomlConf.Paths != nil {
 		for _, p := range tomlConf.Paths {
+			if !filepath.IsLocal(p) {
+				return res, fmt.Errorf("%s: the group paths must be relative to the notebook root, inside the notebook", p)
+			}
 			res.Paths = append(res.Paths, p)
 		}
 	} else {
@@ -465,6 +745,13 @@ func (c GroupConfig) merge(tomlConf tomlGroupConfig, name string) GroupConfig {
 	if note.Template != "" {
 		res.Note.BodyTemplatePath = opt.NewNotEmptyString(note.Template)
 	}
+	if note.IDStrategy != "" {
+		strategy, err := idStrategyFromString(note.IDStrategy)
+		if err != nil {
+			return res, err
+		}
+		res.Note.IDOptions.Strategy = strategy
+	}
 	if note.IDLength != 0 {
 		res.Note.IDOptions.Length = note.IDLength
 	}
@@ -480,6 +767,12 @@ func (c GroupConfig) merge(tomlConf tomlGroupConfig, name string) GroupConfig {
 	if note.DefaultTitle != "" {
 		res.Note.DefaultTitle = note.DefaultTitle
 	}
+	if note.Encoding != "" {
+		if _, err := EncodingFromString(note.Encoding); err != nil {
+			return res, errors.Wrapf(err, "note.encoding")
+		}
+		res.Note.Encoding = note.Encoding
+	}
 	for _, v := range note.Exclude {
 		res.Note.Exclude = append(res.Note.Exclude, v)
 	}
@@ -492,24 +785,47 @@ func (c GroupConfig) merge(tomlConf tomlGroupConfig, name string) GroupConfig {
 		}
 	}
 
-	return res
+	return res, nil
 }
 
 // tomlConfig holds the TOML representation of Config
 type tomlConfig struct {
 	Notebook tomlNotebookConfig
+	Index    tomlIndexConfig
 	Note     tomlNoteConfig
 	Groups   map[string]tomlGroupConfig `toml:"group"`
 	Format   tomlFormatConfig
 	Tool     tomlToolConfig
 	LSP      tomlLSPConfig
+	Journal  tomlJournalConfig
+	Confirm  tomlConfirmConfig
 	Extra    map[string]string
 	Filters  map[string]string `toml:"filter"`
 	Aliases  map[string]string `toml:"alias"`
+
+	// Default flag values of the commands.
+	New  map[string]interface{}
+	List map[string]interface{}
+	Edit map[string]interface{}
+
+	FrontmatterSchema map[string]tomlFrontmatterFieldSchema `toml:"frontmatter-schema"`
+	Notebooks         map[string]string                     `toml:"notebooks"`
+}
+
+type tomlFrontmatterFieldSchema struct {
+	Required bool
+	Type     string
+	Values   []string
 }
 
 type tomlNotebookConfig struct {
-	Dir string
+	Dir          string
+	Ignore       []string `toml:"ignore"`
+	IgnoreHidden *bool    `toml:"ignore-hidden"`
+}
+
+type tomlIndexConfig struct {
+	Include []string
 }
 
 type tomlNoteConfig struct {
@@ -518,11 +834,16 @@ type tomlNoteConfig struct {
 	Template     string
 	Lang         string   `toml:"language"`
 	DefaultTitle string   `toml:"default-title"`
+	Encoding     string   `toml:"encoding"`
+	IDStrategy   string   `toml:"id-strategy"`
 	IDCharset    string   `toml:"id-charset"`
 	IDLength     int      `toml:"id-length"`
 	IDCase       string   `toml:"id-case"`
 	Exclude      []string `toml:"exclude"`
 	Ignore      []string `toml:"ignore"` // Legacy alias to `exclude`
+	TodoMarkers  []string `toml:"todo-markers"`
+	// Either a path to a YAML file or an inline table.
+	FrontmatterBase interface{} `toml:"frontmatter-base"`
 }
 
 type tomlGroupConfig struct {
@@ -540,18 +861,20 @@ type tomlMarkdownConfig struct {
 	ColonTags         *bool   `toml:"colon-tags"`
 	MultiwordTags     *bool   `toml:"multiword-tags"`
 	LinkFormat        *string `toml:"link-format"`
+	LinkTarget        *string `toml:"link-target"`
 	LinkEncodePath    *bool   `toml:"link-encode-path"`
 	LinkDropExtension *bool   `toml:"link-drop-extension"`
 }
 
 type tomlToolConfig struct {
-	Editor     *string
-	Shell      *string
-	Pager      *string
-	FzfPreview *string `toml:"fzf-preview"`
-	FzfLine    *string `toml:"fzf-line"`
-	FzfOptions *string `toml:"fzf-options"`
-	FzfBindNew *string `toml:"fzf-bind-new"`
+	Editor           *string
+	EditorSequential *bool `toml:"editor-sequential"`
+	Shell            *string
+	Pager            *string
+	FzfPreview       *string `toml:"fzf-preview"`
+	FzfLine          *string `toml:"fzf-line"`
+	FzfOptions       *string `toml:"fzf-options"`
+	FzfBindNew       *string `toml:"fzf-bind-new"`
 }
 
 type tomlLSPConfig struct {
@@ -567,6 +890,15 @@ type tomlLSPConfig struct {
 	}
 }
 
+type tomlJournalConfig struct {
+	Path     string
+	Template string
+}
+
+type tomlConfirmConfig struct {
+	Default *string
+}
+
 func charsetFromString(charset string) Charset {
 	switch charset {
 	case "alphanum":
@@ -582,6 +914,35 @@ func charsetFromString(charset string) Charset {
 	}
 }
 
+func linkTargetFromString(s string) (string, error) {
+	switch s {
+	case "path", "title":
+		return s, nil
+	default:
+		return "path", fmt.Errorf("%s: unknown link target - may be path or title", s)
+	}
+}
+
+func confirmDefaultFromString(s string) (opt.Bool, error) {
+	switch s {
+	case "yes":
+		return opt.True, nil
+	case "no":
+		return opt.False, nil
+	default:
+		return opt.NullBool, fmt.Errorf("%s: unknown confirmation default - may be yes or no", s)
+	}
+}
+
+func idStrategyFromString(s string) (IDStrategy, error) {
+	switch IDStrategy(s) {
+	case IDStrategyRandom, IDStrategyTimestamp, IDStrategySequence:
+		return IDStrategy(s), nil
+	default:
+		return IDStrategyRandom, fmt.Errorf("%s: unknown note ID strategy - may be random, timestamp or sequence", s)
+	}
+}
+
 func caseFromString(c string) Case {
 	switch c {
 	case "lower":
@@ -595,6 +956,15 @@ func caseFromString(c string) Case {
 	}
 }
 
+func frontmatterFieldTypeFromString(s string) (FrontmatterFieldType, error) {
+	switch FrontmatterFieldType(s) {
+	case FrontmatterFieldAny, FrontmatterFieldString, FrontmatterFieldNumber, FrontmatterFieldBoolean, FrontmatterFieldList:
+		return FrontmatterFieldType(s), nil
+	default:
+		return FrontmatterFieldAny, fmt.Errorf("%s: unknown frontmatter type - may be string, number, boolean or list", s)
+	}
+}
+
 func lspDiagnosticSeverityFromString(s string) (LSPDiagnosticSeverity, error) {
 	switch s {
 	case "", "none":
@@ -15,20 +15,27 @@ func TestParseDefaultConfig(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, conf, Config{
 		Notebook: NotebookConfig{
-			Dir: opt.NullString,
+			Dir:          opt.NullString,
+			Ignore:       []string{},
+			IgnoreHidden: true,
+		},
+		Index: IndexConfig{
+			Include: []string{},
 		},
 		Note: NoteConfig{
 			FilenameTemplate: "{{id}}",
 			Extension:        "md",
 			BodyTemplatePath: opt.NullString,
 			IDOptions: IDOptions{
-				Length:  4,
-				Charset: CharsetAlphanum,
-				Case:    CaseLower,
+				Strategy: IDStrategyRandom,
+				Length:   4,
+				Charset:  CharsetAlphanum,
+				Case:     CaseLower,
 			},
 			DefaultTitle: "Untitled",
 			Lang:         "en",
 			Exclude:      []string{},
+			TodoMarkers:  []string{"TODO", "FIXME"},
 		},
 		Groups: make(map[string]GroupConfig),
 		Format: FormatConfig{
@@ -37,6 +44,7 @@ func TestParseDefaultConfig(t *testing.T) {
 				ColonTags:         false,
 				MultiwordTags:     false,
 				LinkFormat:        "markdown",
+				LinkTarget:        "path",
 				LinkEncodePath:    true,
 				LinkDropExtension: true,
 			},
@@ -54,9 +62,16 @@ func TestParseDefaultConfig(t *testing.T) {
 				DeadLink:  LSPDiagnosticError,
 			},
 		},
-		Filters: make(map[string]string),
-		Aliases: make(map[string]string),
-		Extra:   make(map[string]string),
+		Journal: JournalConfig{
+			Path:             `journal/{{format-date now "%Y-%m-%d"}}.md`,
+			BodyTemplatePath: opt.NullString,
+		},
+		Filters:           make(map[string]string),
+		Aliases:           make(map[string]string),
+		Extra:             make(map[string]string),
+		FrontmatterSchema: map[string]FrontmatterFieldSchema{},
+		Notebooks:         map[string]string{},
+		CommandDefaults:   map[string]map[string]interface{}{},
 	})
 }
 
@@ -78,21 +93,25 @@ func TestParseComplete(t *testing.T) {
 		template = "default.note"
 		language = "fr"
 		default-title = "Sans titre"
+		id-strategy = "timestamp"
 		id-charset = "alphanum"
 		id-length = 4
 		id-case = "lower"
 		exclude = ["ignored", ".git"]
+		todo-markers = ["TODO", "XXX"]
 
 		[format.markdown]
 		hashtags = false
 		colon-tags = true
 		multiword-tags = true
 		link-format = "custom"
+		link-target = "title"
 		link-encode-path = true
 		link-drop-extension = false
 
 		[tool]
 		editor = "vim"
+		editor-sequential = true
 		shell = "/bin/bash"
 		pager = "less"
 		fzf-preview = "bat {1}"
@@ -104,6 +123,10 @@ func TestParseComplete(t *testing.T) {
 		hello = "world"
 		salut = "le monde"
 
+		[journal]
+		path = "daily/{{format-date now}}.md"
+		template = "daily.md"
+
 		[filter]
 		recents = "--created-after '2 weeks ago'"
 		journal = "journal --sort created"
@@ -121,6 +144,7 @@ func TestParseComplete(t *testing.T) {
 		template = "log.md"
 		language = "de"
 		default-title = "Ohne Titel"
+		id-strategy = "sequence"
 		id-charset = "letters"
 		id-length = 8
 		id-case = "mixed"
@@ -144,25 +168,47 @@ func TestParseComplete(t *testing.T) {
 		[lsp.diagnostics]
 		wiki-title = "hint"
 		dead-link = "none"
+
+		[frontmatter-schema.Status]
+		required = true
+		values = ["draft", "published"]
+
+		[frontmatter-schema.tags]
+		type = "list"
+
+		[list]
+		sort = ["created-"]
+		limit = 20
+		quiet = true
+
+		[edit]
+		interactive = true
 	`), ".zk/config.toml", NewDefaultConfig(), true)
 
 	assert.Nil(t, err)
 	assert.Equal(t, conf, Config{
 		Notebook: NotebookConfig{
-			Dir: opt.NewString("~/notebook"),
+			Dir:          opt.NewString("~/notebook"),
+			Ignore:       []string{},
+			IgnoreHidden: true,
+		},
+		Index: IndexConfig{
+			Include: []string{},
 		},
 		Note: NoteConfig{
 			FilenameTemplate: "{{id}}.note",
 			Extension:        "txt",
 			BodyTemplatePath: opt.NewString("default.note"),
 			IDOptions: IDOptions{
-				Length:  4,
-				Charset: CharsetAlphanum,
-				Case:    CaseLower,
+				Strategy: IDStrategyTimestamp,
+				Length:   4,
+				Charset:  CharsetAlphanum,
+				Case:     CaseLower,
 			},
 			Lang:         "fr",
 			DefaultTitle: "Sans titre",
 			Exclude:      []string{"ignored", ".git"},
+			TodoMarkers:  []string{"TODO", "XXX"},
 		},
 		Groups: map[string]GroupConfig{
 			"log": {
@@ -172,13 +218,15 @@ func TestParseComplete(t *testing.T) {
 					Extension:        "note",
 					BodyTemplatePath: opt.NewString("log.md"),
 					IDOptions: IDOptions{
-						Length:  8,
-						Charset: CharsetLetters,
-						Case:    CaseMixed,
+						Strategy: IDStrategySequence,
+						Length:   8,
+						Charset:  CharsetLetters,
+						Case:     CaseMixed,
 					},
 					Lang:         "de",
 					DefaultTitle: "Ohne Titel",
 					Exclude:      []string{"ignored", ".git", "new-ignored"},
+					TodoMarkers:  []string{"TODO", "XXX"},
 				},
 				Extra: map[string]string{
 					"hello":   "world",
@@ -193,13 +241,15 @@ func TestParseComplete(t *testing.T) {
 					Extension:        "txt",
 					BodyTemplatePath: opt.NewString("default.note"),
 					IDOptions: IDOptions{
-						Length:  4,
-						Charset: CharsetAlphanum,
-						Case:    CaseLower,
+						Strategy: IDStrategyTimestamp,
+						Length:   4,
+						Charset:  CharsetAlphanum,
+						Case:     CaseLower,
 					},
 					Lang:         "fr",
 					DefaultTitle: "Sans titre",
 					Exclude:      []string{"ignored", ".git"},
+					TodoMarkers:  []string{"TODO", "XXX"},
 				},
 				Extra: map[string]string{
 					"hello": "world",
@@ -213,13 +263,15 @@ func TestParseComplete(t *testing.T) {
 					Extension:        "txt",
 					BodyTemplatePath: opt.NewString("default.note"),
 					IDOptions: IDOptions{
-						Length:  4,
-						Charset: CharsetAlphanum,
-						Case:    CaseLower,
+						Strategy: IDStrategyTimestamp,
+						Length:   4,
+						Charset:  CharsetAlphanum,
+						Case:     CaseLower,
 					},
 					Lang:         "fr",
 					DefaultTitle: "Sans titre",
 					Exclude:      []string{"ignored", ".git"},
+					TodoMarkers:  []string{"TODO", "XXX"},
 				},
 				Extra: map[string]string{
 					"hello": "world",
@@ -233,18 +285,20 @@ func TestParseComplete(t *testing.T) {
 				ColonTags:         true,
 				MultiwordTags:     true,
 				LinkFormat:        "custom",
+				LinkTarget:        "title",
 				LinkEncodePath:    true,
 				LinkDropExtension: false,
 			},
 		},
 		Tool: ToolConfig{
-			Editor:     opt.NewString("vim"),
-			Shell:      opt.NewString("/bin/bash"),
-			Pager:      opt.NewString("less"),
-			FzfPreview: opt.NewString("bat {1}"),
-			FzfLine:    opt.NewString("{{title}}"),
-			FzfOptions: opt.NewString("--border --height 40%"),
-			FzfBindNew: opt.NewString("Ctrl-C"),
+			Editor:           opt.NewString("vim"),
+			EditorSequential: true,
+			Shell:            opt.NewString("/bin/bash"),
+			Pager:            opt.NewString("less"),
+			FzfPreview:       opt.NewString("bat {1}"),
+			FzfLine:          opt.NewString("{{title}}"),
+			FzfOptions:       opt.NewString("--border --height 40%"),
+			FzfBindNew:       opt.NewString("Ctrl-C"),
 		},
 		LSP: LSPConfig{
 			Completion: LSPCompletionConfig{
@@ -260,6 +314,10 @@ func TestParseComplete(t *testing.T) {
 				DeadLink:  LSPDiagnosticNone,
 			},
 		},
+		Journal: JournalConfig{
+			Path:             "daily/{{format-date now}}.md",
+			BodyTemplatePath: opt.NewString("daily.md"),
+		},
 		Filters: map[string]string{
 			"recents": "--created-after '2 weeks ago'",
 			"journal": "journal --sort created",
@@ -272,6 +330,26 @@ func TestParseComplete(t *testing.T) {
 			"hello": "world",
 			"salut": "le monde",
 		},
+		FrontmatterSchema: map[string]FrontmatterFieldSchema{
+			"status": {
+				Required: true,
+				Values:   []string{"draft", "published"},
+			},
+			"tags": {
+				Type: FrontmatterFieldList,
+			},
+		},
+		Notebooks: map[string]string{},
+		CommandDefaults: map[string]map[string]interface{}{
+			"list": {
+				"sort":  []interface{}{"created-"},
+				"limit": int64(20),
+				"quiet": true,
+			},
+			"edit": {
+				"interactive": true,
+			},
+		},
 	})
 }
 
@@ -308,18 +386,27 @@ func TestParseMergesGroupConfig(t *testing.T) {
 
 	assert.Nil(t, err)
 	assert.Equal(t, conf, Config{
+		Notebook: NotebookConfig{
+			Ignore:       []string{},
+			IgnoreHidden: true,
+		},
+		Index: IndexConfig{
+			Include: []string{},
+		},
 		Note: NoteConfig{
 			FilenameTemplate: "root-filename",
 			Extension:        "txt",
 			BodyTemplatePath: opt.NewString("root-template"),
 			IDOptions: IDOptions{
-				Length:  42,
-				Charset: CharsetLetters,
-				Case:    CaseUpper,
+				Strategy: IDStrategyRandom,
+				Length:   42,
+				Charset:  CharsetLetters,
+				Case:     CaseUpper,
 			},
 			Lang:         "fr",
 			DefaultTitle: "Sans titre",
 			Exclude:      []string{"ignored", ".git"},
+			TodoMarkers:  []string{"TODO", "FIXME"},
 		},
 		Groups: map[string]GroupConfig{
 			"log": {
@@ -329,13 +416,15 @@ func TestParseMergesGroupConfig(t *testing.T) {
 					Extension:        "txt",
 					BodyTemplatePath: opt.NewString("log-template"),
 					IDOptions: IDOptions{
-						Length:  8,
-						Charset: CharsetNumbers,
-						Case:    CaseMixed,
+						Strategy: IDStrategyRandom,
+						Length:   8,
+						Charset:  CharsetNumbers,
+						Case:     CaseMixed,
 					},
 					Lang:         "fr",
 					DefaultTitle: "Sans titre",
 					Exclude:      []string{"ignored", ".git"},
+					TodoMarkers:  []string{"TODO", "FIXME"},
 				},
 				Extra: map[string]string{
 					"hello":   "override",
@@ -350,13 +439,15 @@ func TestParseMergesGroupConfig(t *testing.T) {
 					Extension:        "txt",
 					BodyTemplatePath: opt.NewString("root-template"),
 					IDOptions: IDOptions{
-						Length:  42,
-						Charset: CharsetLetters,
-						Case:    CaseUpper,
+						Strategy: IDStrategyRandom,
+						Length:   42,
+						Charset:  CharsetLetters,
+						Case:     CaseUpper,
 					},
 					Lang:         "fr",
 					DefaultTitle: "Sans titre",
 					Exclude:      []string{"ignored", ".git"},
+					TodoMarkers:  []string{"TODO", "FIXME"},
 				},
 				Extra: map[string]string{
 					"hello": "world",
@@ -370,6 +461,7 @@ func TestParseMergesGroupConfig(t *testing.T) {
 				ColonTags:         false,
 				MultiwordTags:     false,
 				LinkFormat:        "markdown",
+				LinkTarget:        "path",
 				LinkEncodePath:    true,
 				LinkDropExtension: true,
 			},
@@ -387,12 +479,19 @@ func TestParseMergesGroupConfig(t *testing.T) {
 				DeadLink:  LSPDiagnosticError,
 			},
 		},
+		Journal: JournalConfig{
+			Path:             `journal/{{format-date now "%Y-%m-%d"}}.md`,
+			BodyTemplatePath: opt.NullString,
+		},
 		Filters: make(map[string]string),
 		Aliases: make(map[string]string),
 		Extra: map[string]string{
 			"hello": "world",
 			"salut": "le monde",
 		},
+		FrontmatterSchema: map[string]FrontmatterFieldSchema{},
+		Notebooks:         map[string]string{},
+		CommandDefaults:   map[string]map[string]interface{}{},
 	})
 }
 
@@ -428,6 +527,58 @@ func TestParseNotebook(t *testing.T) {
 	assert.Err(t, err, "notebook.dir should not be set on local configuration")
 }
 
+func TestParseNotebooks(t *testing.T) {
+	toml := `
+			[notebooks]
+			work = "~/work-notes"
+			personal = "/home/user/notes"
+		`
+	conf, err := ParseConfig([]byte(toml), ".zk/config.toml", NewDefaultConfig(), true)
+	assert.Nil(t, err)
+	assert.Equal(t, conf.Notebooks, map[string]string{
+		"work":     "~/work-notes",
+		"personal": "/home/user/notes",
+	})
+
+	// Named notebooks can only be registered in the global config.
+	_, err = ParseConfig([]byte(toml), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "notebooks should not be set on local configuration")
+}
+
+func TestParseFrontmatterSchemaUnknownType(t *testing.T) {
+	_, err := ParseConfig([]byte(`
+		[frontmatter-schema.created]
+		type = "date"
+	`), ".zk/config.toml", NewDefaultConfig(), true)
+	assert.Err(t, err, "frontmatter-schema.created.type: date: unknown frontmatter type - may be string, number, boolean or list")
+}
+
+func TestParseNotebookIgnore(t *testing.T) {
+	parent := NewDefaultConfig()
+	parent.Notebook.Ignore = []string{"generated/"}
+
+	conf, err := ParseConfig([]byte(`
+		[notebook]
+		ignore = ["**/*.draft.md", "!keep.draft.md"]
+		ignore-hidden = false
+	`), ".zk/config.toml", parent, false)
+	assert.Nil(t, err)
+	assert.Equal(t, conf.Notebook.Ignore, []string{"generated/", "**/*.draft.md", "!keep.draft.md"})
+	assert.Equal(t, conf.Notebook.IgnoreHidden, false)
+}
+
+func TestParseIndexInclude(t *testing.T) {
+	parent := NewDefaultConfig()
+	parent.Index.Include = []string{"**/*.txt"}
+
+	conf, err := ParseConfig([]byte(`
+		[index]
+		include = ["**/*.canvas"]
+	`), ".zk/config.toml", parent, false)
+	assert.Nil(t, err)
+	assert.Equal(t, conf.Index.Include, []string{"**/*.txt", "**/*.canvas"})
+}
+
 func TestParseIDCharset(t *testing.T) {
 	test := func(charset string, expected Charset) {
 		toml := fmt.Sprintf(`
@@ -468,6 +619,136 @@ func TestParseIDCase(t *testing.T) {
 	test("unknown", CaseLower)
 }
 
+func TestParseIDStrategy(t *testing.T) {
+	test := func(strategy string, expected IDStrategy) {
+		toml := fmt.Sprintf(`
+			[note]
+			id-strategy = "%v"
+		`, strategy)
+		conf, err := ParseConfig([]byte(toml), ".zk/config.toml", NewDefaultConfig(), false)
+		assert.Nil(t, err)
+		assert.Equal(t, conf.Note.IDOptions.Strategy, expected)
+	}
+
+	test("", IDStrategyRandom)
+	test("random", IDStrategyRandom)
+	test("timestamp", IDStrategyTimestamp)
+	test("sequence", IDStrategySequence)
+
+	_, err := ParseConfig([]byte(`
+		[note]
+		id-strategy = "uuid"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "uuid: unknown note ID strategy - may be random, timestamp or sequence")
+
+	_, err = ParseConfig([]byte(`
+		[group.log.note]
+		id-strategy = "uuid"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "group.log: uuid: unknown note ID strategy")
+}
+
+func TestParseGroupPathsOutsideNotebook(t *testing.T) {
+	test := func(path string) {
+		_, err := ParseConfig([]byte(`
+			[group.log]
+			paths = ["journal", "`+path+`"]
+		`), ".zk/config.toml", NewDefaultConfig(), false)
+		assert.Err(t, err, "group.log: "+path+": the group paths must be relative to the notebook root, inside the notebook")
+	}
+
+	test("../journal")
+	test("journal/../../log")
+	test("/home/user/journal")
+	test("")
+
+	_, err := ParseConfig([]byte(`
+		[group.log]
+		paths = ["journal/*", "log/../daily"]
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Nil(t, err)
+}
+
+func TestParseNoteEncoding(t *testing.T) {
+	conf, err := ParseConfig([]byte(`
+		[note]
+		encoding = "latin1"
+
+		[group.legacy.note]
+		encoding = "windows-1252"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Nil(t, err)
+	assert.Equal(t, conf.Note.Encoding, "latin1")
+	assert.Equal(t, conf.Groups["legacy"].Note.Encoding, "windows-1252")
+
+	_, err = ParseConfig([]byte(`
+		[note]
+		encoding = "klingon"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "note.encoding: klingon: unknown encoding - may be any IANA character set name, e.g. utf-8, latin1 or windows-1252")
+
+	_, err = ParseConfig([]byte(`
+		[group.legacy.note]
+		encoding = "klingon"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "group.legacy: note.encoding: klingon: unknown encoding")
+}
+
+func TestParseFrontmatterBase(t *testing.T) {
+	conf, err := ParseConfig([]byte(`
+		[note]
+		frontmatter-base = "base.yml"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Nil(t, err)
+	assert.Equal(t, conf.Note.FrontmatterBasePath, opt.NewString("base.yml"))
+	assert.Equal(t, conf.Note.FrontmatterBase, map[string]interface{}(nil))
+
+	conf, err = ParseConfig([]byte(`
+		[note.frontmatter-base]
+		status = "draft"
+		tags = ["inbox"]
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Nil(t, err)
+	assert.Equal(t, conf.Note.FrontmatterBasePath, opt.NullString)
+	assert.Equal(t, conf.Note.FrontmatterBase, map[string]interface{}{
+		"status": "draft",
+		"tags":   []interface{}{"inbox"},
+	})
+
+	_, err = ParseConfig([]byte(`
+		[note]
+		frontmatter-base = 42
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "note.frontmatter-base should be a path to a YAML file or a table")
+}
+
+func TestOpenConfigValidatesFrontmatterBaseFile(t *testing.T) {
+	test := func(base string, expectedErr string) {
+		fs := newFileStorageMock("/notebook", []string{})
+		fs.files["/notebook/.zk/config.toml"] = "[note]\nfrontmatter-base = \"base.yml\""
+		fs.files["/notebook/.zk/templates/base.yml"] = base
+
+		_, err := OpenConfig("/notebook/.zk/config.toml", NewDefaultConfig(), fs, false)
+		if expectedErr == "" {
+			assert.Nil(t, err)
+		} else {
+			assert.Err(t, err, expectedErr)
+		}
+	}
+
+	test("status: draft\ntags: [inbox]\n", "")
+	// The template expressions are not rendered yet.
+	test("author: {{extra.author}}\n{{#if extra.project}}\nproject: \"{{extra.project}}\"\n{{/if}}\n", "")
+	test("- draft\n", "/notebook/.zk/templates/base.yml: invalid frontmatter base")
+	test("status: draft\n\ttags: inbox\n", "/notebook/.zk/templates/base.yml: invalid frontmatter base")
+
+	// The template might be in another templates directory.
+	fs := newFileStorageMock("/notebook", []string{})
+	fs.files["/notebook/.zk/config.toml"] = "[note]\nfrontmatter-base = \"base.yml\""
+	_, err := OpenConfig("/notebook/.zk/config.toml", NewDefaultConfig(), fs, false)
+	assert.Nil(t, err)
+}
+
 // If link-encode-path is not set explicitly, it defaults to true for
 // "markdown" format and false for anything else.
 func TestParseMarkdownLinkEncodePath(t *testing.T) {
@@ -515,6 +796,52 @@ func TestParseLSPDiagnosticsSeverity(t *testing.T) {
 	assert.Err(t, err, "foobar: unknown LSP diagnostic severity - may be none, hint, info, warning or error")
 }
 
+func TestParseLinkTarget(t *testing.T) {
+	test := func(target string, expected string) {
+		toml := fmt.Sprintf(`
+			[format.markdown]
+			link-target = "%s"
+		`, target)
+		conf, err := ParseConfig([]byte(toml), ".zk/config.toml", NewDefaultConfig(), false)
+		assert.Nil(t, err)
+		assert.Equal(t, conf.Format.Markdown.LinkTarget, expected)
+	}
+
+	test("path", "path")
+	test("title", "title")
+
+	_, err := ParseConfig([]byte(`
+		[format.markdown]
+		link-target = "id"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "id: unknown link target - may be path or title")
+}
+
+func TestParseConfirmDefault(t *testing.T) {
+	test := func(value string, expected opt.Bool) {
+		toml := fmt.Sprintf(`
+			[confirm]
+			default = "%s"
+		`, value)
+		conf, err := ParseConfig([]byte(toml), ".zk/config.toml", NewDefaultConfig(), false)
+		assert.Nil(t, err)
+		assert.Equal(t, conf.Confirm.Default, expected)
+	}
+
+	test("yes", opt.True)
+	test("no", opt.False)
+
+	conf, err := ParseConfig([]byte(""), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Nil(t, err)
+	assert.Equal(t, conf.Confirm.Default, opt.NullBool)
+
+	_, err = ParseConfig([]byte(`
+		[confirm]
+		default = "maybe"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "maybe: unknown confirmation default - may be yes or no")
+}
+
 func TestGroupConfigExcludeGlobs(t *testing.T) {
 	// empty globs
 	config := GroupConfig{
@@ -542,6 +869,32 @@ func TestGroupConfigExcludeGlobs(t *testing.T) {
 	assert.Equal(t, config.ExcludeGlobs(), []string{"log/ignored", "log/*.git", "drafts/ignored", "drafts/*.git"})
 }
 
+func TestGroupNameForNote(t *testing.T) {
+	config := Config{
+		Groups: map[string]GroupConfig{
+			"log":     {Paths: []string{"journal/daily", "journal/weekly"}},
+			"journal": {Paths: []string{"journal/*"}},
+			"drafts":  {Paths: []string{"drafts"}},
+		},
+	}
+
+	test := func(path string, expected string) {
+		t.Helper()
+		name, err := config.GroupNameForNote(path)
+		assert.Nil(t, err)
+		assert.Equal(t, name, expected)
+	}
+
+	test("note.md", "")
+	test("journal/note.md", "")
+	test("drafts/note.md", "drafts")
+	test("drafts/sub/dir/note.md", "drafts")
+	test("journal/monthly/note.md", "journal")
+	// The first matching group in alphabetical order wins.
+	test("journal/daily/note.md", "journal")
+	test("journal/weekly/sub/note.md", "log")
+}
+
 func TestGroupConfigClone(t *testing.T) {
 	original := GroupConfig{
 		Paths: []string{"original"},
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FuzzyPattern converts a fuzzy query into a regular expression matching the
+// same texts, or an empty string if the query matches anything.
+func FuzzyPattern(query string) string {
+	chars := []string{}
+	for _, c := range query {
+		if !unicode.IsSpace(c) {
+			chars = append(chars, regexp.QuoteMeta(string(c)))
+		}
+	}
+	if len(chars) == 0 {
+		return ""
+	}
+	return "(?is)" + strings.Join(chars, ".*")
+}
+
+// FuzzyScore ranks how well the query matches the given text. The characters
+// of the query must appear in order in the text, ignoring the case and the
+// whitespaces of the query. Consecutive characters and characters at the
+// start of a word score higher. Returns false if the text doesn't match.
+func FuzzyScore(query string, text string) (int, bool) {
+	score, _, ok := fuzzyMatch(query, text)
+	return score, ok
+}
+
+// FuzzyMatchPositions returns the indexes of the runes of the text matched
+// by the characters of the query, for the best scoring occurrence. Returns
+// nil if the text doesn't match.
+func FuzzyMatchPositions(query string, text string) []int {
+	_, positions, ok := fuzzyMatch(query, text)
+	if !ok {
+		return nil
+	}
+	return positions
+}
+
+// fuzzyMatch returns the score and the matched rune positions of the best
+// occurrence of the query in the text.
+func fuzzyMatch(query string, text string) (int, []int, bool) {
+	chars := []rune{}
+	for _, c := range query {
+		if !unicode.IsSpace(c) {
+			chars = append(chars, unicode.ToLower(c))
+		}
+	}
+	// The runes are lowered one by one to keep their positions in the text.
+	runes := []rune(text)
+	for i, c := range runes {
+		runes[i] = unicode.ToLower(c)
+	}
+	if len(chars) == 0 {
+		return 0, []int{}, true
+	}
+
+	// Keeps the best score among the occurrences of the first character.
+	best, positions, found := 0, []int{}, false
+	for start, c := range runes {
+		if c != chars[0] {
+			continue
+		}
+		if score, pos, ok := fuzzyMatchFrom(chars, runes, start); ok && (!found || score > best) {
+			best, positions, found = score, pos, true
+		}
+	}
+	return best, positions, found
+}
+
+// fuzzyMatchFrom scores the query characters greedily matched in the text,
+// from the given position.
+func fuzzyMatchFrom(chars []rune, runes []rune, start int) (int, []int, bool) {
+	score := 0
+	positions := make([]int, 0, len(chars))
+	prev := start - 2
+	i := start
+	for _, c := range chars {
+		for i < len(runes) && runes[i] != c {
+			i++
+		}
+		if i == len(runes) {
+			return 0, nil, false
+		}
+
+		score++
+		if i == prev+1 {
+			score += 4
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 3
+		}
+		positions = append(positions, i)
+		prev = i
+		i++
+	}
+	return score, positions, true
+}
+
+// fuzzyMatchPositionsAll returns the sorted rune positions of the text
+// matched by any of the given fuzzy queries.
+func fuzzyMatchPositionsAll(queries []string, text string) []int {
+	found := map[int]bool{}
+	positions := []int{}
+	for _, query := range queries {
+		for _, pos := range FuzzyMatchPositions(query, text) {
+			if !found[pos] {
+				found[pos] = true
+				positions = append(positions, pos)
+			}
+		}
+	}
+	sort.Ints(positions)
+	return positions
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestFuzzyPattern(t *testing.T) {
+	assert.Equal(t, FuzzyPattern(""), "")
+	assert.Equal(t, FuzzyPattern("  "), "")
+	assert.Equal(t, FuzzyPattern("ab c"), "(?is)a.*b.*c")
+	assert.Equal(t, FuzzyPattern("a.b"), `(?is)a.*\..*b`)
+}
+
+func TestFuzzyScore(t *testing.T) {
+	_, ok := FuzzyScore("xyz", "Fuzzy finder")
+	assert.False(t, ok)
+
+	score, ok := FuzzyScore("", "Fuzzy finder")
+	assert.True(t, ok)
+	assert.Equal(t, score, 0)
+
+	// Consecutive characters and word starts score higher.
+	prefix, _ := FuzzyScore("fin", "Fuzzy finder")
+	scattered, _ := FuzzyScore("fzr", "Fuzzy finder")
+	assert.True(t, prefix > scattered)
+}
+
+func TestFuzzyMatchPositions(t *testing.T) {
+	test := func(query string, text string, expected []int) {
+		t.Helper()
+		assert.Equal(t, FuzzyMatchPositions(query, text), expected)
+	}
+
+	test("xyz", "Fuzzy finder", nil)
+	test("", "Fuzzy finder", []int{})
+	test("fzf", "Fuzzy finder", []int{0, 2, 6})
+	// The best scoring occurrence is picked.
+	test("fin", "Fuzzy finder", []int{6, 7, 8})
+	// Case and whitespaces are ignored.
+	test("F D", "fuzzy finder", []int{0, 9})
+	// The positions are rune indexes.
+	test("hw", "§How", []int{1, 3})
+	test("i", "İi", []int{0})
+
+	assert.Equal(t, fuzzyMatchPositionsAll([]string{"der", "fzf"}, "Fuzzy finder"), []int{0, 2, 6, 9, 10, 11})
+}
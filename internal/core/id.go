@@ -1,12 +1,39 @@
 package core
 
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zk-org/zk/internal/util/paths"
+)
+
 // IDOptions holds the options used to generate an ID.
 type IDOptions struct {
-	Length  int
-	Charset Charset
-	Case    Case
+	Strategy IDStrategy
+	Length   int
+	Charset  Charset
+	Case     Case
 }
 
+// IDStrategy represents the method used to generate the ID of new notes.
+type IDStrategy string
+
+const (
+	// IDStrategyRandom generates random IDs made of the characters of a
+	// Charset.
+	IDStrategyRandom IDStrategy = "random"
+	// IDStrategyTimestamp generates IDs from the creation date of the note,
+	// in the YYYYMMDDHHMM shape.
+	IDStrategyTimestamp IDStrategy = "timestamp"
+	// IDStrategySequence generates incrementing numeric IDs.
+	IDStrategySequence IDStrategy = "sequence"
+)
+
 // Charset is a set of characters.
 type Charset []rune
 
@@ -35,3 +62,158 @@ type IDGenerator func() string
 
 // IDGeneratorFactory creates a new IDGenerator function using the given IDOptions.
 type IDGeneratorFactory func(opts IDOptions) func() string
+
+// newTimestampIDGenerator returns a generator of IDs made of the given date,
+// in the YYYYMMDDHHMM shape. Each invocation returns the following minute, to
+// resolve collisions with existing notes while keeping the IDs sortable.
+func newTimestampIDGenerator(date time.Time) IDGenerator {
+	date = date.Add(-time.Minute)
+	return func() string {
+		date = date.Add(time.Minute)
+		return date.Format("200601021504")
+	}
+}
+
+// newSequenceIDGenerator returns a generator of incrementing numeric IDs,
+// starting after the last issued one. The IDs are padded with zeros up to the
+// given length.
+func newSequenceIDGenerator(last int, length int) IDGenerator {
+	return func() string {
+		last++
+		return fmt.Sprintf("%0*d", length, last)
+	}
+}
+
+// FilenameHasID returns whether the filename of the note at the given path
+// contains the note ID, as a distinct word. For example, `200911172034` is the
+// ID of `200911172034 An interesting concept.md` and `i2hn8-a-concept.md`
+// contains `i2hn8`.
+//
+// An empty ID is always matching.
+func FilenameHasID(path string, id string) bool {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return true
+	}
+
+	stem := paths.FilenameStem(path)
+	for offset := 0; offset < len(stem); {
+		i := strings.Index(stem[offset:], id)
+		if i < 0 {
+			break
+		}
+		start := offset + i
+		end := start + len(id)
+		if isWordBoundary(stem, start, end) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(stem[start:])
+		offset = start + size
+	}
+	return false
+}
+
+// isWordBoundary returns whether the given range of s is not surrounded by
+// letters or numbers.
+func isWordBoundary(s string, start int, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(s[:start])
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return false
+		}
+	}
+	if end < len(s) {
+		r, _ := utf8.DecodeRuneInString(s[end:])
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// newIDGenerator creates a generator of IDs for new notes, according to the
+// strategy of the given options. The timestamp strategy uses the given
+// creation date.
+func (n *Notebook) newIDGenerator(opts IDOptions, date time.Time) (IDGenerator, error) {
+	switch opts.Strategy {
+	case IDStrategyTimestamp:
+		return newTimestampIDGenerator(date), nil
+	case IDStrategySequence:
+		last, err := n.lastIDSequence()
+		if err != nil {
+			return nil, err
+		}
+		return newSequenceIDGenerator(last, opts.Length), nil
+	default:
+		return n.idGeneratorFactory(opts), nil
+	}
+}
+
+// indexedIDChecker returns a function reporting whether the given ID is
+// already used in the filename of an indexed note.
+func (n *Notebook) indexedIDChecker() (func(id string) bool, error) {
+	paths := make([]string, 0)
+	// The indexed paths are streamed from the database, so they must be
+	// consumed before the transaction is closed.
+	err := n.index.Commit(func(index NoteIndex) error {
+		metadata, err := index.IndexedPaths()
+		if err != nil {
+			return err
+		}
+		for m := range metadata {
+			paths = append(paths, m.Path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func(id string) bool {
+		for _, path := range paths {
+			if FilenameHasID(path, id) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// idSequencePath returns the path to the file tracking the last ID issued
+// with the sequence strategy. It is kept outside of the index, to never reuse
+// the IDs of deleted notes even after rebuilding the index.
+func (n *Notebook) idSequencePath() string {
+	return filepath.Join(n.Path, ".zk/id-sequence")
+}
+
+// lastIDSequence returns the last ID issued with the sequence strategy, or 0.
+func (n *Notebook) lastIDSequence() (int, error) {
+	path := n.idSequencePath()
+	exists, err := n.fs.FileExists(path)
+	if err != nil || !exists {
+		return 0, err
+	}
+	content, err := n.fs.Read(path)
+	if err != nil {
+		return 0, err
+	}
+	last, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid note ID sequence", path)
+	}
+	return last, nil
+}
+
+// saveIDSequence records the given ID issued with the sequence strategy, if
+// it is the highest one so far.
+func (n *Notebook) saveIDSequence(id string) error {
+	seq, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("%s: invalid sequential note ID", id)
+	}
+	last, err := n.lastIDSequence()
+	if err != nil || seq <= last {
+		return err
+	}
+	return n.fs.Write(n.idSequencePath(), []byte(strconv.Itoa(seq)+"\n"))
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestFilenameHasID(t *testing.T) {
+	test := func(path string, id string, expected bool) {
+		t.Helper()
+		assert.Equal(t, FilenameHasID(path, id), expected)
+	}
+
+	test("i2hn8.md", "i2hn8", true)
+	test("dir/i2hn8.md", "i2hn8", true)
+	test("200911172034 An interesting concept.md", "200911172034", true)
+	test("i2hn8-an-interesting-concept.md", "i2hn8", true)
+	test("an-interesting-concept-i2hn8.md", "i2hn8", true)
+	test("ai2hn8-i2hn8.md", "i2hn8", true)
+	test("é-i2hn8.md", "i2hn8", true)
+	test("dir/i2hn8.md", "", true)
+	test("dir/i2hn8.md", "  ", true)
+
+	test("i2hn9.md", "i2hn8", false)
+	test("i2hn8b.md", "i2hn8", false)
+	test("ai2hn8.md", "i2hn8", false)
+	test("éi2hn8.md", "i2hn8", false)
+	test("i2hn8/note.md", "i2hn8", false)
+	test("a+b.md", "a.b", false)
+}
+
+func TestTimestampIDGenerator(t *testing.T) {
+	gen := newTimestampIDGenerator(time.Date(2009, 11, 17, 23, 59, 58, 0, time.UTC))
+	assert.Equal(t, gen(), "200911172359")
+	// The next IDs are bumped by one minute.
+	assert.Equal(t, gen(), "200911180000")
+	assert.Equal(t, gen(), "200911180001")
+}
+
+func TestSequenceIDGenerator(t *testing.T) {
+	gen := newSequenceIDGenerator(0, 0)
+	assert.Equal(t, gen(), "1")
+	assert.Equal(t, gen(), "2")
+
+	// Padded with zeros
+	gen = newSequenceIDGenerator(98, 3)
+	assert.Equal(t, gen(), "099")
+	assert.Equal(t, gen(), "100")
+	gen = newSequenceIDGenerator(999, 3)
+	assert.Equal(t, gen(), "1000")
+}
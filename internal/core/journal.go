@@ -0,0 +1,74 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/opt"
+)
+
+// JournalNoteOpts holds the options used to open a journal note.
+type JournalNoteOpts struct {
+	// Date of the journal entry.
+	Date time.Time
+}
+
+// JournalNote returns the absolute path to the journal note of the given
+// date, after creating it if it does not exist yet.
+//
+// The path of the note is generated from the journal.path template of the
+// config, so it is stable for a given date.
+func (n *Notebook) JournalNote(opts JournalNoteOpts) (path string, created bool, err error) {
+	wrap := errors.Wrapper("journal note")
+
+	templates, err := n.templateLoaderFactory(n.Config.Note.Lang)
+	if err != nil {
+		return "", false, wrap(err)
+	}
+	template, err := templates.LoadTemplate(n.Config.Journal.Path)
+	if err != nil {
+		return "", false, wrap(err)
+	}
+	filename, err := template.Render(newNoteTemplateContext{
+		Now:   opts.Date,
+		Extra: n.Config.Extra,
+		Env:   n.osEnv(),
+	})
+	if err != nil {
+		return "", false, wrap(err)
+	}
+
+	path = filepath.Join(n.Path, filename)
+	relPath, err := filepath.Rel(n.Path, path)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, "../") {
+		return "", false, wrap(fmt.Errorf("%s: the journal note must be inside the notebook", filename))
+	}
+
+	exists, err := n.fs.FileExists(path)
+	if err != nil {
+		return "", false, wrap(err)
+	}
+	if exists {
+		return path, false, nil
+	}
+
+	group, err := n.Config.GroupNameForPath(filepath.Dir(relPath))
+	if err != nil {
+		return "", false, wrap(err)
+	}
+
+	note, err := n.NewNote(NewNoteOpts{
+		Group:            opt.NewNotEmptyString(group),
+		Template:         n.Config.Journal.BodyTemplatePath,
+		FilenameTemplate: opt.NewString(n.Config.Journal.Path),
+		Date:             opts.Date,
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	return filepath.Join(n.Path, note.Path), true, nil
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/opt"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestNotebookJournalNoteCreatesMissingNote(t *testing.T) {
+	test := newJournalTest()
+
+	path, created, err := test.notebook().JournalNote(JournalNoteOpts{Date: now})
+	assert.Nil(t, err)
+	assert.True(t, created)
+	assert.Equal(t, path, "/notebook/journal/2009-11-17.md")
+	assert.Equal(t, test.fs.files["/notebook/journal/2009-11-17.md"], "journal body")
+
+	// The filename is rendered with the frozen date.
+	for _, context := range test.filenameTemplate.Contexts {
+		assert.Equal(t, context.(newNoteTemplateContext).Now, now)
+	}
+}
+
+func TestNotebookJournalNoteOpensExistingNote(t *testing.T) {
+	test := newJournalTest()
+	test.fs.files["/notebook/journal/2009-11-17.md"] = "existing"
+
+	path, created, err := test.notebook().JournalNote(JournalNoteOpts{Date: now})
+	assert.Nil(t, err)
+	assert.False(t, created)
+	assert.Equal(t, path, "/notebook/journal/2009-11-17.md")
+	assert.Equal(t, test.fs.files["/notebook/journal/2009-11-17.md"], "existing")
+}
+
+func TestNotebookJournalNoteRejectsPathOutsideNotebook(t *testing.T) {
+	test := newJournalTest()
+	test.templateLoader.SpyString("../journal.md")
+	test.config.Journal.Path = "../journal.md"
+
+	_, _, err := test.notebook().JournalNote(JournalNoteOpts{Date: now})
+	assert.Err(t, err, "../journal.md: the journal note must be inside the notebook")
+}
+
+func newJournalTest() *newNoteTest {
+	test := &newNoteTest{rootDir: "/notebook"}
+	test.setup()
+
+	test.filenameTemplate = test.templateLoader.Spy("journal/{{date}}.md", func(context interface{}) string {
+		return "journal/" + context.(newNoteTemplateContext).Now.Format("2006-01-02") + ".md"
+	})
+	test.templateLoader.SpyFile("journal.md", "journal body")
+	test.config.Journal = JournalConfig{
+		Path:             "journal/{{date}}.md",
+		BodyTemplatePath: opt.NewString("journal.md"),
+	}
+	return test
+}
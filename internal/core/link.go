@@ -1,5 +1,11 @@
 package core
 
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
 // LinkID represents the unique ID of a note link relative to a given
 // NoteIndex implementation.
 type LinkID int64
@@ -26,6 +32,9 @@ type Link struct {
 	SnippetStart int `json:"snippetStart"`
 	// End byte offset of the snippet in the note content.
 	SnippetEnd int `json:"snippetEnd"`
+	// Byte offset of the link in the note content, pointing at its label or
+	// href.
+	Start int `json:"start"`
 }
 
 // ResolvedLink represents a link between two indexed notes.
@@ -36,6 +45,21 @@ type ResolvedLink struct {
 	SourcePath string `json:"sourcePath"`
 	TargetID   NoteID `json:"targetId"`
 	TargetPath string `json:"targetPath"`
+	// Notes the link could resolve to, when it is ambiguous.
+	CandidateIDs []NoteID `json:"-"`
+}
+
+// AmbiguousLink is a link whose href could resolve to several notes.
+type AmbiguousLink struct {
+	// Destination URI of the link.
+	Href string `json:"href"`
+	// Paths of the notes matching the href, the first one being the target.
+	Candidates []string `json:"candidates"`
+}
+
+// String implements Stringer.
+func (l AmbiguousLink) String() string {
+	return l.Href + ": " + strings.Join(l.Candidates, ", ")
 }
 
 // LinkType represents the kind of link, e.g. wiki link.
@@ -65,3 +89,45 @@ func LinkRels(rel ...string) []LinkRelation {
 	}
 	return rels
 }
+
+// LinkDomain returns the domain name of the given external link href, in
+// lowercase and without the `www.` prefix, e.g. `example.com` for
+// `https://www.Example.com/page`. It is empty for the hrefs without a host,
+// such as `mailto:` links or the paths to other notes.
+func LinkDomain(href string) string {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	return strings.TrimPrefix(host, "www.")
+}
+
+// LinkDomains returns the sorted domain names of the given external links,
+// without duplicates.
+func LinkDomains(links []Link) []string {
+	seen := map[string]bool{}
+	var domains []string
+	for _, link := range links {
+		if !link.IsExternal {
+			continue
+		}
+		if domain := LinkDomain(link.Href); domain != "" && !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// LinkMatchesDomain returns whether the domain of the given href is the
+// given domain or one of its subdomains.
+func LinkMatchesDomain(href string, domain string) bool {
+	linkDomain := LinkDomain(href)
+	domain = strings.TrimPrefix(strings.ToLower(domain), "www.")
+	if linkDomain == "" || domain == "" {
+		return false
+	}
+	return linkDomain == domain || strings.HasSuffix(linkDomain, "."+domain)
+}
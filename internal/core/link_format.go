@@ -51,6 +51,8 @@ func NewLinkFormatter(config MarkdownConfig, templateLoader TemplateLoader) (Lin
 		return NewMarkdownLinkFormatter(config, false)
 	case "wiki":
 		return NewWikiLinkFormatter(config)
+	case "wiki-label":
+		return NewWikiLabelLinkFormatter(config)
 	default:
 		return NewCustomLinkFormatter(config, templateLoader)
 	}
@@ -76,15 +78,41 @@ func NewMarkdownLinkFormatter(config MarkdownConfig, onlyHref bool) (LinkFormatt
 
 func NewWikiLinkFormatter(config MarkdownConfig) (LinkFormatter, error) {
 	return func(context LinkFormatterContext) (string, error) {
-		path := formatPath(context.Path, config)
-		if !config.LinkEncodePath {
-			path = strings.ReplaceAll(path, `\`, `\\`)
-			path = strings.ReplaceAll(path, `]]`, `\]]`)
+		return "[[" + wikiLinkTarget(context, config) + "]]", nil
+	}, nil
+}
+
+// NewWikiLabelLinkFormatter generates wiki links labelled with the title of
+// the target note, e.g. [[path|Title]].
+func NewWikiLabelLinkFormatter(config MarkdownConfig) (LinkFormatter, error) {
+	return func(context LinkFormatterContext) (string, error) {
+		target := wikiLinkTarget(context, config)
+		label := context.Title
+		if label == "" || label == target {
+			return "[[" + target + "]]", nil
 		}
-		return "[[" + path + "]]", nil
+		return "[[" + target + "|" + escapeWikiLink(label) + "]]", nil
 	}, nil
 }
 
+// wikiLinkTarget returns the escaped target of a wiki link: the path of the
+// note, or its title when the link-target setting is "title".
+func wikiLinkTarget(context LinkFormatterContext, config MarkdownConfig) string {
+	if config.LinkTarget == "title" && context.Title != "" {
+		return strings.ReplaceAll(escapeWikiLink(context.Title), `|`, `\|`)
+	}
+	path := formatPath(context.Path, config)
+	if !config.LinkEncodePath {
+		path = escapeWikiLink(path)
+	}
+	return path
+}
+
+func escapeWikiLink(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	return strings.ReplaceAll(text, `]]`, `\]]`)
+}
+
 func NewCustomLinkFormatter(config MarkdownConfig, templateLoader TemplateLoader) (LinkFormatter, error) {
 	wrap := errors.Wrapperf("failed to render custom link with format: %s", config.LinkFormat)
 	template, err := templateLoader.LoadTemplate(config.LinkFormat)
@@ -116,6 +116,61 @@ func TestWikiLinkFormatter(t *testing.T) {
 	test("path/to note.md", "title", "[[path/to%20note]]")
 }
 
+func TestWikiLinkFormatterWithTitleTarget(t *testing.T) {
+	formatter, err := NewLinkFormatter(MarkdownConfig{
+		LinkFormat:        "wiki",
+		LinkTarget:        "title",
+		LinkEncodePath:    true,
+		LinkDropExtension: true,
+	}, &NullTemplateLoader)
+	assert.Nil(t, err)
+
+	test := func(path, title, expected string) {
+		actual, err := formatter(LinkFormatterContext{
+			Path:  path,
+			Title: title,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	test("path/to note.md", "An interesting subject", "[[An interesting subject]]")
+	test("path/to note.md", `A [[subject]] with a | and a \`, `[[A [[subject\]] with a \| and a \\]]`)
+	// Fallback on the path when there's no title.
+	test("path/to note.md", "", "[[path/to%20note]]")
+}
+
+func TestWikiLabelLinkFormatter(t *testing.T) {
+	newTester := func(target string, encodePath bool) func(path, title, expected string) {
+		formatter, err := NewLinkFormatter(MarkdownConfig{
+			LinkFormat:        "wiki-label",
+			LinkTarget:        target,
+			LinkEncodePath:    encodePath,
+			LinkDropExtension: true,
+		}, &NullTemplateLoader)
+		assert.Nil(t, err)
+
+		return func(path, title, expected string) {
+			actual, err := formatter(LinkFormatterContext{
+				Path:  path,
+				Title: title,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, actual, expected)
+		}
+	}
+
+	test := newTester("path", false)
+	test("path/to note.md", "An interesting subject", "[[path/to note|An interesting subject]]")
+	test("path/to note.md", "A [[subject]]", `[[path/to note|A [[subject\]]]]`)
+	test("path/to note.md", "", "[[path/to note]]")
+	test = newTester("path", true)
+	test("path/to note.md", "An interesting subject", "[[path/to%20note|An interesting subject]]")
+	// The label is omitted when it is the same as the target.
+	test = newTester("title", false)
+	test("path/to note.md", "An interesting subject", "[[An interesting subject]]")
+}
+
 func TestCustomLinkFormatter(t *testing.T) {
 	newTester := func(encodePath, dropExtension bool) func(path, title string, expected LinkFormatterContext) {
 		return func(path, title string, expected LinkFormatterContext) {
@@ -2,6 +2,7 @@ package core
 
 import (
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/zk-org/zk/internal/util/paths"
@@ -39,6 +40,8 @@ type Note struct {
 	Lead string
 	// Content of the note, after any frontmatter and title heading.
 	Body string
+	// Body rendered as plain text, without the Markdown syntax.
+	Plain string
 	// Whole raw content of the note.
 	RawContent string
 	// Number of words found in the content.
@@ -47,6 +50,13 @@ type Note struct {
 	Links []Link
 	// List of tags found in the content.
 	Tags []string
+	// Byte offset of the first occurrence of each tag in the raw content.
+	// Tags only declared in the frontmatter are missing.
+	TagStarts map[string]int
+	// List of headings found in the content, in order.
+	Headings []Heading
+	// Sorted domain names of the external links found in the content.
+	Domains []string
 	// JSON dictionary of raw metadata extracted from the frontmatter.
 	Metadata map[string]interface{}
 	// Date of creation.
@@ -77,6 +87,19 @@ func (n Note) FilenameStem() string {
 	return paths.FilenameStem(n.Path)
 }
 
+// MaxTagDepth returns the depth of the most nested hierarchical tag of the
+// note, which is its number of `/`-separated segments. Returns 0 when the
+// note has no tags.
+func (n Note) MaxTagDepth() int {
+	depth := 0
+	for _, tag := range n.Tags {
+		if d := len(strings.Split(strings.Trim(tag, "/"), "/")); d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
 // ContextualNote holds a Note and context-sensitive content snippets.
 //
 // This is used for example:
@@ -86,4 +109,6 @@ type ContextualNote struct {
 	Note
 	// List of context-sensitive excerpts from the note.
 	Snippets []string
+	// Positions of the runes of the title matched by a fuzzy query.
+	MatchPositions []int
 }
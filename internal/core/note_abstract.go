@@ -0,0 +1,71 @@
+package core
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+)
+
+// noteAbstract returns the first prose paragraph of the given note body.
+//
+// Unlike the lead, which is the first block of the body whatever it is, the
+// headings, lists, quotes, tables, code blocks, HTML blocks and the paragraphs
+// made only of metadata lines (e.g. `Date: 2021-01-02`) or tags are skipped.
+func noteAbstract(body string) string {
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	paragraph := []string{}
+	fence := ""
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if len(paragraph) == 0 {
+			if f := codeFenceRegex.FindString(trimmed); f != "" {
+				fence = f
+				continue
+			}
+		}
+
+		if trimmed == "" {
+			if isAbstractParagraph(paragraph) {
+				break
+			}
+			paragraph = []string{}
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+
+	if !isAbstractParagraph(paragraph) {
+		return ""
+	}
+	return strings.TrimSpace(strings.Join(paragraph, "\n"))
+}
+
+var (
+	codeFenceRegex     = regexp.MustCompile("^(```+|~~~+)")
+	nonProseBlockRegex = regexp.MustCompile(`^(    |\t|\s*(#|>|\||<|[-*+] |\d+[.)] |---|\*\*\*))`)
+	metadataLineRegex  = regexp.MustCompile(`^[\pL\pN_-]+( [\pL\pN_-]+)?::? `)
+	tagsLineRegex      = regexp.MustCompile(`^((#[^\s#]+|:([^\s:]+:)+)\s*)+$`)
+)
+
+// isAbstractParagraph returns whether the given lines are a prose paragraph.
+func isAbstractParagraph(lines []string) bool {
+	if len(lines) == 0 || nonProseBlockRegex.MatchString(lines[0]) {
+		return false
+	}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if !metadataLineRegex.MatchString(line) && !tagsLineRegex.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
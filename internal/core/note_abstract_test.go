@@ -0,0 +1,36 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestNoteAbstract(t *testing.T) {
+	test := func(body string, expected string) {
+		assert.Equal(t, noteAbstract(body), expected)
+	}
+
+	test("", "")
+	test("A single paragraph.", "A single paragraph.")
+	test("First line\nsecond line.\n\nAnother paragraph.", "First line\nsecond line.")
+	test("\n\n  Indented paragraph.  \n", "Indented paragraph.")
+
+	// Metadata lines and tags are skipped.
+	test("Date: 2021-01-02\nAuthors: Jane Doe\n\nThe abstract.", "The abstract.")
+	test("Source:: https://example.com\n\nThe abstract.", "The abstract.")
+	test("#paper #biology\n\nThe abstract.", "The abstract.")
+	test(":paper:biology:\n\nThe abstract.", "The abstract.")
+	// ...unless they are mixed with prose.
+	test("Note: this paragraph is prose.\nIt has several lines.", "Note: this paragraph is prose.\nIt has several lines.")
+
+	// Other blocks are skipped.
+	test("## Heading\n\nThe abstract.", "The abstract.")
+	test("- item 1\n- item 2\n\n1. item\n\nThe abstract.", "The abstract.")
+	test("> Quote\n\n| a | b |\n|---|---|\n\n<div>\n\n---\n\nThe abstract.", "The abstract.")
+	test("    indented code\n\nThe abstract.", "The abstract.")
+	test("```go\nfunc main() {\n\n}\n```\n\nThe abstract.", "The abstract.")
+	test("~~~\ncode\n\n~~~\nThe abstract.", "The abstract.")
+
+	test("## Heading\n\n- list", "")
+}
@@ -0,0 +1,222 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// backlinksSection is the section of a note listing its backlinks.
+var backlinksSection = MarkedSection{
+	StartMarker: "<!-- backlinks -->",
+	EndMarker:   "<!-- /backlinks -->",
+}
+
+// BacklinksOpts holds the options used to update the backlinks section of
+// notes.
+type BacklinksOpts struct {
+	// Heading printed at the top of the backlinks section.
+	Heading string
+	// Don't save the updated notes on the file system.
+	DryRun bool
+}
+
+// BacklinksUpdate is the outcome of updating the backlinks section of a note.
+type BacklinksUpdate struct {
+	// Path to the note, relative to the notebook.
+	Path string
+	// Content of the note before and after the update.
+	OldContent string
+	NewContent string
+}
+
+// Changed returns whether the content of the note was updated.
+func (u BacklinksUpdate) Changed() bool {
+	return u.OldContent != u.NewContent
+}
+
+// UpdateBacklinks inserts or updates, in each of the given notes, a section
+// delimited by `<!-- backlinks -->` markers listing the indexed notes linking
+// to it. Updated notes are reindexed.
+//
+// The links found in the backlinks sections are ignored, so that running it
+// again doesn't change the notes. A note without any backlink has its
+// section removed.
+func (n *Notebook) UpdateBacklinks(notes []ContextualNote, opts BacklinksOpts) ([]BacklinksUpdate, error) {
+	wrap := errors.Wrapper("failed to update the backlinks")
+
+	allNotes, err := n.FindNotes(NoteFindOpts{})
+	if err != nil {
+		return nil, wrap(err)
+	}
+	notesByID := map[NoteID]Note{}
+	ids := make([]NoteID, 0)
+	for _, note := range allNotes {
+		notesByID[note.ID] = note.Note
+		ids = append(ids, note.ID)
+	}
+
+	links, err := n.FindLinksBetweenNotes(ids)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	sources := map[NoteID][]Note{}
+	seen := map[[2]NoteID]bool{}
+	for _, link := range links {
+		source, ok := notesByID[link.SourceID]
+		if !ok || link.SourceID == link.TargetID || seen[[2]NoteID{link.SourceID, link.TargetID}] {
+			continue
+		}
+		if start, end, ok := backlinksSection.Find(source.RawContent); ok && link.SnippetStart >= start && link.SnippetStart < end {
+			continue
+		}
+		seen[[2]NoteID{link.SourceID, link.TargetID}] = true
+		sources[link.TargetID] = append(sources[link.TargetID], source)
+	}
+
+	formatter, err := n.NewLinkFormatter()
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	updates := make([]BacklinksUpdate, 0)
+	for _, note := range notes {
+		wrap := errors.Wrapperf("%s: failed to update the backlinks", note.Path)
+		absPath := filepath.Join(n.Path, note.Path)
+
+		items := make([]string, 0)
+		for _, source := range sortBacklinks(sources[note.ID]) {
+			link, err := n.FormatNoteLink(formatter, source.AsMinimalNote(), filepath.Dir(absPath))
+			if err != nil {
+				return nil, wrap(err)
+			}
+			items = append(items, link)
+		}
+
+		update := BacklinksUpdate{
+			Path:       note.Path,
+			OldContent: note.RawContent,
+		}
+		if len(items) > 0 {
+			update.NewContent = backlinksSection.Replace(note.RawContent, backlinksText(opts.Heading, items))
+		} else {
+			update.NewContent = backlinksSection.Remove(note.RawContent)
+		}
+		updates = append(updates, update)
+		if !update.Changed() || opts.DryRun {
+			continue
+		}
+
+		err = n.fs.Write(absPath, []byte(update.NewContent))
+		if err != nil {
+			return nil, wrap(err)
+		}
+		parsed, err := n.ParseNoteWithContent(absPath, []byte(update.NewContent))
+		if err != nil {
+			return nil, wrap(err)
+		}
+		err = n.index.Update(*parsed)
+		if err != nil {
+			return nil, wrap(err)
+		}
+	}
+
+	return updates, nil
+}
+
+// sortBacklinks orders the notes by title, then by path.
+func sortBacklinks(notes []Note) []Note {
+	sort.SliceStable(notes, func(i, j int) bool {
+		ti, tj := strings.ToLower(notes[i].Title), strings.ToLower(notes[j].Title)
+		if ti != tj {
+			return ti < tj
+		}
+		return notes[i].Path < notes[j].Path
+	})
+	return notes
+}
+
+// backlinksText generates the content of the backlinks section listing the
+// given links.
+func backlinksText(heading string, links []string) string {
+	var out strings.Builder
+	if heading = strings.TrimSpace(heading); heading != "" {
+		out.WriteString(heading + "\n\n")
+	}
+	for _, link := range links {
+		out.WriteString("- " + link + "\n")
+	}
+	return out.String()
+}
+
+// UnifiedDiff prints the changes made to the content of the note, in the
+// unified diff format.
+func (u BacklinksUpdate) UnifiedDiff() string {
+	if !u.Changed() {
+		return ""
+	}
+
+	oldLines := splitDiffLines(u.OldContent)
+	newLines := splitDiffLines(u.NewContent)
+
+	// The backlinks section is the only changed region of the note, so the
+	// diff is made of a single hunk between the common prefix and suffix.
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix && oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	const context = 3
+	start := prefix - context
+	if start < 0 {
+		start = 0
+	}
+	oldEnd := len(oldLines) - suffix + context
+	if oldEnd > len(oldLines) {
+		oldEnd = len(oldLines)
+	}
+	newEnd := len(newLines) - suffix + context
+	if newEnd > len(newLines) {
+		newEnd = len(newLines)
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", u.Path, u.Path)
+	fmt.Fprintf(&out, "@@ -%s +%s @@\n", diffRange(start, oldEnd-start), diffRange(start, newEnd-start))
+	for _, line := range oldLines[start:prefix] {
+		out.WriteString(" " + line + "\n")
+	}
+	for _, line := range oldLines[prefix : len(oldLines)-suffix] {
+		out.WriteString("-" + line + "\n")
+	}
+	for _, line := range newLines[prefix : len(newLines)-suffix] {
+		out.WriteString("+" + line + "\n")
+	}
+	for _, line := range oldLines[len(oldLines)-suffix : oldEnd] {
+		out.WriteString(" " + line + "\n")
+	}
+	return out.String()
+}
+
+func splitDiffLines(content string) []string {
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return []string{}
+	}
+	return strings.Split(content, "\n")
+}
+
+// diffRange prints a range of lines in a unified diff hunk header.
+func diffRange(start int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
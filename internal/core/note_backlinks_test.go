@@ -0,0 +1,70 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestBacklinksText(t *testing.T) {
+	assert.Equal(t, backlinksText("## Backlinks", []string{"[[a]]", "[[b]]"}), "## Backlinks\n\n- [[a]]\n- [[b]]\n")
+	// Without heading
+	assert.Equal(t, backlinksText(" ", []string{"[[a]]"}), "- [[a]]\n")
+}
+
+func TestBacklinksUpdateUnifiedDiff(t *testing.T) {
+	test := func(oldContent string, newContent string, expected string) {
+		update := BacklinksUpdate{
+			Path:       "dir/note.md",
+			OldContent: oldContent,
+			NewContent: newContent,
+		}
+		assert.Equal(t, update.UnifiedDiff(), expected)
+	}
+
+	// Unchanged
+	test("# Note\n", "# Note\n", "")
+
+	// Appended lines
+	test(
+		"# Note\n\nLine 1\nLine 2\nLine 3\nLine 4\n",
+		"# Note\n\nLine 1\nLine 2\nLine 3\nLine 4\n\n- [[a]]\n",
+		`--- a/dir/note.md
++++ b/dir/note.md
+@@ -4,3 +4,5 @@
+ Line 2
+ Line 3
+ Line 4
++
++- [[a]]
+`,
+	)
+
+	// Changed lines in the middle of the note
+	test(
+		"# Note\n\n- [[old]]\n\nLine 1\nLine 2\nLine 3\nLine 4\n",
+		"# Note\n\n- [[a]]\n- [[b]]\n\nLine 1\nLine 2\nLine 3\nLine 4\n",
+		"--- a/dir/note.md\n"+
+			"+++ b/dir/note.md\n"+
+			"@@ -1,6 +1,7 @@\n"+
+			" # Note\n"+
+			" \n"+
+			"-- [[old]]\n"+
+			"+- [[a]]\n"+
+			"+- [[b]]\n"+
+			" \n"+
+			" Line 1\n"+
+			" Line 2\n",
+	)
+
+	// Empty note
+	test(
+		"",
+		"- [[a]]\n",
+		`--- a/dir/note.md
++++ b/dir/note.md
+@@ -0,0 +1,1 @@
++- [[a]]
+`,
+	)
+}
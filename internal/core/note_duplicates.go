@@ -0,0 +1,147 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// duplicateShingleSize is the number of consecutive words in a shingle, used
+// to compare the content of near-duplicate notes.
+const duplicateShingleSize = 3
+
+// FindDuplicateNotes groups the notes having identical or similar bodies.
+//
+// The bodies are compared without their frontmatter, title and whitespace
+// differences. With a threshold of 1, only identical bodies are grouped.
+// Below 1, two notes are grouped when the Jaccard similarity of the word
+// shingles of their bodies reaches the threshold. Notes with an empty body
+// are ignored.
+//
+// The notes of each cluster are sorted by path, and the clusters by the path
+// of their first note.
+func FindDuplicateNotes(notes []ContextualNote, threshold float64) [][]ContextualNote {
+	contents := make([]string, len(notes))
+	for i, note := range notes {
+		contents[i] = normalizeDuplicateContent(note.Body)
+	}
+
+	clusters := newUnionFind(len(notes))
+	if threshold >= 1 {
+		hashes := map[string]int{}
+		for i, content := range contents {
+			if content == "" {
+				continue
+			}
+			hash := contentHash(content)
+			if j, ok := hashes[hash]; ok {
+				clusters.union(i, j)
+			} else {
+				hashes[hash] = i
+			}
+		}
+	} else {
+		shingles := make([]map[string]bool, len(notes))
+		for i, content := range contents {
+			shingles[i] = contentShingles(content)
+		}
+		for i := range notes {
+			if contents[i] == "" {
+				continue
+			}
+			for j := i + 1; j < len(notes); j++ {
+				if contents[j] != "" && jaccardSimilarity(shingles[i], shingles[j]) >= threshold {
+					clusters.union(i, j)
+				}
+			}
+		}
+	}
+
+	groups := map[int][]ContextualNote{}
+	for i, note := range notes {
+		root := clusters.find(i)
+		groups[root] = append(groups[root], note)
+	}
+
+	res := make([][]ContextualNote, 0)
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Path < group[j].Path
+		})
+		res = append(res, group)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i][0].Path < res[j][0].Path
+	})
+	return res
+}
+
+// normalizeDuplicateContent lowercases the content and collapses its
+// whitespaces, to ignore insignificant differences between notes.
+func normalizeDuplicateContent(content string) string {
+	return strings.Join(strings.Fields(strings.ToLower(content)), " ")
+}
+
+func contentHash(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+// contentShingles returns the set of sequences of consecutive words in the
+// normalized content.
+func contentShingles(content string) map[string]bool {
+	shingles := map[string]bool{}
+	words := strings.Fields(content)
+	if len(words) < duplicateShingleSize {
+		if len(words) > 0 {
+			shingles[content] = true
+		}
+		return shingles
+	}
+	for i := 0; i+duplicateShingleSize <= len(words); i++ {
+		shingles[strings.Join(words[i:i+duplicateShingleSize], " ")] = true
+	}
+	return shingles
+}
+
+// jaccardSimilarity returns the ratio of the shingles shared by both sets,
+// from 0 to 1.
+func jaccardSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for shingle := range a {
+		if b[shingle] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// unionFind tracks the clusters of a set of items identified by their index.
+type unionFind []int
+
+func newUnionFind(size int) unionFind {
+	parents := make(unionFind, size)
+	for i := range parents {
+		parents[i] = i
+	}
+	return parents
+}
+
+func (u unionFind) find(i int) int {
+	for u[i] != i {
+		u[i] = u[u[i]]
+		i = u[i]
+	}
+	return i
+}
+
+func (u unionFind) union(i, j int) {
+	u[u.find(i)] = u.find(j)
+}
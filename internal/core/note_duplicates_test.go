@@ -0,0 +1,67 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestFindDuplicateNotes(t *testing.T) {
+	note := func(path string, body string) ContextualNote {
+		return ContextualNote{Note: Note{Path: path, Body: body}}
+	}
+	paths := func(clusters [][]ContextualNote) [][]string {
+		res := [][]string{}
+		for _, cluster := range clusters {
+			p := []string{}
+			for _, note := range cluster {
+				p = append(p, note.Path)
+			}
+			res = append(res, p)
+		}
+		return res
+	}
+
+	notes := []ContextualNote{
+		note("e.md", "The quick brown fox jumps over the lazy dog."),
+		note("a.md", "The quick  brown fox\njumps over the LAZY dog."),
+		note("b.md", "The quick brown fox jumps over the lazy cat."),
+		note("c.md", "Something completely different."),
+		note("d.md", "Something completely different."),
+		note("empty1.md", ""),
+		note("empty2.md", " \n"),
+	}
+
+	// Identical bodies, ignoring whitespace and case differences.
+	assert.Equal(t, paths(FindDuplicateNotes(notes, 1)), [][]string{
+		{"a.md", "e.md"},
+		{"c.md", "d.md"},
+	})
+
+	// Similar bodies: a.md and b.md share 6 of their 8 distinct shingles.
+	assert.Equal(t, paths(FindDuplicateNotes(notes, 0.7)), [][]string{
+		{"a.md", "b.md", "e.md"},
+		{"c.md", "d.md"},
+	})
+	assert.Equal(t, paths(FindDuplicateNotes(notes, 0.8)), [][]string{
+		{"a.md", "e.md"},
+		{"c.md", "d.md"},
+	})
+
+	assert.Equal(t, paths(FindDuplicateNotes([]ContextualNote{}, 1)), [][]string{})
+}
+
+func TestJaccardSimilarity(t *testing.T) {
+	test := func(a, b string, expected float64) {
+		actual := jaccardSimilarity(contentShingles(a), contentShingles(b))
+		assert.Equal(t, actual, expected)
+	}
+
+	test("", "", 0)
+	test("a b c", "", 0)
+	test("a b c", "a b c", 1)
+	test("a b c d", "a b c e", 1.0/3)
+	// Short contents are compared as a whole.
+	test("a b", "a b", 1)
+	test("a b", "a c", 0)
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// EncodingFromString returns the text encoding with the given IANA name or
+// alias, e.g. latin1 or windows-1252.
+func EncodingFromString(name string) (encoding.Encoding, error) {
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil || enc == nil {
+		return nil, fmt.Errorf("%s: unknown encoding - may be any IANA character set name, e.g. utf-8, latin1 or windows-1252", name)
+	}
+	return enc, nil
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// decodeNoteContent converts the content of a note file to UTF-8.
+//
+// A byte order mark selects the UTF-8 or UTF-16 encoding. Without one, the
+// content is decoded with the fallback encoding if it is not valid UTF-8. The
+// characters which can't be converted are replaced with U+FFFD, which is
+// reported with lossy.
+func decodeNoteContent(content []byte, fallback encoding.Encoding) (text string, lossy bool, err error) {
+	switch {
+	case bytes.HasPrefix(content, bomUTF8):
+		content = content[len(bomUTF8):]
+	case bytes.HasPrefix(content, bomUTF16LE), bytes.HasPrefix(content, bomUTF16BE):
+		return decodeWith(content, unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM))
+	}
+
+	if utf8.Valid(content) {
+		return string(content), false, nil
+	}
+	if fallback == nil {
+		return strings.ToValidUTF8(string(content), string(utf8.RuneError)), true, nil
+	}
+	return decodeWith(content, fallback)
+}
+
+func decodeWith(content []byte, enc encoding.Encoding) (string, bool, error) {
+	decoded, err := enc.NewDecoder().Bytes(content)
+	if err != nil {
+		return "", false, err
+	}
+	return string(decoded), bytes.ContainsRune(decoded, utf8.RuneError), nil
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+	"golang.org/x/text/encoding"
+)
+
+func TestDecodeNoteContent(t *testing.T) {
+	latin1, err := EncodingFromString("latin1")
+	assert.Nil(t, err)
+	windows1252, err := EncodingFromString("windows-1252")
+	assert.Nil(t, err)
+
+	test := func(content []byte, fallback encoding.Encoding, expected string, expectedLossy bool) {
+		t.Helper()
+		text, lossy, err := decodeNoteContent(content, fallback)
+		assert.Nil(t, err)
+		assert.Equal(t, text, expected)
+		assert.Equal(t, lossy, expectedLossy)
+	}
+
+	// Valid UTF-8 is kept as is, whatever the fallback.
+	test([]byte("Café"), nil, "Café", false)
+	test([]byte("Café"), latin1, "Café", false)
+	// The UTF-8 byte order mark is removed.
+	test([]byte("\xEF\xBB\xBFCafé"), latin1, "Café", false)
+	// UTF-16 is detected from its byte order mark.
+	test([]byte("\xFF\xFEC\x00a\x00f\x00\xE9\x00"), nil, "Café", false)
+	test([]byte("\xFE\xFF\x00C\x00a\x00f\x00\xE9"), nil, "Café", false)
+	// Invalid UTF-8 is decoded with the fallback encoding.
+	test([]byte("Caf\xE9"), latin1, "Café", false)
+	test([]byte("\x93Caf\xE9\x94"), windows1252, "“Café”", false)
+	// Without fallback, the invalid characters are replaced.
+	test([]byte("Caf\xE9"), nil, "Caf�", true)
+	// Bytes undefined in the fallback encoding are replaced.
+	test([]byte("Caf\xE9\x81"), windows1252, "Café�", true)
+}
+
+func TestEncodingFromString(t *testing.T) {
+	_, err := EncodingFromString("ISO-8859-1")
+	assert.Nil(t, err)
+	_, err = EncodingFromString("klingon")
+	assert.Err(t, err, "klingon: unknown encoding - may be any IANA character set name, e.g. utf-8, latin1 or windows-1252")
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// NoteExportOpts holds the options used to export notes into a single
+// document.
+type NoteExportOpts struct {
+	// Prepends a table of contents linking to each exported note.
+	TOC bool
+}
+
+// ExportNotes concatenates the given notes into a single Markdown document,
+// in the given order.
+//
+// Each note is introduced by its title heading and an anchor. Links between
+// the exported notes are rewritten to point to these in-document anchors,
+// while other links are left untouched.
+func (n *Notebook) ExportNotes(notes []ContextualNote, opts NoteExportOpts) (string, error) {
+	ids := make([]NoteID, 0)
+	for _, note := range notes {
+		ids = append(ids, note.ID)
+	}
+	links, err := n.FindLinksBetweenNotes(ids)
+	if err != nil {
+		return "", err
+	}
+
+	return exportNotes(notes, links, opts), nil
+}
+
+func exportNotes(notes []ContextualNote, links []ResolvedLink, opts NoteExportOpts) string {
+	anchors := map[NoteID]string{}
+	usedAnchors := HeadingAnchors{}
+	for _, note := range notes {
+		anchors[note.ID] = usedAnchors.unique(exportAnchor(exportTitle(note.Note)))
+	}
+
+	// Targets of the links between exported notes, indexed by source note
+	// and href.
+	targets := map[NoteID]map[string]NoteID{}
+	for _, link := range links {
+		if _, ok := targets[link.SourceID]; !ok {
+			targets[link.SourceID] = map[string]NoteID{}
+		}
+		targets[link.SourceID][link.Href] = link.TargetID
+	}
+
+	var out strings.Builder
+
+	if opts.TOC {
+		out.WriteString("# Table of contents\n\n")
+		for _, note := range notes {
+			fmt.Fprintf(&out, "- [%s](#%s)\n", exportTitle(note.Note), anchors[note.ID])
+		}
+	}
+
+	for i, note := range notes {
+		if i > 0 || opts.TOC {
+			out.WriteString("\n")
+		}
+		body := rewriteExportLinks(note.Note, targets[note.ID], anchors)
+		fmt.Fprintf(&out, "<a id=\"%s\"></a>\n\n# %s\n", anchors[note.ID], exportTitle(note.Note))
+		if body != "" {
+			out.WriteString("\n" + body + "\n")
+		}
+	}
+
+	return out.String()
+}
+
+var (
+	markdownLinkRegex   = regexp.MustCompile(`(!?)\[((?:\\.|[^\]\\])*)\]\(\s*<?([^()<>\s]+)>?((?:\s+[^)]*)?)\)`)
+	exportWikiLinkRegex = regexp.MustCompile(`\[\[([^\]|]+?)(?:\|([^\]]*))?\]\]`)
+)
+
+// rewriteExportLinks replaces the links of the note pointing to the given
+// exported targets with links to their anchors.
+func rewriteExportLinks(note Note, targets map[string]NoteID, anchors map[NoteID]string) string {
+	return rewriteNoteLinks(note, func(href string) (string, bool) {
+		id, ok := targets[href]
+		if !ok {
+			return "", false
+		}
+		anchor, ok := anchors[id]
+		return "#" + anchor, ok
+	})
+}
+
+// rewriteNoteLinks replaces the internal Markdown links and wiki links of the
+// note body with Markdown links to the destination returned by resolve for
+// their href, relative to the notebook root. The links which can't be
+// resolved are left untouched.
+func rewriteNoteLinks(note Note, resolve func(href string) (string, bool)) string {
+	body := markdownLinkRegex.ReplaceAllStringFunc(note.Body, func(match string) string {
+		groups := markdownLinkRegex.FindStringSubmatch(match)
+		if groups[1] == "!" {
+			return match
+		}
+		href, err := url.PathUnescape(groups[3])
+		if err != nil || href == "" || strutil.IsURL(href) {
+			return match
+		}
+		// The markdown links are indexed relative to the notebook root.
+		href = filepath.Join(filepath.Dir(note.Path), href)
+		dest, ok := resolve(href)
+		if !ok {
+			return match
+		}
+		return fmt.Sprintf("[%s](%s)", groups[2], dest)
+	})
+
+	return exportWikiLinkRegex.ReplaceAllStringFunc(body, func(match string) string {
+		groups := exportWikiLinkRegex.FindStringSubmatch(match)
+		href := strings.TrimSpace(groups[1])
+		dest, ok := resolve(href)
+		if !ok {
+			return match
+		}
+		label := strings.TrimSpace(groups[2])
+		if label == "" {
+			label = href
+		}
+		return fmt.Sprintf("[%s](%s)", label, dest)
+	})
+}
+
+// exportTitle returns the title of the note heading in the exported
+// document, falling back on its filename.
+func exportTitle(note Note) string {
+	if note.Title != "" {
+		return note.Title
+	}
+	return note.FilenameStem()
+}
+
+// exportAnchor generates the anchor of a note title, like the headings
+// anchors.
+func exportAnchor(title string) string {
+	anchor := Anchor(title)
+	if anchor == "" {
+		anchor = "note"
+	}
+	return anchor
+}
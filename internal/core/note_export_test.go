@@ -0,0 +1,77 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestExportNotes(t *testing.T) {
+	notes := []ContextualNote{
+		{Note: Note{ID: 1, Path: "index.md", Title: "Index", Body: "See [the first note](dir/first) and [[Second]], [[dir/first | with a label]].\n\nAn [external link](https://example.com) and ![an image](dir/first)."}},
+		{Note: Note{ID: 2, Path: "dir/first.md", Title: "A note", Body: "Back to [index](../index.md).\n\nNot exported: [other](other)."}},
+		{Note: Note{ID: 3, Path: "second.md", Title: "A note!", Body: ""}},
+		{Note: Note{ID: 4, Path: "untitled.md", Title: "", Body: "Body"}},
+	}
+	links := []ResolvedLink{
+		{SourceID: 1, TargetID: 2, Link: Link{Href: "dir/first"}},
+		{SourceID: 1, TargetID: 3, Link: Link{Href: "Second"}},
+		{SourceID: 1, TargetID: 2, Link: Link{Href: "dir/first"}},
+		{SourceID: 2, TargetID: 1, Link: Link{Href: "index.md"}},
+	}
+
+	assert.Equal(t, exportNotes(notes, links, NoteExportOpts{}), `<a id="index"></a>
+
+# Index
+
+See [the first note](#a-note) and [Second](#a-note-1), [with a label](#a-note).
+
+An [external link](https://example.com) and ![an image](dir/first).
+
+<a id="a-note"></a>
+
+# A note
+
+Back to [index](#index).
+
+Not exported: [other](other).
+
+<a id="a-note-1"></a>
+
+# A note!
+
+<a id="untitled"></a>
+
+# untitled
+
+Body
+`)
+
+	assert.Equal(t, exportNotes(notes[2:], []ResolvedLink{}, NoteExportOpts{TOC: true}), `# Table of contents
+
+- [A note!](#a-note)
+- [untitled](#untitled)
+
+<a id="a-note"></a>
+
+# A note!
+
+<a id="untitled"></a>
+
+# untitled
+
+Body
+`)
+}
+
+func TestExportAnchor(t *testing.T) {
+	test := func(title string, expected string) {
+		assert.Equal(t, exportAnchor(title), expected)
+	}
+
+	test("Title", "title")
+	test("A title with spaces", "a-title-with-spaces")
+	test("When to prefer PUT over POST?", "when-to-prefer-put-over-post")
+	test("Été 2021", "été-2021")
+	test("???", "note")
+}
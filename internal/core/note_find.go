@@ -13,10 +13,19 @@ type NoteFindOpts struct {
 	Match []string
 	// Text matching strategy used with Match.
 	MatchStrategy MatchStrategy
+	// Filter excluding the notes whose body matches any of the given
+	// full-text queries.
+	NotMatch []string
 	// Filter by note hrefs.
 	IncludeHrefs []string
 	// Filter excluding notes at the given hrefs.
 	ExcludeHrefs []string
+	// Filter including only the notes whose path, relative to the notebook
+	// root, matches any of the given doublestar globs.
+	IncludeGlobs []string
+	// Filter excluding the notes whose path, relative to the notebook root,
+	// matches any of the given doublestar globs.
+	ExcludeGlobs []string
 	// Indicates whether href options can match any portion of a path.
 	// This is used for wiki links.
 	AllowPartialHrefs bool
@@ -26,6 +35,11 @@ type NoteFindOpts struct {
 	ExcludeIDs []NoteID
 	// Filter by tags found in the notes.
 	Tags []string
+	// Indicates whether the Tags filters match the nested tags as well, e.g.
+	// `project/alpha` for `project`.
+	RecursiveTags bool
+	// Filter by tag namespaces, matching a tag and any of its nested tags.
+	TagPrefixes []string
 	// Filter the notes mentioning the given ones.
 	Mention []string
 	// Filter the notes mentioned by the given ones.
@@ -36,18 +50,38 @@ type NoteFindOpts struct {
 	LinkTo *LinkFilter
 	// Filter to select notes which could might be related to the given notes hrefs.
 	Related []string
+	// Filter to select notes linking to a URL of any of the given domains or
+	// their subdomains.
+	LinkDomains []string
 	// Filter to select notes having no other notes linking to them.
 	Orphan bool
+	// Filter to select notes having links which could resolve to several notes.
+	AmbiguousLinks bool
+	// Filter to select notes whose filename doesn't contain the ID recorded
+	// in their frontmatter.
+	IDMismatch bool
 	// Filter notes created after the given date.
 	CreatedStart *time.Time
 	// Filter notes created before the given date.
 	CreatedEnd *time.Time
+	// Filter notes whose most nested tag has at least the given depth.
+	MinTagDepth int
+	// Filter notes whose most nested tag has at most the given depth.
+	MaxTagDepth int
+	// Filter notes containing any of the given TODO markers in their body,
+	// outside of the code blocks.
+	TodoMarkers []string
+	// Filter notes containing the given terms a minimum number of times in
+	// their body, outside of the code blocks.
+	MinOccurrences []OccurrencesFilter
 	// Filter notes modified after the given date.
 	ModifiedStart *time.Time
 	// Filter notes modified before the given date.
 	ModifiedEnd *time.Time
 	// Limits the number of results
 	Limit int
+	// Skips the given number of results, after sorting them.
+	Offset int
 	// Sorting criteria
 	Sorters []NoteSorter
 }
@@ -86,6 +120,9 @@ type LinkFilter struct {
 type NoteSorter struct {
 	Field     NoteSortField
 	Ascending bool
+	// Seed used to shuffle the notes reproducibly with NoteSortRandom.
+	// When nil, the order changes with each query.
+	Seed *int64
 }
 
 // NoteSortField represents a note field used to sort a list of notes.
@@ -125,12 +162,16 @@ func NoteSortersFromStrings(strs []string) ([]NoteSorter, error) {
 
 // NoteSorterFromString returns a NoteSorter from its string representation.
 //
-// If the input str has for suffix `+`, then the order will be ascending, while
-// descending for `-`. If no suffix is given, then the default order for the
-// sorting field will be used.
+// The order is given either with a `:asc` or `:desc` suffix, or with the
+// shorter `+` (ascending) and `-` (descending) suffixes. If no suffix is
+// given, then the default order for the sorting field will be used.
 func NoteSorterFromString(str string) (NoteSorter, error) {
+	original := str
+	str, direction, hasDirection := strings.Cut(str, ":")
 	orderSymbol, _ := utf8.DecodeLastRuneInString(str)
-	str = strings.TrimRight(str, "+-")
+	if !hasDirection {
+		str = strings.TrimRight(str, "+-")
+	}
 
 	var sorter NoteSorter
 	switch str {
@@ -150,10 +191,16 @@ func NoteSorterFromString(str string) (NoteSorter, error) {
 		return sorter, fmt.Errorf("%s: unknown sorting term\ntry created, modified, path, title, random or word-count", str)
 	}
 
-	switch orderSymbol {
-	case '+':
+	switch {
+	case hasDirection && direction == "asc":
+		sorter.Ascending = true
+	case hasDirection && direction == "desc":
+		sorter.Ascending = false
+	case hasDirection:
+		return sorter, fmt.Errorf("%s: unknown sorting direction\ntry asc or desc", original)
+	case orderSymbol == '+':
 		sorter.Ascending = true
-	case '-':
+	case orderSymbol == '-':
 		sorter.Ascending = false
 	}
 
@@ -170,6 +217,12 @@ const (
 	MatchStrategyExact
 	// Regular expression.
 	MatchStrategyRe
+	// Full text search of the exact phrase.
+	MatchStrategyPhrase
+	// Full text search of the words starting with the given prefixes.
+	MatchStrategyPrefix
+	// Characters of the query found in order in the title.
+	MatchStrategyFuzzy
 )
 
 // MatchStrategyFromString returns a MatchStrategy from its string representation.
@@ -177,11 +230,17 @@ func MatchStrategyFromString(str string) (MatchStrategy, error) {
 	switch str {
 	case "fts", "f", "":
 		return MatchStrategyFts, nil
-	case "re", "grep", "r":
+	case "re", "regex", "grep", "r":
 		return MatchStrategyRe, nil
 	case "exact", "e":
 		return MatchStrategyExact, nil
+	case "phrase":
+		return MatchStrategyPhrase, nil
+	case "prefix":
+		return MatchStrategyPrefix, nil
+	case "fuzzy":
+		return MatchStrategyFuzzy, nil
 	default:
-		return 0, fmt.Errorf("%s: unknown match strategy\ntry fts (full-text search), re (regular expression) or exact", str)
+		return 0, fmt.Errorf("%s: unknown match strategy\ntry fts (full-text search), phrase, prefix, re (regular expression), exact or fuzzy", str)
 	}
 }
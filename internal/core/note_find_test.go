@@ -39,8 +39,25 @@ func TestNoteSorterFromString(t *testing.T) {
 	test("word-count", NoteSortWordCount, true)
 	test("word-count-", NoteSortWordCount, false)
 
+	// Explicit direction
+	test("created:asc", NoteSortCreated, true)
+	test("c:desc", NoteSortCreated, false)
+	test("path:desc", NoteSortPath, false)
+	test("title:asc", NoteSortTitle, true)
+	test("random:desc", NoteSortRandom, false)
+	test("wc:desc", NoteSortWordCount, false)
+	test("modified:asc", NoteSortModified, true)
+
 	_, err := NoteSorterFromString("foobar")
 	assert.Err(t, err, "foobar: unknown sorting term")
+	_, err = NoteSorterFromString("foobar:asc")
+	assert.Err(t, err, "foobar: unknown sorting term")
+	_, err = NoteSorterFromString("created:up")
+	assert.Err(t, err, "created:up: unknown sorting direction")
+	_, err = NoteSorterFromString("created:")
+	assert.Err(t, err, "created:: unknown sorting direction")
+	_, err = NoteSorterFromString("created+:asc")
+	assert.Err(t, err, "created+: unknown sorting term")
 }
 
 func TestSortersFromStrings(t *testing.T) {
@@ -85,6 +102,10 @@ func TestMatchStrategyFromString(t *testing.T) {
 	test("e", MatchStrategyExact)
 	test("exact", MatchStrategyExact)
 
+	test("phrase", MatchStrategyPhrase)
+	test("prefix", MatchStrategyPrefix)
+	test("fuzzy", MatchStrategyFuzzy)
+
 	_, err := MatchStrategyFromString("foobar")
-	assert.Err(t, err, "foobar: unknown match strategy\ntry fts (full-text search), re (regular expression) or exact")
+	assert.Err(t, err, "foobar: unknown match strategy\ntry fts (full-text search), phrase, prefix, re (regular expression), exact or fuzzy")
 }
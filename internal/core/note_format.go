@@ -3,26 +3,60 @@ package core
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"regexp"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/zk-org/zk/internal/util/paths"
+	strutil "github.com/zk-org/zk/internal/util/strings"
 )
 
 // NoteFormatter formats notes to be printed on the screen.
 type NoteFormatter func(note ContextualNote) (string, error)
 
-func newNoteFormatter(basePath string, template Template, linkFormatter LinkFormatter, env map[string]string, fs FileStorage) (NoteFormatter, error) {
-	termRepl, err := template.Styler().Style("$1", StyleTerm)
+// NoteListFormatter formats a note printed at the given position in a list of
+// count notes. The position is available in the template with the `@index`,
+// `@first` and `@last` variables.
+type NoteListFormatter func(note ContextualNote, index int, count int) (string, error)
+
+// NoteListHeaderFormatter formats the header or the footer of a list of count
+// notes.
+type NoteListHeaderFormatter func(count int) (string, error)
+
+func newNoteFormatter(basePath string, template Template, linkFormatter LinkFormatter, pathStyle PathStyle, index NoteIndex, todoMarkers []string, groupName func(path string) (string, error), env map[string]string, fs FileStorage, renderHTML HTMLRenderer, now time.Time) (NoteFormatter, error) {
+	format, err := newNoteListFormatter(basePath, template, linkFormatter, pathStyle, index, todoMarkers, groupName, env, fs, renderHTML, now)
 	if err != nil {
 		return nil, err
 	}
 
 	return func(note ContextualNote) (string, error) {
+		return format(note, 0, 1)
+	}, nil
+}
+
+// newNoteListFormatter creates a NoteListFormatter. The note bodies are
+// rendered as HTML in the `html` variable only when renderHTML is not nil.
+// The `days-since-*` variables are counted from now.
+func newNoteListFormatter(basePath string, template Template, linkFormatter LinkFormatter, pathStyle PathStyle, index NoteIndex, todoMarkers []string, groupName func(path string) (string, error), env map[string]string, fs FileStorage, renderHTML HTMLRenderer, now time.Time) (NoteListFormatter, error) {
+	termRepl, err := template.Styler().Style("$1", StyleTerm)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(note ContextualNote, position int, count int) (string, error) {
 		path := NotebookPath{
 			Path:       note.Path,
 			BasePath:   basePath,
 			WorkingDir: fs.WorkingDir(),
 		}
-		relPath, err := path.PathRelToWorkingDir()
+		printedPath, err := formatNotePath(note.Note, path, pathStyle)
+		if err != nil {
+			return "", err
+		}
+
+		group, err := groupName(note.Path)
 		if err != nil {
 			return "", err
 		}
@@ -32,10 +66,10 @@ func newNoteFormatter(basePath string, template Template, linkFormatter LinkForm
 			snippets = append(snippets, noteTermRegex.ReplaceAllString(snippet, termRepl))
 		}
 
-		return template.Render(noteFormatRenderContext{
+		context := noteFormatRenderContext{
 			Filename:     note.Filename(),
 			FilenameStem: note.FilenameStem(),
-			Path:         relPath,
+			Path:         printedPath,
 			AbsPath:      path.AbsPath(),
 			Title:        note.Title,
 			Link: newLazyStringer(func() string {
@@ -46,43 +80,350 @@ func newNoteFormatter(basePath string, template Template, linkFormatter LinkForm
 				link, _ := linkFormatter(context)
 				return link
 			}),
-			Lead:       note.Lead,
-			Body:       note.Body,
-			Snippets:   snippets,
-			Tags:       note.Tags,
-			RawContent: note.RawContent,
-			WordCount:  note.WordCount,
-			Metadata:   note.Metadata,
-			Created:    note.Created,
-			Modified:   note.Modified,
-			Checksum:   note.Checksum,
-			Env:        env,
-		})
+			LinksAll: func() []noteFormatLink {
+				links, _ := findNoteFormatLinks(note.Note, index, basePath, pathStyle, fs)
+				return links
+			},
+			Backlinks: func() []noteFormatBacklink {
+				backlinks, _ := findNoteFormatBacklinks(note.Note, index, basePath, pathStyle, fs)
+				return backlinks
+			},
+			TagStarts: func() map[string]int {
+				starts, _ := index.FindTagStarts(note.ID)
+				return starts
+			},
+			LinkStarts: func() map[string]int {
+				starts, _ := findNoteFormatLinkStarts(note.Note, index)
+				return starts
+			},
+			AmbiguousLinks: func() []AmbiguousLink {
+				links, _ := index.FindAmbiguousLinks(note.ID)
+				return links
+			},
+			Lead:              note.Lead,
+			Abstract:          noteAbstract(note.Body),
+			Body:              note.Body,
+			Plain:             note.Plain,
+			Snippets:          snippets,
+			Tags:              sortedTags(note.Tags),
+			Headings:          note.Headings,
+			Domains:           note.Domains,
+			Group:             group,
+			RawContent:        note.RawContent,
+			WordCount:         note.WordCount,
+			Metadata:          note.Metadata,
+			Created:           note.Created,
+			Modified:          note.Modified,
+			Checksum:          note.Checksum,
+			DaysSinceCreated:  daysBetween(note.Created, now, time.Local),
+			DaysSinceModified: daysBetween(note.Modified, now, time.Local),
+			MaxTagDepth:       note.MaxTagDepth(),
+			TodoCount:         countTodoMarkers(note.Plain, todoMarkers),
+			MatchPositions:    note.MatchPositions,
+			Env:               env,
+		}
+		if renderHTML != nil {
+			context.HTML = newLazyStringer(func() string {
+				html, _ := noteHTML(note.Note, index, renderHTML, basePath, pathStyle, fs)
+				return html
+			})
+		}
+
+		if template, ok := template.(DataTemplate); ok {
+			return template.RenderWithData(context, map[string]interface{}{
+				"index": position,
+				"first": position == 0,
+				"last":  position == count-1,
+			})
+		}
+		return template.Render(context)
+	}, nil
+}
+
+// newNoteListHeaderFormatter renders the header or footer template of a list
+// of notes, with the number of notes as context.
+func newNoteListHeaderFormatter(template Template) (NoteListHeaderFormatter, error) {
+	return func(count int) (string, error) {
+		return template.Render(noteListRenderContext{Count: count})
 	}, nil
 }
 
+// noteListRenderContext holds the variables available to the header and
+// footer templates of a list of notes.
+type noteListRenderContext struct {
+	Count int `json:"count"`
+}
+
+// daysBetween returns the number of calendar days elapsed from the date from
+// to the date to, using the day boundaries of the given timezone.
+func daysBetween(from time.Time, to time.Time, loc *time.Location) int {
+	fromYear, fromMonth, fromDay := from.In(loc).Date()
+	toYear, toMonth, toDay := to.In(loc).Date()
+	// Compares the dates at midnight UTC to ignore daylight saving changes.
+	start := time.Date(fromYear, fromMonth, fromDay, 0, 0, 0, 0, time.UTC)
+	end := time.Date(toYear, toMonth, toDay, 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// sortedTags returns a sorted copy of the given tags, to print them in a
+// stable order.
+func sortedTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	sorted := make([]string, len(tags))
+	copy(sorted, tags)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// formatNotePath prints the path of the note with the given style.
+func formatNotePath(note Note, path NotebookPath, style PathStyle) (string, error) {
+	switch style {
+	case PathStyleNotebook:
+		return note.Path, nil
+	case PathStyleAbsolute:
+		return path.AbsPath(), nil
+	case PathStyleID:
+		// The ID recorded in the frontmatter takes precedence over the
+		// filename.
+		if id, ok := note.Metadata["id"]; ok && id != nil && fmt.Sprint(id) != "" {
+			return fmt.Sprint(id), nil
+		}
+		return note.FilenameStem(), nil
+	default: // PathStyleRelative
+		return path.PathRelToWorkingDir()
+	}
+}
+
+// noteFormatLink is a link between the formatted note and another note, in
+// either direction.
+type noteFormatLink struct {
+	// Either "out" for outgoing links, or "in" for backlinks.
+	Direction string `json:"direction"`
+	Title     string `json:"title"`
+	Path      string `json:"path"`
+}
+
+// findNoteFormatLinks returns the notes linked by the given note, followed by
+// the notes linking to it. Both are found from the indexed links.
+func findNoteFormatLinks(note Note, index NoteIndex, basePath string, pathStyle PathStyle, fs FileStorage) ([]noteFormatLink, error) {
+	links := make([]noteFormatLink, 0)
+
+	find := func(direction string, opts NoteFindOpts) error {
+		notes, err := index.FindMinimal(opts)
+		if err != nil {
+			return err
+		}
+		for _, other := range notes {
+			path, err := formatNotePath(
+				Note{Path: other.Path, Title: other.Title, Metadata: other.Metadata},
+				NotebookPath{Path: other.Path, BasePath: basePath, WorkingDir: fs.WorkingDir()},
+				pathStyle,
+			)
+			if err != nil {
+				return err
+			}
+			links = append(links, noteFormatLink{
+				Direction: direction,
+				Title:     other.Title,
+				Path:      path,
+			})
+		}
+		return nil
+	}
+
+	err := find("out", NoteFindOpts{
+		LinkedBy:   &LinkFilter{Hrefs: []string{note.Path}},
+		ExcludeIDs: []NoteID{note.ID},
+	})
+	if err != nil {
+		return links, err
+	}
+	err = find("in", NoteFindOpts{
+		LinkTo:     &LinkFilter{Hrefs: []string{note.Path}},
+		ExcludeIDs: []NoteID{note.ID},
+	})
+	return links, err
+}
+
+// noteFormatBacklink is a link to the formatted note from another note, with
+// the sentence surrounding the link.
+type noteFormatBacklink struct {
+	Title   string `json:"title"`
+	Path    string `json:"path"`
+	Context string `json:"context"`
+}
+
+// findNoteFormatBacklinks returns each indexed link to the given note from
+// another note, ordered by source path and position in the source.
+func findNoteFormatBacklinks(note Note, index NoteIndex, basePath string, pathStyle PathStyle, fs FileStorage) ([]noteFormatBacklink, error) {
+	backlinks := make([]noteFormatBacklink, 0)
+
+	links, err := index.FindLinksOfNotes([]NoteID{note.ID})
+	if err != nil {
+		return backlinks, err
+	}
+	incoming := make([]ResolvedLink, 0)
+	sourceIDs := make([]NoteID, 0)
+	for _, link := range links {
+		if link.TargetID == note.ID && link.SourceID != note.ID {
+			incoming = append(incoming, link)
+			sourceIDs = append(sourceIDs, link.SourceID)
+		}
+	}
+	if len(incoming) == 0 {
+		return backlinks, nil
+	}
+	sort.SliceStable(incoming, func(i, j int) bool {
+		if incoming[i].SourcePath != incoming[j].SourcePath {
+			return incoming[i].SourcePath < incoming[j].SourcePath
+		}
+		return incoming[i].Start < incoming[j].Start
+	})
+
+	sources, err := index.FindMinimal(NoteFindOpts{IncludeIDs: sourceIDs})
+	if err != nil {
+		return backlinks, err
+	}
+	sourcesByID := map[NoteID]MinimalNote{}
+	for _, source := range sources {
+		sourcesByID[source.ID] = source
+	}
+
+	for _, link := range incoming {
+		source, ok := sourcesByID[link.SourceID]
+		if !ok {
+			continue
+		}
+		path, err := formatNotePath(
+			Note{Path: source.Path, Title: source.Title, Metadata: source.Metadata},
+			NotebookPath{Path: source.Path, BasePath: basePath, WorkingDir: fs.WorkingDir()},
+			pathStyle,
+		)
+		if err != nil {
+			return backlinks, err
+		}
+		backlinks = append(backlinks, noteFormatBacklink{
+			Title:   source.Title,
+			Path:    path,
+			Context: linkContext(link.Link),
+		})
+	}
+	return backlinks, nil
+}
+
+// findNoteFormatLinkStarts returns the byte offset of the first link from the
+// given note to each of its targets, keyed by href and by target path with and
+// without extension.
+func findNoteFormatLinkStarts(note Note, index NoteIndex) (map[string]int, error) {
+	starts := map[string]int{}
+
+	links, err := index.FindLinksOfNotes([]NoteID{note.ID})
+	if err != nil {
+		return starts, err
+	}
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].Start < links[j].Start
+	})
+
+	for _, link := range links {
+		if link.SourceID != note.ID {
+			continue
+		}
+		keys := []string{link.Href}
+		if link.TargetID.IsValid() {
+			keys = append(keys, link.TargetPath, paths.DropExt(link.TargetPath))
+		}
+		for _, key := range keys {
+			if _, ok := starts[key]; !ok {
+				starts[key] = link.Start
+			}
+		}
+	}
+	return starts, nil
+}
+
+// linkContext returns the sentence surrounding the link in its snippet,
+// falling back on the whole snippet when the link offset is unknown.
+func linkContext(link Link) string {
+	if context := strutil.SentenceAt(link.Snippet, link.Start-link.SnippetStart); context != "" {
+		return context
+	}
+	return strings.TrimSpace(link.Snippet)
+}
+
+// noteHTML renders the body of the note as HTML, with its internal links
+// pointing to the paths of their target notes printed with the given style.
+func noteHTML(note Note, index NoteIndex, renderHTML HTMLRenderer, basePath string, pathStyle PathStyle, fs FileStorage) (string, error) {
+	links, err := index.FindLinksOfNotes([]NoteID{note.ID})
+	if err != nil {
+		return "", err
+	}
+	targets := map[string]string{}
+	for _, link := range links {
+		if link.SourceID != note.ID || !link.TargetID.IsValid() {
+			continue
+		}
+		path, err := formatNotePath(
+			Note{Path: link.TargetPath},
+			NotebookPath{Path: link.TargetPath, BasePath: basePath, WorkingDir: fs.WorkingDir()},
+			pathStyle,
+		)
+		if err != nil {
+			return "", err
+		}
+		targets[link.Href] = (&url.URL{Path: path}).String()
+	}
+
+	body := rewriteNoteLinks(note, func(href string) (string, bool) {
+		dest, ok := targets[href]
+		return dest, ok
+	})
+	return renderHTML(body)
+}
+
 var noteTermRegex = regexp.MustCompile(`<zk:match>(.*?)</zk:match>`)
 
 // noteFormatRenderContext holds the variables available to the note formatting
 // templates.
+//
+// The fields are serialized to JSON in the order of declaration, which is
+// documented in docs/template-format.md. Don't reorder them.
 type noteFormatRenderContext struct {
-	Filename     string                 `json:"filename"`
-	FilenameStem string                 `json:"filenameStem" handlebars:"filename-stem"`
-	Path         string                 `json:"path"`
-	AbsPath      string                 `json:"absPath" handlebars:"abs-path"`
-	Title        string                 `json:"title"`
-	Link         fmt.Stringer           `json:"link"`
-	Lead         string                 `json:"lead"`
-	Body         string                 `json:"body"`
-	Snippets     []string               `json:"snippets"`
-	RawContent   string                 `json:"rawContent" handlebars:"raw-content"`
-	WordCount    int                    `json:"wordCount" handlebars:"word-count"`
-	Tags         []string               `json:"tags"`
-	Metadata     map[string]interface{} `json:"metadata"`
-	Created      time.Time              `json:"created"`
-	Modified     time.Time              `json:"modified"`
-	Checksum     string                 `json:"checksum"`
-	Env          map[string]string      `json:"-"`
+	Filename          string                      `json:"filename"`
+	FilenameStem      string                      `json:"filenameStem" handlebars:"filename-stem"`
+	Path              string                      `json:"path"`
+	AbsPath           string                      `json:"absPath" handlebars:"abs-path"`
+	Title             string                      `json:"title"`
+	Link              fmt.Stringer                `json:"link"`
+	LinksAll          func() []noteFormatLink     `json:"-" handlebars:"links-all"`
+	Backlinks         func() []noteFormatBacklink `json:"-"`
+	TagStarts         func() map[string]int       `json:"-" handlebars:"tag-starts"`
+	LinkStarts        func() map[string]int       `json:"-" handlebars:"link-starts"`
+	Lead              string                      `json:"lead"`
+	Abstract          string                      `json:"-"`
+	Body              string                      `json:"body"`
+	Plain             string                      `json:"plain"`
+	Snippets          []string                    `json:"snippets"`
+	RawContent        string                      `json:"rawContent" handlebars:"raw-content"`
+	WordCount         int                         `json:"wordCount" handlebars:"word-count"`
+	Tags              []string                    `json:"tags"`
+	Headings          []Heading                   `json:"-"`
+	Domains           []string                    `json:"-"`
+	Group             string                      `json:"-"`
+	Metadata          map[string]interface{}      `json:"metadata"`
+	Created           time.Time                   `json:"created"`
+	Modified          time.Time                   `json:"modified"`
+	Checksum          string                      `json:"checksum"`
+	HTML              fmt.Stringer                `json:"html,omitempty"`
+	MatchPositions    []int                       `json:"matchPositions,omitempty" handlebars:"match-positions"`
+	DaysSinceCreated  int                         `json:"-" handlebars:"days-since-created"`
+	DaysSinceModified int                         `json:"-" handlebars:"days-since-modified"`
+	MaxTagDepth       int                         `json:"-" handlebars:"max-tag-depth"`
+	TodoCount         int                         `json:"-" handlebars:"todo-count"`
+	AmbiguousLinks    func() []AmbiguousLink      `json:"-" handlebars:"ambiguous-links"`
+	Env               map[string]string           `json:"-"`
 }
 
 func (c noteFormatRenderContext) Equal(other noteFormatRenderContext) bool {
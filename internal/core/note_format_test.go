@@ -1,10 +1,12 @@
 package core
 
 import (
+	"encoding/json"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/zk-org/zk/internal/util/date"
 	"github.com/zk-org/zk/internal/util/opt"
 	"github.com/zk-org/zk/internal/util/paths"
 
@@ -149,6 +151,96 @@ func TestNoteFormatterMakesPathRelative(t *testing.T) {
 	test("/abs/zk", "/abs", "dir/note.md", "zk/dir/note.md", "/abs/zk/dir/note.md")
 }
 
+func TestNoteFormatterPathStyle(t *testing.T) {
+	test := func(style PathStyle, note Note, expected string) {
+		test := formatTest{
+			rootDir:    "/abs/zk",
+			workingDir: "/abs/zk/dir",
+			pathStyle:  style,
+		}
+		test.setup()
+		formatter, err := test.run("format")
+		assert.Nil(t, err)
+		_, err = formatter(ContextualNote{Note: note})
+		assert.Nil(t, err)
+		assert.Equal(t, len(test.template.Contexts), 1)
+		context := test.template.Contexts[0].(noteFormatRenderContext)
+		assert.Equal(t, context.Path, expected)
+		// The dedicated path variables are not affected by the style.
+		assert.Equal(t, context.AbsPath, filepath.Join("/abs/zk", note.Path))
+		assert.Equal(t, context.Filename, filepath.Base(note.Path))
+	}
+
+	note := Note{Path: "other/note.md"}
+	test("", note, "../other/note.md")
+	test(PathStyleRelative, note, "../other/note.md")
+	test(PathStyleNotebook, note, "other/note.md")
+	test(PathStyleAbsolute, note, "/abs/zk/other/note.md")
+	test(PathStyleID, note, "note")
+
+	// The ID of the frontmatter takes precedence over the filename.
+	test(PathStyleID, Note{
+		Path:     "other/note.md",
+		Metadata: map[string]interface{}{"id": "abc123"},
+	}, "abc123")
+	test(PathStyleID, Note{
+		Path:     "other/note.md",
+		Metadata: map[string]interface{}{"id": 42},
+	}, "42")
+	test(PathStyleID, Note{
+		Path:     "other/note.md",
+		Metadata: map[string]interface{}{"id": ""},
+	}, "note")
+}
+
+func TestNoteFormatterDaysSince(t *testing.T) {
+	test := formatTest{
+		now: time.Date(2022, 3, 10, 9, 0, 0, 0, time.Local),
+	}
+	test.setup()
+	formatter, err := test.run("format")
+	assert.Nil(t, err)
+	_, err = formatter(ContextualNote{Note: Note{
+		Path:     "note.md",
+		Created:  time.Date(2021, 12, 25, 18, 0, 0, 0, time.Local),
+		Modified: time.Date(2022, 3, 9, 23, 0, 0, 0, time.Local),
+	}})
+	assert.Nil(t, err)
+	context := test.template.Contexts[0].(noteFormatRenderContext)
+	assert.Equal(t, context.DaysSinceCreated, 75)
+	assert.Equal(t, context.DaysSinceModified, 1)
+}
+
+func TestDaysBetween(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	assert.Nil(t, err)
+
+	test := func(from time.Time, to time.Time, loc *time.Location, expected int) {
+		assert.Equal(t, daysBetween(from, to, loc), expected)
+	}
+
+	now := time.Date(2022, 3, 10, 9, 0, 0, 0, time.UTC)
+	test(now, now, time.UTC, 0)
+	test(time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC), now, time.UTC, 0)
+	test(time.Date(2022, 3, 9, 23, 59, 0, 0, time.UTC), now, time.UTC, 1)
+	test(time.Date(2022, 2, 10, 12, 0, 0, 0, time.UTC), now, time.UTC, 28)
+	test(time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC), now, time.UTC, 365)
+	// Dates in the future are negative.
+	test(time.Date(2022, 3, 12, 12, 0, 0, 0, time.UTC), now, time.UTC, -2)
+
+	// The day boundaries depend on the timezone: 23:30 UTC is already the
+	// next day in Paris.
+	test(time.Date(2022, 3, 9, 23, 30, 0, 0, time.UTC), now, time.UTC, 1)
+	test(time.Date(2022, 3, 9, 23, 30, 0, 0, time.UTC), now, paris, 0)
+
+	// Daylight saving changes don't affect the count.
+	test(
+		time.Date(2022, 3, 26, 12, 0, 0, 0, paris),
+		time.Date(2022, 3, 28, 1, 0, 0, 0, paris),
+		paris, 2,
+	)
+}
+
 func TestNoteFormatterStylesSnippetTerm(t *testing.T) {
 	test := func(snippet string, expected string) {
 		test := formatTest{}
@@ -182,11 +274,13 @@ type formatTest struct {
 	format         string
 	rootDir        string
 	workingDir     string
+	pathStyle      PathStyle
 	fs             *fileStorageMock
 	config         Config
 	templateLoader *templateLoaderMock
 	template       *templateSpy
 	receivedLang   string
+	now            time.Time
 }
 
 func (t *formatTest) setup() {
@@ -207,9 +301,14 @@ func (t *formatTest) setup() {
 
 	t.config = NewDefaultConfig()
 	t.config.Note.Lang = "fr"
+
+	if t.now.IsZero() {
+		t.now = time.Now()
+	}
 }
 
 func (t *formatTest) run(format string) (NoteFormatter, error) {
+	now := date.NewFrozen(t.now)
 	notebook := NewNotebook(t.rootDir, t.config, NotebookPorts{
 		TemplateLoaderFactory: func(language string) (TemplateLoader, error) {
 			t.receivedLang = language
@@ -219,7 +318,94 @@ func (t *formatTest) run(format string) (NoteFormatter, error) {
 		OSEnv: func() map[string]string {
 			return map[string]string{}
 		},
+		Now: &now,
+	})
+
+	return notebook.NewNoteFormatter(format, t.pathStyle)
+}
+
+func TestLinkContext(t *testing.T) {
+	test := func(link Link, expected string) {
+		assert.Equal(t, linkContext(link), expected)
+	}
+
+	test(Link{
+		Snippet:      "A first sentence. Then a [link](target) to a note. The end.",
+		SnippetStart: 10,
+		SnippetEnd:   69,
+		Start:        36,
+	}, "Then a [link](target) to a note.")
+
+	// Unknown link offset.
+	test(Link{
+		Snippet:      " A first sentence. Then a [link](target) to a note. ",
+		SnippetStart: 10,
+		SnippetEnd:   69,
+	}, "A first sentence. Then a [link](target) to a note.")
+}
+
+// noteIndexLinksMock is a NoteIndex returning the given links.
+type noteIndexLinksMock struct {
+	noteIndexAddMock
+	links []ResolvedLink
+}
+
+func (m *noteIndexLinksMock) FindLinksOfNotes(ids []NoteID) ([]ResolvedLink, error) {
+	return m.links, nil
+}
+
+func TestNoteHTML(t *testing.T) {
+	index := &noteIndexLinksMock{links: []ResolvedLink{
+		{SourceID: 1, TargetID: 2, TargetPath: "dir/target note.md", Link: Link{Href: "dir/target note"}},
+		{SourceID: 1, TargetID: 3, TargetPath: "other.md", Link: Link{Href: "dir/other.md"}},
+		// Dead link
+		{SourceID: 1, TargetID: 0, Link: Link{Href: "missing"}},
+		// Backlink
+		{SourceID: 4, TargetID: 1, TargetPath: "dir/note.md", Link: Link{Href: "note"}},
+	}}
+	renderHTML := func(markdown string) (string, error) {
+		return "<p>" + markdown + "</p>", nil
+	}
+	note := Note{
+		ID:   1,
+		Path: "dir/note.md",
+		Body: "See [[dir/target note|the target]], [other](other.md), [[missing]] and [web](https://example.com).",
+	}
+
+	test := func(workingDir string, pathStyle PathStyle, expected string) {
+		fs := newFileStorageMock(workingDir, []string{})
+		html, err := noteHTML(note, index, renderHTML, "/notebook", pathStyle, fs)
+		assert.Nil(t, err)
+		assert.Equal(t, html, expected)
+	}
+
+	test("/notebook", PathStyleNotebook, "<p>See [the target](dir/target%20note.md), [other](other.md), [[missing]] and [web](https://example.com).</p>")
+	test("/notebook/dir", PathStyleRelative, "<p>See [the target](target%20note.md), [other](../other.md), [[missing]] and [web](https://example.com).</p>")
+}
+
+func TestNoteFormatterJSONIsStable(t *testing.T) {
+	test := formatTest{}
+	test.setup()
+	formatter, err := test.run("format")
+	assert.Nil(t, err)
+
+	date := time.Date(2009, 1, 17, 20, 34, 58, 0, time.UTC)
+	_, err = formatter(ContextualNote{
+		Note: Note{
+			Path:  "note.md",
+			Title: "Note",
+			Tags:  []string{"zeta", "alpha", "mu"},
+			Metadata: map[string]interface{}{
+				"z": "last",
+				"a": map[string]interface{}{"y": 2, "b": 1},
+			},
+			Created:  date,
+			Modified: date,
+		},
 	})
+	assert.Nil(t, err)
 
-	return notebook.NewNoteFormatter(format)
+	out, err := json.Marshal(test.template.Contexts[0])
+	assert.Nil(t, err)
+	assert.Equal(t, string(out), `{"filename":"note.md","filenameStem":"note","path":"note.md","absPath":"/notebook/note.md","title":"Note","link":"[Note](note)","lead":"","body":"","plain":"","snippets":[],"rawContent":"","wordCount":0,"tags":["alpha","mu","zeta"],"metadata":{"a":{"b":1,"y":2},"z":"last"},"created":"2009-01-17T20:34:58Z","modified":"2009-01-17T20:34:58Z","checksum":""}`)
 }
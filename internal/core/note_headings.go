@@ -0,0 +1,146 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Heading is a heading of a note, as recorded in the index.
+type Heading struct {
+	// Level of the heading, from 1 to 6.
+	Level int `json:"level"`
+	// Text of the heading, without its Markdown syntax.
+	Text string `json:"text"`
+	// Anchor linking to the heading from another document, e.g.
+	// `note.md#anchor`.
+	Anchor string `json:"anchor"`
+	// Line of the heading in the raw content of the note, starting from 0.
+	Line int `json:"line"`
+}
+
+// HeadingMatch is a heading of an indexed note matching a search.
+type HeadingMatch struct {
+	// Path of the note relative to the notebook root.
+	Path string
+	// Title of the note.
+	Title string
+	// Matched heading. The title of the note is reported as a heading of
+	// level 0.
+	Heading Heading
+}
+
+// NoteHeading is a Markdown ATX heading found in the content of a note.
+type NoteHeading struct {
+	// Level of the heading, from 1 to 6.
+	Level int
+	// Text of the heading, without its # markers.
+	Text string
+	// Line of the heading in the content, starting from 0.
+	Line int
+}
+
+// ParseNoteHeadings returns the ATX headings of the given Markdown content,
+// in order. The lines of the frontmatter and of the fenced code blocks are
+// ignored.
+func ParseNoteHeadings(content string) []NoteHeading {
+	headings := make([]NoteHeading, 0)
+	fence := ""
+
+	lines := strings.Split(content, "\n")
+	for i := FrontmatterLineCount(content); i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		trimmed := strings.TrimLeft(line, " ")
+		// Lines indented with four spaces are code blocks.
+		if len(line)-len(trimmed) > 3 {
+			continue
+		}
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]+" \t") == "" {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		level := headingLevel(trimmed)
+		if level == 0 {
+			continue
+		}
+		headings = append(headings, NoteHeading{
+			Level: level,
+			Text:  headingText(trimmed[level:]),
+			Line:  i,
+		})
+	}
+
+	return headings
+}
+
+// headingText removes the optional closing sequence of # from the text of an
+// ATX heading.
+func headingText(text string) string {
+	text = strings.TrimSpace(text)
+	closed := strings.TrimRight(text, "#")
+	if closed == "" {
+		return ""
+	}
+	if closed != text && (strings.HasSuffix(closed, " ") || strings.HasSuffix(closed, "\t")) {
+		text = closed
+	}
+	return strings.TrimSpace(text)
+}
+
+// FrontmatterLineCount returns the number of lines spanned by the frontmatter
+// of the given content, including its delimiters, or 0 if there is none.
+func FrontmatterLineCount(content string) int {
+	loc := FrontmatterRegex.FindStringIndex(content)
+	if loc == nil {
+		return 0
+	}
+	frontmatter := content[loc[0]:loc[1]]
+	count := strings.Count(frontmatter, "\n")
+	if !strings.HasSuffix(frontmatter, "\n") {
+		count++
+	}
+	return count
+}
+
+var anchorSpecialCharsRegex = regexp.MustCompile(`[^\pL\pN\s_-]`)
+
+// HeadingAnchors generates the anchors of the headings of a note, following
+// the GitHub convention. The anchors of the headings sharing the same text are
+// suffixed with -1, -2, etc. in order of appearance.
+type HeadingAnchors map[string]int
+
+// Anchor returns the anchor of the next heading of the note with the given
+// text.
+func (a HeadingAnchors) Anchor(text string) string {
+	return a.unique(Anchor(text))
+}
+
+// unique suffixes the anchor with a counter when it was already generated,
+// skipping the suffixed anchors generated for other headings.
+func (a HeadingAnchors) unique(anchor string) string {
+	for {
+		count := a[anchor]
+		a[anchor] = count + 1
+		if count == 0 {
+			return anchor
+		}
+		anchor = fmt.Sprintf("%s-%d", anchor, count)
+	}
+}
+
+// Anchor returns the slug used as the anchor of a heading with the given
+// text, following the GitHub convention: lowercase, without punctuation and
+// with dashes instead of spaces.
+func Anchor(text string) string {
+	anchor := strings.ToLower(text)
+	anchor = anchorSpecialCharsRegex.ReplaceAllString(anchor, "")
+	return strings.ReplaceAll(strings.TrimSpace(anchor), " ", "-")
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestParseNoteHeadings(t *testing.T) {
+	test := func(content string, expected []NoteHeading) {
+		assert.Equal(t, ParseNoteHeadings(content), expected)
+	}
+
+	test("", []NoteHeading{})
+	test("Paragraph\n", []NoteHeading{})
+
+	test(`# Title
+
+## Section
+
+### Sub-section
+#### Closed ####
+##### Hash# in text
+###### Level 6
+####### Not a heading
+#Not a heading
+   ## Indented
+    ## Code block
+`, []NoteHeading{
+		{Level: 1, Text: "Title", Line: 0},
+		{Level: 2, Text: "Section", Line: 2},
+		{Level: 3, Text: "Sub-section", Line: 4},
+		{Level: 4, Text: "Closed", Line: 5},
+		{Level: 5, Text: "Hash# in text", Line: 6},
+		{Level: 6, Text: "Level 6", Line: 7},
+		{Level: 2, Text: "Indented", Line: 10},
+	})
+
+	// Empty headings
+	test("#\n## ##\n", []NoteHeading{
+		{Level: 1, Text: "", Line: 0},
+		{Level: 2, Text: "", Line: 1},
+	})
+
+	// Windows line endings
+	test("# Title\r\n\r\n## Section\r\n", []NoteHeading{
+		{Level: 1, Text: "Title", Line: 0},
+		{Level: 2, Text: "Section", Line: 2},
+	})
+
+	// Frontmatter and fenced code blocks are skipped.
+	test("---\ntitle: Note\n---\n# Title\n```sh\n# Comment\n```\n~~~\n## Code\n~~~~\n## Section\n", []NoteHeading{
+		{Level: 1, Text: "Title", Line: 3},
+		{Level: 2, Text: "Section", Line: 10},
+	})
+}
+
+func TestHeadingAnchors(t *testing.T) {
+	anchors := HeadingAnchors{}
+	test := func(text string, expected string) {
+		assert.Equal(t, anchors.Anchor(text), expected)
+	}
+
+	test("Getting Started", "getting-started")
+	test("  What's new in v2.0?  ", "whats-new-in-v20")
+	test("Café & Crème", "café--crème")
+	test("snake_case-and-dashes", "snake_case-and-dashes")
+	// Duplicated headings are suffixed in order of appearance.
+	test("Getting started", "getting-started-1")
+	test("getting STARTED", "getting-started-2")
+	test("Café & Crème", "café--crème-1")
+	// A heading can't reuse the suffixed anchor of another one.
+	test("Getting started 1", "getting-started-1-1")
+	test("Getting started", "getting-started-3")
+}
+
+func TestFrontmatterLineCount(t *testing.T) {
+	test := func(content string, expected int) {
+		assert.Equal(t, FrontmatterLineCount(content), expected)
+	}
+
+	test("", 0)
+	test("# Title\n", 0)
+	test("---\n---\n# Title\n", 2)
+	test("---\ntitle: Note\ntags: [a]\n---\n\n# Title\n", 4)
+	test("---\ntitle: Note\n---", 3)
+	// Unclosed frontmatter
+	test("---\ntitle: Note\n", 0)
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
@@ -26,9 +27,25 @@ type NoteIndex interface {
 
 	// FindLinksBetweenNotes retrieves the links between the given notes.
 	FindLinksBetweenNotes(ids []NoteID) ([]ResolvedLink, error)
+	// FindLinksOfNotes retrieves the links from or to the given notes,
+	// including the external and unresolved ones.
+	FindLinksOfNotes(ids []NoteID) ([]ResolvedLink, error)
+	// FindTagStarts retrieves the byte offset of the first occurrence of
+	// each tag in the content of the given note, as recorded when indexing.
+	FindTagStarts(id NoteID) (map[string]int, error)
+	// FindAmbiguousLinks retrieves the links of the given note which could
+	// resolve to several notes, as recorded when indexing.
+	FindAmbiguousLinks(id NoteID) ([]AmbiguousLink, error)
+	// FindHeadings retrieves the note titles and headings matching the given
+	// regular expression, the shortest first, up to limit of them.
+	FindHeadings(regex string, limit int) ([]HeadingMatch, error)
 
 	// FindCollections retrieves all the collections of the given kind.
 	FindCollections(kind CollectionKind, sorters []CollectionSorter) ([]Collection, error)
+	// FindNestedCollections retrieves all the collections of the given kind
+	// and the parents of the nested ones, counting in each collection the
+	// notes of its nested collections.
+	FindNestedCollections(kind CollectionKind, sorters []CollectionSorter) ([]Collection, error)
 
 	// Indexed returns the list of indexed note file metadata.
 	IndexedPaths() (<-chan paths.Metadata, error)
@@ -46,6 +63,13 @@ type NoteIndex interface {
 	NeedsReindexing() (bool, error)
 	// SetNeedsReindexing indicates whether all notes should be reindexed.
 	SetNeedsReindexing(needsReindexing bool) error
+
+	// Reset drops all the indexed data and recreates an empty index.
+	Reset() error
+
+	// Close releases the resources held by the index, such as the database
+	// connections. The index can't be used afterwards.
+	Close() error
 }
 
 // NoteIndexingStats holds statistics about a notebook indexing process.
@@ -58,13 +82,15 @@ type NoteIndexingStats struct {
 	ModifiedCount int `json:"modifiedCount"`
 	// Number of notes removed since last indexing.
 	RemovedCount int `json:"removedCount"`
+	// Number of files skipped by the ignore rules.
+	SkippedCount int `json:"skippedCount"`
 	// Duration of the indexing process.
 	Duration time.Duration `json:"duration"`
 }
 
 // String implements Stringer
 func (s NoteIndexingStats) String() string {
-	return fmt.Sprintf(`Indexed %d %v in %v
+	res := fmt.Sprintf(`Indexed %d %v in %v
   + %d added
   ~ %d modified
   - %d removed`,
@@ -73,13 +99,22 @@ func (s NoteIndexingStats) String() string {
 		s.Duration.Round(500*time.Millisecond),
 		s.AddedCount, s.ModifiedCount, s.RemovedCount,
 	)
+	if s.SkippedCount > 0 {
+		res += fmt.Sprintf("\n  ! %d skipped", s.SkippedCount)
+	}
+	return res
 }
 
 // NoteIndexOpts holds the options for the indexing process.
 type NoteIndexOpts struct {
 	// When true, existing notes will be reindexed.
-	Force   bool
+	Force bool
+	// When true, the index is dropped and rebuilt from scratch.
+	Rebuild bool
 	Verbose bool
+	// Globs of additional files to index besides the notes, relative to the
+	// notebook root. They are added to the `index.include` setting.
+	Include []string
 }
 
 // indexTask indexes the notes in the given directory with the NoteIndex.
@@ -88,6 +123,8 @@ type indexTask struct {
 	config  Config
 	force   bool
 	verbose bool
+	// Globs of additional files to index besides the notes.
+	include []string
 	index   NoteIndex
 	parser  NoteParser
 	logger  util.Logger
@@ -111,6 +148,11 @@ func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexin
 	}
 
 	force := t.force || needsReindexing
+	if needsReindexing {
+		t.logger.Debugf("the index is outdated, reindexing all the notes")
+	} else if t.force {
+		t.logger.Debugf("forcing the reindexing of all the notes")
+	}
 
 	type IgnoredFile struct {
 		Path   string
@@ -118,22 +160,54 @@ func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexin
 	}
 	ignoredFiles := []IgnoredFile{}
 
+	// Paths already in the index, read before walking the notebook.
+	indexedPaths := map[string]bool{}
+
+	ignoreRules, err := paths.NewIgnoreRules(t.config.Notebook.Ignore)
+	if err != nil {
+		return stats, wrap(err)
+	}
+
 	shouldIgnorePath := func(path string) (bool, error) {
 		notifyIgnored := func(reason string) {
+			t.logger.Debugf("ignoring %s: %s", path, reason)
 			ignoredFiles = append(ignoredFiles, IgnoredFile{
 				Path:   path,
 				Reason: reason,
 			})
 		}
 
+		// The notebook's own files are never indexed, even when hidden files
+		// are.
+		if strings.HasPrefix(filepath.ToSlash(path), ".zk/") {
+			return true, nil
+		}
+
+		if ignored, pattern := ignoreRules.Match(path); ignored {
+			stats.SkippedCount += 1
+			notifyIgnored("matched ignore pattern \"" + pattern + "\"")
+			return true, nil
+		}
+
 		group, err := t.config.GroupConfigForPath(path)
 		if err != nil {
 			return true, err
 		}
 
 		if filepath.Ext(path) != "."+group.Note.Extension {
-			notifyIgnored("expected extension \"" + group.Note.Extension + "\"")
-			return true, nil
+			included, err := t.isIncluded(path)
+			if err != nil {
+				return true, err
+			}
+			// The files included with `zk index --include` are kept by the
+			// following indexings, until the index is rebuilt.
+			if !included && indexedPaths[path] {
+				included = true
+			}
+			if !included {
+				notifyIgnored("expected extension \"" + group.Note.Extension + "\"")
+				return true, nil
+			}
 		}
 
 		for _, ignoreGlob := range group.ExcludeGlobs() {
@@ -142,6 +216,7 @@ func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexin
 				return true, errors.Wrapf(err, "failed to match exclude glob %s to %s", ignoreGlob, path)
 			}
 			if matches {
+				stats.SkippedCount += 1
 				notifyIgnored("matched exclude glob \"" + ignoreGlob + "\"")
 				return true, nil
 			}
@@ -150,18 +225,32 @@ func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexin
 		return false, nil
 	}
 
-	notebookPath := &NotebookPath{Path: t.path}
-	source := paths.Walk(t.path, t.logger, notebookPath.Filename(), shouldIgnorePath)
-
-	target, err := t.index.IndexedPaths()
+	indexed, err := t.index.IndexedPaths()
 	if err != nil {
 		return stats, wrap(err)
 	}
+	indexedMetadata := []paths.Metadata{}
+	for metadata := range indexed {
+		indexedMetadata = append(indexedMetadata, metadata)
+		indexedPaths[metadata.Path] = true
+	}
+	target := make(chan paths.Metadata, len(indexedMetadata))
+	for _, metadata := range indexedMetadata {
+		target <- metadata
+	}
+	close(target)
+
+	notebookPath := &NotebookPath{Path: t.path}
+	includeHidden := !t.config.Notebook.IgnoreHidden
+	source := paths.Walk(t.path, t.logger, notebookPath.Filename(), includeHidden, shouldIgnorePath)
 
 	// FIXME: Use the FS?
 	count, err := paths.Diff(source, target, force, func(change paths.DiffChange) error {
 		callback(change)
 		print("- " + change.Kind.String() + " " + change.Path)
+		if change.Kind != paths.DiffUnchanged {
+			t.logger.Debugf("indexing %s note %s", change.Kind.String(), change.Path)
+		}
 		absPath := filepath.Join(t.path, change.Path)
 
 		switch change.Kind {
@@ -204,3 +293,18 @@ func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexin
 	print("")
 	return stats, wrap(err)
 }
+
+// isIncluded returns whether the given path matches one of the globs of the
+// additional files to index.
+func (t *indexTask) isIncluded(path string) (bool, error) {
+	for _, glob := range t.include {
+		matches, err := doublestar.PathMatch(glob, path)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to match include glob %s to %s", glob, path)
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
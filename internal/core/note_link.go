@@ -0,0 +1,143 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// LinkFromNoteOpts holds the options used to add a link to a note from an
+// existing source note.
+type LinkFromNoteOpts struct {
+	// Path to the source note which will receive the link.
+	SourcePath string
+	// Heading of the section in which the link is appended. The section is
+	// created at the end of the source note if it doesn't exist yet.
+	Section string
+	// Don't save the updated source note on the file system.
+	DryRun bool
+}
+
+// LinkFromNote appends a link to the target note in the given section of the
+// source note, and reindexes it.
+//
+// The source note is left untouched if it already links to the target. Returns
+// the updated content of the source note and whether it changed.
+func (n *Notebook) LinkFromNote(target Note, opts LinkFromNoteOpts) (content string, changed bool, err error) {
+	wrap := errors.Wrapperf("%s: failed to add link", opts.SourcePath)
+
+	sourcePath, err := n.fs.Abs(opts.SourcePath)
+	if err != nil {
+		return "", false, wrap(err)
+	}
+	if _, err = n.RelPath(sourcePath); err != nil {
+		return "", false, wrap(err)
+	}
+	source, err := n.fs.Read(sourcePath)
+	if err != nil {
+		return "", false, wrap(err)
+	}
+
+	formatter, err := n.NewLinkFormatter()
+	if err != nil {
+		return "", false, wrap(err)
+	}
+	link, err := n.FormatNoteLink(formatter, target.AsMinimalNote(), filepath.Dir(sourcePath))
+	if err != nil {
+		return "", false, wrap(err)
+	}
+
+	content, changed = appendLinkToSection(string(source), opts.Section, link)
+	if !changed || opts.DryRun {
+		return content, changed, nil
+	}
+
+	err = n.fs.Write(sourcePath, []byte(content))
+	if err != nil {
+		return "", false, wrap(err)
+	}
+
+	note, err := n.ParseNoteWithContent(sourcePath, []byte(content))
+	if err != nil {
+		return "", false, wrap(err)
+	}
+	err = n.index.Update(*note)
+	if err != nil {
+		return "", false, wrap(err)
+	}
+
+	return content, true, nil
+}
+
+// appendLinkToSection inserts the given link as a list item at the end of the
+// section with the given heading. If the content already contains the link,
+// it is returned unchanged.
+func appendLinkToSection(content string, section string, link string) (string, bool) {
+	if strings.Contains(content, link) {
+		return content, false
+	}
+
+	item := "- " + link
+	section = strings.TrimSpace(section)
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == section {
+			start = i
+			break
+		}
+	}
+
+	// The section doesn't exist yet, so we create it at the end of the note.
+	if start == -1 {
+		if strings.TrimSpace(content) == "" {
+			return section + "\n\n" + item + "\n", true
+		}
+		return strings.Join(lines, "\n") + "\n\n" + section + "\n\n" + item + "\n", true
+	}
+
+	// The section ends with the next heading of the same or a higher level.
+	level := headingLevel(section)
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if l := headingLevel(lines[i]); l > 0 && (level == 0 || l <= level) {
+			end = i
+			break
+		}
+	}
+
+	// Insert the link after the last non-blank line of the section.
+	insert := start + 1
+	for i := end - 1; i > start; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			insert = i + 1
+			break
+		}
+	}
+
+	res := make([]string, 0, len(lines)+2)
+	res = append(res, lines[:insert]...)
+	if insert == start+1 {
+		res = append(res, "")
+	}
+	res = append(res, item)
+	if insert == end && end < len(lines) {
+		res = append(res, "")
+	}
+	res = append(res, lines[insert:]...)
+
+	return strings.Join(res, "\n") + "\n", true
+}
+
+// headingLevel returns the level of the Markdown ATX heading on the given
+// line, or 0 if it is not a heading.
+func headingLevel(line string) int {
+	trimmed := strings.TrimLeft(line, "#")
+	level := len(line) - len(trimmed)
+	if level == 0 || level > 6 || (trimmed != "" && trimmed[0] != ' ' && trimmed[0] != '\t') {
+		return 0
+	}
+	return level
+}
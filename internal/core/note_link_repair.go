@@ -0,0 +1,224 @@
+package core
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/paths"
+)
+
+// BrokenLink is a relative link whose target note doesn't exist anymore, for
+// example after moving notes manually.
+type BrokenLink struct {
+	// Path to the note containing the link, relative to the notebook.
+	SourcePath string
+	// Destination of the link, as written in the note.
+	Href string
+	// Destination pointing to the moved note, or an empty string when the
+	// link can't be repaired.
+	NewHref string
+	// Paths of the notes which could be the target of the link.
+	Candidates []string
+}
+
+// IsRepairable returns whether a single note was found to repair the link.
+func (l BrokenLink) IsRepairable() bool {
+	return l.NewHref != ""
+}
+
+// FindBrokenLinks returns the relative Markdown links of the given notes
+// which don't resolve to an existing file.
+//
+// A link can be repaired when a single note of the notebook has the same
+// filename as its former target, or failing that, contains the ID of the
+// target in its filename.
+func (n *Notebook) FindBrokenLinks(notes []ContextualNote) ([]BrokenLink, error) {
+	wrap := errors.Wrapper("failed to find broken links")
+
+	allNotes, err := n.index.FindMinimal(NoteFindOpts{})
+	if err != nil {
+		return nil, wrap(err)
+	}
+	allPaths := make([]string, 0)
+	for _, note := range allNotes {
+		allPaths = append(allPaths, note.Path)
+	}
+
+	broken := make([]BrokenLink, 0)
+	for _, note := range notes {
+		wrap := errors.Wrapperf("%s: failed to find broken links", note.Path)
+		absPath := filepath.Join(n.Path, note.Path)
+		baseDir := filepath.Dir(absPath)
+
+		parsed, err := n.ParseNoteWithContent(absPath, []byte(note.RawContent))
+		if err != nil {
+			return nil, wrap(err)
+		}
+
+		seen := map[string]bool{}
+		for _, link := range parsed.Links {
+			target := strings.SplitN(link.Href, "#", 2)[0]
+			if link.Type != LinkTypeMarkdown || link.IsExternal || target == "" || filepath.IsAbs(target) || seen[link.Href] {
+				continue
+			}
+			seen[link.Href] = true
+
+			id, err := n.index.FindLinkMatch(baseDir, link.Href, link.Type)
+			if err != nil {
+				return nil, wrap(err)
+			}
+			if id.IsValid() {
+				continue
+			}
+			// Links to other kind of files are not broken as long as they
+			// exist.
+			exists, err := n.fs.FileExists(filepath.Join(baseDir, target))
+			if err != nil {
+				return nil, wrap(err)
+			}
+			if exists {
+				continue
+			}
+
+			brokenLink := BrokenLink{
+				SourcePath: note.Path,
+				Href:       link.Href,
+				Candidates: brokenLinkCandidates(target, allPaths),
+			}
+			if len(brokenLink.Candidates) == 1 {
+				brokenLink.NewHref = repairedLinkHref(note.Path, link.Href, brokenLink.Candidates[0])
+			}
+			broken = append(broken, brokenLink)
+		}
+	}
+
+	return broken, nil
+}
+
+// brokenLinkCandidates returns the paths of the notes which could be the
+// moved target of a broken link.
+func brokenLinkCandidates(target string, notePaths []string) []string {
+	filename := filepath.Base(target)
+	hasExt := filepath.Ext(filename) != ""
+
+	candidates := make([]string, 0)
+	for _, path := range notePaths {
+		if filepath.Base(path) == filename || (!hasExt && paths.FilenameStem(path) == filename) {
+			candidates = append(candidates, path)
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates
+	}
+
+	// Falls back on the note ID, in case the note was renamed as well.
+	id := paths.FilenameStem(filename)
+	for _, path := range notePaths {
+		if FilenameHasID(path, id) && (!hasExt || filepath.Ext(path) == filepath.Ext(filename)) {
+			candidates = append(candidates, path)
+		}
+	}
+	return candidates
+}
+
+// repairedLinkHref returns the destination of a link from the note at
+// sourcePath to the moved note at targetPath, keeping the style and anchor of
+// the original href.
+func repairedLinkHref(sourcePath string, href string, targetPath string) string {
+	parts := strings.SplitN(href, "#", 2)
+
+	newHref, err := filepath.Rel(filepath.Dir(sourcePath), targetPath)
+	if err != nil {
+		return ""
+	}
+	newHref = filepath.ToSlash(newHref)
+	if filepath.Ext(parts[0]) == "" {
+		newHref = strings.TrimSuffix(newHref, filepath.Ext(newHref))
+	}
+	if len(parts) == 2 {
+		newHref += "#" + parts[1]
+	}
+	return newHref
+}
+
+// RepairLinks rewrites the repairable broken links in their source notes, and
+// reindexes them. Returns the number of updated notes.
+func (n *Notebook) RepairLinks(links []BrokenLink) (int, error) {
+	sources := make([]string, 0)
+	hrefs := map[string]map[string]string{}
+	for _, link := range links {
+		if !link.IsRepairable() {
+			continue
+		}
+		if _, ok := hrefs[link.SourcePath]; !ok {
+			sources = append(sources, link.SourcePath)
+			hrefs[link.SourcePath] = map[string]string{}
+		}
+		hrefs[link.SourcePath][link.Href] = link.NewHref
+	}
+
+	count := 0
+	for _, source := range sources {
+		wrap := errors.Wrapperf("%s: failed to repair links", source)
+		absPath := filepath.Join(n.Path, source)
+
+		content, err := n.fs.Read(absPath)
+		if err != nil {
+			return count, wrap(err)
+		}
+		newContent := replaceMarkdownLinkHrefs(string(content), hrefs[source])
+		if newContent == string(content) {
+			continue
+		}
+
+		err = n.fs.Write(absPath, []byte(newContent))
+		if err != nil {
+			return count, wrap(err)
+		}
+		parsed, err := n.ParseNoteWithContent(absPath, []byte(newContent))
+		if err != nil {
+			return count, wrap(err)
+		}
+		err = n.index.Update(*parsed)
+		if err != nil {
+			return count, wrap(err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// replaceMarkdownLinkHrefs replaces the destinations of the Markdown links
+// found in the content, using the given map of old to new hrefs.
+func replaceMarkdownLinkHrefs(content string, hrefs map[string]string) string {
+	var out strings.Builder
+	last := 0
+	for _, match := range markdownLinkRegex.FindAllStringSubmatchIndex(content, -1) {
+		// Images are not note links.
+		if match[3] > match[2] {
+			continue
+		}
+		start, end := match[6], match[7]
+		rawHref := content[start:end]
+		href, err := url.PathUnescape(rawHref)
+		if err != nil {
+			continue
+		}
+		newHref, ok := hrefs[href]
+		if !ok {
+			continue
+		}
+		// Keeps the percent-encoding of the original href.
+		if rawHref != href {
+			newHref = strings.ReplaceAll(newHref, " ", "%20")
+		}
+		out.WriteString(content[last:start])
+		out.WriteString(newHref)
+		last = end
+	}
+	out.WriteString(content[last:])
+	return out.String()
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestBrokenLinkCandidates(t *testing.T) {
+	paths := []string{"a.md", "dir/b.md", "dir/c.md", "archive/c.md", "dir/id1 Title.md"}
+
+	test := func(target string, expected []string) {
+		assert.Equal(t, brokenLinkCandidates(target, paths), expected)
+	}
+
+	test("b.md", []string{"dir/b.md"})
+	test("../old/b.md", []string{"dir/b.md"})
+	// Without extension
+	test("b", []string{"dir/b.md"})
+	// A different extension doesn't match
+	test("b.txt", []string{})
+	// Ambiguous
+	test("c.md", []string{"dir/c.md", "archive/c.md"})
+	// Matching the ID of a renamed note
+	test("id1.md", []string{"dir/id1 Title.md"})
+	test("missing.md", []string{})
+}
+
+func TestRepairedLinkHref(t *testing.T) {
+	test := func(sourcePath string, href string, targetPath string, expected string) {
+		assert.Equal(t, repairedLinkHref(sourcePath, href, targetPath), expected)
+	}
+
+	test("a.md", "b.md", "dir/b.md", "dir/b.md")
+	test("dir/a.md", "b.md", "b.md", "../b.md")
+	test("dir/a.md", "b.md", "other/b.md", "../other/b.md")
+	// Keeps the anchor
+	test("a.md", "b.md#section", "dir/b.md", "dir/b.md#section")
+	// Keeps the missing extension
+	test("a.md", "b", "dir/b.md", "dir/b")
+	test("a.md", "b#section", "dir/b.md", "dir/b#section")
+}
+
+func TestReplaceMarkdownLinkHrefs(t *testing.T) {
+	hrefs := map[string]string{
+		"b.md":      "dir/b.md",
+		"c#intro":   "dir/c#intro",
+		"f note.md": "dir/f note.md",
+		"image.png": "dir/image.png",
+		"unused.md": "dir/unused.md",
+	}
+
+	test := func(content string, expected string) {
+		assert.Equal(t, replaceMarkdownLinkHrefs(content, hrefs), expected)
+	}
+
+	test("", "")
+	test("No links", "No links")
+	test(
+		"See [B](b.md) and [B again](b.md \"title\"), but not [other](other.md).",
+		"See [B](dir/b.md) and [B again](dir/b.md \"title\"), but not [other](other.md).",
+	)
+	test("[C](c#intro)", "[C](dir/c#intro)")
+	// Keeps the percent-encoding
+	test("[F](f%20note.md)", "[F](dir/f%20note.md)")
+	// Images are left untouched
+	test("![Image](image.png)", "![Image](image.png)")
+	// Wiki links are left untouched
+	test("[[b.md]]", "[[b.md]]")
+}
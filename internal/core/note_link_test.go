@@ -0,0 +1,49 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestAppendLinkToSection(t *testing.T) {
+	test := func(content string, expected string, expectedChanged bool) {
+		actual, changed := appendLinkToSection(content, "## Links", "[[child]]")
+		assert.Equal(t, actual, expected)
+		assert.Equal(t, changed, expectedChanged)
+	}
+
+	// Empty note
+	test("", "## Links\n\n- [[child]]\n", true)
+
+	// Missing section
+	test("# Parent\n\nContent\n", "# Parent\n\nContent\n\n## Links\n\n- [[child]]\n", true)
+
+	// Existing section at the end of the note
+	test("# Parent\n\n## Links\n\n- [[other]]\n", "# Parent\n\n## Links\n\n- [[other]]\n- [[child]]\n", true)
+
+	// Existing empty section
+	test("# Parent\n\n## Links\n", "# Parent\n\n## Links\n\n- [[child]]\n", true)
+
+	// Existing section followed by another one
+	test(
+		"# Parent\n\n## Links\n\n- [[other]]\n\n## Notes\n\nContent\n",
+		"# Parent\n\n## Links\n\n- [[other]]\n- [[child]]\n\n## Notes\n\nContent\n",
+		true,
+	)
+	test(
+		"## Links\n- [[other]]\n## Notes\n",
+		"## Links\n- [[other]]\n- [[child]]\n\n## Notes\n",
+		true,
+	)
+
+	// Sub-headings are part of the section
+	test(
+		"## Links\n\n### Sub\n\n- [[other]]\n",
+		"## Links\n\n### Sub\n\n- [[other]]\n- [[child]]\n",
+		true,
+	)
+
+	// Already linked
+	test("# Parent\n\nSee [[child]].\n", "# Parent\n\nSee [[child]].\n", false)
+}
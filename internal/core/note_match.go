@@ -0,0 +1,83 @@
+package core
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// matchSnippetWords is the number of words kept on each side of a match in
+// the snippets generated by MatchSnippet.
+const matchSnippetWords = 10
+
+// MatchSnippet returns an extract of the text surrounding the first match of
+// the regular expression, with the match wrapped in <zk:match> tags, similar
+// to the snippets of the full-text search.
+//
+// Returns an empty string when the pattern is invalid or doesn't match.
+func MatchSnippet(pattern string, text string) string {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return ""
+	}
+	loc := re.FindStringIndex(text)
+	if loc == nil || loc[0] == loc[1] {
+		return ""
+	}
+
+	before, truncatedBefore := lastWords(text[:loc[0]], matchSnippetWords)
+	after, truncatedAfter := firstWords(text[loc[1]:], matchSnippetWords)
+
+	snippet := before + "<zk:match>" + text[loc[0]:loc[1]] + "</zk:match>" + after
+	if truncatedBefore {
+		snippet = "…" + snippet
+	}
+	if truncatedAfter {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// lastWords returns the end of the text containing at most count words, and
+// whether the text was truncated.
+func lastWords(text string, count int) (string, bool) {
+	words := 0
+	inWord := false
+	for i := len(text); i > 0; {
+		r, size := utf8.DecodeLastRuneInString(text[:i])
+		if unicode.IsSpace(r) {
+			if inWord {
+				words++
+				if words == count {
+					return text[i:], true
+				}
+			}
+			inWord = false
+		} else {
+			inWord = true
+		}
+		i -= size
+	}
+	return text, false
+}
+
+// firstWords returns the start of the text containing at most count words,
+// and whether the text was truncated.
+func firstWords(text string, count int) (string, bool) {
+	words := 0
+	inWord := false
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if inWord {
+				words++
+				if words == count {
+					return text[:i], true
+				}
+			}
+			inWord = false
+		} else {
+			inWord = true
+		}
+	}
+	return text, false
+}
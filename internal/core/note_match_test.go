@@ -0,0 +1,40 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestMatchSnippet(t *testing.T) {
+	test := func(pattern string, text string, expected string) {
+		assert.Equal(t, MatchSnippet(pattern, text), expected)
+	}
+
+	test("TODO", "", "")
+	test("TODO", "Nothing to do", "")
+	test("TODO(", "TODO(", "")
+	// Empty matches are ignored.
+	test("x*", "Text", "")
+
+	test("TODO.*urgent", "TODO: this is urgent", "<zk:match>TODO: this is urgent</zk:match>")
+	test("(?i)todo", "A short TODO list", "A short <zk:match>TODO</zk:match> list")
+	test("b+", "aaa bbb ccc", "aaa <zk:match>bbb</zk:match> ccc")
+
+	// Only the first match is highlighted.
+	test("one", "one two one", "<zk:match>one</zk:match> two one")
+
+	// Long texts are truncated around the match.
+	test(
+		"match",
+		"1 2 3 4 5 6 7 8 9 10 11 12 match 1 2 3 4 5 6 7 8 9 10 11 12",
+		"…3 4 5 6 7 8 9 10 11 12 <zk:match>match</zk:match> 1 2 3 4 5 6 7 8 9 10…",
+	)
+	test(
+		"match",
+		"1 2 3 4 5 6 7 8 9 10 match 1 2 3 4 5 6 7 8 9 10",
+		"1 2 3 4 5 6 7 8 9 10 <zk:match>match</zk:match> 1 2 3 4 5 6 7 8 9 10",
+	)
+	// Unicode
+	test("été", "Un été à Paris", "Un <zk:match>été</zk:match> à Paris")
+}
@@ -1,69 +1,102 @@
 package core
 
 import (
+	"fmt"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/zk-org/zk/internal/util/errors"
 	"github.com/zk-org/zk/internal/util/opt"
 	"github.com/zk-org/zk/internal/util/paths"
+	"gopkg.in/yaml.v2"
 )
 
 type newNoteTask struct {
-	dir              Dir
-	title            string
-	content          string
-	date             time.Time
-	extra            map[string]string
-	env              map[string]string
+	// Absolute path to the root of the notebook.
+	notebookPath string
+	dir          Dir
+	title        string
+	content      string
+	date         time.Time
+	extra        map[string]string
+	env          map[string]string
+	siblings     []newNoteSibling
+	parent       *newNoteParent
+	// Heading of the section in which the link to the parent is appended.
+	linkSection      string
 	fs               FileStorage
 	filenameTemplate string
 	bodyTemplatePath opt.String
+	frontmatterBase  map[string]interface{}
+	frontmatterPath  opt.String
 	templates        TemplateLoader
 	genID            IDGenerator
-	dryRun           bool
+	// Reports whether a generated ID is already used by another note.
+	idTaken func(id string) bool
+	dryRun  bool
 }
 
-func (t *newNoteTask) execute() (string, string, error) {
+// execute generates the new note and returns its path, content and ID.
+func (t *newNoteTask) execute() (string, string, string, error) {
 	filenameTemplate, err := t.templates.LoadTemplate(t.filenameTemplate)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 
 	var contentTemplate Template = NullTemplate
 	if templatePath := t.bodyTemplatePath.Unwrap(); templatePath != "" {
 		contentTemplate, err = t.templates.LoadTemplateAt(templatePath)
 		if err != nil {
-			return "", "", err
+			return "", "", "", err
 		}
 	}
 
 	context := newNoteTemplateContext{
-		Title:   t.title,
-		Content: t.content,
-		Dir:     t.dir.Name,
-		Extra:   t.extra,
-		Now:     t.date,
-		Env:     t.env,
+		Title:    t.title,
+		Content:  t.content,
+		Dir:      t.dir.Name,
+		Extra:    t.extra,
+		Now:      t.date,
+		Env:      t.env,
+		Siblings: t.siblings,
+		Parent:   t.parent,
 	}
 
 	path, context, err := t.generatePath(context, filenameTemplate)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 
 	content, err := contentTemplate.Render(context)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
+	}
+	// Without a template, the provided content is the body of the note.
+	if t.bodyTemplatePath.IsNull() {
+		content = t.content
+	}
+
+	content, err = t.mergeFrontmatterBase(content, context)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// The template may already link to the parent, e.g. with {{parent.link}}.
+	if t.parent != nil {
+		content, _ = appendLinkToSection(content, t.linkSection, t.parent.Link)
 	}
 
 	if !t.dryRun {
 		err = t.fs.Write(path, []byte(content))
 		if err != nil {
-			return "", "", err
+			return "", "", "", err
 		}
 	}
 
-	return path, content, nil
+	return path, content, context.ID, nil
 }
 
 func (c *newNoteTask) generatePath(context newNoteTemplateContext, filenameTemplate Template) (string, newNoteTemplateContext, error) {
@@ -79,17 +112,32 @@ func (c *newNoteTask) generatePath(context newNoteTemplateContext, filenameTempl
 			return "", context, err
 		}
 
+		// The filename template may create subdirectories, but not outside
+		// of the notebook.
 		path = filepath.Join(c.dir.Path, filename)
+		if rel, err := filepath.Rel(c.notebookPath, path); err != nil || strings.HasPrefix(rel, "..") {
+			return "", context, fmt.Errorf("%s: the note filename must be inside the notebook", filename)
+		}
+
 		exists, err := c.fs.FileExists(path)
 		if err != nil {
 			return "", context, err
 		} else if !exists {
+			// The ID might be used by a note in another directory.
+			if c.idTaken != nil && c.idTaken(context.ID) {
+				continue
+			}
 			context.Filename = filepath.Base(path)
 			context.FilenameStem = paths.FilenameStem(path)
 			return path, context, nil
 		}
 	}
 
+	exists, err := c.fs.FileExists(path)
+	if err == nil && !exists {
+		return "", context, fmt.Errorf("failed to generate a unique note ID, try increasing note.id-length")
+	}
+
 	return "", context, ErrNoteExists{
 		Name: filepath.Join(c.dir.Name, filename),
 		Path: path,
@@ -107,4 +155,128 @@ type newNoteTemplateContext struct {
 	Extra        map[string]string
 	Now          time.Time
 	Env          map[string]string
+	// Notes already indexed in the directory of the new note.
+	Siblings []newNoteSibling
+	// Parent note given with --parent, if any.
+	Parent *newNoteParent
+}
+
+// newNoteSibling is a note found in the directory of a new note.
+type newNoteSibling struct {
+	Title        string
+	Path         string
+	Filename     string
+	FilenameStem string `handlebars:"filename-stem"`
+}
+
+// findSiblings returns the indexed notes located directly in the given
+// directory, ordered by path.
+func (n *Notebook) findSiblings(dir Dir) ([]newNoteSibling, error) {
+	opts := NoteFindOpts{
+		Sorters: []NoteSorter{{Field: NoteSortPath, Ascending: true}},
+	}
+	if dir.Name != "" {
+		opts.IncludeHrefs = []string{dir.Name}
+	}
+	notes, err := n.index.FindMinimal(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var siblings []newNoteSibling
+	for _, note := range notes {
+		if filepath.Dir(note.Path) != filepath.Clean(filepath.Join(".", dir.Name)) {
+			continue
+		}
+		siblings = append(siblings, newNoteSibling{
+			Title:        note.Title,
+			Path:         note.Path,
+			Filename:     filepath.Base(note.Path),
+			FilenameStem: paths.FilenameStem(note.Path),
+		})
+	}
+	return siblings, nil
+}
+
+// mergeFrontmatterBase adds the metadata of the frontmatter base from the
+// config to the frontmatter of the given rendered note content. Keys already
+// set by the note template win.
+func (t *newNoteTask) mergeFrontmatterBase(content string, context newNoteTemplateContext) (string, error) {
+	base := yaml.MapSlice{}
+
+	if path := t.frontmatterPath.Unwrap(); path != "" {
+		template, err := t.templates.LoadTemplateAt(path)
+		if err != nil {
+			return "", err
+		}
+		yml, err := template.Render(context)
+		if err != nil {
+			return "", err
+		}
+		err = yaml.Unmarshal([]byte(yml), &base)
+		if err != nil {
+			return "", errors.Wrapf(err, "%s: invalid frontmatter base", path)
+		}
+
+	} else {
+		keys := make([]string, 0, len(t.frontmatterBase))
+		for key := range t.frontmatterBase {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			base = append(base, yaml.MapItem{Key: key, Value: t.frontmatterBase[key]})
+		}
+	}
+
+	if len(base) == 0 {
+		return content, nil
+	}
+	return mergeFrontmatter(content, base)
+}
+
+// FrontmatterRegex matches the YAML frontmatter at the start of a note, with
+// its delimiters. The first group captures the YAML content.
+var FrontmatterRegex = regexp.MustCompile(`(?s)^---[ \t]*\r?\n(.*?\r?\n)?---[ \t]*(?:\r?\n|$)`)
+
+// mergeFrontmatter appends the items of base which are missing from the YAML
+// frontmatter of content. A frontmatter is created if the content has none.
+func mergeFrontmatter(content string, base yaml.MapSlice) (string, error) {
+	frontmatter := ""
+	body := content
+	loc := FrontmatterRegex.FindStringSubmatchIndex(content)
+	if loc != nil {
+		if loc[2] != -1 {
+			frontmatter = content[loc[2]:loc[3]]
+		}
+		body = content[loc[1]:]
+	} else {
+		body = "\n" + body
+	}
+
+	existing := yaml.MapSlice{}
+	if err := yaml.Unmarshal([]byte(frontmatter), &existing); err != nil {
+		return "", errors.Wrap(err, "invalid frontmatter in the note template")
+	}
+	keys := map[string]bool{}
+	for _, item := range existing {
+		keys[fmt.Sprint(item.Key)] = true
+	}
+
+	missing := yaml.MapSlice{}
+	for _, item := range base {
+		if !keys[fmt.Sprint(item.Key)] {
+			missing = append(missing, item)
+		}
+	}
+	if len(missing) == 0 {
+		return content, nil
+	}
+
+	yml, err := yaml.Marshal(missing)
+	if err != nil {
+		return "", err
+	}
+
+	return "---\n" + frontmatter + string(yml) + "---\n" + body, nil
 }
@@ -65,6 +65,51 @@ func TestNotebookNewNote(t *testing.T) {
 	})
 }
 
+func TestNotebookNewNoteMergesInlineFrontmatterBase(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+	}
+	test.setup()
+	test.templateLoader.SpyFile("fm.md", "---\ntitle: Note\nauthor: Jane\n---\n\n# Note\n")
+	test.config.Note.FrontmatterBase = map[string]interface{}{
+		"status": "draft",
+		"author": "Team",
+	}
+
+	_, err := test.run(NewNoteOpts{Template: opt.NewString("fm.md"), Date: now})
+	assert.Nil(t, err)
+
+	// The keys of the template win.
+	assert.Equal(t, test.fs.files["/notebook/filename.ext"], "---\ntitle: Note\nauthor: Jane\nstatus: draft\n---\n\n# Note\n")
+}
+
+func TestNotebookNewNoteMergesFrontmatterBaseFile(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+	}
+	test.setup()
+	test.templateLoader.SpyFile("base.yml", "status: draft\ntags: [inbox]\n")
+	test.config.Note.FrontmatterBasePath = opt.NewString("base.yml")
+
+	_, err := test.run(NewNoteOpts{Date: now})
+	assert.Nil(t, err)
+
+	// A frontmatter is added to notes without one.
+	assert.Equal(t, test.fs.files["/notebook/filename.ext"], "---\nstatus: draft\ntags:\n- inbox\n---\n\nbody")
+}
+
+func TestNotebookNewNoteRejectsInvalidFrontmatterBase(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+	}
+	test.setup()
+	test.templateLoader.SpyFile("base.yml", "status: [draft\n")
+	test.config.Note.FrontmatterBasePath = opt.NewString("base.yml")
+
+	_, err := test.run(NewNoteOpts{Date: now})
+	assert.Err(t, err, "base.yml: invalid frontmatter base")
+}
+
 func TestNotebookNewNoteWithDefaultTitle(t *testing.T) {
 	test := newNoteTest{
 		rootDir: "/notebook",
@@ -87,6 +132,45 @@ func TestNotebookNewNoteWithDefaultTitle(t *testing.T) {
 	})
 }
 
+func TestNotebookNewNoteBackfilledWithDate(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+	}
+	test.setup()
+	backfill := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+
+	note, err := test.run(NewNoteOpts{Date: backfill})
+	assert.Nil(t, err)
+
+	// The templates receive the custom date.
+	assert.Equal(t, test.bodyTemplate.Contexts[0].(newNoteTemplateContext).Now, backfill)
+
+	// The index records the custom date as the creation date.
+	assert.Equal(t, note.Created, backfill)
+	assert.Equal(t, len(test.index.Added), 1)
+	assert.Equal(t, test.index.Added[0].Created, backfill)
+}
+
+func TestNotebookNewNoteBackfilledWithFrontmatterDate(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+	}
+	test.setup()
+	test.parseContentAsNote("body", &NoteContent{
+		Metadata: map[string]interface{}{
+			"created": "2005-06-07 08:09",
+		},
+	})
+
+	note, err := test.run(NewNoteOpts{Date: time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)})
+	assert.Nil(t, err)
+
+	// The creation date written by the template in the frontmatter wins.
+	expected := time.Date(2005, 6, 7, 8, 9, 0, 0, time.UTC)
+	assert.Equal(t, note.Created, expected)
+	assert.Equal(t, test.index.Added[0].Created, expected)
+}
+
 func TestNotebookNewNoteInUnknownDir(t *testing.T) {
 	test := newNoteTest{
 		rootDir: "/notebook",
@@ -329,6 +413,22 @@ func TestNotebookNewNoteWithCustomTemplate(t *testing.T) {
 	assert.Equal(t, test.fs.files["/notebook/"+note.Path], "custom body template")
 }
 
+func TestNotebookNewNoteWithContentWithoutTemplate(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+	}
+	test.setup()
+	test.config.Note.BodyTemplatePath = opt.NullString
+
+	note, err := test.run(NewNoteOpts{
+		Content: "Piped content\n",
+		Date:    now,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, test.fs.files["/notebook/"+note.Path], "Piped content\n")
+}
+
 // Tries to generate a filename until one is free.
 func TestNotebookNewNoteTriesUntilFreePath(t *testing.T) {
 	test := newNoteTest{
@@ -356,6 +456,75 @@ func TestNotebookNewNoteTriesUntilFreePath(t *testing.T) {
 	assert.Equal(t, test.fs.files["/notebook/filename4.ext"], "body")
 }
 
+// The filename template can create the note in nested subdirectories.
+func TestNotebookNewNoteWithNestedFilename(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+		dirs:    []string{"/notebook/a-dir"},
+		filenameTemplateRender: func(context newNoteTemplateContext) string {
+			return "2021/01/" + context.ID + ".ext"
+		},
+	}
+	test.setup()
+
+	note, err := test.run(NewNoteOpts{
+		Directory: opt.NewString("a-dir"),
+		Date:      now,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, note.Path, "a-dir/2021/01/id.ext")
+	assert.Equal(t, test.fs.files["/notebook/a-dir/2021/01/id.ext"], "body")
+
+	assert.Equal(t, test.bodyTemplate.Contexts[0].(newNoteTemplateContext).Filename, "id.ext")
+}
+
+func TestNotebookNewNoteErrorWhenFilenameIsOutsideNotebook(t *testing.T) {
+	test := func(filename string) {
+		test := newNoteTest{
+			rootDir: "/notebook",
+			dirs:    []string{"/notebook/a-dir"},
+			filenameTemplateRender: func(context newNoteTemplateContext) string {
+				return filename
+			},
+		}
+		test.setup()
+
+		_, err := test.run(NewNoteOpts{
+			Directory: opt.NewString("a-dir"),
+			Date:      now,
+		})
+
+		assert.Err(t, err, filename+": the note filename must be inside the notebook")
+		assert.Equal(t, test.fs.files, map[string]string{})
+	}
+
+	test("../../outside.ext")
+	test("sub/../../../outside.ext")
+	test("../../notebook-sibling/note.ext")
+}
+
+// Traversing a parent directory is allowed as long as the note stays in the
+// notebook.
+func TestNotebookNewNoteWithFilenameInParentDir(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+		dirs:    []string{"/notebook/a-dir"},
+		filenameTemplateRender: func(context newNoteTemplateContext) string {
+			return "../inbox/" + context.ID + ".ext"
+		},
+	}
+	test.setup()
+
+	note, err := test.run(NewNoteOpts{
+		Directory: opt.NewString("a-dir"),
+		Date:      now,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, note.Path, "inbox/id.ext")
+}
+
 func TestNotebookNewNoteErrorWhenNoFreePath(t *testing.T) {
 	files := map[string]string{}
 	for i := 1; i < 51; i++ {
@@ -379,6 +548,115 @@ func TestNotebookNewNoteErrorWhenNoFreePath(t *testing.T) {
 	assert.Equal(t, test.fs.files, files)
 }
 
+func TestNotebookNewNoteRetriesWhenIDIsIndexed(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+		filenameTemplateRender: func(context newNoteTemplateContext) string {
+			return "filename" + context.ID + ".ext"
+		},
+		idGeneratorFactory: incrementingID,
+		// Notes in other directories are not found by the file system check.
+		indexedPaths: []string{"dir/1.md", "dir/filename-2-title.md"},
+	}
+	test.setup()
+
+	note, err := test.run(NewNoteOpts{
+		Date: now,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, note.Path, "filename3.ext")
+}
+
+func TestNotebookNewNoteErrorWhenNoUniqueID(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+		filenameTemplateRender: func(context newNoteTemplateContext) string {
+			return "filename" + context.ID + ".ext"
+		},
+		indexedPaths: []string{"dir/id.md"},
+	}
+	test.setup()
+
+	_, err := test.run(NewNoteOpts{
+		Date: now,
+	})
+
+	assert.Err(t, err, "failed to generate a unique note ID, try increasing note.id-length")
+}
+
+func TestNotebookNewNoteWithTimestampID(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+		filenameTemplateRender: func(context newNoteTemplateContext) string {
+			return context.ID + ".ext"
+		},
+		idOptions: &IDOptions{
+			Strategy: IDStrategyTimestamp,
+		},
+		indexedPaths: []string{"200911172034.md"},
+	}
+	test.setup()
+
+	note, err := test.run(NewNoteOpts{
+		Date: now,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, note.Path, "200911172035.ext")
+}
+
+func TestNotebookNewNoteWithSequenceIDDoesntReuseDeletedIDs(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+		filenameTemplateRender: func(context newNoteTemplateContext) string {
+			return context.ID + ".ext"
+		},
+		idOptions: &IDOptions{
+			Strategy: IDStrategySequence,
+			Length:   3,
+		},
+	}
+	test.setup()
+
+	note, err := test.run(NewNoteOpts{Date: now})
+	assert.Nil(t, err)
+	assert.Equal(t, note.Path, "001.ext")
+	note, err = test.run(NewNoteOpts{Date: now})
+	assert.Nil(t, err)
+	assert.Equal(t, note.Path, "002.ext")
+	assert.Equal(t, test.fs.files["/notebook/.zk/id-sequence"], "2\n")
+
+	// The last note is deleted.
+	delete(test.fs.files, "/notebook/002.ext")
+
+	note, err = test.run(NewNoteOpts{Date: now})
+	assert.Nil(t, err)
+	assert.Equal(t, note.Path, "003.ext")
+	assert.Equal(t, test.fs.files["/notebook/.zk/id-sequence"], "3\n")
+}
+
+func TestNotebookNewNoteWithSequenceIDDryRun(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+		filenameTemplateRender: func(context newNoteTemplateContext) string {
+			return context.ID + ".ext"
+		},
+		idOptions: &IDOptions{
+			Strategy: IDStrategySequence,
+		},
+		files: map[string]string{
+			"/notebook/.zk/id-sequence": "41\n",
+		},
+	}
+	test.setup()
+
+	note, err := test.run(NewNoteOpts{Date: now, DryRun: true})
+	assert.Nil(t, err)
+	assert.Equal(t, note.Path, "42.ext")
+	assert.Equal(t, test.fs.files["/notebook/.zk/id-sequence"], "41\n")
+}
+
 var now = time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
 
 // newNoteTest builds and runs the SUT for new note test cases.
@@ -396,6 +674,8 @@ type newNoteTest struct {
 	filenameTemplate       *templateSpy
 	bodyTemplate           *templateSpy
 	idGeneratorFactory     IDGeneratorFactory
+	idOptions              *IDOptions
+	indexedPaths           []string
 	osEnv                  map[string]string
 
 	receivedLang   string
@@ -415,7 +695,7 @@ func (t *newNoteTest) setup() {
 		t.fs.files = t.files
 	}
 
-	t.index = &noteIndexAddMock{ReturnedID: 42}
+	t.index = &noteIndexAddMock{ReturnedID: 42, Paths: t.indexedPaths}
 	t.parser = newNoteContentParserMock(map[string]*NoteContent{})
 
 	t.templateLoader = newTemplateLoaderMock()
@@ -445,6 +725,15 @@ func (t *newNoteTest) setup() {
 		t.groups = map[string]GroupConfig{}
 	}
 
+	idOptions := IDOptions{
+		Length:  42,
+		Charset: []rune("hello"),
+		Case:    CaseUpper,
+	}
+	if t.idOptions != nil {
+		idOptions = *t.idOptions
+	}
+
 	t.config = Config{
 		Note: NoteConfig{
 			FilenameTemplate: "filename",
@@ -452,11 +741,7 @@ func (t *newNoteTest) setup() {
 			BodyTemplatePath: opt.NewString("default"),
 			Lang:             "fr",
 			DefaultTitle:     "Titre par défaut",
-			IDOptions: IDOptions{
-				Length:  42,
-				Charset: []rune("hello"),
-				Case:    CaseUpper,
-			},
+			IDOptions:        idOptions,
 		},
 		Groups: t.groups,
 		Extra: map[string]string{
@@ -470,7 +755,11 @@ func (t *newNoteTest) parseContentAsNote(content string, note *NoteContent) {
 }
 
 func (t *newNoteTest) run(opts NewNoteOpts) (*Note, error) {
-	notebook := NewNotebook(t.rootDir, t.config, NotebookPorts{
+	return t.notebook().NewNote(opts)
+}
+
+func (t *newNoteTest) notebook() *Notebook {
+	return NewNotebook(t.rootDir, t.config, NotebookPorts{
 		TemplateLoaderFactory: func(language string) (TemplateLoader, error) {
 			t.receivedLang = language
 			return t.templateLoader, nil
@@ -485,8 +774,6 @@ func (t *newNoteTest) run(opts NewNoteOpts) (*Note, error) {
 		Logger:            &util.NullLogger,
 		OSEnv:             func() map[string]string { return t.osEnv },
 	})
-
-	return notebook.NewNote(opts)
 }
 
 // incrementingID returns a generator of incrementing string ID.
@@ -500,6 +787,10 @@ func incrementingID(opts IDOptions) func() string {
 
 type noteIndexAddMock struct {
 	ReturnedID NoteID
+	// Notes added to the index.
+	Added []Note
+	// Paths of the notes already indexed.
+	Paths []string
 }
 
 func (m *noteIndexAddMock) Find(opts NoteFindOpts) ([]ContextualNote, error)     { return nil, nil }
@@ -510,13 +801,43 @@ func (m *noteIndexAddMock) FindLinkMatch(baseDir string, href string, linkType L
 func (m *noteIndexAddMock) FindLinksBetweenNotes(ids []NoteID) ([]ResolvedLink, error) {
 	return nil, nil
 }
+func (m *noteIndexAddMock) FindLinksOfNotes(ids []NoteID) ([]ResolvedLink, error) {
+	return nil, nil
+}
+func (m *noteIndexAddMock) FindHeadings(regex string, limit int) ([]HeadingMatch, error) {
+	return []HeadingMatch{}, nil
+}
+
+func (m *noteIndexAddMock) FindTagStarts(id NoteID) (map[string]int, error) {
+	return nil, nil
+}
+func (m *noteIndexAddMock) FindAmbiguousLinks(id NoteID) ([]AmbiguousLink, error) {
+	return nil, nil
+}
 func (m *noteIndexAddMock) FindCollections(kind CollectionKind, sorters []CollectionSorter) ([]Collection, error) {
 	return nil, nil
 }
-func (m *noteIndexAddMock) IndexedPaths() (<-chan paths.Metadata, error)       { return nil, nil }
-func (m *noteIndexAddMock) Add(note Note) (NoteID, error)                      { return m.ReturnedID, nil }
-func (m *noteIndexAddMock) Update(note Note) error                             { return nil }
-func (m *noteIndexAddMock) Remove(path string) error                           { return nil }
-func (m *noteIndexAddMock) Commit(transaction func(idx NoteIndex) error) error { return nil }
-func (m *noteIndexAddMock) NeedsReindexing() (bool, error)                     { return false, nil }
-func (m *noteIndexAddMock) SetNeedsReindexing(needsReindexing bool) error      { return nil }
+func (m *noteIndexAddMock) FindNestedCollections(kind CollectionKind, sorters []CollectionSorter) ([]Collection, error) {
+	return nil, nil
+}
+func (m *noteIndexAddMock) IndexedPaths() (<-chan paths.Metadata, error) {
+	c := make(chan paths.Metadata, len(m.Paths))
+	for _, path := range m.Paths {
+		c <- paths.Metadata{Path: path}
+	}
+	close(c)
+	return c, nil
+}
+func (m *noteIndexAddMock) Add(note Note) (NoteID, error) {
+	m.Added = append(m.Added, note)
+	return m.ReturnedID, nil
+}
+func (m *noteIndexAddMock) Update(note Note) error   { return nil }
+func (m *noteIndexAddMock) Remove(path string) error { return nil }
+func (m *noteIndexAddMock) Commit(transaction func(idx NoteIndex) error) error {
+	return transaction(m)
+}
+func (m *noteIndexAddMock) NeedsReindexing() (bool, error)                { return false, nil }
+func (m *noteIndexAddMock) SetNeedsReindexing(needsReindexing bool) error { return nil }
+func (m *noteIndexAddMock) Reset() error                                  { return nil }
+func (m *noteIndexAddMock) Close() error                                  { return nil }
@@ -0,0 +1,27 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+)
+
+// OccurrencesFilter is a note filter matching the notes containing at least
+// Min occurrences of the given Term.
+type OccurrencesFilter struct {
+	Term string
+	Min  int
+}
+
+// CountOccurrences returns the number of occurrences of the term in the given
+// text, as whole words and ignoring the case.
+func CountOccurrences(text string, term string) int {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return 0
+	}
+	re, err := regexp.Compile("(?i)" + wholeWordPattern(term))
+	if err != nil {
+		return 0
+	}
+	return len(re.FindAllStringIndex(text, -1))
+}
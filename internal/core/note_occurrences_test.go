@@ -0,0 +1,24 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestCountOccurrences(t *testing.T) {
+	test := func(text string, term string, expected int) {
+		assert.Equal(t, CountOccurrences(text, term), expected)
+	}
+
+	test("", "rust", 0)
+	test("Rust is great", "", 0)
+	test("Rust is great", "  ", 0)
+	test("Rust is great, I like rust and RUST.", "rust", 3)
+	// Only whole words are counted.
+	test("Rustacean trust rust_lang rust", "rust", 1)
+	test("borrow checker, the borrow-checker", "borrow checker", 1)
+	// Terms starting or ending with punctuation.
+	test("Use @todo or @TODO, not @todos", "@todo", 2)
+	test("C++ and c++, not C", "c++", 2)
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zk-org/zk/internal/util/paths"
+)
+
+// newNoteParent is the parent of a new note, available in the templates as
+// {{parent}}.
+type newNoteParent struct {
+	ID    string `handlebars:"id"`
+	Title string
+	Path  string
+	// Link to the parent note, from the directory of the new note.
+	Link string
+}
+
+// findParentNote returns the indexed note at the given path, relative to the
+// working directory.
+func (n *Notebook) findParentNote(path string) (*MinimalNote, error) {
+	absPath, err := n.fs.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	relPath, err := n.RelPath(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := n.index.FindMinimal(NoteFindOpts{IncludeHrefs: []string{relPath}})
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		if note.Path == relPath {
+			return &note, nil
+		}
+	}
+	return nil, ErrNoteNotFound{Path: path}
+}
+
+// newNoteParent creates the template context of the given parent note, for a
+// new note created in dir.
+func (n *Notebook) newNoteParent(parent MinimalNote, dir Dir) (*newNoteParent, error) {
+	formatter, err := n.NewLinkFormatter()
+	if err != nil {
+		return nil, err
+	}
+	link, err := n.FormatNoteLink(formatter, parent, dir.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &newNoteParent{
+		ID:    noteIDOf(parent),
+		Title: parent.Title,
+		Path:  parent.Path,
+		Link:  link,
+	}, nil
+}
+
+var leadingIDRegex = regexp.MustCompile(`^[\pL\pN]+`)
+
+// noteIDOf returns the ID of the given note, from the `id` key of its
+// frontmatter or else the leading word of its filename.
+func noteIDOf(note MinimalNote) string {
+	if id, ok := note.Metadata["id"]; ok && id != nil && fmt.Sprint(id) != "" {
+		return fmt.Sprint(id)
+	}
+	return leadingIDRegex.FindString(paths.FilenameStem(note.Path))
+}
+
+// newChildIDGenerator creates a generator of Folgezettel IDs for the children
+// of the note with the given ID, alternating numbers and letters: `1a`, `1b`,
+// etc. under `1`, and `1a1`, `1a2`, etc. under `1a`. The IDs already taken are
+// skipped.
+func newChildIDGenerator(parentID string, idTaken func(id string) bool) IDGenerator {
+	last, _ := utf8.DecodeLastRuneInString(parentID)
+	letters := unicode.IsDigit(last)
+	i := 0
+
+	return func() string {
+		for {
+			i++
+			id := parentID + childIDSuffix(i, letters)
+			if idTaken == nil || !idTaken(id) {
+				return id
+			}
+		}
+	}
+}
+
+// childIDSuffix returns the suffix of the nth child ID, starting from 1. The
+// letters continue after `z` with `aa`, `ab`, etc.
+func childIDSuffix(n int, letters bool) string {
+	if !letters {
+		return strconv.Itoa(n)
+	}
+	suffix := ""
+	for n > 0 {
+		n--
+		suffix = string(rune('a'+n%26)) + suffix
+		n /= 26
+	}
+	return suffix
+}
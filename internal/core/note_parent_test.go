@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestNoteIDOf(t *testing.T) {
+	test := func(path string, metadata map[string]interface{}, expected string) {
+		assert.Equal(t, noteIDOf(MinimalNote{Path: path, Metadata: metadata}), expected)
+	}
+
+	test("1a.md", nil, "1a")
+	test("dir/1a2 Some title.md", nil, "1a2")
+	test("1a-title.md", nil, "1a")
+	test("1a.md", map[string]interface{}{"id": "2b"}, "2b")
+	test("1a.md", map[string]interface{}{"id": 12}, "12")
+	test("1a.md", map[string]interface{}{"id": ""}, "1a")
+}
+
+func TestChildIDGenerator(t *testing.T) {
+	test := func(parentID string, taken []string, expected ...string) {
+		idTaken := func(id string) bool {
+			for _, t := range taken {
+				if t == id {
+					return true
+				}
+			}
+			return false
+		}
+		gen := newChildIDGenerator(parentID, idTaken)
+		actual := []string{}
+		for range expected {
+			actual = append(actual, gen())
+		}
+		assert.Equal(t, actual, expected)
+	}
+
+	test("1", nil, "1a", "1b", "1c")
+	test("1a", nil, "1a1", "1a2", "1a3")
+	test("1a12", nil, "1a12a", "1a12b")
+	test("1", []string{"1a", "1c"}, "1b", "1d")
+	test("1a", []string{"1a1"}, "1a2")
+}
+
+func TestChildIDSuffix(t *testing.T) {
+	assert.Equal(t, childIDSuffix(1, true), "a")
+	assert.Equal(t, childIDSuffix(26, true), "z")
+	assert.Equal(t, childIDSuffix(27, true), "aa")
+	assert.Equal(t, childIDSuffix(28, true), "ab")
+	assert.Equal(t, childIDSuffix(702, true), "zz")
+	assert.Equal(t, childIDSuffix(703, true), "aaa")
+	assert.Equal(t, childIDSuffix(1, false), "1")
+	assert.Equal(t, childIDSuffix(12, false), "12")
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/zk-org/zk/internal/util/opt"
 	strutil "github.com/zk-org/zk/internal/util/strings"
 	"github.com/relvacode/iso8601"
+	"golang.org/x/text/encoding"
 	"gopkg.in/djherbis/times.v1"
 )
 
@@ -32,10 +33,18 @@ type NoteContent struct {
 	Lead opt.String
 	// Body is the content of the note, including the Lead but without the Title.
 	Body opt.String
+	// Plain is the Body rendered as plain text, without the Markdown syntax.
+	Plain opt.String
 	// Tags is the list of tags found in the note content.
 	Tags []string
+	// TagStarts holds the byte offset of the first occurrence of each tag in
+	// the content. Tags only declared in the frontmatter are missing.
+	TagStarts map[string]int
 	// Links is the list of outbound links found in the note.
 	Links []Link
+	// Headings is the list of headings found in the note, including the
+	// title heading.
+	Headings []Heading
 	// Additional metadata. For example, extracted from a YAML frontmatter.
 	Metadata map[string]interface{}
 }
@@ -60,8 +69,15 @@ func (n *Notebook) ParseNoteWithContent(absPath string, content []byte) (*Note,
 		return nil, wrap(err)
 	}
 
-	contentStr := string(content)
-	contentParts, err := n.Parser.ParseNoteContent(contentStr)
+	contentStr, err := n.decodeNoteContent(relPath, content)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	parser, err := n.contentParserFor(relPath)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	contentParts, err := parser.ParseNoteContent(contentStr)
 	if err != nil {
 		return nil, wrap(err)
 	}
@@ -71,10 +87,14 @@ func (n *Notebook) ParseNoteWithContent(absPath string, content []byte) (*Note,
 		Title:      contentParts.Title.String(),
 		Lead:       contentParts.Lead.String(),
 		Body:       contentParts.Body.String(),
+		Plain:      contentParts.Plain.String(),
 		RawContent: contentStr,
 		WordCount:  len(strings.Fields(contentStr)),
 		Links:      make([]Link, 0),
 		Tags:       contentParts.Tags,
+		TagStarts:  contentParts.TagStarts,
+		Headings:   contentParts.Headings,
+		Domains:    LinkDomains(contentParts.Links),
 		Metadata:   contentParts.Metadata,
 		Checksum:   fmt.Sprintf("%x", sha256.Sum256(content)),
 	}
@@ -101,22 +121,80 @@ func (n *Notebook) ParseNoteWithContent(absPath string, content []byte) (*Note,
 	return &note, nil
 }
 
-func creationDateFrom(metadata map[string]interface{}, times times.Timespec) time.Time {
-	// Read the creation date from the YAML frontmatter `date` key.
-	if dateVal, ok := metadata["date"]; ok {
-		if dateStr, ok := dateVal.(string); ok {
-			if time, err := iso8601.ParseString(dateStr); err == nil {
-				return time
-			}
-			// Omitting the `T` is common
-			if time, err := time.Parse("2006-01-02 15:04:05", dateStr); err == nil {
-				return time
-			}
-			if time, err := time.Parse("2006-01-02 15:04", dateStr); err == nil {
-				return time
-			}
+// contentParserFor returns the parser of the file at relPath. The notes are
+// parsed with the notebook Parser, while the additional files included in the
+// index use the parser registered for their extension, or else plain text.
+func (n *Notebook) contentParserFor(relPath string) (NoteContentParser, error) {
+	group, err := n.Config.GroupConfigForPath(relPath)
+	if err != nil {
+		return nil, err
+	}
+	ext := strings.TrimPrefix(filepath.Ext(relPath), ".")
+	if ext == group.Note.Extension {
+		return n.Parser, nil
+	}
+	if parser, ok := n.parsers[ext]; ok {
+		return parser, nil
+	}
+	return plainTextParser{}, nil
+}
+
+// plainTextParser parses the content of a file as plain text, without title
+// nor metadata.
+type plainTextParser struct{}
+
+func (p plainTextParser) ParseNoteContent(content string) (*NoteContent, error) {
+	lead := strings.TrimSpace(content)
+	if i := strings.Index(lead, "\n\n"); i != -1 {
+		lead = lead[:i]
+	}
+
+	return &NoteContent{
+		Title:     opt.NullString,
+		Lead:      opt.NewNotEmptyString(lead),
+		Body:      opt.NewNotEmptyString(content),
+		Plain:     opt.NewNotEmptyString(content),
+		Tags:      []string{},
+		TagStarts: map[string]int{},
+		Links:     []Link{},
+		Headings:  []Heading{},
+		Metadata:  map[string]interface{}{},
+	}, nil
+}
+
+// decodeNoteContent converts the content of the note at relPath to UTF-8,
+// using the `note.encoding` setting of its group as a fallback.
+func (n *Notebook) decodeNoteContent(relPath string, content []byte) (string, error) {
+	group, err := n.Config.GroupConfigForPath(relPath)
+	if err != nil {
+		return "", err
+	}
+	var fallback encoding.Encoding
+	if group.Note.Encoding != "" {
+		fallback, err = EncodingFromString(group.Note.Encoding)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	text, lossy, err := decodeNoteContent(content, fallback)
+	if err != nil {
+		return "", err
+	}
+	if lossy {
+		if fallback == nil {
+			n.logger.Err(fmt.Errorf("%s: the note is not valid UTF-8, set `note.encoding` in the config to read it with another encoding", relPath))
+		} else {
+			n.logger.Err(fmt.Errorf("%s: some characters could not be decoded with the %s encoding", relPath, group.Note.Encoding))
 		}
 	}
+	return text, nil
+}
+
+func creationDateFrom(metadata map[string]interface{}, times times.Timespec) time.Time {
+	if date, ok := creationDateFromMetadata(metadata); ok {
+		return date
+	}
 
 	if times.HasBirthTime() {
 		return times.BirthTime().UTC()
@@ -124,3 +202,26 @@ func creationDateFrom(metadata map[string]interface{}, times times.Timespec) tim
 
 	return time.Now().UTC()
 }
+
+// creationDateFromMetadata reads the creation date from the YAML frontmatter
+// `date` or `created` keys.
+func creationDateFromMetadata(metadata map[string]interface{}) (time.Time, bool) {
+	for _, key := range []string{"date", "created"} {
+		switch date := metadata[key].(type) {
+		case time.Time:
+			return date, true
+		case string:
+			if t, err := iso8601.ParseString(date); err == nil {
+				return t, true
+			}
+			// Omitting the `T` is common
+			if t, err := time.Parse("2006-01-02 15:04:05", date); err == nil {
+				return t, true
+			}
+			if t, err := time.Parse("2006-01-02 15:04", date); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -1,5 +1,12 @@
 package core
 
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/opt"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
 type noteContentParserMock struct {
 	results map[string]*NoteContent
 }
@@ -16,3 +23,43 @@ func (p *noteContentParserMock) ParseNoteContent(content string) (*NoteContent,
 	}
 	return &NoteContent{}, nil
 }
+
+func TestContentParserFor(t *testing.T) {
+	notes := newNoteContentParserMock(map[string]*NoteContent{})
+	markdown := newNoteContentParserMock(map[string]*NoteContent{})
+	config := NewDefaultConfig()
+	config.Groups["canvas"] = GroupConfig{
+		Paths: []string{"boards"},
+		Note:  NoteConfig{Extension: "canvas"},
+	}
+	notebook := &Notebook{
+		Config:  config,
+		Parser:  notes,
+		parsers: map[string]NoteContentParser{"markdown": markdown},
+	}
+
+	test := func(path string, expected NoteContentParser) {
+		t.Helper()
+		parser, err := notebook.contentParserFor(path)
+		assert.Nil(t, err)
+		assert.Equal(t, parser, expected)
+	}
+
+	test("note.md", notes)
+	test("dir/note.md", notes)
+	test("boards/board.canvas", notes)
+	test("board.canvas", plainTextParser{})
+	test("readme.markdown", markdown)
+	test("data.json", plainTextParser{})
+}
+
+func TestPlainTextParser(t *testing.T) {
+	content, err := plainTextParser{}.ParseNoteContent("\nFirst paragraph\non two lines.\n\nSecond paragraph.\n")
+	assert.Nil(t, err)
+	assert.Equal(t, content.Title, opt.NullString)
+	assert.Equal(t, content.Lead, opt.NewString("First paragraph\non two lines."))
+	assert.Equal(t, content.Body, opt.NewString("\nFirst paragraph\non two lines.\n\nSecond paragraph.\n"))
+	assert.Equal(t, content.Plain, content.Body)
+	assert.Equal(t, content.Tags, []string{})
+	assert.Equal(t, content.Links, []Link{})
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// EmptyNote is a note without enough content to be kept, found by
+// FindEmptyNotes.
+type EmptyNote struct {
+	ContextualNote
+	// Paths of the other notes linking to this one, which would have dead
+	// links if the note is deleted.
+	Backlinks []string
+}
+
+// FindEmptyNotes returns the notes whose body, without the title and
+// frontmatter, has fewer than minWords words.
+//
+// The backlinks of each empty note are found as well, except the links from
+// the other empty notes.
+func (n *Notebook) FindEmptyNotes(notes []ContextualNote, minWords int) ([]EmptyNote, error) {
+	wrap := errors.Wrapper("failed to find the empty notes")
+
+	empties := make([]EmptyNote, 0)
+	emptyIDs := map[NoteID]int{}
+	ids := make([]NoteID, 0)
+	for _, note := range notes {
+		if len(strings.Fields(note.Body)) >= minWords {
+			continue
+		}
+		emptyIDs[note.ID] = len(empties)
+		ids = append(ids, note.ID)
+		empties = append(empties, EmptyNote{ContextualNote: note})
+	}
+	if len(empties) == 0 {
+		return empties, nil
+	}
+
+	links, err := n.index.FindLinksOfNotes(ids)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	for _, link := range links {
+		i, isTarget := emptyIDs[link.TargetID]
+		if _, isEmptySource := emptyIDs[link.SourceID]; !isTarget || isEmptySource {
+			continue
+		}
+		empties[i].Backlinks = appendUnique(empties[i].Backlinks, link.SourcePath)
+	}
+	for _, empty := range empties {
+		sort.Strings(empty.Backlinks)
+	}
+
+	return empties, nil
+}
+
+func appendUnique(items []string, item string) []string {
+	for _, i := range items {
+		if i == item {
+			return items
+		}
+	}
+	return append(items, item)
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestFindEmptyNotes(t *testing.T) {
+	index := &noteIndexLinksMock{links: []ResolvedLink{
+		{SourceID: 3, SourcePath: "full.md", TargetID: 1, TargetPath: "empty.md"},
+		{SourceID: 4, SourcePath: "other.md", TargetID: 1, TargetPath: "empty.md"},
+		// Duplicate link
+		{SourceID: 3, SourcePath: "full.md", TargetID: 1, TargetPath: "empty.md"},
+		// Link from another empty note
+		{SourceID: 2, SourcePath: "stub.md", TargetID: 1, TargetPath: "empty.md"},
+		// Outgoing link
+		{SourceID: 2, SourcePath: "stub.md", TargetID: 3, TargetPath: "full.md"},
+	}}
+	notebook := NewNotebook("/notebook", NewDefaultConfig(), NotebookPorts{
+		NoteIndex: index,
+	})
+
+	notes := []ContextualNote{
+		{Note: Note{ID: 1, Path: "empty.md", Title: "Empty note", Body: ""}},
+		{Note: Note{ID: 2, Path: "stub.md", Body: "Only three words."}},
+		{Note: Note{ID: 3, Path: "full.md", Body: "A note with enough content to be kept."}},
+		{Note: Note{ID: 5, Path: "blank.md", Body: " \n\t\n"}},
+	}
+
+	test := func(minWords int, expected []EmptyNote) {
+		empties, err := notebook.FindEmptyNotes(notes, minWords)
+		assert.Nil(t, err)
+		assert.Equal(t, empties, expected)
+	}
+
+	test(1, []EmptyNote{
+		{ContextualNote: notes[0], Backlinks: []string{"full.md", "other.md", "stub.md"}},
+		{ContextualNote: notes[3]},
+	})
+	test(5, []EmptyNote{
+		{ContextualNote: notes[0], Backlinks: []string{"full.md", "other.md"}},
+		{ContextualNote: notes[1]},
+		{ContextualNote: notes[3]},
+	})
+	test(0, []EmptyNote{})
+}
@@ -0,0 +1,76 @@
+package core
+
+import "strings"
+
+// MarkedSection is a region of a note delimited by two HTML comment markers,
+// e.g. `<!-- backlinks -->` and `<!-- /backlinks -->`. zk regenerates its
+// content without touching the rest of the note.
+type MarkedSection struct {
+	StartMarker string
+	EndMarker   string
+}
+
+// NamedSection returns the section delimited by the `<!-- zk:name -->` and
+// `<!-- /zk:name -->` markers.
+func NamedSection(name string) MarkedSection {
+	return MarkedSection{
+		StartMarker: "<!-- zk:" + name + " -->",
+		EndMarker:   "<!-- /zk:" + name + " -->",
+	}
+}
+
+// Find returns the byte offsets of the section in the given content,
+// including its markers.
+func (s MarkedSection) Find(content string) (start int, end int, found bool) {
+	end = strings.Index(content, s.EndMarker)
+	if end == -1 {
+		return 0, 0, false
+	}
+	// Looks for the closest start marker, in case of a stray one.
+	start = strings.LastIndex(content[:end], s.StartMarker)
+	if start == -1 {
+		return 0, 0, false
+	}
+	return start, end + len(s.EndMarker), true
+}
+
+// Replace replaces the text between the markers of the section with the
+// given one, or appends the section at the end of the content if it is
+// missing.
+func (s MarkedSection) Replace(content string, text string) string {
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	section := s.StartMarker + "\n" + text + s.EndMarker
+
+	if start, end, found := s.Find(content); found {
+		return content[:start] + section + content[end:]
+	}
+	if strings.TrimSpace(content) == "" {
+		return section + "\n"
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + section + "\n"
+}
+
+// Remove deletes the section and its markers from the content, with the
+// blank lines around it.
+func (s MarkedSection) Remove(content string) string {
+	start, end, found := s.Find(content)
+	if !found {
+		return content
+	}
+
+	before := strings.TrimRight(content[:start], "\n")
+	after := strings.TrimLeft(content[end:], "\n")
+	switch {
+	case after == "":
+		if before == "" {
+			return ""
+		}
+		return before + "\n"
+	case before == "":
+		return after
+	default:
+		return before + "\n\n" + after
+	}
+}
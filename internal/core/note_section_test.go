@@ -0,0 +1,74 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestNamedSection(t *testing.T) {
+	assert.Equal(t, NamedSection("notes"), MarkedSection{
+		StartMarker: "<!-- zk:notes -->",
+		EndMarker:   "<!-- /zk:notes -->",
+	})
+}
+
+func TestMarkedSectionFind(t *testing.T) {
+	test := func(content string, expectedStart int, expectedEnd int, expectedFound bool) {
+		start, end, found := backlinksSection.Find(content)
+		assert.Equal(t, start, expectedStart)
+		assert.Equal(t, end, expectedEnd)
+		assert.Equal(t, found, expectedFound)
+	}
+
+	test("", 0, 0, false)
+	test("Content", 0, 0, false)
+	test("<!-- /backlinks -->\n<!-- backlinks -->", 0, 0, false)
+	test("Content\n<!-- backlinks -->\n- [[a]]\n<!-- /backlinks -->\n", 8, 54, true)
+	// Stray start marker
+	test("<!-- backlinks -->\n<!-- backlinks -->\n<!-- /backlinks -->", 19, 57, true)
+}
+
+func TestMarkedSectionReplace(t *testing.T) {
+	section := NamedSection("notes")
+
+	test := func(content string, text string, expected string) {
+		actual := section.Replace(content, text)
+		assert.Equal(t, actual, expected)
+		// Replacing the section again doesn't change the content.
+		assert.Equal(t, section.Replace(actual, text), expected)
+	}
+
+	// The markers are created when missing.
+	test("", "- a\n", "<!-- zk:notes -->\n- a\n<!-- /zk:notes -->\n")
+	test("# Index\n", "- a\n", "# Index\n\n<!-- zk:notes -->\n- a\n<!-- /zk:notes -->\n")
+	test("# Index", "- a", "# Index\n\n<!-- zk:notes -->\n- a\n<!-- /zk:notes -->\n")
+
+	// An unclosed section is not replaced.
+	test("# Index\n<!-- zk:notes -->\n", "- a", "# Index\n<!-- zk:notes -->\n\n<!-- zk:notes -->\n- a\n<!-- /zk:notes -->\n")
+
+	// The text between the markers is replaced.
+	test(
+		"# Index\n\n<!-- zk:notes -->\n- old\n- older\n<!-- /zk:notes -->\n\nFooter\n",
+		"- a\n- b\n",
+		"# Index\n\n<!-- zk:notes -->\n- a\n- b\n<!-- /zk:notes -->\n\nFooter\n",
+	)
+	test(
+		"<!-- zk:other -->\n- other\n<!-- /zk:other -->\n<!-- zk:notes --><!-- /zk:notes -->",
+		"",
+		"<!-- zk:other -->\n- other\n<!-- /zk:other -->\n<!-- zk:notes -->\n<!-- /zk:notes -->",
+	)
+}
+
+func TestMarkedSectionRemove(t *testing.T) {
+	test := func(content string, expected string) {
+		assert.Equal(t, backlinksSection.Remove(content), expected)
+	}
+
+	test("", "")
+	test("# Note\n\nContent\n", "# Note\n\nContent\n")
+	test("# Note\n\nContent\n\n<!-- backlinks -->\n- [[old]]\n<!-- /backlinks -->\n", "# Note\n\nContent\n")
+	test("# Note\n\n<!-- backlinks -->\n- [[old]]\n<!-- /backlinks -->\n\nContent\n", "# Note\n\nContent\n")
+	test("<!-- backlinks -->\n- [[old]]\n<!-- /backlinks -->\n\nContent\n", "Content\n")
+	test("<!-- backlinks -->\n- [[old]]\n<!-- /backlinks -->\n", "")
+}
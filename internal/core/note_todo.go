@@ -0,0 +1,58 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TodoMarkersPattern returns a regular expression matching any of the given
+// TODO markers, e.g. TODO or FIXME, as whole words. Returns an empty string
+// when there are no markers.
+func TodoMarkersPattern(markers []string) string {
+	alternatives := make([]string, 0)
+	for _, marker := range markers {
+		if marker = strings.TrimSpace(marker); marker == "" {
+			continue
+		}
+		alternatives = append(alternatives, wholeWordPattern(marker))
+	}
+	if len(alternatives) == 0 {
+		return ""
+	}
+	return "(?:" + strings.Join(alternatives, "|") + ")"
+}
+
+// wholeWordPattern returns a regular expression matching the given text as
+// whole words.
+func wholeWordPattern(text string) string {
+	pattern := regexp.QuoteMeta(text)
+	// Word boundaries are only meaningful next to word characters, to
+	// support terms such as @todo.
+	if r, _ := utf8.DecodeRuneInString(text); isTodoWordChar(r) {
+		pattern = `\b` + pattern
+	}
+	if r, _ := utf8.DecodeLastRuneInString(text); isTodoWordChar(r) {
+		pattern += `\b`
+	}
+	return pattern
+}
+
+func isTodoWordChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// countTodoMarkers returns the number of TODO markers found in the given
+// plain text.
+func countTodoMarkers(text string, markers []string) int {
+	pattern := TodoMarkersPattern(markers)
+	if pattern == "" {
+		return 0
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return 0
+	}
+	return len(re.FindAllStringIndex(text, -1))
+}
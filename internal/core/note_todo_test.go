@@ -0,0 +1,36 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestTodoMarkersPattern(t *testing.T) {
+	test := func(markers []string, expected string) {
+		assert.Equal(t, TodoMarkersPattern(markers), expected)
+	}
+
+	test([]string{}, "")
+	test([]string{"", " "}, "")
+	test([]string{"TODO"}, `(?:\bTODO\b)`)
+	test([]string{"TODO", "FIXME"}, `(?:\bTODO\b|\bFIXME\b)`)
+	test([]string{"@todo", "XXX:"}, `(?:@todo\b|\bXXX:)`)
+	test([]string{"T.B.D"}, `(?:\bT\.B\.D\b)`)
+}
+
+func TestCountTodoMarkers(t *testing.T) {
+	markers := []string{"TODO", "FIXME", "@later"}
+	test := func(text string, expected int) {
+		assert.Equal(t, countTodoMarkers(text, markers), expected)
+	}
+
+	test("", 0)
+	test("Nothing to do here, todo", 0)
+	test("TODO", 1)
+	test("TODO: write this. FIXME the typo. TODO TODO", 4)
+	test("TODOS and UNFIXME are not markers", 0)
+	test("Call back (@later).", 1)
+
+	assert.Equal(t, countTodoMarkers("TODO", []string{}), 0)
+}
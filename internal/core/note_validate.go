@@ -0,0 +1,112 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FrontmatterViolation describes a note frontmatter which doesn't comply with
+// the schema of the config.
+type FrontmatterViolation struct {
+	// Path of the note, relative to the notebook root.
+	Path string
+	// Frontmatter key the violation is about.
+	Key string
+	// Human-readable description of the violation.
+	Message string
+}
+
+// String implements Stringer.
+func (v FrontmatterViolation) String() string {
+	return fmt.Sprintf("%s: %s: %s", v.Path, v.Key, v.Message)
+}
+
+// ValidateFrontmatter checks the frontmatter of the given notes against the
+// schema of the config. Violations are reported in the order of the notes,
+// then of the keys.
+func (n *Notebook) ValidateFrontmatter(notes []ContextualNote) []FrontmatterViolation {
+	violations := []FrontmatterViolation{}
+	for _, note := range notes {
+		violations = append(violations, validateFrontmatter(note.Note, n.Config.FrontmatterSchema)...)
+	}
+	return violations
+}
+
+func validateFrontmatter(note Note, schema map[string]FrontmatterFieldSchema) []FrontmatterViolation {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	violations := []FrontmatterViolation{}
+	for _, key := range keys {
+		value, ok := note.Metadata[key]
+		var message string
+		if !ok || value == nil {
+			if schema[key].Required {
+				message = "required key is missing"
+			}
+		} else {
+			message = validateFrontmatterValue(value, schema[key])
+		}
+
+		if message != "" {
+			violations = append(violations, FrontmatterViolation{
+				Path:    note.Path,
+				Key:     key,
+				Message: message,
+			})
+		}
+	}
+	return violations
+}
+
+// validateFrontmatterValue returns a description of the violation of the
+// given field schema, or an empty string if the value is valid.
+func validateFrontmatterValue(value interface{}, field FrontmatterFieldSchema) string {
+	if field.Type != FrontmatterFieldAny && frontmatterTypeOf(value) != field.Type {
+		return fmt.Sprintf("expected a %s, got %v", field.Type, value)
+	}
+
+	if len(field.Values) == 0 {
+		return ""
+	}
+
+	items, isList := value.([]interface{})
+	if !isList {
+		items = []interface{}{value}
+	}
+	for _, item := range items {
+		if !isFrontmatterValueAllowed(item, field.Values) {
+			return fmt.Sprintf("%v is not allowed - may be %s", item, strings.Join(field.Values, ", "))
+		}
+	}
+	return ""
+}
+
+func frontmatterTypeOf(value interface{}) FrontmatterFieldType {
+	switch value.(type) {
+	case string:
+		return FrontmatterFieldString
+	case int, int64, uint64, float64:
+		return FrontmatterFieldNumber
+	case bool:
+		return FrontmatterFieldBoolean
+	case []interface{}:
+		return FrontmatterFieldList
+	default:
+		return FrontmatterFieldAny
+	}
+}
+
+func isFrontmatterValueAllowed(value interface{}, allowed []string) bool {
+	str := fmt.Sprint(value)
+	for _, a := range allowed {
+		if str == a {
+			return true
+		}
+	}
+	return false
+}
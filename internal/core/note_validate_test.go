@@ -0,0 +1,78 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestValidateFrontmatter(t *testing.T) {
+	schema := map[string]FrontmatterFieldSchema{
+		"status": {
+			Required: true,
+			Values:   []string{"draft", "published"},
+		},
+		"tags": {
+			Type:   FrontmatterFieldList,
+			Values: []string{"inbox", "work"},
+		},
+		"priority": {
+			Type: FrontmatterFieldNumber,
+		},
+		"public": {
+			Type: FrontmatterFieldBoolean,
+		},
+		"author": {
+			Type: FrontmatterFieldString,
+		},
+	}
+
+	test := func(metadata map[string]interface{}, expected []FrontmatterViolation) {
+		t.Helper()
+		note := Note{Path: "dir/note.md", Metadata: metadata}
+		assert.Equal(t, validateFrontmatter(note, schema), expected)
+	}
+
+	test(map[string]interface{}{
+		"status":   "draft",
+		"tags":     []interface{}{"inbox", "work"},
+		"priority": float64(2),
+		"public":   true,
+		"author":   "Mickaël",
+		"unknown":  "value",
+	}, []FrontmatterViolation{})
+
+	test(map[string]interface{}{}, []FrontmatterViolation{
+		{Path: "dir/note.md", Key: "status", Message: "required key is missing"},
+	})
+
+	test(map[string]interface{}{"status": nil}, []FrontmatterViolation{
+		{Path: "dir/note.md", Key: "status", Message: "required key is missing"},
+	})
+
+	test(map[string]interface{}{
+		"status":   "review",
+		"tags":     []interface{}{"inbox", "perso"},
+		"priority": "high",
+		"public":   "yes",
+		"author":   12,
+	}, []FrontmatterViolation{
+		{Path: "dir/note.md", Key: "author", Message: "expected a string, got 12"},
+		{Path: "dir/note.md", Key: "priority", Message: "expected a number, got high"},
+		{Path: "dir/note.md", Key: "public", Message: "expected a boolean, got yes"},
+		{Path: "dir/note.md", Key: "status", Message: "review is not allowed - may be draft, published"},
+		{Path: "dir/note.md", Key: "tags", Message: "perso is not allowed - may be inbox, work"},
+	})
+
+	test(map[string]interface{}{
+		"status": "draft",
+		"tags":   "inbox",
+	}, []FrontmatterViolation{
+		{Path: "dir/note.md", Key: "tags", Message: "expected a list, got inbox"},
+	})
+}
+
+func TestFrontmatterViolationString(t *testing.T) {
+	violation := FrontmatterViolation{Path: "dir/note.md", Key: "status", Message: "required key is missing"}
+	assert.Equal(t, violation.String(), "dir/note.md: status: required key is missing")
+}
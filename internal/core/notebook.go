@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/date"
 	"github.com/zk-org/zk/internal/util/errors"
 	"github.com/zk-org/zk/internal/util/opt"
 	"github.com/zk-org/zk/internal/util/paths"
@@ -18,12 +19,15 @@ type Notebook struct {
 	Config Config
 	Parser NoteContentParser
 
+	parsers               map[string]NoteContentParser
 	index                 NoteIndex
 	templateLoaderFactory TemplateLoaderFactory
 	idGeneratorFactory    IDGeneratorFactory
 	fs                    FileStorage
 	logger                util.Logger
 	osEnv                 func() map[string]string
+	renderHTML            HTMLRenderer
+	now                   date.Provider
 }
 
 // NewNotebook creates a new Notebook instance.
@@ -36,12 +40,15 @@ func NewNotebook(
 		Path:                  path,
 		Config:                config,
 		Parser:                ports.NoteContentParser,
+		parsers:               ports.NoteContentParsers,
 		index:                 ports.NoteIndex,
 		templateLoaderFactory: ports.TemplateLoaderFactory,
 		idGeneratorFactory:    ports.IDGeneratorFactory,
 		fs:                    ports.FS,
 		logger:                ports.Logger,
 		osEnv:                 ports.OSEnv,
+		renderHTML:            ports.HTMLRenderer,
+		now:                   ports.Now,
 	}
 }
 
@@ -53,24 +60,52 @@ type NotebookPorts struct {
 	FS                    FileStorage
 	Logger                util.Logger
 	OSEnv                 func() map[string]string
+	HTMLRenderer          HTMLRenderer
+	// Provides the current date, used for example to compute the number of
+	// days since a note was modified. Defaults to the system clock.
+	Now date.Provider
+	// Parsers of the files included with the `index.include` setting, by
+	// extension without the leading dot. The files of other types are
+	// parsed as plain text.
+	NoteContentParsers map[string]NoteContentParser
 }
 
+// HTMLRenderer converts a Markdown document to HTML.
+type HTMLRenderer func(markdown string) (string, error)
+
 // NotebookFactory creates a new Notebook instance at the given root path.
 type NotebookFactory func(path string, config Config) (*Notebook, error)
 
+// Close releases the resources of the notebook, such as its index. The
+// notebook can't be used afterwards.
+func (n *Notebook) Close() error {
+	return n.index.Close()
+}
+
 // Index indexes the content of the notebook to be searchable.
 func (n *Notebook) Index(opts NoteIndexOpts) (stats NoteIndexingStats, err error) {
 	return n.IndexWithCallback(opts, func(change paths.DiffChange) {})
 }
 
 // Index indexes the content of the notebook to be searchable.
+//
+// With opts.Rebuild, the index is dropped and recreated from scratch. As it is
+// done in a single transaction, the previous index is kept if the rebuild
+// fails.
 func (n *Notebook) IndexWithCallback(opts NoteIndexOpts, callback func(change paths.DiffChange)) (stats NoteIndexingStats, err error) {
 	err = n.index.Commit(func(index NoteIndex) error {
+		if opts.Rebuild {
+			if err := index.Reset(); err != nil {
+				return err
+			}
+		}
+
 		task := indexTask{
 			path:    n.Path,
 			config:  n.Config,
-			force:   opts.Force,
+			force:   opts.Force || opts.Rebuild,
 			verbose: opts.Verbose,
+			include: append(append([]string{}, n.Config.Index.Include...), opts.Include...),
 			index:   index,
 			parser:  n,
 			logger:  n.logger,
@@ -95,14 +130,25 @@ type NewNoteOpts struct {
 	Group opt.String
 	// Path to a custom template used to render the note.
 	Template opt.String
+	// Template used to generate the filename of the note, including its
+	// extension. Takes precedence over the config of the group.
+	FilenameTemplate opt.String
 	// Extra variables passed to the templates.
 	Extra map[string]string
-	// Creation date provided to the templates.
+	// Creation date of the note, provided to the templates. It is recorded in
+	// the index unless the frontmatter has a `date` or `created` key.
 	Date time.Time
 	// Don't save the generated note on the file system.
 	DryRun bool
 	// Use a provided id over generating one
 	ID string
+	// Path to the parent note, relative to the working directory. The ID of
+	// the new note is derived from the parent's, e.g. `1a` under `1`, and it
+	// is created in the parent's directory unless Directory is set.
+	Parent opt.String
+	// Heading of the section in which the link to the parent is appended,
+	// defaults to `## Links`.
+	LinkSection string
 }
 
 // ErrNoteExists is an error returned when a note already exists with the
@@ -116,12 +162,38 @@ func (e ErrNoteExists) Error() string {
 	return fmt.Sprintf("%s: note already exists", e.Path)
 }
 
+// ErrNoteNotFound is an error returned when a note given by the user can't be
+// found in the index, either by its path or with a search query.
+type ErrNoteNotFound struct {
+	Path  string
+	Query string
+}
+
+func (e ErrNoteNotFound) Error() string {
+	if e.Query != "" {
+		return fmt.Sprintf("no notes found matching: %s", e.Query)
+	}
+	return fmt.Sprintf("%s: note not found", e.Path)
+}
+
 // NewNote generates a new note in the notebook, index and returns it.
 //
 // Returns ErrNoteExists if no free filename can be generated for this note.
 func (n *Notebook) NewNote(opts NewNoteOpts) (*Note, error) {
 	wrap := errors.Wrapper("new note")
 
+	var parent *MinimalNote
+	if path := opts.Parent.Unwrap(); path != "" {
+		var err error
+		parent, err = n.findParentNote(path)
+		if err != nil {
+			return nil, wrap(err)
+		}
+		if opts.Directory.IsNull() {
+			opts.Directory = opt.NewString(filepath.Dir(filepath.Join(n.Path, parent.Path)))
+		}
+	}
+
 	dir, err := n.RequireDirAt(opts.Directory.OrString(n.Path).Unwrap())
 	if err != nil {
 		return nil, wrap(err)
@@ -143,38 +215,89 @@ func (n *Notebook) NewNote(opts NewNoteOpts) (*Note, error) {
 	}
 
 	var idGenerator IDGenerator
+	var idTaken func(id string) bool
 	if opts.ID != "" {
 		idGenerator = func() string {
 			return opts.ID
 		}
 	} else {
-		idGenerator = n.idGeneratorFactory(config.Note.IDOptions)
+		idTaken, err = n.indexedIDChecker()
+		if err != nil {
+			return nil, wrap(err)
+		}
+		if parent != nil {
+			idGenerator = newChildIDGenerator(noteIDOf(*parent), idTaken)
+		} else {
+			idGenerator, err = n.newIDGenerator(config.Note.IDOptions, opts.Date)
+			if err != nil {
+				return nil, wrap(err)
+			}
+		}
+	}
+
+	siblings, err := n.findSiblings(dir)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	var parentContext *newNoteParent
+	if parent != nil {
+		parentContext, err = n.newNoteParent(*parent, dir)
+		if err != nil {
+			return nil, wrap(err)
+		}
+	}
+	linkSection := opts.LinkSection
+	if linkSection == "" {
+		linkSection = "## Links"
 	}
 
 	task := newNoteTask{
+		notebookPath:     n.Path,
 		dir:              dir,
 		title:            opts.Title.OrString(config.Note.DefaultTitle).Unwrap(),
 		content:          opts.Content,
 		date:             opts.Date,
 		extra:            extra,
 		env:              n.osEnv(),
+		siblings:         siblings,
+		parent:           parentContext,
+		linkSection:      linkSection,
 		fs:               n.fs,
-		filenameTemplate: config.Note.FilenameTemplate + "." + config.Note.Extension,
+		filenameTemplate: opts.FilenameTemplate.OrString(config.Note.FilenameTemplate + "." + config.Note.Extension).Unwrap(),
 		bodyTemplatePath: opts.Template.Or(config.Note.BodyTemplatePath),
+		frontmatterBase:  config.Note.FrontmatterBase,
+		frontmatterPath:  config.Note.FrontmatterBasePath,
 		templates:        templates,
 		genID:            idGenerator,
+		idTaken:          idTaken,
 		dryRun:           opts.DryRun,
 	}
-	path, content, err := task.execute()
+	path, content, id, err := task.execute()
 	if err != nil {
 		return nil, wrap(err)
 	}
 
+	if opts.ID == "" && parent == nil && config.Note.IDOptions.Strategy == IDStrategySequence && !opts.DryRun {
+		err = n.saveIDSequence(id)
+		if err != nil {
+			return nil, wrap(err)
+		}
+	}
+
 	note, err := n.ParseNoteWithContent(path, []byte(content))
 	if note == nil || err != nil {
 		return nil, wrap(err)
 	}
 
+	// A note backfilled with a custom date was created at this date, unless
+	// its frontmatter tells otherwise.
+	if date, ok := creationDateFromMetadata(note.Metadata); ok {
+		note.Created = date
+	} else if !opts.Date.IsZero() {
+		note.Created = opts.Date.UTC()
+	}
+
 	if !opts.DryRun {
 		id, err := n.index.Add(*note)
 		if err != nil {
@@ -188,7 +311,16 @@ func (n *Notebook) NewNote(opts NewNoteOpts) (*Note, error) {
 
 // FindNotes retrieves the notes matching the given filtering options.
 func (n *Notebook) FindNotes(opts NoteFindOpts) ([]ContextualNote, error) {
-	return n.index.Find(opts)
+	notes, err := n.index.Find(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MatchStrategy == MatchStrategyFuzzy && len(opts.Match) > 0 {
+		for i, note := range notes {
+			notes[i].MatchPositions = fuzzyMatchPositionsAll(opts.Match, note.Title)
+		}
+	}
+	return notes, nil
 }
 
 // FindNote retrieves the first note matching the given filtering options.
@@ -235,6 +367,12 @@ func (n *Notebook) FindByHref(href string, allowPartialHref bool) (*MinimalNote,
 	})
 }
 
+// FindHeadings retrieves the note titles and headings matching the given
+// regular expression, the shortest first, up to limit of them.
+func (n *Notebook) FindHeadings(regex string, limit int) ([]HeadingMatch, error) {
+	return n.index.FindHeadings(regex, limit)
+}
+
 // FindLinksBetweenNotes retrieves the links between the given notes.
 func (n *Notebook) FindLinksBetweenNotes(ids []NoteID) ([]ResolvedLink, error) {
 	return n.index.FindLinksBetweenNotes(ids)
@@ -245,6 +383,13 @@ func (n *Notebook) FindCollections(kind CollectionKind, sorters []CollectionSort
 	return n.index.FindCollections(kind, sorters)
 }
 
+// FindNestedCollections retrieves all the collections of the given kind,
+// rolling up the notes of the nested collections (e.g. `project/alpha`) into
+// their parents (`project`).
+func (n *Notebook) FindNestedCollections(kind CollectionKind, sorters []CollectionSorter) ([]Collection, error) {
+	return n.index.FindNestedCollections(kind, sorters)
+}
+
 // RelPath returns the path relative to the notebook root to the given path.
 func (n *Notebook) RelPath(originalPath string) (string, error) {
 	wrap := errors.Wrapperf("%v: not a valid notebook path", originalPath)
@@ -327,8 +472,9 @@ func (n *Notebook) RequireDirAt(path string) (Dir, error) {
 	return dir, nil
 }
 
-// NewNoteFormatter returns a NoteFormatter used to format notes with the given template.
-func (n *Notebook) NewNoteFormatter(templateString string) (NoteFormatter, error) {
+// NewNoteFormatter returns a NoteFormatter used to format notes with the given
+// template, printing the note paths with the given style.
+func (n *Notebook) NewNoteFormatter(templateString string, pathStyle PathStyle) (NoteFormatter, error) {
 	templates, err := n.templateLoaderFactory(n.Config.Note.Lang)
 	if err != nil {
 		return nil, err
@@ -343,7 +489,61 @@ func (n *Notebook) NewNoteFormatter(templateString string) (NoteFormatter, error
 		return nil, err
 	}
 
-	return newNoteFormatter(n.Path, template, linkFormatter, n.osEnv(), n.fs)
+	return newNoteFormatter(n.Path, template, linkFormatter, pathStyle, n.index, n.Config.Note.TodoMarkers, n.Config.GroupNameForNote, n.osEnv(), n.fs, nil, n.currentDate())
+}
+
+// NewNoteListFormatter returns a NoteListFormatter used to format notes
+// printed in a list with the given template, printing the note paths with the
+// given style. With html, the bodies of the notes rendered as HTML are
+// available in the `html` variable and in the JSON representation.
+func (n *Notebook) NewNoteListFormatter(templateString string, pathStyle PathStyle, html bool) (NoteListFormatter, error) {
+	templates, err := n.templateLoaderFactory(n.Config.Note.Lang)
+	if err != nil {
+		return nil, err
+	}
+	template, err := templates.LoadTemplate(templateString)
+	if err != nil {
+		return nil, err
+	}
+
+	linkFormatter, err := NewLinkFormatter(n.Config.Format.Markdown, templates)
+	if err != nil {
+		return nil, err
+	}
+
+	var renderHTML HTMLRenderer
+	if html {
+		if n.renderHTML == nil {
+			return nil, errors.New("rendering notes as HTML is not supported")
+		}
+		renderHTML = n.renderHTML
+	}
+
+	return newNoteListFormatter(n.Path, template, linkFormatter, pathStyle, n.index, n.Config.Note.TodoMarkers, n.Config.GroupNameForNote, n.osEnv(), n.fs, renderHTML, n.currentDate())
+}
+
+// currentDate returns the current date from the Now port, or from the system
+// clock when not provided.
+func (n *Notebook) currentDate() time.Time {
+	if n.now == nil {
+		return time.Now()
+	}
+	return n.now.Date()
+}
+
+// NewNoteListHeaderFormatter returns a NoteListHeaderFormatter used to format
+// the header or the footer of a list of notes with the given template.
+func (n *Notebook) NewNoteListHeaderFormatter(templateString string) (NoteListHeaderFormatter, error) {
+	templates, err := n.templateLoaderFactory(n.Config.Note.Lang)
+	if err != nil {
+		return nil, err
+	}
+	template, err := templates.LoadTemplate(templateString)
+	if err != nil {
+		return nil, err
+	}
+
+	return newNoteListHeaderFormatter(template)
 }
 
 // NewCollectionFormatter returns a CollectionFormatter used to format notes with the given template.
@@ -369,3 +569,24 @@ func (n *Notebook) NewLinkFormatter() (LinkFormatter, error) {
 
 	return NewLinkFormatter(n.Config.Format.Markdown, templates)
 }
+
+// FormatNoteLink generates a link to the target note with the given
+// formatter. Relative paths are computed from the source directory.
+//
+// All the code paths inserting links in notes should use it, so that they
+// honor the same link settings.
+func (n *Notebook) FormatNoteLink(formatter LinkFormatter, target MinimalNote, sourceDir string) (string, error) {
+	context, err := NewLinkFormatterContext(
+		NotebookPath{
+			Path:       target.Path,
+			BasePath:   n.Path,
+			WorkingDir: sourceDir,
+		},
+		target.Title,
+		target.Metadata,
+	)
+	if err != nil {
+		return "", err
+	}
+	return formatter(context)
+}
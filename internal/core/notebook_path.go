@@ -30,3 +30,17 @@ func (p NotebookPath) PathRelToWorkingDir() (string, error) {
 	}
 	return filepath.Rel(p.WorkingDir, p.AbsPath())
 }
+
+// PathStyle defines how the path to a notebook file is printed.
+type PathStyle string
+
+const (
+	// PathStyleRelative prints the path relative to the working directory.
+	PathStyleRelative PathStyle = "relative"
+	// PathStyleNotebook prints the path relative to the notebook directory.
+	PathStyleNotebook PathStyle = "notebook"
+	// PathStyleAbsolute prints the absolute path.
+	PathStyleAbsolute PathStyle = "absolute"
+	// PathStyleID prints the ID of the note instead of its path.
+	PathStyleID PathStyle = "id"
+)
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// NotebookStats holds aggregate metrics about a set of notes.
+type NotebookStats struct {
+	// Number of notes.
+	NoteCount int `json:"noteCount"`
+	// Total number of words in the notes.
+	WordCount int `json:"wordCount"`
+	// Number of outgoing links, including the external ones.
+	LinkCount int `json:"linkCount"`
+	// Number of notes which are not linked by any note, like with the
+	// --orphan filter.
+	OrphanCount int `json:"orphanCount"`
+	// Number of internal links which don't resolve to any note.
+	DeadLinkCount int `json:"deadLinkCount"`
+	// Number of distinct tags.
+	TagCount int `json:"tagCount"`
+	// Average number of outgoing links per note.
+	AverageLinkCount float64 `json:"averageLinkCount"`
+	// Number of notes created during each month, in chronological order.
+	Created []NotebookStatsBucket `json:"created"`
+}
+
+// NotebookStatsBucket counts the notes of a period of time.
+type NotebookStatsBucket struct {
+	// Month of the period, e.g. 2021-05.
+	Period string `json:"period"`
+	Count  int    `json:"count"`
+}
+
+// Stats computes aggregate metrics about the given notes, from the index.
+func (n *Notebook) Stats(notes []ContextualNote) (NotebookStats, error) {
+	ids := make([]NoteID, 0)
+	for _, note := range notes {
+		ids = append(ids, note.ID)
+	}
+
+	links, err := n.index.FindLinksOfNotes(ids)
+	if err != nil {
+		return NotebookStats{}, errors.Wrap(err, "failed to compute the notebook stats")
+	}
+
+	return notebookStats(notes, links), nil
+}
+
+func notebookStats(notes []ContextualNote, links []ResolvedLink) NotebookStats {
+	stats := NotebookStats{
+		NoteCount: len(notes),
+		Created:   make([]NotebookStatsBucket, 0),
+	}
+
+	inScope := map[NoteID]bool{}
+	tags := map[string]bool{}
+	created := map[string]int{}
+	for _, note := range notes {
+		inScope[note.ID] = true
+		stats.WordCount += note.WordCount
+		for _, tag := range note.Tags {
+			tags[tag] = true
+		}
+		if !note.Created.IsZero() {
+			created[note.Created.Format("2006-01")]++
+		}
+	}
+	stats.TagCount = len(tags)
+
+	linked := map[NoteID]bool{}
+	for _, link := range links {
+		if inScope[link.SourceID] {
+			stats.LinkCount++
+			if !link.IsExternal && !link.TargetID.IsValid() {
+				stats.DeadLinkCount++
+			}
+		}
+		if link.TargetID.IsValid() {
+			linked[link.TargetID] = true
+		}
+	}
+	for id := range inScope {
+		if !linked[id] {
+			stats.OrphanCount++
+		}
+	}
+
+	if stats.NoteCount > 0 {
+		stats.AverageLinkCount = float64(stats.LinkCount) / float64(stats.NoteCount)
+	}
+
+	for period, count := range created {
+		stats.Created = append(stats.Created, NotebookStatsBucket{Period: period, Count: count})
+	}
+	sort.Slice(stats.Created, func(i, j int) bool {
+		return stats.Created[i].Period < stats.Created[j].Period
+	})
+
+	return stats
+}
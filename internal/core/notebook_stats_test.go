@@ -0,0 +1,49 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestNotebookStats(t *testing.T) {
+	date := func(month time.Month) time.Time {
+		return time.Date(2021, month, 3, 10, 0, 0, 0, time.UTC)
+	}
+
+	notes := []ContextualNote{
+		{Note: Note{ID: 1, WordCount: 10, Tags: []string{"a", "b"}, Created: date(time.May)}},
+		{Note: Note{ID: 2, WordCount: 20, Tags: []string{"b"}, Created: date(time.March)}},
+		{Note: Note{ID: 3, WordCount: 5, Tags: []string{}, Created: date(time.May)}},
+		{Note: Note{ID: 4, WordCount: 0}},
+	}
+	links := []ResolvedLink{
+		{SourceID: 1, TargetID: 2},
+		{SourceID: 1, TargetID: 0, Link: Link{Href: "missing"}},
+		{SourceID: 1, TargetID: 0, Link: Link{Href: "https://example.com", IsExternal: true}},
+		{SourceID: 2, TargetID: 1},
+		// Backlink from a note out of scope
+		{SourceID: 5, TargetID: 3},
+	}
+
+	assert.Equal(t, notebookStats(notes, links), NotebookStats{
+		NoteCount:        4,
+		WordCount:        35,
+		LinkCount:        4,
+		OrphanCount:      1,
+		DeadLinkCount:    1,
+		TagCount:         2,
+		AverageLinkCount: 1,
+		Created: []NotebookStatsBucket{
+			{Period: "2021-03", Count: 1},
+			{Period: "2021-05", Count: 2},
+		},
+	})
+}
+
+func TestNotebookStatsWithoutNotes(t *testing.T) {
+	assert.Equal(t, notebookStats([]ContextualNote{}, []ResolvedLink{}), NotebookStats{
+		Created: []NotebookStatsBucket{},
+	})
+}
@@ -36,11 +36,18 @@ func NewNotebookStore(config Config, ports NotebookStorePorts) *NotebookStore {
 	}
 }
 
-// ErrNotebookNotFound is an error returned when a notebook cannot be found at the given path or its parents.
-type ErrNotebookNotFound string
+// ErrNotebookNotFound is an error returned when a notebook cannot be found at the given path or its parents,
+// or when no notebook is registered with the given name in the global config.
+type ErrNotebookNotFound struct {
+	Path string
+	Name string
+}
 
 func (e ErrNotebookNotFound) Error() string {
-	return fmt.Sprintf("no notebook found in %s or a parent directory", string(e))
+	if e.Name != "" {
+		return fmt.Sprintf("%s: notebook not found, register it in the [notebooks] section of the global config", e.Name)
+	}
+	return fmt.Sprintf("no notebook found in %s or a parent directory", e.Path)
 }
 
 // Open returns a new Notebook instance for the notebook containing the
@@ -126,7 +133,7 @@ func (ns *NotebookStore) Init(path string, options InitOpts) (*Notebook, error)
 	}
 
 	// Create the default configuration file.
-	config, err := ns.generateConfig(options)
+	config, err := ns.GenerateConfig(options)
 	if err != nil {
 		return nil, wrap(err)
 	}
@@ -154,7 +161,7 @@ func (ns *NotebookStore) locateNotebook(path string) (string, error) {
 	locate = func(currentPath string) (string, error) {
 		// For Windows, the root dir may end with volume name, e.g. E:\\
 		if currentPath == "/" || currentPath == filepath.VolumeName(currentPath)+"\\" || currentPath == "." {
-			return "", ErrNotebookNotFound(path)
+			return "", ErrNotebookNotFound{Path: path}
 		}
 		exists, err := ns.fs.DirExists(filepath.Join(currentPath, ".zk"))
 		switch {
@@ -170,7 +177,9 @@ func (ns *NotebookStore) locateNotebook(path string) (string, error) {
 	return locate(path)
 }
 
-func (ns *NotebookStore) generateConfig(options InitOpts) (string, error) {
+// GenerateConfig renders the content of the default configuration file for
+// the given user preferences.
+func (ns *NotebookStore) GenerateConfig(options InitOpts) (string, error) {
 	template, err := ns.templateLoader.LoadTemplate(defaultConfig)
 	if err != nil {
 		return "", err
@@ -210,7 +219,16 @@ template = "default.md"
 #	"log.md"
 #]
 
-# Configure random ID generation.
+# Words counted as TODO markers by \{{todo-count}} and --has-todos.
+#todo-markers = ["TODO", "FIXME"]
+
+# Configure the ID generation.
+
+# Strategy used to generate IDs, among:
+#   * random: random characters, configured below
+#   * timestamp: creation date as YYYYMMDDHHMM
+#   * sequence: incrementing number, never reusing the ID of deleted notes
+#id-strategy = "random"
 
 # The charset used for random IDs. You can use:
 #   * letters: only letters from a to z.
@@ -220,7 +238,7 @@ template = "default.md"
 #   * custom string: will use any character from the provided value
 #id-charset = "alphanum"
 
-# Length of the generated IDs.
+# Length of the generated IDs. Sequential IDs are padded with zeros.
 #id-length = 4
 
 # Letter case for the random IDs, among lower, upper or mixed.
@@ -260,12 +278,14 @@ template = "default.md"
 [format.markdown]
 
 # Format used to generate links between notes.
-# Either "wiki", "markdown" or a custom template. Default is "markdown".
+# Either "wiki", "wiki-label", "markdown" or a custom template. Default is "markdown".
 {{#if WikiLinks}}
 link-format = "wiki"
 {{else}}
 #link-format = "wiki"
 {{/if}}
+# Target of the wiki links: the note "path" or its "title". Default is "path".
+#link-target = "path"
 # Indicates whether a link's path will be percent-encoded.
 # Defaults to true for "markdown" format and false for "wiki" format.
 #link-encode-path = true
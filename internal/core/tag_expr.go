@@ -0,0 +1,285 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TagExpr is a boolean expression evaluated against the tag set of a note,
+// e.g. `work AND (urgent OR today) NOT done`.
+//
+// It is one of TagExprTag, TagExprNot, TagExprAnd or TagExprOr.
+type TagExpr interface{}
+
+// TagExprTag matches the notes having a tag named after the given glob
+// pattern, or any of its nested tags.
+type TagExprTag struct {
+	Name string
+}
+
+// TagExprNot matches the notes not matched by its operand.
+type TagExprNot struct {
+	Expr TagExpr
+}
+
+// TagExprAnd matches the notes matched by all of its operands.
+type TagExprAnd struct {
+	Exprs []TagExpr
+}
+
+// TagExprOr matches the notes matched by any of its operands.
+type TagExprOr struct {
+	Exprs []TagExpr
+}
+
+// ParseTagExpr parses a tag filter expression into a TagExpr.
+//
+// Tags separated by whitespaces or `AND` must all be present, while tags
+// separated by `|` or `OR` are alternatives. A tag prefixed with `-` or `NOT`
+// is excluded, and parentheses group sub-expressions. Tags containing
+// whitespaces can be wrapped in double quotes.
+//
+// Returns nil for an empty expression.
+func ParseTagExpr(expr string) (TagExpr, error) {
+	tokens, err := tokenizeTagExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := tagExprParser{expr: expr, tokens: tokens}
+	res, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != nil {
+		return nil, p.errorf(*tok, "unexpected %s", tok)
+	}
+	return res, nil
+}
+
+type tagExprTokenKind int
+
+const (
+	tagExprTokenTag tagExprTokenKind = iota + 1
+	tagExprTokenAnd
+	tagExprTokenOr
+	tagExprTokenNot
+	tagExprTokenLParen
+	tagExprTokenRParen
+)
+
+type tagExprToken struct {
+	kind tagExprTokenKind
+	text string
+	// Position of the token in the expression, as a 1-based rune offset.
+	pos int
+}
+
+func (t tagExprToken) String() string {
+	switch t.kind {
+	case tagExprTokenTag:
+		return fmt.Sprintf("tag `%s`", t.text)
+	default:
+		return fmt.Sprintf("`%s`", t.text)
+	}
+}
+
+func tokenizeTagExpr(expr string) ([]tagExprToken, error) {
+	tokens := make([]tagExprToken, 0)
+	runes := []rune(expr)
+
+	i := 0
+	for i < len(runes) {
+		c := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '(':
+			tokens = append(tokens, tagExprToken{kind: tagExprTokenLParen, text: "(", pos: pos})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, tagExprToken{kind: tagExprTokenRParen, text: ")", pos: pos})
+			i++
+
+		case c == '|':
+			tokens = append(tokens, tagExprToken{kind: tagExprTokenOr, text: "|", pos: pos})
+			i++
+
+		// - is an alias to NOT, but only at the start of a term, to allow
+		// compound tags such as "well-known".
+		case c == '-':
+			tokens = append(tokens, tagExprToken{kind: tagExprTokenNot, text: "-", pos: pos})
+			i++
+
+		case c == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("invalid tag expression `%s`: unterminated quote at position %d", expr, pos)
+			}
+			tokens = append(tokens, tagExprToken{kind: tagExprTokenTag, text: string(runes[i+1 : end]), pos: pos})
+			i = end + 1
+
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()|"`, runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			i = end
+
+			switch {
+			case word == "AND":
+				tokens = append(tokens, tagExprToken{kind: tagExprTokenAnd, text: word, pos: pos})
+			case word == "OR":
+				tokens = append(tokens, tagExprToken{kind: tagExprTokenOr, text: word, pos: pos})
+			case strings.HasPrefix(word, "NOT"):
+				// For backward compatibility, NOT may be glued to the tag.
+				tokens = append(tokens, tagExprToken{kind: tagExprTokenNot, text: "NOT", pos: pos})
+				if word != "NOT" {
+					tokens = append(tokens, tagExprToken{kind: tagExprTokenTag, text: strings.TrimPrefix(word, "NOT"), pos: pos + 3})
+				}
+			default:
+				tokens = append(tokens, tagExprToken{kind: tagExprTokenTag, text: word, pos: pos})
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+// tagExprParser is a recursive descent parser for the grammar:
+//
+//	or   = and { ("OR" | "|") and }
+//	and  = not { ["AND"] not }
+//	not  = ("NOT" | "-") not | atom
+//	atom = tag | "(" or ")"
+type tagExprParser struct {
+	expr   string
+	tokens []tagExprToken
+	index  int
+}
+
+func (p *tagExprParser) peek() *tagExprToken {
+	if p.index >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.index]
+}
+
+func (p *tagExprParser) next() *tagExprToken {
+	tok := p.peek()
+	if tok != nil {
+		p.index++
+	}
+	return tok
+}
+
+func (p *tagExprParser) errorf(tok tagExprToken, format string, args ...interface{}) error {
+	return fmt.Errorf("invalid tag expression `%s`: %s at position %d", p.expr, fmt.Sprintf(format, args...), tok.pos)
+}
+
+func (p *tagExprParser) errorEOF() error {
+	return fmt.Errorf("invalid tag expression `%s`: unexpected end of expression", p.expr)
+}
+
+func (p *tagExprParser) parseOr() (TagExpr, error) {
+	exprs := make([]TagExpr, 0)
+	for {
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		tok := p.peek()
+		if tok == nil || tok.kind != tagExprTokenOr {
+			break
+		}
+		p.next()
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return TagExprOr{Exprs: exprs}, nil
+}
+
+func (p *tagExprParser) parseAnd() (TagExpr, error) {
+	exprs := make([]TagExpr, 0)
+	for {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		tok := p.peek()
+		if tok == nil || tok.kind == tagExprTokenOr || tok.kind == tagExprTokenRParen {
+			break
+		}
+		if tok.kind == tagExprTokenAnd {
+			p.next()
+		}
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return TagExprAnd{Exprs: exprs}, nil
+}
+
+func (p *tagExprParser) parseNot() (TagExpr, error) {
+	tok := p.peek()
+	if tok != nil && tok.kind == tagExprTokenNot {
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return TagExprNot{Expr: expr}, nil
+	}
+	return p.parseAtom()
+}
+
+func (p *tagExprParser) parseAtom() (TagExpr, error) {
+	tok := p.next()
+	if tok == nil {
+		return nil, p.errorEOF()
+	}
+
+	switch tok.kind {
+	case tagExprTokenTag:
+		if tok.text == "" {
+			return nil, p.errorf(*tok, "empty tag")
+		}
+		return TagExprTag{Name: tok.text}, nil
+
+	case tagExprTokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing := p.next()
+		if closing == nil {
+			return nil, p.errorf(*tok, "unclosed `(`")
+		}
+		if closing.kind != tagExprTokenRParen {
+			return nil, p.errorf(*closing, "unexpected %s", closing)
+		}
+		return expr, nil
+
+	default:
+		return nil, p.errorf(*tok, "unexpected %s", tok)
+	}
+}
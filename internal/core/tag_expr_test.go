@@ -0,0 +1,62 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestParseTagExpr(t *testing.T) {
+	test := func(expr string, expected TagExpr) {
+		actual, err := ParseTagExpr(expr)
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	tag := func(name string) TagExpr { return TagExprTag{Name: name} }
+	not := func(expr TagExpr) TagExpr { return TagExprNot{Expr: expr} }
+	and := func(exprs ...TagExpr) TagExpr { return TagExprAnd{Exprs: exprs} }
+	or := func(exprs ...TagExpr) TagExpr { return TagExprOr{Exprs: exprs} }
+
+	test("", nil)
+	test("  ", nil)
+	test("work", tag("work"))
+	test(" work ", tag("work"))
+	test("project/alpha", tag("project/alpha"))
+	test("year/201*", tag("year/201*"))
+	test("well-known", tag("well-known"))
+	test(`"multi word"`, tag("multi word"))
+	test("work urgent", and(tag("work"), tag("urgent")))
+	test("work AND urgent", and(tag("work"), tag("urgent")))
+	test("work OR urgent", or(tag("work"), tag("urgent")))
+	test("work|urgent", or(tag("work"), tag("urgent")))
+	test("work | urgent", or(tag("work"), tag("urgent")))
+	test("NOT done", not(tag("done")))
+	test("NOTdone", not(tag("done")))
+	test("-done", not(tag("done")))
+	test("NOT NOT done", not(not(tag("done"))))
+	test("a OR b c", or(tag("a"), and(tag("b"), tag("c"))))
+	test("NOT a OR b", or(not(tag("a")), tag("b")))
+	test("NOT (a OR b)", not(or(tag("a"), tag("b"))))
+	test(
+		"work AND (urgent OR today) NOT done",
+		and(tag("work"), or(tag("urgent"), tag("today")), not(tag("done"))),
+	)
+	test("((work))", tag("work"))
+}
+
+func TestParseTagExprErrors(t *testing.T) {
+	test := func(expr string, expected string) {
+		_, err := ParseTagExpr(expr)
+		assert.Err(t, err, expected)
+	}
+
+	test("work AND", "invalid tag expression `work AND`: unexpected end of expression")
+	test("OR work", "invalid tag expression `OR work`: unexpected `OR` at position 1")
+	test("work OR OR urgent", "invalid tag expression `work OR OR urgent`: unexpected `OR` at position 9")
+	test("(work", "invalid tag expression `(work`: unclosed `(` at position 1")
+	test("work)", "invalid tag expression `work)`: unexpected `)` at position 5")
+	test("work ()", "invalid tag expression `work ()`: unexpected `)` at position 7")
+	test(`"work`, "invalid tag expression `\"work`: unterminated quote at position 1")
+	test(`work ""`, "invalid tag expression `work \"\"`: empty tag at position 6")
+}
@@ -10,6 +10,30 @@ type Template interface {
 	Render(context interface{}) (string, error)
 }
 
+// DataTemplate is a Template which can be rendered with additional private
+// variables, such as `@index` in Handlebars templates.
+type DataTemplate interface {
+	Template
+
+	// RenderWithData generates this template using the given variable
+	// context and private variables.
+	RenderWithData(context interface{}, data map[string]interface{}) (string, error)
+}
+
+// ErrTemplate is an error returned when a template can't be parsed or
+// rendered.
+type ErrTemplate struct {
+	Err error
+}
+
+func (e ErrTemplate) Error() string {
+	return e.Err.Error()
+}
+
+func (e ErrTemplate) Unwrap() error {
+	return e.Err
+}
+
 // TemplateFunc is an adapter to use a function as a Template.
 type TemplateFunc func(context interface{}) (string, error)
 
@@ -1,5 +1,10 @@
 package core
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 // lazyStringer implements Stringer and wait for String() to be called the first
 // time before computing its value.
 type lazyStringer struct {
@@ -24,5 +29,10 @@ func (s *lazyStringer) String() string {
 }
 
 func (s *lazyStringer) MarshalJSON() ([]byte, error) {
-	return []byte(`"` + s.String() + `"`), nil
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	// The HTML characters are escaped by the caller if needed.
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(s.String())
+	return bytes.TrimRight(out.Bytes(), "\n"), err
 }
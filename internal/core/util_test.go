@@ -0,0 +1,24 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestLazyStringerMarshalJSON(t *testing.T) {
+	test := func(value string, expected string) {
+		out, err := json.Marshal(map[string]interface{}{
+			"value": newLazyStringer(func() string { return value }),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, string(out), expected)
+	}
+
+	test("", `{"value":""}`)
+	test("[Title](path)", `{"value":"[Title](path)"}`)
+	test("\"quoted\"\n", `{"value":"\"quoted\"\n"}`)
+	// json.Marshal escapes the HTML characters.
+	test("<p>HTML</p>", `{"value":"\u003cp\u003eHTML\u003c/p\u003e"}`)
+}
@@ -2,6 +2,34 @@ package fts5
 
 import "strings"
 
+// PhraseQuery creates a SQLite FTS5 query matching the given text as a
+// phrase, which is the sequence of its tokens in the same order.
+func PhraseQuery(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+	return quote(text)
+}
+
+// PrefixQuery creates a SQLite FTS5 query matching the notes containing
+// words starting with each of the given space-separated prefixes. An
+// optional * suffix is accepted on each prefix.
+func PrefixQuery(text string) string {
+	terms := []string{}
+	for _, prefix := range strings.Fields(text) {
+		prefix = strings.TrimRight(prefix, "*")
+		if prefix != "" {
+			terms = append(terms, quote(prefix)+"*")
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+// quote turns the text into a FTS5 string, escaping its double quotes.
+func quote(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+}
+
 // ConvertQuery transforms a Google-like query into a SQLite FTS5 one.
 func ConvertQuery(query string) string {
 	out := ""
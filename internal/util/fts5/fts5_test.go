@@ -82,3 +82,29 @@ func TestConvertQuery(t *testing.T) {
 	// NEAR is not supported
 	test(`NEAR(foo, bar, 4)`, `"NEAR"("foo," "bar," "4")`)
 }
+
+func TestPhraseQuery(t *testing.T) {
+	test := func(text, expected string) {
+		assert.Equal(t, PhraseQuery(text), expected)
+	}
+
+	test(``, ``)
+	test(`  `, ``)
+	test(`foo`, `"foo"`)
+	test(`foo bar`, `"foo bar"`)
+	test(`foo OR -bar*`, `"foo OR -bar*"`)
+	test(`"quoted" text`, `"""quoted"" text"`)
+}
+
+func TestPrefixQuery(t *testing.T) {
+	test := func(text, expected string) {
+		assert.Equal(t, PrefixQuery(text), expected)
+	}
+
+	test(``, ``)
+	test(`*`, ``)
+	test(`comput`, `"comput"*`)
+	test(`comput*`, `"comput"*`)
+	test(`comput  sci*`, `"comput"* "sci"*`)
+	test(`"quo`, `"""quo"*`)
+}
@@ -11,6 +11,8 @@ type Logger interface {
 	Printf(format string, v ...interface{})
 	Println(v ...interface{})
 	Err(error)
+	// Debugf reports a detailed message, useful to diagnose issues.
+	Debugf(format string, v ...interface{})
 }
 
 // NullLogger is a logger ignoring any input.
@@ -24,6 +26,8 @@ func (n *nullLogger) Println(v ...interface{}) {}
 
 func (n *nullLogger) Err(err error) {}
 
+func (n *nullLogger) Debugf(format string, v ...interface{}) {}
+
 // StdLogger is a logger using the standard logger.
 type StdLogger struct {
 	*log.Logger
@@ -39,14 +43,20 @@ func (l StdLogger) Err(err error) {
 	}
 }
 
+func (l StdLogger) Debugf(format string, v ...interface{}) {
+	l.Printf("debug: "+format, v...)
+}
+
 // ProxyLogger is a logger delegating to an underlying logger.
 // Can be used to change the active logger during runtime.
 type ProxyLogger struct {
 	Logger Logger
+	// Debug enables the debug messages, which are ignored otherwise.
+	Debug bool
 }
 
 func NewProxyLogger(logger Logger) *ProxyLogger {
-	return &ProxyLogger{logger}
+	return &ProxyLogger{Logger: logger}
 }
 
 func (l *ProxyLogger) Printf(format string, v ...interface{}) {
@@ -60,3 +70,9 @@ func (l *ProxyLogger) Println(v ...interface{}) {
 func (l *ProxyLogger) Err(err error) {
 	l.Logger.Err(err)
 }
+
+func (l *ProxyLogger) Debugf(format string, v ...interface{}) {
+	if l.Debug {
+		l.Logger.Debugf(format, v...)
+	}
+}
@@ -0,0 +1,104 @@
+package paths
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// IgnoreRules matches paths against a list of patterns using the .gitignore
+// syntax.
+//
+// Patterns are relative to the root directory and evaluated in order, the
+// last matching one wins:
+//   - a `!` prefix re-includes paths ignored by a previous pattern,
+//   - a trailing `/` matches only the files inside a directory,
+//   - a pattern containing a `/` is anchored to the root directory, otherwise
+//     it matches at any depth,
+//   - `**` matches any number of directories.
+type IgnoreRules struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	// Pattern as written by the user.
+	pattern string
+	// Doublestar globs matching the ignored paths.
+	globs  []string
+	negate bool
+}
+
+// NewIgnoreRules parses the given .gitignore-style patterns. Blank patterns
+// and comments starting with `#` are skipped.
+func NewIgnoreRules(patterns []string) (IgnoreRules, error) {
+	rules := IgnoreRules{}
+	for _, pattern := range patterns {
+		rule, ok, err := parseIgnoreRule(pattern)
+		if err != nil {
+			return rules, err
+		}
+		if ok {
+			rules.rules = append(rules.rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func parseIgnoreRule(pattern string) (ignoreRule, bool, error) {
+	rule := ignoreRule{pattern: pattern}
+
+	p := strings.TrimSpace(pattern)
+	if p == "" || strings.HasPrefix(p, "#") {
+		return rule, false, nil
+	}
+
+	if strings.HasPrefix(p, "!") {
+		rule.negate = true
+		p = p[1:]
+	} else if strings.HasPrefix(p, `\!`) || strings.HasPrefix(p, `\#`) {
+		p = p[1:]
+	}
+
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimRight(p, "/")
+	if p == "" {
+		return rule, false, fmt.Errorf("%s: invalid ignore pattern", pattern)
+	}
+
+	if strings.Contains(p, "/") {
+		p = strings.TrimPrefix(p, "/")
+	} else {
+		p = "**/" + p
+	}
+
+	if !doublestar.ValidatePattern(p) {
+		return rule, false, fmt.Errorf("%s: invalid ignore pattern", pattern)
+	}
+
+	if !dirOnly {
+		rule.globs = append(rule.globs, p)
+	}
+	rule.globs = append(rule.globs, p+"/**/*")
+
+	return rule, true, nil
+}
+
+// Match returns whether the given path, relative to the root directory,
+// is ignored. The pattern which took the decision is returned as well.
+func (r IgnoreRules) Match(path string) (ignored bool, pattern string) {
+	path = filepath.ToSlash(path)
+
+	for i := len(r.rules) - 1; i >= 0; i-- {
+		rule := r.rules[i]
+		for _, glob := range rule.globs {
+			// The globs were validated when parsing the rules.
+			if matches, _ := doublestar.Match(glob, path); matches {
+				return !rule.negate, rule.pattern
+			}
+		}
+	}
+
+	return false, ""
+}
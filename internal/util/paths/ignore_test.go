@@ -0,0 +1,62 @@
+package paths
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestIgnoreRulesMatch(t *testing.T) {
+	test := func(patterns []string, path string, expectedIgnored bool, expectedPattern string) {
+		rules, err := NewIgnoreRules(patterns)
+		assert.Nil(t, err)
+		ignored, pattern := rules.Match(path)
+		assert.Equal(t, ignored, expectedIgnored)
+		assert.Equal(t, pattern, expectedPattern)
+	}
+
+	test([]string{}, "a.md", false, "")
+	test([]string{"# comment", "  "}, "a.md", false, "")
+
+	// Unanchored patterns match at any depth.
+	test([]string{"a.md"}, "a.md", true, "a.md")
+	test([]string{"a.md"}, "dir/a.md", true, "a.md")
+	test([]string{"*.md"}, "dir/sub/a.md", true, "*.md")
+	test([]string{"b.md"}, "a.md", false, "")
+
+	// Unanchored directory names ignore their content.
+	test([]string{"build"}, "build/a.md", true, "build")
+	test([]string{"build"}, "dir/build/sub/a.md", true, "build")
+	test([]string{"build/"}, "dir/build/a.md", true, "build/")
+	test([]string{"build/"}, "build", false, "")
+
+	// Patterns with a slash are anchored to the root.
+	test([]string{"dir/a.md"}, "dir/a.md", true, "dir/a.md")
+	test([]string{"dir/a.md"}, "sub/dir/a.md", false, "")
+	test([]string{"/a.md"}, "a.md", true, "/a.md")
+	test([]string{"/a.md"}, "dir/a.md", false, "")
+	test([]string{"dir/*"}, "dir/sub/a.md", true, "dir/*")
+
+	// Double stars match any number of directories.
+	test([]string{"**/gen/*.md"}, "gen/a.md", true, "**/gen/*.md")
+	test([]string{"**/gen/*.md"}, "dir/sub/gen/a.md", true, "**/gen/*.md")
+	test([]string{"dir/**/a.md"}, "dir/a.md", true, "dir/**/a.md")
+	test([]string{"dir/**/a.md"}, "dir/sub/sub/a.md", true, "dir/**/a.md")
+
+	// The last matching pattern wins.
+	test([]string{"gen/", "!gen/keep.md"}, "gen/keep.md", false, "!gen/keep.md")
+	test([]string{"gen/", "!gen/keep.md"}, "gen/other.md", true, "gen/")
+	test([]string{"!gen/keep.md", "gen/"}, "gen/keep.md", true, "gen/")
+
+	// Escaped special characters.
+	test([]string{`\!important.md`}, "!important.md", true, `\!important.md`)
+	test([]string{`\#tag.md`}, "#tag.md", true, `\#tag.md`)
+}
+
+func TestIgnoreRulesInvalidPattern(t *testing.T) {
+	_, err := NewIgnoreRules([]string{"a.md", "[invalid"})
+	assert.Err(t, err, "[invalid: invalid ignore pattern")
+
+	_, err = NewIgnoreRules([]string{"!/"})
+	assert.Err(t, err, "!/: invalid ignore pattern")
+}
@@ -9,8 +9,9 @@ import (
 )
 
 // Walk emits the metadata of each file stored in the directory if they pass
-// the given shouldIgnorePath closure. Hidden files and directories are ignored.
-func Walk(basePath string, logger util.Logger, notebookRoot string, shouldIgnorePath func(string) (bool, error)) <-chan Metadata {
+// the given shouldIgnorePath closure. Hidden files and directories are ignored
+// unless includeHidden is true.
+func Walk(basePath string, logger util.Logger, notebookRoot string, includeHidden bool, shouldIgnorePath func(string) (bool, error)) <-chan Metadata {
 	c := make(chan Metadata, 50)
 	go func() {
 		defer close(c)
@@ -21,7 +22,7 @@ func Walk(basePath string, logger util.Logger, notebookRoot string, shouldIgnore
 			}
 
 			filename := info.Name()
-			isHidden := strings.HasPrefix(filename, ".")
+			isHidden := !includeHidden && strings.HasPrefix(filename, ".")
 			isNotebookRoot := filename == notebookRoot
 
 			if info.IsDir() {
@@ -18,7 +18,7 @@ func TestWalk(t *testing.T) {
 
 	notebookRoot := filepath.Base(path)
 	actual := make([]string, 0)
-	for m := range Walk(path, &util.NullLogger, notebookRoot, shouldIgnore) {
+	for m := range Walk(path, &util.NullLogger, notebookRoot, false, shouldIgnore) {
 		assert.NotNil(t, m.Modified)
 		actual = append(actual, m.Path)
 	}
@@ -47,7 +47,7 @@ func TestWalkHidden(t *testing.T) {
 
 	notebookRoot := filepath.Base(path)
 	actual := make([]string, 0)
-	for m := range Walk(path, &util.NullLogger, notebookRoot, shouldIgnore) {
+	for m := range Walk(path, &util.NullLogger, notebookRoot, false, shouldIgnore) {
 		assert.NotNil(t, m.Modified)
 		actual = append(actual, m.Path)
 	}
@@ -63,3 +63,33 @@ func TestWalkHidden(t *testing.T) {
 		"dir2/a.md",
 	})
 }
+
+func TestWalkIncludeHidden(t *testing.T) {
+	var path = fixtures.Path(".walk-hidden")
+
+	shouldIgnore := func(path string) (bool, error) {
+		return false, nil
+	}
+
+	notebookRoot := filepath.Base(path)
+	actual := make([]string, 0)
+	for m := range Walk(path, &util.NullLogger, notebookRoot, true, shouldIgnore) {
+		actual = append(actual, m.Path)
+	}
+
+	assert.Equal(t, actual, []string{
+		".hidden-dir/.hidden-file-in-hidden-dir",
+		".hidden-file-at-root",
+		"Dir3/a.md",
+		"a.md",
+		"b.md",
+		"dir1/.ignored/a.md",
+		"dir1/.ignored.md",
+		"dir1/a.md",
+		"dir1/b.md",
+		"dir1/dir1/a.md",
+		"dir1/ignored.txt",
+		"dir1 a space/a.md",
+		"dir2/a.md",
+	})
+}
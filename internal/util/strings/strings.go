@@ -7,6 +7,10 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/gosimple/slug"
+	"github.com/mattn/go-runewidth"
 )
 
 // Prepend prefixes each lines of a string with the given prefix.
@@ -150,6 +154,46 @@ func WordAt(str string, index int) string {
 
 var wordRegex = regexp.MustCompile(`[^ \t\n\f\r,;\[\]\"\']+`)
 
+// SentenceAt returns the sentence containing the given byte offset. Sentences
+// are delimited by line breaks or by a punctuation mark followed by a space.
+func SentenceAt(content string, index int) string {
+	if index < 0 || index >= len(content) {
+		return ""
+	}
+
+	start := 0
+	for i := index - 1; i >= 0; i-- {
+		c := content[i]
+		if c == '\n' || (isSentenceEnd(c) && i+1 < index && isSpace(content[i+1])) {
+			start = i + 1
+			break
+		}
+	}
+
+	end := len(content)
+	for i := index; i < len(content); i++ {
+		c := content[i]
+		if c == '\n' {
+			end = i
+			break
+		}
+		if isSentenceEnd(c) && (i+1 == len(content) || isSpace(content[i+1])) {
+			end = i + 1
+			break
+		}
+	}
+
+	return strings.TrimSpace(content[start:end])
+}
+
+func isSentenceEnd(c byte) bool {
+	return c == '.' || c == '!' || c == '?'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
 func CopyList(list []string) []string {
 	out := make([]string, len(list))
 	copy(out, list)
@@ -166,3 +210,123 @@ func ByteIndexToRuneIndex(s string, i int) int {
 	}
 	return res
 }
+
+// Wrap hard-wraps the lines of the given Markdown text at word boundaries, so
+// that they fit in the given width.
+//
+// Fenced or indented code blocks, tables and headings are left untouched, and
+// the continuation lines of list items and quotes keep their prefix
+// indentation. Words longer than the width are not broken.
+func Wrap(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	res := make([]string, 0, len(lines))
+	fence := ""
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			res = append(res, line)
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			res = append(res, line)
+			continue
+		}
+
+		if DisplayWidth(line) <= width ||
+			strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") ||
+			strings.HasPrefix(trimmed, "|") || strings.HasPrefix(trimmed, "#") {
+			res = append(res, line)
+			continue
+		}
+
+		res = append(res, wrapLine(line, width)...)
+	}
+
+	return strings.Join(res, "\n")
+}
+
+var wrapPrefixRegex = regexp.MustCompile(`^\s*(?:(?:>\s?)+|[-*+]\s+|\d+[.)]\s+)?`)
+
+// wrapLine splits a single line at word boundaries.
+func wrapLine(line string, width int) []string {
+	prefix := wrapPrefixRegex.FindString(line)
+	content := line[len(prefix):]
+
+	// Quotes are repeated on each line, while list items are indented.
+	indent := prefix
+	if !strings.Contains(prefix, ">") {
+		indent = strings.Repeat(" ", DisplayWidth(prefix))
+	}
+
+	lines := []string{}
+	current := prefix
+	currentHasWord := false
+	for _, word := range strings.Fields(content) {
+		if currentHasWord && DisplayWidth(current)+1+DisplayWidth(word) > width {
+			lines = append(lines, current)
+			current = indent
+			currentHasWord = false
+		}
+		if currentHasWord {
+			current += " "
+		}
+		current += word
+		currentHasWord = true
+	}
+	return append(lines, current)
+}
+
+var ansiRegex = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// DisplayWidth returns the number of columns needed to display the given
+// string in a terminal, ignoring any ANSI escape sequence.
+//
+// East Asian wide characters and most emojis take two columns, while
+// combining marks and zero-width characters don't take any.
+func DisplayWidth(s string) int {
+	return runewidth.StringWidth(ansiRegex.ReplaceAllString(s, ""))
+}
+
+// SlugCase is the letter case of the slugs generated by Slugify.
+type SlugCase string
+
+const (
+	SlugLowercase SlugCase = "lower"
+	SlugUppercase SlugCase = "upper"
+	SlugPreserve  SlugCase = "preserve"
+)
+
+// slugMutex guards the global settings of the slug package.
+var slugMutex sync.Mutex
+
+// Slugify converts the given text into a slug made of ASCII letters, digits
+// and the separator, suitable for filenames and URLs.
+//
+// The accented and non-Latin letters are transliterated according to the
+// given language, e.g. ü becomes ue in German, and the runs of spaces and
+// punctuation collapse into a single separator.
+func Slugify(text string, lang string, separator string, letterCase SlugCase) string {
+	slugMutex.Lock()
+	slug.Lowercase = letterCase != SlugPreserve
+	res := slug.MakeLang(text, lang)
+	slug.Lowercase = true
+	slugMutex.Unlock()
+
+	if letterCase == SlugUppercase {
+		res = strings.ToUpper(res)
+	}
+	if separator != "-" {
+		res = strings.ReplaceAll(res, "-", separator)
+	}
+	return res
+}
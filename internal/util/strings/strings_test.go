@@ -152,6 +152,21 @@ func TestWordAt(t *testing.T) {
 	test("one @:~two three", 5, "@:~two")
 }
 
+func TestSentenceAt(t *testing.T) {
+	test := func(s string, index int, expected string) {
+		assert.Equal(t, SentenceAt(s, index), expected)
+	}
+
+	test("", 0, "")
+	test("One sentence", -1, "")
+	test("One sentence", 12, "")
+	test("One sentence", 4, "One sentence")
+	test("First one. Second one! Third?", 13, "Second one!")
+	test("First one. Second one! Third?", 26, "Third?")
+	test("Version 1.2 is out. Next", 4, "Version 1.2 is out.")
+	test("First line\nSecond line", 14, "Second line")
+}
+
 func TestByteIndexToRuneIndex(t *testing.T) {
 	test := func(s string, index int, expected int) {
 		assert.Equal(t, ByteIndexToRuneIndex(s, index), expected)
@@ -176,3 +191,69 @@ func TestByteIndexToRuneIndex(t *testing.T) {
 	test(source, 21, 19)
 	test(source, 22, 19)
 }
+
+func TestWrap(t *testing.T) {
+	test := func(text string, width int, expected string) {
+		assert.Equal(t, Wrap(text, width), expected)
+	}
+
+	test("", 10, "")
+	test("A short line", 0, "A short line")
+	test("A short line", 20, "A short line")
+	test("A line which is too long", 10, "A line\nwhich is\ntoo long")
+	test("Averyveryverylongword and more", 10, "Averyveryverylongword\nand more")
+	test("First paragraph\n\nSecond paragraph", 10, "First\nparagraph\n\nSecond\nparagraph")
+	test("- A list item too long", 10, "- A list\n  item too\n  long")
+	test("  1. A list item too long", 13, "  1. A list\n     item too\n     long")
+	test("> A quote which is long", 10, "> A quote\n> which is\n> long")
+	test("# A heading which is too long", 10, "# A heading which is too long")
+	test("| a table | which is | long |", 10, "| a table | which is | long |")
+	test("```\na code block which is long\n```\nText too long", 10, "```\na code block which is long\n```\nText too\nlong")
+	test("    indented code which is long", 10, "    indented code which is long")
+	test("\x1b[1mBold\x1b[0m text", 9, "\x1b[1mBold\x1b[0m text")
+	// Wide characters take two columns
+	test("日本語 の テキスト", 10, "日本語 の\nテキスト")
+	test("😀 😀 😀 😀", 8, "😀 😀 😀\n😀")
+	// Combining marks don't take any column
+	test("cafe\u0301 cafe\u0301", 10, "cafe\u0301 cafe\u0301")
+}
+
+func TestDisplayWidth(t *testing.T) {
+	test := func(text string, expected int) {
+		assert.Equal(t, DisplayWidth(text), expected)
+	}
+
+	test("", 0)
+	test("Hello", 5)
+	test("\x1b[1mBold\x1b[0m", 4)
+	// CJK
+	test("日本語", 6)
+	test("한국어 text", 11)
+	// Combining marks
+	test("cafe\u0301", 4)
+	test("e\u0301\u0302", 1)
+	// Zero-width characters
+	test("a\u200bb", 2)
+	// Emojis
+	test("😀", 2)
+	test("👍🏽", 2)
+	test("👩‍💻", 2)
+}
+
+func TestSlugify(t *testing.T) {
+	test := func(text string, lang string, separator string, letterCase SlugCase, expected string) {
+		assert.Equal(t, Slugify(text, lang, separator, letterCase), expected)
+	}
+
+	test("This will be slugified!", "en", "-", SlugLowercase, "this-will-be-slugified")
+	test("  Spaces -- and ... punctuation!  ", "en", "-", SlugLowercase, "spaces-and-punctuation")
+	test("Crème Brûlée", "en", "-", SlugLowercase, "creme-brulee")
+	test("Über Größe", "de", "-", SlugLowercase, "ueber-groesse")
+	test("Über Größe", "en", "-", SlugLowercase, "uber-grosse")
+	test("Crème Brûlée", "en", "_", SlugPreserve, "Creme_Brulee")
+	test("Crème Brûlée", "en", ".", SlugUppercase, "CREME.BRULEE")
+	test("Crème Brûlée", "en", "", SlugLowercase, "cremebrulee")
+
+	// The global settings of the slug package are restored.
+	test("Crème Brûlée", "en", "-", SlugLowercase, "creme-brulee")
+}
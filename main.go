@@ -9,9 +9,11 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/alecthomas/kong"
+	"github.com/mattn/go-isatty"
 	"github.com/zk-org/zk/internal/cli"
 	"github.com/zk-org/zk/internal/cli/cmd"
 	"github.com/zk-org/zk/internal/core"
@@ -25,19 +27,38 @@ var root struct {
 	Init  cmd.Init  `cmd group:"zk" help:"Create a new notebook in the given directory."`
 	Index cmd.Index `cmd group:"zk" help:"Index the notes to be searchable."`
 
-	New   cmd.New   `cmd group:"notes" help:"Create a new note in the given notebook directory."`
-	List  cmd.List  `cmd group:"notes" help:"List notes matching the given criteria."`
-	Graph cmd.Graph `cmd group:"notes" help:"Produce a graph of the notes matching the given criteria."`
-	Edit  cmd.Edit  `cmd group:"notes" help:"Edit notes matching the given criteria."`
-	Tag   cmd.Tag   `cmd group:"notes" help:"Manage the note tags."`
+	Completion cmd.Completion `cmd group:"zk" help:"Generate a completion script for the given shell."`
+	Serve      cmd.Serve      `cmd group:"zk" help:"Serve the notes as a JSON API over HTTP."`
+	Config     cmd.Config     `cmd group:"zk" help:"Manage the configuration files."`
+
+	New     cmd.New     `cmd group:"notes" help:"Create a new note in the given notebook directory."`
+	Journal cmd.Journal `cmd group:"notes" help:"Create or open the journal note of the day."`
+	List    cmd.List    `cmd group:"notes" help:"List notes matching the given criteria."`
+	Graph   cmd.Graph   `cmd group:"notes" help:"Produce a graph of the notes matching the given criteria."`
+	Edit    cmd.Edit    `cmd group:"notes" help:"Edit notes matching the given criteria."`
+	Export  cmd.Export  `cmd group:"notes" help:"Bundle the notes matching the given criteria into a single document."`
+	Tag     cmd.Tag     `cmd group:"notes" help:"Manage the note tags."`
+	Stats   cmd.Stats   `cmd group:"notes" help:"Print statistics about the notes matching the given criteria."`
+	Dedup   cmd.Dedup   `cmd group:"notes" help:"Find the notes with duplicate content among the notes matching the given criteria."`
+
+	PruneEmpty cmd.PruneEmpty `cmd group:"notes" help:"List or delete the empty notes among the notes matching the given criteria."`
+
+	Backlinks   cmd.Backlinks   `cmd group:"notes" help:"Maintain a section listing the notes linking to the notes matching the given criteria."`
+	RepairLinks cmd.RepairLinks `cmd group:"notes" help:"Repair the relative links broken by moving notes manually."`
+
+	ValidateFrontmatter cmd.ValidateFrontmatter `cmd group:"notes" help:"Check the frontmatter of notes against the schema of the config."`
 
 	NotebookDir string  `type:path placeholder:PATH help:"Turn off notebook auto-discovery and set manually the notebook where commands are run."`
+	Notebook    string  `placeholder:NAME help:"Run the commands in a notebook registered in the global config."`
 	WorkingDir  string  `short:W type:path placeholder:PATH help:"Run as if zk was started in <PATH> instead of the current working directory."`
 	NoInput     NoInput `help:"Never prompt or ask for confirmation."`
+	Yes         Yes     `short:"y" xor:"input" help:"Automatically answer yes to any confirmation."`
 	// ForceInput is a debugging flag overriding the default value of interaction prompts.
 	ForceInput string `hidden xor:"input"`
-	Debug      bool   `default:"0" hidden help:"Print a debug stacktrace on SIGINT."`
+	Debug      bool   `default:"0" env:"ZK_DEBUG" help:"Print detailed messages to diagnose issues, and a stacktrace on SIGINT."`
 	DebugStyle bool   `default:"0" hidden help:"Force styling output as XML tags."`
+	// OutputErrors is checked before parsing the command line, see jsonErrors.
+	OutputErrors string `default:"text" enum:"text,json,auto" env:"ZK_OUTPUT_ERRORS" placeholder:"FORMAT" help:"Print the errors as text, as JSON objects with an error code, or as JSON only when the standard error is not a terminal (auto)."`
 
 	ShowHelp ShowHelp         `cmd hidden default:"1"`
 	LSP      cmd.LSP          `cmd hidden`
@@ -52,6 +73,14 @@ func (f NoInput) BeforeApply(container *cli.Container) error {
 	return nil
 }
 
+// Yes is a flag confirming automatically any prompt when enabled.
+type Yes bool
+
+func (f Yes) BeforeApply(container *cli.Container) error {
+	container.Terminal.AutoConfirm = true
+	return nil
+}
+
 // ShowHelp is the default command run. It's equivalent to `zk --help`.
 type ShowHelp struct{}
 
@@ -73,17 +102,19 @@ func main() {
 	// Create the dependency graph.
 	container, err := cli.NewContainer(Version)
 	fatalIfError(err)
+	container.Logger.Debug = isDebug(args)
+	jsonErrors = isJSONErrors(args)
 
 	// Open the notebook if there's any.
 	dirs, args, err := parseDirs(args)
 	fatalIfError(err)
-	searchDirs, err := notebookSearchDirs(dirs)
+	searchDirs, err := notebookSearchDirs(dirs, container)
 	fatalIfError(err)
 	err = container.SetCurrentNotebook(searchDirs)
 	fatalIfError(err)
 
 	// Run the alias or command.
-	if isAlias, err := runAlias(container, args); isAlias {
+	if isAlias, err := runAlias(container, args, dirs.WorkingDir); isAlias {
 		fatalIfError(err)
 	} else {
 		parser, err := kong.New(&root, options(container)...)
@@ -106,12 +137,12 @@ func main() {
 			if notebook, err := container.CurrentNotebook(); err == nil {
 				index := cmd.Index{Quiet: true}
 				err = index.RunWithNotebook(container, notebook)
-				ctx.FatalIfErrorf(err)
+				fatalIfContextError(ctx, err)
 			}
 		}
 
 		err = ctx.Run(container)
-		ctx.FatalIfErrorf(err)
+		fatalIfContextError(ctx, err)
 	}
 }
 
@@ -120,6 +151,7 @@ func options(container *cli.Container) []kong.Option {
 	return []kong.Option{
 		kong.Bind(container),
 		kong.Name("zk"),
+		kong.Resolvers(cli.NewCommandDefaultsResolver(container.Config.CommandDefaults)),
 		kong.UsageOnError(),
 		kong.HelpOptions{
 			Compact:             true,
@@ -141,13 +173,27 @@ func options(container *cli.Container) []kong.Option {
 	}
 }
 
+// jsonErrors is whether the errors are printed as JSON objects, with
+// --output-errors json.
+var jsonErrors bool
+
 func fatalIfError(err error) {
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "zk: error: %v\n", err)
+		cli.PrintError(os.Stderr, err, jsonErrors)
 		os.Exit(1)
 	}
 }
 
+// fatalIfContextError is like fatalIfError, but prints the usage of the
+// command after a parsing error when the errors are printed as text.
+func fatalIfContextError(ctx *kong.Context, err error) {
+	if jsonErrors {
+		fatalIfError(err)
+	} else {
+		ctx.FatalIfErrorf(err)
+	}
+}
+
 func setupDebugMode() {
 	c := make(chan os.Signal)
 	go func() {
@@ -161,24 +207,100 @@ func setupDebugMode() {
 	signal.Notify(c, os.Interrupt)
 }
 
+// isDebug returns whether the debug messages are enabled with the --debug
+// flag or the ZK_DEBUG environment variable. It is checked before parsing
+// the command line to report the notebook discovery as well.
+func isDebug(args []string) bool {
+	if debug, err := strconv.ParseBool(os.Getenv("ZK_DEBUG")); err == nil && debug {
+		return true
+	}
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--debug" {
+			// Propagates the flag to the zk commands run by the aliases.
+			os.Setenv("ZK_DEBUG", "1")
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONErrors returns whether the errors are printed as JSON objects, with
+// the --output-errors flag or the ZK_OUTPUT_ERRORS environment variable. Like
+// isDebug, it is checked before parsing the command line to report the errors
+// happening earlier.
+func isJSONErrors(args []string) bool {
+	format := os.Getenv("ZK_OUTPUT_ERRORS")
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--output-errors" && i+1 < len(args) {
+			format = args[i+1]
+		} else if strings.HasPrefix(arg, "--output-errors=") {
+			format = strings.TrimPrefix(arg, "--output-errors=")
+		}
+	}
+	// Propagates the flag to the zk commands run by the aliases.
+	if format != "" {
+		os.Setenv("ZK_OUTPUT_ERRORS", format)
+	}
+
+	switch format {
+	case "json":
+		return true
+	case "auto":
+		return !isatty.IsTerminal(os.Stderr.Fd())
+	default:
+		return false
+	}
+}
+
 // runAlias will execute a user alias if the command is one of them.
-func runAlias(container *cli.Container, args []string) (bool, error) {
+//
+// The alias is run from the working directory given with --working-dir, or
+// from the root of the current notebook otherwise.
+func runAlias(container *cli.Container, args []string, workingDir string) (bool, error) {
 	if len(args) < 1 {
 		return false, nil
 	}
 
-	runningAlias := os.Getenv("ZK_RUNNING_ALIAS")
+	// Aliases being run by the parent zk processes, in calling order.
+	runningAliases := []string{}
+	if env := os.Getenv("ZK_RUNNING_ALIASES"); env != "" {
+		runningAliases = strings.Split(env, ":")
+	}
+
 	for alias, cmdStr := range container.Config.Aliases {
-		if alias == runningAlias || alias != args[0] {
+		if alias != args[0] {
 			continue
 		}
 
-		// Prevent infinite loop if an alias calls itself.
-		os.Setenv("ZK_RUNNING_ALIAS", alias)
+		for i, running := range runningAliases {
+			if running != alias {
+				continue
+			}
+			// An alias calling itself runs the native command instead.
+			if i == len(runningAliases)-1 {
+				return false, nil
+			}
+			// Prevent infinite loop if aliases call each other.
+			cycle := append(runningAliases[i:], alias)
+			return true, fmt.Errorf("alias cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+		os.Setenv("ZK_RUNNING_ALIASES", strings.Join(append(runningAliases, alias), ":"))
+		container.Logger.Debugf("running alias %s: %s", alias, cmdStr)
 
-		// Move to the current notebook's root directory before running the alias.
-		if notebook, err := container.CurrentNotebook(); err == nil {
-			cmdStr = `cd "` + notebook.Path + `" && ` + cmdStr
+		dir := workingDir
+		if dir == "" {
+			if notebook, err := container.CurrentNotebook(); err == nil {
+				dir = notebook.Path
+			}
+		}
+		if dir != "" {
+			cmdStr = `cd "` + dir + `" && ` + cmdStr
 		}
 
 		cmd := executil.CommandFromString(cmdStr, args[1:]...)
@@ -206,9 +328,10 @@ func runAlias(container *cli.Container, args []string) (bool, error) {
 //
 // By order of precedence:
 //  1. --notebook-dir flag
-//  2. current working directory
-//  3. ZK_NOTEBOOK_DIR environment variable
-func notebookSearchDirs(dirs cli.Dirs) ([]cli.Dirs, error) {
+//  2. --notebook flag
+//  3. current working directory
+//  4. ZK_NOTEBOOK_DIR environment variable
+func notebookSearchDirs(dirs cli.Dirs, container *cli.Container) ([]cli.Dirs, error) {
 	wd, err := os.Getwd()
 	if err != nil {
 		return nil, err
@@ -224,9 +347,24 @@ func notebookSearchDirs(dirs cli.Dirs) ([]cli.Dirs, error) {
 		return []cli.Dirs{dirs}, nil
 	}
 
+	// 2. --notebook flag
+	if dirs.NotebookName != "" {
+		notebookDir, err := container.NamedNotebookDir(dirs.NotebookName)
+		if err != nil {
+			return nil, err
+		}
+		dirs.NotebookDir = notebookDir
+		// Path arguments are relative to the named notebook, wherever zk is
+		// started from.
+		if dirs.WorkingDir == "" {
+			dirs.WorkingDir = notebookDir
+		}
+		return []cli.Dirs{dirs}, nil
+	}
+
 	candidates := []cli.Dirs{}
 
-	// 2. current working directory
+	// 3. current working directory
 	wdDirs := dirs
 	if wdDirs.WorkingDir == "" {
 		wdDirs.WorkingDir = wd
@@ -234,7 +372,7 @@ func notebookSearchDirs(dirs cli.Dirs) ([]cli.Dirs, error) {
 	wdDirs.NotebookDir = wdDirs.WorkingDir
 	candidates = append(candidates, wdDirs)
 
-	// 3. ZK_NOTEBOOK_DIR environment variable
+	// 4. ZK_NOTEBOOK_DIR environment variable
 	if notebookDir, ok := os.LookupEnv("ZK_NOTEBOOK_DIR"); ok {
 		dirs := dirs
 		dirs.NotebookDir = notebookDir
@@ -248,7 +386,7 @@ func notebookSearchDirs(dirs cli.Dirs) ([]cli.Dirs, error) {
 }
 
 // parseDirs returns the paths specified with the --notebook-dir and
-// --working-dir flags.
+// --working-dir flags, and the name given with the --notebook flag.
 //
 // We need to parse these flags before Kong, because we might need it to
 // resolve zk command aliases before parsing the CLI.
@@ -278,7 +416,7 @@ func parseDirs(args []string) (cli.Dirs, []string, error) {
 		return str == long || (short != "" && str == short)
 	}
 
-	findFlag := func(long string, short string, args []string) (string, []string, error) {
+	findFlag := func(long string, short string, isPath bool, args []string) (string, []string, error) {
 		newArgs := []string{}
 		for i, arg := range args {
 			// We can be given "--notebook-dir x" (two args) or "--notebook-dir=x" (one arg)
@@ -305,23 +443,34 @@ func parseDirs(args []string) (cli.Dirs, []string, error) {
 				newArgs = append(newArgs, arg)
 			}
 
+			argName := "a name"
+			if isPath {
+				argName = "a path"
+			}
 			if option != "" && value != "" {
+				if !isPath {
+					return value, newArgs, nil
+				}
 				path, err := filepath.Abs(value)
 				return path, newArgs, err
 			} else if option != "" && value == "" {
-				return "", newArgs, errors.New(option + " requires a path argument")
+				return "", newArgs, errors.New(option + " requires " + argName + " argument")
 			} else if len(args) == (i+1) && matchesLongOrShort(arg, long, short) {
-				return "", newArgs, errors.New(arg + " requires a path argument")
+				return "", newArgs, errors.New(arg + " requires " + argName + " argument")
 			}
 		}
 		return "", newArgs, nil
 	}
 
-	d.NotebookDir, args, err = findFlag("--notebook-dir", "", args)
+	d.NotebookDir, args, err = findFlag("--notebook-dir", "", true, args)
+	if err != nil {
+		return d, args, err
+	}
+	d.NotebookName, args, err = findFlag("--notebook", "", false, args)
 	if err != nil {
 		return d, args, err
 	}
-	d.WorkingDir, args, err = findFlag("--working-dir", "-W", args)
+	d.WorkingDir, args, err = findFlag("--working-dir", "-W", true, args)
 	if err != nil {
 		return d, args, err
 	}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestParseDirs(t *testing.T) {
+	abs := func(path string) string {
+		path, err := filepath.Abs(path)
+		assert.Nil(t, err)
+		return path
+	}
+
+	test := func(args []string, expectedDirs cli.Dirs, expectedArgs []string) {
+		dirs, newArgs, err := parseDirs(args)
+		assert.Nil(t, err)
+		assert.Equal(t, dirs, expectedDirs)
+		assert.Equal(t, newArgs, expectedArgs)
+	}
+
+	test([]string{"list", "-q"}, cli.Dirs{}, []string{"list", "-q"})
+
+	test([]string{"list", "--notebook-dir", "./x", "-q"}, cli.Dirs{NotebookDir: abs("x")}, []string{"list", "-q"})
+	test([]string{"list", "--notebook-dir=./x", "-q"}, cli.Dirs{NotebookDir: abs("x")}, []string{"list", "-q"})
+	test([]string{"--notebook-dir=/foo", "list"}, cli.Dirs{NotebookDir: "/foo"}, []string{"list"})
+
+	test([]string{"list", "--working-dir", "./x"}, cli.Dirs{WorkingDir: abs("x")}, []string{"list"})
+	test([]string{"list", "--working-dir=./x"}, cli.Dirs{WorkingDir: abs("x")}, []string{"list"})
+	test([]string{"list", "-W", "./x"}, cli.Dirs{WorkingDir: abs("x")}, []string{"list"})
+	test([]string{"list", "-W=./x"}, cli.Dirs{WorkingDir: abs("x")}, []string{"list"})
+
+	test([]string{"--notebook=work", "list"}, cli.Dirs{NotebookName: "work"}, []string{"list"})
+
+	test(
+		[]string{"--notebook-dir=./nb", "list", "-W=./wd", "--title=a=b"},
+		cli.Dirs{NotebookDir: abs("nb"), WorkingDir: abs("wd")},
+		[]string{"list", "--title=a=b"},
+	)
+}
+
+func TestParseDirsMissingValue(t *testing.T) {
+	test := func(args []string, expectedErr string) {
+		_, _, err := parseDirs(args)
+		assert.Err(t, err, expectedErr)
+	}
+
+	test([]string{"list", "--notebook-dir="}, "--notebook-dir requires a path argument")
+	test([]string{"list", "--notebook-dir"}, "--notebook-dir requires a path argument")
+	test([]string{"list", "-W="}, "-W requires a path argument")
+	test([]string{"list", "--notebook="}, "--notebook requires a name argument")
+}
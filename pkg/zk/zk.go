@@ -0,0 +1,67 @@
+// Package zk is a Go API to open, index, search and create the notes of a zk
+// notebook, without running the zk binary.
+//
+//	notebook, err := zk.OpenNotebook("~/notes", zk.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	defer notebook.Close()
+//
+//	_, err = notebook.Index(zk.IndexOpts{})
+//	notes, err := notebook.FindNotes(zk.Filter{Tags: []string{"draft"}})
+//
+// Like the zk binary, programs using this package must be built with the
+// fts5 build tag, e.g. `go build -tags fts5`, to enable the full-text search
+// of SQLite.
+//
+// # Stability
+//
+// The exported identifiers of this package are its stable surface: they are
+// not removed or changed in a backward incompatible way, except in a new
+// minor version before zk 1.0, with a note in the changelog. New fields may be
+// added to the structs, so initialize them with field names. The notebook
+// format, including the config and the templates, is the one documented for
+// the zk binary.
+//
+// Everything under the internal directory of the zk module is an
+// implementation detail which can change in any release. The zk CLI is built
+// on top of the same implementation.
+package zk
+
+import "github.com/zk-org/zk/internal/api"
+
+type (
+	// Options customizes how a notebook is opened.
+	Options = api.Options
+
+	// Notebook is a zk notebook opened with OpenNotebook. It is not safe for
+	// concurrent use, and must be closed with Close when not needed anymore.
+	Notebook = api.Notebook
+
+	// IndexOpts customizes the indexing of a notebook.
+	IndexOpts = api.IndexOpts
+
+	// IndexStats holds the statistics of an indexing.
+	IndexStats = api.IndexStats
+
+	// Filter selects the notes returned by FindNotes. The zero value matches
+	// all the notes.
+	Filter = api.Filter
+
+	// Note is a note found in a notebook.
+	Note = api.Note
+
+	// NewNoteOpts holds the options used to create a note with CreateNote.
+	NewNoteOpts = api.NewNoteOpts
+)
+
+// ErrNotebookNotFound is returned by OpenNotebook when neither the given
+// directory nor its parents are a notebook.
+var ErrNotebookNotFound = api.ErrNotebookNotFound
+
+// OpenNotebook opens the notebook containing the given directory.
+//
+// A leading ~ in the path is expanded to the home directory of the user.
+func OpenNotebook(path string, opts Options) (*Notebook, error) {
+	return api.OpenNotebook(path, opts)
+}
@@ -0,0 +1,140 @@
+package zk
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func newTestNotebook(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, ".zk"), 0755))
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+// openTestNotebook opens the notebook at the given path, failing the test
+// immediately when it can't be opened. The notebook is closed at the end of
+// the test.
+func openTestNotebook(t *testing.T, path string, opts Options) *Notebook {
+	notebook, err := OpenNotebook(path, opts)
+	if err != nil {
+		t.Fatalf("failed to open the notebook: %v", err)
+	}
+	t.Cleanup(func() { notebook.Close() })
+	return notebook
+}
+
+func TestOpenNotebookNotFound(t *testing.T) {
+	_, err := OpenNotebook(t.TempDir(), Options{})
+	assert.True(t, errors.Is(err, ErrNotebookNotFound))
+}
+
+func TestIndexAndFindNotes(t *testing.T) {
+	dir := newTestNotebook(t, map[string]string{
+		"a.md":         "# Alpha\n\nA #draft about [Beta](ref/b).",
+		"ref/b.md":     "# Beta\n\nThe second note.",
+		"ref/c.md":     "# Gamma\n\nAnother #draft.",
+		"unrelated.md": "# Delta",
+	})
+
+	notebook := openTestNotebook(t, filepath.Join(dir, "ref"), Options{})
+	assert.Equal(t, notebook.Path, dir)
+
+	stats, err := notebook.Index(IndexOpts{})
+	assert.Nil(t, err)
+	assert.Equal(t, stats.SourceCount, 4)
+	assert.Equal(t, stats.AddedCount, 4)
+
+	titles := func(filter Filter) []string {
+		notes, err := notebook.FindNotes(filter)
+		assert.Nil(t, err)
+		titles := []string{}
+		for _, note := range notes {
+			titles = append(titles, note.Title)
+		}
+		return titles
+	}
+
+	assert.Equal(t, titles(Filter{Sort: []string{"title"}}), []string{"Alpha", "Beta", "Delta", "Gamma"})
+	assert.Equal(t, titles(Filter{Tags: []string{"draft"}, Sort: []string{"path"}}), []string{"Alpha", "Gamma"})
+	assert.Equal(t, titles(Filter{Paths: []string{"ref"}, Sort: []string{"path-"}}), []string{"Gamma", "Beta"})
+	assert.Equal(t, titles(Filter{LinkedBy: []string{"a.md"}}), []string{"Beta"})
+	assert.Equal(t, titles(Filter{Match: []string{"second"}}), []string{"Beta"})
+	assert.Equal(t, titles(Filter{Sort: []string{"title-"}, Limit: 1}), []string{"Gamma"})
+
+	_, err = notebook.FindNotes(Filter{Sort: []string{"unknown"}})
+	assert.NotNil(t, err)
+}
+
+func TestCreateNote(t *testing.T) {
+	dir := newTestNotebook(t, map[string]string{
+		".zk/config.toml":          "[note]\nfilename = \"{{slug title}}\"\ntemplate = \"default.md\"\n",
+		".zk/templates/default.md": "# {{title}}\n\n{{content}}\n",
+	})
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "dir"), 0755))
+
+	notebook := openTestNotebook(t, dir, Options{})
+
+	note, err := notebook.CreateNote(NewNoteOpts{
+		Title:     "Hello world",
+		Content:   "Some content.",
+		Directory: "dir",
+	})
+	if err != nil {
+		t.Fatalf("failed to create the note: %v", err)
+	}
+	assert.Equal(t, note.Path, "dir/hello-world.md")
+	assert.Equal(t, note.Title, "Hello world")
+
+	content, err := os.ReadFile(filepath.Join(dir, "dir/hello-world.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, string(content), "# Hello world\n\nSome content.\n")
+
+	// The new note is indexed.
+	notes, err := notebook.FindNotes(Filter{})
+	assert.Nil(t, err)
+	if len(notes) != 1 {
+		t.Fatalf("expected 1 indexed note, got %d", len(notes))
+	}
+	assert.Equal(t, notes[0].Path, "dir/hello-world.md")
+}
+
+func TestCloseNotebook(t *testing.T) {
+	dir := newTestNotebook(t, map[string]string{"a.md": "# Alpha"})
+
+	notebook, err := OpenNotebook(dir, Options{})
+	assert.Nil(t, err)
+	assert.Nil(t, notebook.Close())
+
+	_, err = notebook.FindNotes(Filter{})
+	assert.NotNil(t, err)
+}
+
+// The notebooks opened in the same process report the template errors to
+// their own logger.
+func TestNotebooksUseTheirOwnLogger(t *testing.T) {
+	files := map[string]string{
+		".zk/config.toml":          "[note]\ntemplate = \"default.md\"\n",
+		".zk/templates/default.md": "{{prepend \"> \" 42}}",
+	}
+
+	var firstLog, secondLog bytes.Buffer
+	openTestNotebook(t, newTestNotebook(t, files), Options{Logger: log.New(&firstLog, "", 0)})
+	second := openTestNotebook(t, newTestNotebook(t, files), Options{Logger: log.New(&secondLog, "", 0)})
+
+	_, err := second.CreateNote(NewNoteOpts{Title: "Note", DryRun: true})
+	assert.Nil(t, err)
+	assert.Equal(t, firstLog.String(), "")
+	assert.True(t, strings.Contains(secondLog.String(), "the {{prepend}} template helper is expecting a string as argument, received: 42"))
+}